@@ -0,0 +1,157 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Alg identifies an AEAD algorithm. The value is also the first byte of an envelope.
+type Alg byte
+
+const (
+	Aes256Gcm         Alg = 1
+	ChaCha20Poly1305  Alg = 2
+	XChaCha20Poly1305 Alg = 3
+)
+
+// KeySize is the key length in bytes shared by all supported algorithms.
+const KeySize = 32
+
+var (
+	ErrUnsupportedAlg = errors.New("aead: unsupported algorithm")
+	ErrNonceSize      = errors.New("aead: nonce has the wrong length for the algorithm")
+	ErrNonceReuse     = errors.New("aead: nonce already used with this key")
+	ErrAuthFailed     = errors.New("aead: message authentication failed")
+)
+
+// newCipher returns the cipher.AEAD for alg keyed with a 32-byte key.
+func newCipher(alg Alg, key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("aead: key must be 32 bytes")
+	}
+	switch alg {
+	case Aes256Gcm:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+// NonceSize returns the nonce length in bytes for alg: 12 for AES-256-GCM and
+// ChaCha20-Poly1305, 24 for XChaCha20-Poly1305.
+func NonceSize(alg Alg) (int, error) {
+	switch alg {
+	case Aes256Gcm, ChaCha20Poly1305:
+		return 12, nil
+	case XChaCha20Poly1305:
+		return 24, nil
+	default:
+		return 0, ErrUnsupportedAlg
+	}
+}
+
+// NewNonce returns a random nonce of the right length for alg.
+// With 12-byte nonces keep random nonces to about 2^32 messages per key; 24-byte nonces have no practical limit.
+func NewNonce(alg Alg) ([]byte, error) {
+	size, err := NonceSize(alg)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, size)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// Seal encrypts and authenticates plaintext and aad, returning ciphertext || 16-byte tag.
+// The caller must never reuse a nonce with the same key; see Sealer.
+func Seal(alg Alg, key []byte, nonce []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	c, err := newCipher(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != c.NonceSize() {
+		return nil, ErrNonceSize
+	}
+	return c.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// Open authenticates and decrypts ciphertext || tag produced by Seal.
+// A wrong key, nonce, aad or a modified ciphertext fails with ErrAuthFailed.
+func Open(alg Alg, key []byte, nonce []byte, ciphertext []byte, aad []byte) ([]byte, error) {
+	c, err := newCipher(alg, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != c.NonceSize() {
+		return nil, ErrNonceSize
+	}
+	plaintext, err := c.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrAuthFailed
+	}
+	if plaintext == nil {
+		plaintext = []byte{}
+	}
+	return plaintext, nil
+}
+
+// Sealer seals messages under one key and refuses to use a nonce twice.
+// It remembers every nonce it has sealed with, so use one Sealer per key and session.
+type Sealer struct {
+	alg  Alg
+	key  []byte
+	used map[string]struct{}
+}
+
+// NewSealer returns a Sealer for alg and a 32-byte key.
+func NewSealer(alg Alg, key []byte) (*Sealer, error) {
+	if _, err := newCipher(alg, key); err != nil {
+		return nil, err
+	}
+	k := make([]byte, len(key))
+	copy(k, key)
+	return &Sealer{alg: alg, key: k, used: make(map[string]struct{})}, nil
+}
+
+// claim records nonce as used, failing with ErrNonceReuse if it was used before.
+func (s *Sealer) claim(nonce []byte) error {
+	size, _ := NonceSize(s.alg)
+	if len(nonce) != size {
+		return ErrNonceSize
+	}
+	if _, ok := s.used[string(nonce)]; ok {
+		return ErrNonceReuse
+	}
+	s.used[string(nonce)] = struct{}{}
+	return nil
+}
+
+// Seal is the package-level Seal with nonce reuse detection.
+func (s *Sealer) Seal(nonce []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	if err := s.claim(nonce); err != nil {
+		return nil, err
+	}
+	return Seal(s.alg, s.key, nonce, plaintext, aad)
+}
+
+// SealEnvelope is the package-level SealEnvelope with nonce reuse detection.
+func (s *Sealer) SealEnvelope(nonce []byte, plaintext []byte, aad []byte) (string, error) {
+	if err := s.claim(nonce); err != nil {
+		return "", err
+	}
+	return SealEnvelope(s.alg, s.key, nonce, plaintext, aad)
+}
@@ -0,0 +1,64 @@
+package aead
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x07}, KeySize)
+	for _, alg := range []Alg{Aes256Gcm, ChaCha20Poly1305, XChaCha20Poly1305} {
+		nonce, err := NewNonce(alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := Seal(alg, key, nonce, []byte("payload"), []byte("aad"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pt, err := Open(alg, key, nonce, ct, []byte("aad"))
+		if err != nil || string(pt) != "payload" {
+			t.Fatalf("alg %d: got %q, %v", alg, pt, err)
+		}
+		if _, err := Open(alg, key, nonce, ct, nil); !errors.Is(err, ErrAuthFailed) {
+			t.Fatalf("alg %d: expected ErrAuthFailed, got %v", alg, err)
+		}
+	}
+}
+
+func TestSealErrors(t *testing.T) {
+	if _, err := Seal(Aes256Gcm, make([]byte, 16), make([]byte, 12), nil, nil); err == nil {
+		t.Fatal("expected error for a 16-byte key")
+	}
+	if _, err := Seal(Alg(0), make([]byte, KeySize), make([]byte, 12), nil, nil); !errors.Is(err, ErrUnsupportedAlg) {
+		t.Fatalf("expected ErrUnsupportedAlg, got %v", err)
+	}
+	if _, err := Seal(XChaCha20Poly1305, make([]byte, KeySize), make([]byte, 12), nil, nil); !errors.Is(err, ErrNonceSize) {
+		t.Fatalf("expected ErrNonceSize, got %v", err)
+	}
+}
+
+func TestSealerNonceReuse(t *testing.T) {
+	s, err := NewSealer(ChaCha20Poly1305, make([]byte, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, 12)
+	if _, err := s.Seal(nonce, []byte("first"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Seal(nonce, []byte("second"), nil); !errors.Is(err, ErrNonceReuse) {
+		t.Fatalf("expected ErrNonceReuse, got %v", err)
+	}
+	if _, err := s.SealEnvelope(nonce, []byte("third"), nil); !errors.Is(err, ErrNonceReuse) {
+		t.Fatalf("expected ErrNonceReuse for envelope, got %v", err)
+	}
+	nonce[11] = 1
+	if _, err := s.SealEnvelope(nonce, []byte("fourth"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Seal(make([]byte, 24), nil, nil); !errors.Is(err, ErrNonceSize) {
+		t.Fatalf("expected ErrNonceSize, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package aead
+
+import (
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+var (
+	ErrEnvelopeTruncated = errors.New("aead: envelope is truncated")
+	ErrEnvelopeEncoding  = errors.New("aead: envelope is not URL-safe base64")
+	ErrEnvelopeAlg       = errors.New("aead: envelope algorithm does not match the expected one")
+)
+
+// tagSize is the authentication tag length of every supported algorithm.
+const tagSize = 16
+
+// SealEnvelope seals plaintext and returns the envelope alg id || nonce || ciphertext,
+// encoded with util.EncUrlSafe. aad is authenticated but not stored in the envelope.
+func SealEnvelope(alg Alg, key []byte, nonce []byte, plaintext []byte, aad []byte) (string, error) {
+	ciphertext, err := Seal(alg, key, nonce, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	return util.EncUrlSafe(util.ConcatBytes([]byte{byte(alg)}, nonce, ciphertext)), nil
+}
+
+// ParseEnvelope decodes an envelope into its algorithm, nonce and ciphertext without decrypting.
+func ParseEnvelope(envelope string) (Alg, []byte, []byte, error) {
+	raw, err := util.DecUrlSafe(envelope)
+	if err != nil {
+		return 0, nil, nil, ErrEnvelopeEncoding
+	}
+	if len(raw) == 0 {
+		return 0, nil, nil, ErrEnvelopeTruncated
+	}
+	alg := Alg(raw[0])
+	size, err := NonceSize(alg)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if len(raw) < 1+size+tagSize {
+		return 0, nil, nil, ErrEnvelopeTruncated
+	}
+	return alg, raw[1 : 1+size], raw[1+size:], nil
+}
+
+// OpenEnvelope opens an envelope produced by SealEnvelope with the same key and aad.
+// The envelope's algorithm id must be alg, so the sender cannot choose which cipher the
+// key is used with; a different id fails with ErrEnvelopeAlg before decrypting.
+func OpenEnvelope(alg Alg, key []byte, envelope string, aad []byte) ([]byte, error) {
+	got, nonce, ciphertext, err := ParseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if got != alg {
+		return nil, ErrEnvelopeAlg
+	}
+	return Open(alg, key, nonce, ciphertext, aad)
+}
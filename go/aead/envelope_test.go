@@ -0,0 +1,30 @@
+package aead
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseEnvelope(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, KeySize)
+	nonce := bytes.Repeat([]byte{0x02}, 24)
+	env, err := SealEnvelope(XChaCha20Poly1305, key, nonce, []byte("hi"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alg, gotNonce, ct, err := ParseEnvelope(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg != XChaCha20Poly1305 || !bytes.Equal(gotNonce, nonce) || len(ct) != 2+16 {
+		t.Fatalf("parsed alg %d nonce %x ciphertext %d bytes", alg, gotNonce, len(ct))
+	}
+	pt, err := OpenEnvelope(XChaCha20Poly1305, key, env, nil)
+	if err != nil || string(pt) != "hi" {
+		t.Fatalf("open: got %q, %v", pt, err)
+	}
+	if _, err := OpenEnvelope(ChaCha20Poly1305, key, env, nil); !errors.Is(err, ErrEnvelopeAlg) {
+		t.Fatalf("open with another alg: got %v want ErrEnvelopeAlg", err)
+	}
+}
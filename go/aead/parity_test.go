@@ -0,0 +1,146 @@
+package aead
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Aead struct {
+		Seal []struct {
+			Name       string
+			Alg        int
+			Key        string
+			Nonce      string
+			Aad        string
+			Plaintext  string
+			Ciphertext string
+		}
+		Open []struct {
+			Name       string
+			Alg        int
+			Key        string
+			Nonce      string
+			Aad        string
+			Ciphertext string
+			Error      string
+		}
+		Envelope []struct {
+			Alg       int
+			Key       string
+			Nonce     string
+			Aad       string
+			Plaintext string
+			Envelope  string
+		}
+		EnvelopeOpen []struct {
+			Name     string
+			Alg      int
+			Key      string
+			Aad      string
+			Envelope string
+			Error    string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var aeadErrors = map[string]error{
+	"unsupportedAlg": ErrUnsupportedAlg,
+	"nonceSize":      ErrNonceSize,
+	"nonceReuse":     ErrNonceReuse,
+	"auth":           ErrAuthFailed,
+	"truncated":      ErrEnvelopeTruncated,
+	"encoding":       ErrEnvelopeEncoding,
+	"algMismatch":    ErrEnvelopeAlg,
+}
+
+// checkAeadErr fails unless err matches the named vector error.
+func checkAeadErr(t *testing.T, name string, err error, want string) {
+	t.Helper()
+	sentinel, ok := aeadErrors[want]
+	if !ok {
+		t.Fatalf("%s: unknown error name %q", name, want)
+	}
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("%s: got error %v want %v", name, err, sentinel)
+	}
+}
+
+func TestParity_Aead(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Aead.Seal {
+		alg := Alg(tc.Alg)
+		got, err := Seal(alg, mustHex(tc.Key), mustHex(tc.Nonce), mustHex(tc.Plaintext), mustHex(tc.Aad))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if hex.EncodeToString(got) != tc.Ciphertext {
+			t.Fatalf("%s seal: got %x want %s", tc.Name, got, tc.Ciphertext)
+		}
+		pt, err := Open(alg, mustHex(tc.Key), mustHex(tc.Nonce), got, mustHex(tc.Aad))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if hex.EncodeToString(pt) != tc.Plaintext {
+			t.Fatalf("%s open: got %x want %s", tc.Name, pt, tc.Plaintext)
+		}
+	}
+	for _, tc := range v.Aead.Open {
+		_, err := Open(Alg(tc.Alg), mustHex(tc.Key), mustHex(tc.Nonce), mustHex(tc.Ciphertext), mustHex(tc.Aad))
+		checkAeadErr(t, tc.Name, err, tc.Error)
+	}
+}
+
+func TestParity_Envelope(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Aead.Envelope {
+		got, err := SealEnvelope(Alg(tc.Alg), mustHex(tc.Key), mustHex(tc.Nonce), mustHex(tc.Plaintext), mustHex(tc.Aad))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.Envelope {
+			t.Fatalf("alg %d seal: got %s want %s", tc.Alg, got, tc.Envelope)
+		}
+		pt, err := OpenEnvelope(Alg(tc.Alg), mustHex(tc.Key), tc.Envelope, mustHex(tc.Aad))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pt) != tc.Plaintext {
+			t.Fatalf("alg %d open: got %x want %s", tc.Alg, pt, tc.Plaintext)
+		}
+	}
+	for _, tc := range v.Aead.EnvelopeOpen {
+		_, err := OpenEnvelope(Alg(tc.Alg), mustHex(tc.Key), tc.Envelope, mustHex(tc.Aad))
+		checkAeadErr(t, tc.Name, err, tc.Error)
+	}
+}
@@ -0,0 +1,228 @@
+// Package cpace implements the CPace balanced PAKE (draft-irtf-cfrg-cpace) over
+// ristretto255 and P-256, in the initiator-responder setting, with explicit key
+// confirmation. Both parties derive a secret generator g from the password-related string
+// PRS, a channel identifier CI and an optional session ID sid; each sends Y = y*g with its
+// associated data AD, and both compute K = y*Y' and the intermediate session key
+//
+//	ISK = H(lv_cat(DSI || "_ISK", sid, K) || lv_cat(Ya, ADa) || lv_cat(Yb, ADb))
+//
+// where lv_cat prefixes each field with its LEB128 length, as in the draft. Everything
+// after ISK is a local extension that the draft leaves to the application: the key
+// confirmation keys and the session key are expanded from ISK with HKDF-Expand:
+// KcA || KcB under "CPaceConfirmationKeys" and the session key under "CPaceSessionKey". The
+// MACs are HMAC(KcA, transcript) and HMAC(KcB, transcript) over the same lv_cat transcript,
+// so each side learns the session key only after checking the other's MAC. P-256 elements
+// are uncompressed SEC1 points and K is their x-coordinate, as in the draft. Messages on
+// the wire are framed with util.FramedConcat and 2-byte length prefixes.
+package cpace
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/h2c"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Suite is a CPace ciphersuite identifier.
+type Suite string
+
+const (
+	Ristretto255Sha512 Suite = "CPACE-RISTR255-SHA512"
+	P256Sha256         Suite = "CPACE-P256_XMD:SHA-256_SSWU_NU_-SHA256"
+)
+
+// lengthPrefix is the length prefix of every frame in a wire message.
+const lengthPrefix = 2
+
+var (
+	ErrUnsupportedSuite = errors.New("cpace: unsupported suite")
+	ErrInvalidMessage   = errors.New("cpace: malformed message or invalid element")
+	ErrAuthentication   = errors.New("cpace: key confirmation failed")
+	ErrState            = errors.New("cpace: state machine used out of order or reused")
+)
+
+// Config is what both parties must agree on before the exchange.
+type Config struct {
+	Suite Suite
+	// CI is the channel identifier, such as the encoded identities of both parties. It may
+	// be empty.
+	CI []byte
+	// SID is the session ID. CPace is only secure against replays across sessions if it is
+	// unique, for example agreed by the transport; it may be empty.
+	SID []byte
+}
+
+// params is a Config with its suite resolved.
+type params struct {
+	cfg      Config
+	g        group.Group
+	hashBits int
+	// dsi is the domain separation identifier, and sInBytes the input block size of the
+	// hash, which the generator string is padded to.
+	dsi      []byte
+	sInBytes int
+}
+
+func newParams(cfg Config) (*params, error) {
+	p := &params{cfg: cfg}
+	switch cfg.Suite {
+	case Ristretto255Sha512:
+		p.g, p.hashBits, p.dsi, p.sInBytes = group.Ristretto255, 512, []byte("CPaceRistretto255"), 128
+	case P256Sha256:
+		p.g, p.hashBits, p.dsi, p.sInBytes = group.P256, 256, []byte("CPaceP256_XMD:SHA-256_SSWU_NU_"), 64
+	default:
+		return nil, ErrUnsupportedSuite
+	}
+	return p, nil
+}
+
+func (p *params) hash(data []byte) []byte {
+	out, _ := util.Sha2Hash(data, p.hashBits)
+	return out
+}
+
+// prependLen is LEB128(len(data)) || data.
+func prependLen(data []byte) []byte {
+	var out []byte
+	n := len(data)
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			out = append(out, b)
+			break
+		}
+		out = append(out, b|0x80)
+	}
+	return append(out, data...)
+}
+
+// lvCat concatenates prependLen of every field.
+func lvCat(fields ...[]byte) []byte {
+	var out []byte
+	for _, f := range fields {
+		out = append(out, prependLen(f)...)
+	}
+	return out
+}
+
+// generatorString is lv_cat(DSI, PRS, zero padding, CI, sid); the padding makes DSI and PRS
+// fill the hash's first input block.
+func (p *params) generatorString(prs []byte) []byte {
+	pad := p.sInBytes - len(prependLen(prs)) - len(prependLen(p.dsi)) - 1
+	if pad < 0 {
+		pad = 0
+	}
+	return lvCat(p.dsi, prs, make([]byte, pad), p.cfg.CI, p.cfg.SID)
+}
+
+// generator is g: for ristretto255 the one-way map of H(generator string), and for P-256
+// encode_to_curve of the generator string under DSI || "_DST".
+func (p *params) generator(prs []byte) (group.Element, error) {
+	gen := p.generatorString(prs)
+	switch p.cfg.Suite {
+	case Ristretto255Sha512:
+		h := sha512.Sum512(gen)
+		r, err := group.RistrettoElementFromUniformBytes(h[:])
+		if err != nil {
+			return nil, err
+		}
+		return p.g.DecodeElement(r.Bytes())
+	default:
+		x, y, err := h2c.HashToCurve(h2c.P256NU, gen, util.ConcatBytes(p.dsi, []byte("_DST")))
+		if err != nil {
+			return nil, err
+		}
+		return group.DecodeUncompressed(p.g, util.ConcatBytes([]byte{0x04}, x.FillBytes(make([]byte, 32)), y.FillBytes(make([]byte, 32))))
+	}
+}
+
+func encode(e group.Element) []byte {
+	b, _ := group.MarshalUncompressed(e)
+	return b
+}
+
+// decodeShare parses a peer's Y, rejecting the identity.
+func (p *params) decodeShare(b []byte) (group.Element, error) {
+	e, err := group.DecodeUncompressed(p.g, b)
+	if err != nil || e.IsIdentity() {
+		return nil, ErrInvalidMessage
+	}
+	return e, nil
+}
+
+// sharedSecret is scalar_mult_vfy: K = y*peer, rejected if it is the identity, encoded as
+// the element for ristretto255 and as its x-coordinate for P-256.
+func (p *params) sharedSecret(y group.Scalar, peer group.Element) ([]byte, error) {
+	k := peer.ScalarMult(y)
+	if k.IsIdentity() {
+		return nil, ErrInvalidMessage
+	}
+	b := encode(k)
+	if p.cfg.Suite == P256Sha256 {
+		return b[1:33], nil
+	}
+	return b, nil
+}
+
+// keys is the key schedule for one exchange.
+type keys struct {
+	isk        []byte
+	kcA, kcB   []byte
+	macA, macB []byte
+	sessionKey []byte
+}
+
+func (p *params) keySchedule(k, ya, ada, yb, adb []byte) (*keys, error) {
+	transcript := util.ConcatBytes(lvCat(ya, ada), lvCat(yb, adb))
+	isk := p.hash(util.ConcatBytes(lvCat(util.ConcatBytes(p.dsi, []byte("_ISK")), p.cfg.SID, k), transcript))
+	nh := len(isk)
+	kc, err := util.HkdfExpand(isk, []byte("CPaceConfirmationKeys"), 2*nh, p.hashBits)
+	if err != nil {
+		return nil, err
+	}
+	ks := &keys{isk: isk, kcA: kc[:nh], kcB: kc[nh:]}
+	if ks.sessionKey, err = util.HkdfExpand(isk, []byte("CPaceSessionKey"), nh, p.hashBits); err != nil {
+		return nil, err
+	}
+	if ks.macA, err = util.HmacSha2(ks.kcA, transcript, p.hashBits); err != nil {
+		return nil, err
+	}
+	if ks.macB, err = util.HmacSha2(ks.kcB, transcript, p.hashBits); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// frame and unframe are the wire encoding of a message: its fields framed with 2-byte
+// length prefixes.
+func frame(fields ...[]byte) ([]byte, error) {
+	ff := make([]util.FramedField, len(fields))
+	for i, f := range fields {
+		ff[i] = util.FieldOf(f)
+	}
+	out, err := util.FramedConcat(lengthPrefix, ff...)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return out, nil
+}
+
+func unframe(msg []byte, count int) ([][]byte, error) {
+	fields, err := util.ParseFramed(msg, lengthPrefix)
+	if err != nil || len(fields) != count {
+		return nil, ErrInvalidMessage
+	}
+	return fields, nil
+}
+
+// stage tracks a state machine: each step runs once, in order.
+type stage int
+
+const (
+	stageNew stage = iota
+	stageStarted
+	stageDone
+)
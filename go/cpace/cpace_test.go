@@ -0,0 +1,164 @@
+package cpace
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var suites = []Suite{Ristretto255Sha512, P256Sha256}
+
+// run performs a full exchange and returns the initiator's and responder's keys, or the
+// first error.
+func run(t *testing.T, cfgA, cfgB Config, pwA, pwB []byte) ([]byte, []byte, error) {
+	t.Helper()
+	a, err := NewInitiator(cfgA, pwA, []byte("ADa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewResponder(cfgB, pwB, []byte("ADb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg1, err := a.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := b.Respond(msg1)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg3, keyA, err := a.Finish(msg2)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyB, err := b.Finish(msg3)
+	if err == nil && (!bytes.Equal(a.PeerAD(), []byte("ADb")) || !bytes.Equal(b.PeerAD(), []byte("ADa"))) {
+		t.Fatal("associated data not delivered")
+	}
+	return keyA, keyB, err
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, suite := range suites {
+		cfg := Config{Suite: suite, CI: []byte("phone|tv"), SID: []byte("session-1")}
+		keyA, keyB, err := run(t, cfg, cfg, []byte("493817"), []byte("493817"))
+		if err != nil {
+			t.Fatalf("%s: %v", suite, err)
+		}
+		if !bytes.Equal(keyA, keyB) {
+			t.Fatalf("%s: keys differ", suite)
+		}
+		again, _, err := run(t, cfg, cfg, []byte("493817"), []byte("493817"))
+		if err != nil || bytes.Equal(keyA, again) {
+			t.Fatalf("%s: second run: %v", suite, err)
+		}
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	cfg := Config{Suite: P256Sha256, CI: []byte("ci"), SID: []byte("sid")}
+	if _, _, err := run(t, cfg, cfg, []byte("493817"), []byte("493818")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("password: got %v want ErrAuthentication", err)
+	}
+	other := cfg
+	other.CI = []byte("other")
+	if _, _, err := run(t, cfg, other, []byte("pw"), []byte("pw")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("ci: got %v want ErrAuthentication", err)
+	}
+	other = cfg
+	other.SID = []byte("other")
+	if _, _, err := run(t, cfg, other, []byte("pw"), []byte("pw")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("sid: got %v want ErrAuthentication", err)
+	}
+}
+
+func TestTamperedAD(t *testing.T) {
+	cfg := Config{Suite: Ristretto255Sha512}
+	a, _ := NewInitiator(cfg, []byte("pw"), []byte("ADa"))
+	b, _ := NewResponder(cfg, []byte("pw"), []byte("ADb"))
+	msg1, _ := a.Start()
+	fields, _ := unframe(msg1, 2)
+	tampered, _ := frame(fields[0], []byte("ADx"))
+	msg2, err := b.Respond(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.Finish(msg2); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestForgedInitiatorMAC(t *testing.T) {
+	cfg := Config{Suite: P256Sha256}
+	a, _ := NewInitiator(cfg, []byte("pw"), nil)
+	b, _ := NewResponder(cfg, []byte("pw"), nil)
+	msg1, _ := a.Start()
+	if _, err := b.Respond(msg1); err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := frame(make([]byte, 32))
+	if _, err := b.Finish(forged); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestStateReuse(t *testing.T) {
+	cfg := Config{Suite: Ristretto255Sha512}
+	a, _ := NewInitiator(cfg, []byte("pw"), nil)
+	if _, _, err := a.Finish(nil); !errors.Is(err, ErrState) {
+		t.Fatalf("finish before start: got %v", err)
+	}
+	a, _ = NewInitiator(cfg, []byte("pw"), nil)
+	b, _ := NewResponder(cfg, []byte("pw"), nil)
+	if _, err := b.Finish(nil); !errors.Is(err, ErrState) {
+		t.Fatalf("responder finish first: got %v", err)
+	}
+	b, _ = NewResponder(cfg, []byte("pw"), nil)
+	msg1, _ := a.Start()
+	if _, err := a.Start(); !errors.Is(err, ErrState) {
+		t.Fatalf("second start: got %v", err)
+	}
+	msg2, err := b.Respond(msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Respond(msg1); !errors.Is(err, ErrState) {
+		t.Fatalf("second respond: got %v", err)
+	}
+	msg3, _, err := a.Finish(msg2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.Finish(msg2); !errors.Is(err, ErrState) {
+		t.Fatalf("second initiator finish: got %v", err)
+	}
+	if _, err := b.Finish(msg3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Finish(msg3); !errors.Is(err, ErrState) {
+		t.Fatalf("second responder finish: got %v", err)
+	}
+}
+
+func TestMalformedMessages(t *testing.T) {
+	if _, err := NewInitiator(Config{Suite: "CPACE-X25519-SHA512"}, nil, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("suite: got %v", err)
+	}
+	for _, suite := range suites {
+		cfg := Config{Suite: suite}
+		p, _ := newParams(cfg)
+		identity, _ := frame(encode(p.g.Identity()), nil)
+		generator, _ := frame(encode(p.g.Generator()), nil)
+		for name, msg := range map[string][]byte{
+			"identity":     identity,
+			"truncated":    {0x00, 0x20, 0x01},
+			"three frames": append(append([]byte(nil), generator...), 0x00, 0x00),
+		} {
+			b, _ := NewResponder(cfg, []byte("pw"), nil)
+			if _, err := b.Respond(msg); !errors.Is(err, ErrInvalidMessage) {
+				t.Fatalf("%s %s: got %v want ErrInvalidMessage", suite, name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package cpace
+
+import (
+	"crypto/hmac"
+
+	"github.com/grzegorzmaniak/inparity/group"
+)
+
+// Initiator is party A: Start sends Ya and ADa, and Finish checks the responder's Yb, ADb
+// and MAC, returning A's MAC and the session key.
+type Initiator struct {
+	p      *params
+	stage  stage
+	g      group.Element
+	ad     []byte
+	y      group.Scalar
+	ya     []byte
+	peerAD []byte
+}
+
+// NewInitiator returns the initiator for cfg, the shared password (PRS) and the associated
+// data ad that it sends in the clear.
+func NewInitiator(cfg Config, password, ad []byte) (*Initiator, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	g, err := p.generator(password)
+	if err != nil {
+		return nil, err
+	}
+	return &Initiator{p: p, g: g, ad: append([]byte(nil), ad...)}, nil
+}
+
+// Start draws ya and returns the first message, Ya and ADa.
+func (a *Initiator) Start() ([]byte, error) {
+	y, err := a.p.g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return a.start(y)
+}
+
+func (a *Initiator) start(y group.Scalar) ([]byte, error) {
+	if a.stage != stageNew {
+		return nil, ErrState
+	}
+	a.stage = stageStarted
+	a.y = y
+	a.ya = encode(a.g.ScalarMult(y))
+	return frame(a.ya, a.ad)
+}
+
+// Finish takes the responder's message, Yb, ADb and its MAC, and returns the last message,
+// A's MAC, with the session key. A wrong password fails with ErrAuthentication.
+func (a *Initiator) Finish(msg []byte) ([]byte, []byte, error) {
+	if a.stage != stageStarted {
+		return nil, nil, ErrState
+	}
+	a.stage = stageDone
+	y := a.y
+	a.y = nil
+	fields, err := unframe(msg, 3)
+	if err != nil {
+		return nil, nil, err
+	}
+	yb, err := a.p.decodeShare(fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := a.p.sharedSecret(y, yb)
+	if err != nil {
+		return nil, nil, err
+	}
+	ks, err := a.p.keySchedule(k, a.ya, a.ad, fields[0], fields[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hmac.Equal(fields[2], ks.macB) {
+		return nil, nil, ErrAuthentication
+	}
+	a.peerAD = append([]byte(nil), fields[1]...)
+	out, err := frame(ks.macA)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, ks.sessionKey, nil
+}
+
+// PeerAD returns the responder's associated data once Finish has succeeded.
+func (a *Initiator) PeerAD() []byte {
+	return a.peerAD
+}
+
+// Responder is party B: Respond answers Ya and ADa with Yb, ADb and B's MAC, and Finish
+// checks A's MAC and returns the session key.
+type Responder struct {
+	p      *params
+	stage  stage
+	g      group.Element
+	ad     []byte
+	keys   *keys
+	peerAD []byte
+}
+
+// NewResponder returns the responder for cfg, the shared password (PRS) and the
+// associated data ad that it sends in the clear.
+func NewResponder(cfg Config, password, ad []byte) (*Responder, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	g, err := p.generator(password)
+	if err != nil {
+		return nil, err
+	}
+	return &Responder{p: p, g: g, ad: append([]byte(nil), ad...)}, nil
+}
+
+// Respond takes the initiator's first message and returns Yb and ADb with B's MAC.
+func (b *Responder) Respond(msg []byte) ([]byte, error) {
+	y, err := b.p.g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return b.respond(msg, y)
+}
+
+func (b *Responder) respond(msg []byte, y group.Scalar) ([]byte, error) {
+	if b.stage != stageNew {
+		return nil, ErrState
+	}
+	b.stage = stageStarted
+	fields, err := unframe(msg, 2)
+	if err != nil {
+		return nil, err
+	}
+	ya, err := b.p.decodeShare(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	k, err := b.p.sharedSecret(y, ya)
+	if err != nil {
+		return nil, err
+	}
+	yb := encode(b.g.ScalarMult(y))
+	if b.keys, err = b.p.keySchedule(k, fields[0], fields[1], yb, b.ad); err != nil {
+		return nil, err
+	}
+	b.peerAD = append([]byte(nil), fields[1]...)
+	return frame(yb, b.ad, b.keys.macB)
+}
+
+// Finish checks the initiator's MAC and returns the session key, or ErrAuthentication.
+func (b *Responder) Finish(msg []byte) ([]byte, error) {
+	if b.stage != stageStarted || b.keys == nil {
+		return nil, ErrState
+	}
+	b.stage = stageDone
+	ks := b.keys
+	b.keys = nil
+	fields, err := unframe(msg, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(fields[0], ks.macA) {
+		return nil, ErrAuthentication
+	}
+	return ks.sessionKey, nil
+}
+
+// PeerAD returns the initiator's associated data once Respond has succeeded. It is
+// authenticated only after Finish succeeds.
+func (b *Responder) PeerAD() []byte {
+	return b.peerAD
+}
@@ -0,0 +1,198 @@
+package cpace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Cpace struct {
+		Vectors []struct {
+			Name                       string
+			Suite                      Suite
+			Prs, Ci, Sid               string
+			AdA, AdB                   string
+			GeneratorString, Generator string
+			SecretA, SecretB           string
+			PublicA, PublicB           string
+			K, Isk                     string
+			// Draft holds the draft's published Ya, Yb and K where they differ from ours.
+			Draft *struct {
+				PublicA, PublicB, K string
+			}
+			LocalExtension struct {
+				KcA, KcB, MacA, MacB, SessionKey string
+				Msg1, Msg2, Msg3                 string
+			}
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParity_Vectors(t *testing.T) {
+	v := loadVectors(t)
+	if len(v.Cpace.Vectors) == 0 {
+		t.Fatal("no cpace vectors")
+	}
+	for _, tc := range v.Cpace.Vectors {
+		cfg := Config{Suite: tc.Suite, CI: mustHex(tc.Ci), SID: mustHex(tc.Sid)}
+		p, err := newParams(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		prs, adA, adB := mustHex(tc.Prs), mustHex(tc.AdA), mustHex(tc.AdB)
+		if got := p.generatorString(prs); !bytes.Equal(got, mustHex(tc.GeneratorString)) {
+			t.Fatalf("%s: generator string mismatch: got %x", tc.Name, got)
+		}
+		g, err := p.generator(prs)
+		if err != nil || !bytes.Equal(encode(g), mustHex(tc.Generator)) {
+			t.Fatalf("%s: generator mismatch: %v", tc.Name, err)
+		}
+		ext := tc.LocalExtension
+		ya, _ := p.g.DecodeScalar(mustHex(tc.SecretA))
+		yb, _ := p.g.DecodeScalar(mustHex(tc.SecretB))
+
+		// The draft's key schedule.
+		publicA, publicB := encode(g.ScalarMult(ya)), encode(g.ScalarMult(yb))
+		k, err := p.sharedSecret(ya, g.ScalarMult(yb))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		ks, err := p.keySchedule(k, publicA, adA, publicB, adB)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		for _, f := range []struct {
+			name      string
+			got, want []byte
+		}{
+			{"publicA", publicA, mustHex(tc.PublicA)}, {"publicB", publicB, mustHex(tc.PublicB)}, {"K", k, mustHex(tc.K)},
+			{"isk", ks.isk, mustHex(tc.Isk)},
+			// The local extension: confirmation keys, MACs and the session key.
+			{"KcA", ks.kcA, mustHex(ext.KcA)}, {"KcB", ks.kcB, mustHex(ext.KcB)},
+			{"macA", ks.macA, mustHex(ext.MacA)}, {"macB", ks.macB, mustHex(ext.MacB)},
+			{"sessionKey", ks.sessionKey, mustHex(ext.SessionKey)},
+		} {
+			if !bytes.Equal(f.got, f.want) {
+				t.Fatalf("%s: %s mismatch: got %x want %x", tc.Name, f.name, f.got, f.want)
+			}
+		}
+
+		// The draft's own Ya and Yb come from a different generator, but must still agree
+		// on its K under the same ya and yb.
+		if d := tc.Draft; d != nil {
+			for _, side := range []struct {
+				y    []byte
+				peer string
+			}{{mustHex(tc.SecretA), d.PublicB}, {mustHex(tc.SecretB), d.PublicA}} {
+				y, _ := p.g.DecodeScalar(side.y)
+				peer, err := p.decodeShare(mustHex(side.peer))
+				if err != nil {
+					t.Fatalf("%s: draft share: %v", tc.Name, err)
+				}
+				if k, err := p.sharedSecret(y, peer); err != nil || !bytes.Equal(k, mustHex(d.K)) {
+					t.Fatalf("%s: draft K mismatch: got %x want %s", tc.Name, k, d.K)
+				}
+			}
+		}
+
+		// The Go responder answers the recorded initiator messages.
+		responder, err := NewResponder(cfg, prs, adB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg2, err := responder.respond(mustHex(ext.Msg1), yb)
+		if err != nil || !bytes.Equal(msg2, mustHex(ext.Msg2)) {
+			t.Fatalf("%s: msg2 mismatch: %v", tc.Name, err)
+		}
+		if !bytes.Equal(responder.PeerAD(), adA) {
+			t.Fatalf("%s: responder peer AD mismatch", tc.Name)
+		}
+		key, err := responder.Finish(mustHex(ext.Msg3))
+		if err != nil || !bytes.Equal(key, mustHex(ext.SessionKey)) {
+			t.Fatalf("%s: responder key mismatch: %v", tc.Name, err)
+		}
+
+		// The initiator side.
+		initiator, err := NewInitiator(cfg, prs, adA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg1, err := initiator.start(ya)
+		if err != nil || !bytes.Equal(msg1, mustHex(ext.Msg1)) {
+			t.Fatalf("%s: msg1 mismatch: %v", tc.Name, err)
+		}
+		msg3, key, err := initiator.Finish(mustHex(ext.Msg2))
+		if err != nil || !bytes.Equal(msg3, mustHex(ext.Msg3)) || !bytes.Equal(key, mustHex(ext.SessionKey)) {
+			t.Fatalf("%s: initiator mismatch: %v", tc.Name, err)
+		}
+		if !bytes.Equal(initiator.PeerAD(), adB) {
+			t.Fatalf("%s: initiator peer AD mismatch", tc.Name)
+		}
+	}
+}
+
+// TestParity_DraftGenerator pins this implementation's generator for the inputs of the
+// draft's ristretto255 test vector independently of parity.json. It is not the draft's
+// generator, which the draft's Ya and Yb imply is a different element; see parity.json.
+func TestParity_DraftGenerator(t *testing.T) {
+	p, err := newParams(Config{
+		Suite: Ristretto255Sha512,
+		CI:    mustHex("0a41696e69746961746f720a42726573706f6e646572"),
+		SID:   mustHex("7e4b4791d6a8ef019b936c79fb7f2c57"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(p.generatorString([]byte("Password"))); n != 168 {
+		t.Fatalf("generator string length %d want 168", n)
+	}
+	g, err := p.generator([]byte("Password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(encode(g)); got != "5e25411ca1ad7c9debfd0b33ad987a95cefef2d3f15dcc8bd26415a5dfe2e15a" {
+		t.Fatalf("generator %s", got)
+	}
+}
+
+func TestParity_PrependLen(t *testing.T) {
+	for _, tc := range []struct {
+		n      int
+		prefix string
+	}{{0, "00"}, {1, "01"}, {127, "7f"}, {128, "8001"}, {300, "ac02"}} {
+		got := prependLen(make([]byte, tc.n))
+		if hex.EncodeToString(got[:len(got)-tc.n]) != tc.prefix {
+			t.Fatalf("len %d: prefix %x want %s", tc.n, got[:len(got)-tc.n], tc.prefix)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package group
+
+import (
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// backends is every Group implementation; each conformance test runs against all of them.
+var backends = []Group{P256, P384, Secp256k1, Ristretto255}
+
+func backendByName(t *testing.T, name string) Group {
+	t.Helper()
+	for _, g := range backends {
+		if g.Name() == name {
+			return g
+		}
+	}
+	t.Fatalf("no backend named %q", name)
+	return nil
+}
+
+func mustMarshal(t *testing.T, v encoding.BinaryMarshaler) string {
+	t.Helper()
+	b, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func mustRandom(t *testing.T, g Group) Scalar {
+	t.Helper()
+	s, err := g.RandomScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func forEachGroup(t *testing.T, f func(t *testing.T, g Group)) {
+	for _, g := range backends {
+		t.Run(g.Name(), func(t *testing.T) { f(t, g) })
+	}
+}
+
+func TestConformance_ElementLaws(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		a := g.Generator().ScalarMult(mustRandom(t, g))
+		b := g.Generator().ScalarMult(mustRandom(t, g))
+		c := g.Generator().ScalarMult(mustRandom(t, g))
+		id := g.Identity()
+		if !id.IsIdentity() || a.IsIdentity() {
+			t.Fatal("IsIdentity")
+		}
+		if !a.Add(id).Equal(a) || !id.Add(a).Equal(a) {
+			t.Fatal("identity is not neutral")
+		}
+		if !a.Add(b).Equal(b.Add(a)) {
+			t.Fatal("addition is not commutative")
+		}
+		if !a.Add(b).Add(c).Equal(a.Add(b.Add(c))) {
+			t.Fatal("addition is not associative")
+		}
+		if !a.Add(a.Negate()).IsIdentity() || !a.Subtract(a).IsIdentity() {
+			t.Fatal("a - a is not the identity")
+		}
+		if !a.Add(a).Equal(a.ScalarMult(g.NewScalar(big.NewInt(2)))) {
+			t.Fatal("a + a != 2a")
+		}
+		if !id.Negate().IsIdentity() {
+			t.Fatal("-0 is not the identity")
+		}
+	})
+}
+
+func TestConformance_ScalarMult(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		x, y := mustRandom(t, g), mustRandom(t, g)
+		gen := g.Generator()
+		if !gen.ScalarMult(x.Add(y)).Equal(gen.ScalarMult(x).Add(gen.ScalarMult(y))) {
+			t.Fatal("(x+y)G != xG + yG")
+		}
+		if !gen.ScalarMult(x).ScalarMult(y).Equal(gen.ScalarMult(x.Multiply(y))) {
+			t.Fatal("y(xG) != (xy)G")
+		}
+		if !gen.ScalarMult(x.Negate()).Equal(gen.ScalarMult(x).Negate()) {
+			t.Fatal("(-x)G != -(xG)")
+		}
+		minusOne := g.NewScalar(new(big.Int).Sub(g.Order(), big.NewInt(1)))
+		if !gen.ScalarMult(minusOne).Add(gen).IsIdentity() {
+			t.Fatal("(n-1)G + G is not the identity")
+		}
+		if !gen.ScalarMult(g.NewScalar(g.Order())).IsIdentity() || !g.Identity().ScalarMult(x).IsIdentity() {
+			t.Fatal("multiplication by zero or of the identity")
+		}
+	})
+}
+
+func TestConformance_ScalarField(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		x, y := mustRandom(t, g), mustRandom(t, g)
+		if !x.Add(y).Subtract(y).Equal(x) {
+			t.Fatal("x + y - y != x")
+		}
+		if !x.Add(x.Negate()).IsZero() {
+			t.Fatal("x - x != 0")
+		}
+		inv, err := x.Invert()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !x.Multiply(inv).Equal(g.NewScalar(big.NewInt(1))) {
+			t.Fatal("x * x^-1 != 1")
+		}
+		if _, err := g.NewScalar(big.NewInt(0)).Invert(); !errors.Is(err, ErrZeroScalar) {
+			t.Fatalf("got %v want ErrZeroScalar", err)
+		}
+		if got := g.NewScalar(big.NewInt(-1)).BigInt(); got.Cmp(new(big.Int).Sub(g.Order(), big.NewInt(1))) != 0 {
+			t.Fatalf("NewScalar(-1) = %s", got)
+		}
+	})
+}
+
+func TestConformance_Encoding(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		x := mustRandom(t, g)
+		enc, _ := x.MarshalBinary()
+		if len(enc) != g.ScalarSize() {
+			t.Fatalf("scalar encoding is %d bytes, want %d", len(enc), g.ScalarSize())
+		}
+		dx, err := g.DecodeScalar(enc)
+		if err != nil || !dx.Equal(x) {
+			t.Fatalf("scalar round trip: %v", err)
+		}
+		e := g.Generator().ScalarMult(x)
+		enc, _ = e.MarshalBinary()
+		if len(enc) != g.ElementSize() {
+			t.Fatalf("element encoding is %d bytes, want %d", len(enc), g.ElementSize())
+		}
+		de, err := g.DecodeElement(enc)
+		if err != nil || !de.Equal(e) {
+			t.Fatalf("element round trip: %v", err)
+		}
+		id, _ := g.Identity().MarshalBinary()
+		if di, err := g.DecodeElement(id); err != nil || !di.IsIdentity() {
+			t.Fatalf("identity round trip: %v", err)
+		}
+
+		order := g.Order().Bytes()
+		if g == Ristretto255 {
+			reverse(order)
+		}
+		if _, err := g.DecodeScalar(order); !errors.Is(err, ErrInvalidScalar) {
+			t.Fatalf("order as scalar: got %v want ErrInvalidScalar", err)
+		}
+		if _, err := g.DecodeScalar(enc[:g.ScalarSize()-1]); !errors.Is(err, ErrInvalidScalar) {
+			t.Fatalf("short scalar: got %v want ErrInvalidScalar", err)
+		}
+		bad := make([]byte, g.ElementSize())
+		for i := range bad {
+			bad[i] = 0xff
+		}
+		if _, err := g.DecodeElement(bad); !errors.Is(err, ErrInvalidElement) {
+			t.Fatalf("0xff.. element: got %v want ErrInvalidElement", err)
+		}
+		if _, err := g.DecodeElement(enc[:len(enc)-1]); !errors.Is(err, ErrInvalidElement) {
+			t.Fatalf("short element: got %v want ErrInvalidElement", err)
+		}
+	})
+}
+
+func TestConformance_Uncompressed(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		e := g.Generator().ScalarMult(mustRandom(t, g))
+		enc, err := MarshalUncompressed(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g != Ristretto255 && (len(enc) != 2*g.ElementSize()-1 || enc[0] != 0x04) {
+			t.Fatalf("uncompressed encoding %x", enc)
+		}
+		de, err := DecodeUncompressed(g, enc)
+		if err != nil || !de.Equal(e) {
+			t.Fatalf("uncompressed round trip: %v", err)
+		}
+		if g == Ristretto255 {
+			return
+		}
+		enc[len(enc)-1] ^= 1
+		if _, err := DecodeUncompressed(g, enc); !errors.Is(err, ErrInvalidElement) {
+			t.Fatalf("off-curve point: got %v want ErrInvalidElement", err)
+		}
+		compressed, _ := e.MarshalBinary()
+		if _, err := DecodeUncompressed(g, compressed); !errors.Is(err, ErrInvalidElement) {
+			t.Fatalf("compressed point: got %v want ErrInvalidElement", err)
+		}
+	})
+}
+
+func TestConformance_Hashing(t *testing.T) {
+	forEachGroup(t, func(t *testing.T, g Group) {
+		a, err := g.HashToElement([]byte("msg"), []byte("dst-a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a2, _ := g.HashToElement([]byte("msg"), []byte("dst-a"))
+		b, _ := g.HashToElement([]byte("msg"), []byte("dst-b"))
+		if !a.Equal(a2) || a.Equal(b) || a.IsIdentity() {
+			t.Fatal("HashToElement is not a deterministic, domain-separated map")
+		}
+		s, err := g.HashToScalar([]byte("msg"), []byte("dst-a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s2, _ := g.HashToScalar([]byte("msg"), []byte("dst-b"))
+		if s.Equal(s2) || s.BigInt().Cmp(g.Order()) >= 0 {
+			t.Fatal("HashToScalar is not a domain-separated map into [0, order)")
+		}
+	})
+}
+
+func TestConformance_GroupMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrGroupMismatch {
+			t.Fatalf("got panic %v want ErrGroupMismatch", r)
+		}
+	}()
+	P256.Generator().Add(Ristretto255.Generator())
+}
@@ -0,0 +1,169 @@
+package group
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// scalarExtraBytes is how many bytes beyond the order's length are read per random scalar,
+// so reducing modulo the order has a bias below 2^-128.
+const scalarExtraBytes = 16
+
+var ErrGroupMismatch = errors.New("group: operands belong to different groups")
+
+// Group is a prime-order group, so protocol code can be written once for every backend:
+// P256, P384, Secp256k1 and Ristretto255. Elements and scalars are immutable values; mixing
+// values of different groups panics with ErrGroupMismatch.
+type Group interface {
+	// Name is the group's name, such as "P-256" or "ristretto255".
+	Name() string
+	// Order returns the prime order of the group.
+	Order() *big.Int
+	Identity() Element
+	Generator() Element
+	// NewScalar returns v reduced modulo the order.
+	NewScalar(v *big.Int) Scalar
+	// RandomScalar returns a uniformly random scalar from crypto/rand.
+	RandomScalar() (Scalar, error)
+	// HashToElement hashes msg to an element under the domain separation tag dst with the
+	// group's RFC 9380 random-oracle encoding.
+	HashToElement(msg []byte, dst []byte) (Element, error)
+	// HashToScalar hashes msg to a scalar under dst, as HashToScalar in RFC 9497.
+	HashToScalar(msg []byte, dst []byte) (Scalar, error)
+	// DecodeElement parses the canonical encoding written by Element.MarshalBinary.
+	DecodeElement(b []byte) (Element, error)
+	// DecodeScalar parses the canonical encoding written by Scalar.MarshalBinary.
+	DecodeScalar(b []byte) (Scalar, error)
+	// ElementSize is the length of an encoded non-identity element.
+	ElementSize() int
+	// ScalarSize is the length of an encoded scalar.
+	ScalarSize() int
+}
+
+// Element is a group element.
+type Element interface {
+	Group() Group
+	Add(o Element) Element
+	Subtract(o Element) Element
+	Negate() Element
+	ScalarMult(s Scalar) Element
+	Equal(o Element) bool
+	IsIdentity() bool
+	MarshalBinary() ([]byte, error)
+}
+
+// Scalar is an integer modulo the group order.
+type Scalar interface {
+	Group() Group
+	Add(o Scalar) Scalar
+	Subtract(o Scalar) Scalar
+	Multiply(o Scalar) Scalar
+	Negate() Scalar
+	// Invert returns the multiplicative inverse, or ErrZeroScalar for zero.
+	Invert() (Scalar, error)
+	Equal(o Scalar) bool
+	IsZero() bool
+	// BigInt returns the scalar as an integer in [0, order).
+	BigInt() *big.Int
+	MarshalBinary() ([]byte, error)
+}
+
+// scalarField is the scalar arithmetic shared by every backend: integers modulo the order,
+// encoded as fixed-width big-endian bytes, or little-endian for ristretto255.
+type scalarField struct {
+	g            Group
+	order        *big.Int
+	size         int
+	littleEndian bool
+}
+
+func (f *scalarField) newScalar(v *big.Int) *scalar {
+	return &scalar{f: f, v: util.BigModPos(v, f.order)}
+}
+
+func (f *scalarField) random() (Scalar, error) {
+	buf := make([]byte, f.size+scalarExtraBytes)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return nil, err
+	}
+	return f.newScalar(util.BytesToBigInt(buf)), nil
+}
+
+func (f *scalarField) decode(b []byte) (Scalar, error) {
+	if len(b) != f.size {
+		return nil, ErrInvalidScalar
+	}
+	var v *big.Int
+	if f.littleEndian {
+		v = fromLittleEndian(b)
+	} else {
+		v = util.BytesToBigInt(b)
+	}
+	if util.BigCmp(v, f.order) >= 0 {
+		return nil, ErrInvalidScalar
+	}
+	return &scalar{f: f, v: v}, nil
+}
+
+type scalar struct {
+	f *scalarField
+	v *big.Int
+}
+
+func (s *scalar) other(o Scalar) *scalar {
+	so, ok := o.(*scalar)
+	if !ok || so.f != s.f {
+		panic(ErrGroupMismatch)
+	}
+	return so
+}
+
+func (s *scalar) Group() Group { return s.f.g }
+
+func (s *scalar) Add(o Scalar) Scalar {
+	return s.f.newScalar(new(big.Int).Add(s.v, s.other(o).v))
+}
+
+func (s *scalar) Subtract(o Scalar) Scalar {
+	return s.f.newScalar(new(big.Int).Sub(s.v, s.other(o).v))
+}
+
+func (s *scalar) Multiply(o Scalar) Scalar {
+	return s.f.newScalar(new(big.Int).Mul(s.v, s.other(o).v))
+}
+
+func (s *scalar) Negate() Scalar {
+	return s.f.newScalar(new(big.Int).Neg(s.v))
+}
+
+func (s *scalar) Invert() (Scalar, error) {
+	if s.v.Sign() == 0 {
+		return nil, ErrZeroScalar
+	}
+	return &scalar{f: s.f, v: new(big.Int).ModInverse(s.v, s.f.order)}, nil
+}
+
+func (s *scalar) Equal(o Scalar) bool {
+	return util.BigCmp(s.v, s.other(o).v) == 0
+}
+
+func (s *scalar) IsZero() bool {
+	return s.v.Sign() == 0
+}
+
+func (s *scalar) BigInt() *big.Int {
+	return new(big.Int).Set(s.v)
+}
+
+func (s *scalar) MarshalBinary() ([]byte, error) {
+	out := make([]byte, s.f.size)
+	s.v.FillBytes(out)
+	if s.f.littleEndian {
+		reverse(out)
+	}
+	return out, nil
+}
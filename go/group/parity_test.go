@@ -0,0 +1,230 @@
+package group
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Group struct {
+		Backends []struct {
+			Group         string
+			Generator     string
+			Identity      string
+			HashToElement []struct {
+				Dst, Msg, Scalar, Product string
+			}
+			HashToScalar []struct {
+				Dst, Msg, Scalar string
+			}
+		}
+		Ristretto255 struct {
+			Multiples []struct {
+				K       int64
+				Hex     string
+				UrlSafe string
+			}
+			BadEncodings     []string
+			FromUniformBytes []struct {
+				Label   string
+				Uniform string
+				Element string
+			}
+			HashToElement []struct {
+				Dst, Msg, Scalar, Product string
+			}
+			HashToScalar []struct {
+				Dst, Msg, Scalar string
+			}
+			ScalarOps []struct {
+				Op, A, B, Out string
+			}
+			ScalarFromUniformBytes []struct {
+				Uniform, Scalar string
+			}
+			BadScalars []string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustScalar(t *testing.T, s string) *RistrettoScalar {
+	t.Helper()
+	v, err := DecodeRistrettoScalar(mustHex(s))
+	if err != nil {
+		t.Fatalf("scalar %s: %v", s, err)
+	}
+	return v
+}
+
+func TestParity_RistrettoMultiples(t *testing.T) {
+	v := loadVectors(t).Group.Ristretto255
+	acc := RistrettoIdentity()
+	for _, tc := range v.Multiples {
+		if got := hex.EncodeToString(acc.Bytes()); got != tc.Hex {
+			t.Fatalf("%d*G by addition: got %s want %s", tc.K, got, tc.Hex)
+		}
+		if got := RistrettoBaseMult(NewRistrettoScalar(big.NewInt(tc.K))).String(); got != tc.UrlSafe {
+			t.Fatalf("%d*G: got %s want %s", tc.K, got, tc.UrlSafe)
+		}
+		e, err := ParseRistrettoElement(tc.UrlSafe)
+		if err != nil || !e.Equal(acc) {
+			t.Fatalf("%d*G parse: %v", tc.K, err)
+		}
+		acc = acc.Add(RistrettoGenerator())
+	}
+}
+
+func TestParity_RistrettoBadEncodings(t *testing.T) {
+	v := loadVectors(t).Group.Ristretto255
+	for _, enc := range v.BadEncodings {
+		if _, err := DecodeRistrettoElement(mustHex(enc)); !errors.Is(err, ErrInvalidElement) {
+			t.Fatalf("%s: got %v want ErrInvalidElement", enc, err)
+		}
+	}
+}
+
+func TestParity_RistrettoHashing(t *testing.T) {
+	v := loadVectors(t).Group.Ristretto255
+	for _, tc := range v.FromUniformBytes {
+		e, err := RistrettoElementFromUniformBytes(mustHex(tc.Uniform))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(e.Bytes()); got != tc.Element {
+			t.Fatalf("%q: got %s want %s", tc.Label, got, tc.Element)
+		}
+	}
+	for _, tc := range v.HashToElement {
+		e, err := HashToElement(mustHex(tc.Msg), mustHex(tc.Dst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(e.ScalarMult(mustScalar(t, tc.Scalar)).Bytes()); got != tc.Product {
+			t.Fatalf("msg %s: got %s want %s", tc.Msg, got, tc.Product)
+		}
+	}
+	for _, tc := range v.HashToScalar {
+		s, err := HashToScalar(mustHex(tc.Msg), mustHex(tc.Dst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(s.Bytes()); got != tc.Scalar {
+			t.Fatalf("msg %s: got %s want %s", tc.Msg, got, tc.Scalar)
+		}
+	}
+}
+
+func TestParity_RistrettoScalars(t *testing.T) {
+	v := loadVectors(t).Group.Ristretto255
+	for _, tc := range v.ScalarOps {
+		a := mustScalar(t, tc.A)
+		var got *RistrettoScalar
+		var err error
+		switch tc.Op {
+		case "add":
+			got = a.Add(mustScalar(t, tc.B))
+		case "subtract":
+			got = a.Subtract(mustScalar(t, tc.B))
+		case "multiply":
+			got = a.Multiply(mustScalar(t, tc.B))
+		case "negate":
+			got = a.Negate()
+		case "invert":
+			got, err = a.Invert()
+		default:
+			t.Fatalf("unknown op %q", tc.Op)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Op, err)
+		}
+		if hex.EncodeToString(got.Bytes()) != tc.Out {
+			t.Fatalf("%s %s %s: got %x want %s", tc.Op, tc.A, tc.B, got.Bytes(), tc.Out)
+		}
+	}
+	for _, tc := range v.ScalarFromUniformBytes {
+		s, err := RistrettoScalarFromUniformBytes(mustHex(tc.Uniform))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(s.Bytes()); got != tc.Scalar {
+			t.Fatalf("%s: got %s want %s", tc.Uniform, got, tc.Scalar)
+		}
+	}
+	for _, enc := range v.BadScalars {
+		if _, err := DecodeRistrettoScalar(mustHex(enc)); !errors.Is(err, ErrInvalidScalar) {
+			t.Fatalf("%s: got %v want ErrInvalidScalar", enc, err)
+		}
+	}
+}
+
+func TestParity_Backends(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Group.Backends {
+		g := backendByName(t, tc.Group)
+		for _, want := range []struct {
+			name string
+			e    Element
+			enc  string
+		}{{"generator", g.Generator(), tc.Generator}, {"identity", g.Identity(), tc.Identity}} {
+			if got := mustMarshal(t, want.e); got != want.enc {
+				t.Fatalf("%s %s: got %s want %s", tc.Group, want.name, got, want.enc)
+			}
+			if e, err := g.DecodeElement(mustHex(want.enc)); err != nil || !e.Equal(want.e) {
+				t.Fatalf("%s %s decode: %v", tc.Group, want.name, err)
+			}
+		}
+		for _, h := range tc.HashToElement {
+			e, err := g.HashToElement(mustHex(h.Msg), mustHex(h.Dst))
+			if err != nil {
+				t.Fatal(err)
+			}
+			k, err := g.DecodeScalar(mustHex(h.Scalar))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mustMarshal(t, e.ScalarMult(k)); got != h.Product {
+				t.Fatalf("%s msg %s: got %s want %s", tc.Group, h.Msg, got, h.Product)
+			}
+		}
+		for _, h := range tc.HashToScalar {
+			s, err := g.HashToScalar(mustHex(h.Msg), mustHex(h.Dst))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mustMarshal(t, s); got != h.Scalar {
+				t.Fatalf("%s msg %s: got %s want %s", tc.Group, h.Msg, got, h.Scalar)
+			}
+		}
+	}
+}
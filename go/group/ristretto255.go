@@ -0,0 +1,263 @@
+// Package group provides prime-order groups for zero-knowledge proofs and PAKEs. The Group,
+// Element and Scalar interfaces let protocol code run unchanged over the P256, P384,
+// Secp256k1 and Ristretto255 backends. ristretto255 (RFC 9496) is also exposed directly:
+// elements and scalars modulo the group order ℓ are immutable values, encoded as 32 bytes
+// and as text with util.EncUrlSafe.
+package group
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/ristretto255"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+const (
+	// ElementSize is the length of an encoded ristretto255 element.
+	ElementSize = ristretto255.EncodedSize
+	// ScalarSize is the length of an encoded ristretto255 scalar.
+	ScalarSize = 32
+	// UniformSize is the input length of the one-way maps to elements and scalars.
+	UniformSize = 64
+)
+
+var (
+	ErrInvalidElement = errors.New("group: invalid element encoding")
+	ErrInvalidScalar  = errors.New("group: invalid scalar encoding")
+	ErrZeroScalar     = errors.New("group: zero scalar has no inverse")
+)
+
+var ristrettoOrder = ristretto255.Order()
+
+// RistrettoOrder returns ℓ = 2^252 + 27742317777372353535851937790883648493.
+func RistrettoOrder() *big.Int {
+	return new(big.Int).Set(ristrettoOrder)
+}
+
+// RistrettoElement is a ristretto255 group element.
+type RistrettoElement struct {
+	e *ristretto255.Element
+}
+
+// RistrettoIdentity returns the identity element.
+func RistrettoIdentity() *RistrettoElement {
+	return &RistrettoElement{ristretto255.Identity()}
+}
+
+// RistrettoGenerator returns the canonical generator.
+func RistrettoGenerator() *RistrettoElement {
+	return &RistrettoElement{ristretto255.Generator()}
+}
+
+// RistrettoBaseMult returns s*G for the generator G.
+func RistrettoBaseMult(s *RistrettoScalar) *RistrettoElement {
+	return &RistrettoElement{ristretto255.ScalarBaseMult(s.v)}
+}
+
+// DecodeRistrettoElement parses a canonical 32-byte encoding.
+func DecodeRistrettoElement(b []byte) (*RistrettoElement, error) {
+	e, err := ristretto255.Decode(b)
+	if err != nil {
+		return nil, ErrInvalidElement
+	}
+	return &RistrettoElement{e}, nil
+}
+
+// ParseRistrettoElement parses the util.EncUrlSafe text written by RistrettoElement.String.
+func ParseRistrettoElement(s string) (*RistrettoElement, error) {
+	b, err := util.DecUrlSafe(s)
+	if err != nil {
+		return nil, ErrInvalidElement
+	}
+	return DecodeRistrettoElement(b)
+}
+
+// HashToElement is hash_to_ristretto255 (RFC 9380, appendix B): 64 bytes from
+// util.ExpandMessageXmd over SHA-512 under dst, mapped with the RFC 9496 one-way map.
+func HashToElement(msg []byte, dst []byte) (*RistrettoElement, error) {
+	uniform, err := util.ExpandMessageXmd(msg, dst, UniformSize, 512)
+	if err != nil {
+		return nil, err
+	}
+	return RistrettoElementFromUniformBytes(uniform)
+}
+
+// RistrettoElementFromUniformBytes maps 64 uniformly random bytes to an element with no
+// known discrete logarithm (RFC 9496, section 4.3.4).
+func RistrettoElementFromUniformBytes(b []byte) (*RistrettoElement, error) {
+	e, err := ristretto255.FromUniformBytes(b)
+	if err != nil {
+		return nil, ErrInvalidElement
+	}
+	return &RistrettoElement{e}, nil
+}
+
+// Add returns e + o.
+func (e *RistrettoElement) Add(o *RistrettoElement) *RistrettoElement {
+	return &RistrettoElement{e.e.Add(o.e)}
+}
+
+// Subtract returns e - o.
+func (e *RistrettoElement) Subtract(o *RistrettoElement) *RistrettoElement {
+	return &RistrettoElement{e.e.Add(o.e.Negate())}
+}
+
+// Negate returns -e.
+func (e *RistrettoElement) Negate() *RistrettoElement {
+	return &RistrettoElement{e.e.Negate()}
+}
+
+// ScalarMult returns s*e.
+func (e *RistrettoElement) ScalarMult(s *RistrettoScalar) *RistrettoElement {
+	return &RistrettoElement{e.e.ScalarMult(s.v)}
+}
+
+// Equal reports whether e and o are the same element.
+func (e *RistrettoElement) Equal(o *RistrettoElement) bool {
+	return e.e.Equal(o.e)
+}
+
+// IsIdentity reports whether e is the identity element.
+func (e *RistrettoElement) IsIdentity() bool {
+	return e.e.Equal(ristretto255.Identity())
+}
+
+// Bytes returns the canonical 32-byte encoding.
+func (e *RistrettoElement) Bytes() []byte {
+	return e.e.Bytes()
+}
+
+// String returns the encoding as util.EncUrlSafe text.
+func (e *RistrettoElement) String() string {
+	return util.EncUrlSafe(e.Bytes())
+}
+
+// RistrettoScalar is an integer modulo ℓ.
+type RistrettoScalar struct {
+	v *big.Int
+}
+
+// NewRistrettoScalar returns v reduced modulo ℓ.
+func NewRistrettoScalar(v *big.Int) *RistrettoScalar {
+	return &RistrettoScalar{util.BigModPos(v, ristrettoOrder)}
+}
+
+// RandomRistrettoScalar returns a uniformly random scalar from crypto/rand.
+func RandomRistrettoScalar() (*RistrettoScalar, error) {
+	b := make([]byte, UniformSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return RistrettoScalarFromUniformBytes(b)
+}
+
+// DecodeRistrettoScalar parses a canonical 32-byte little-endian encoding, rejecting values
+// of ℓ or more.
+func DecodeRistrettoScalar(b []byte) (*RistrettoScalar, error) {
+	if len(b) != ScalarSize {
+		return nil, ErrInvalidScalar
+	}
+	v := fromLittleEndian(b)
+	if util.BigCmp(v, ristrettoOrder) >= 0 {
+		return nil, ErrInvalidScalar
+	}
+	return &RistrettoScalar{v}, nil
+}
+
+// ParseRistrettoScalar parses the util.EncUrlSafe text written by RistrettoScalar.String.
+func ParseRistrettoScalar(s string) (*RistrettoScalar, error) {
+	b, err := util.DecUrlSafe(s)
+	if err != nil {
+		return nil, ErrInvalidScalar
+	}
+	return DecodeRistrettoScalar(b)
+}
+
+// RistrettoScalarFromUniformBytes reduces 64 little-endian bytes modulo ℓ.
+func RistrettoScalarFromUniformBytes(b []byte) (*RistrettoScalar, error) {
+	if len(b) != UniformSize {
+		return nil, ErrInvalidScalar
+	}
+	return NewRistrettoScalar(fromLittleEndian(b)), nil
+}
+
+// HashToScalar reduces 64 bytes from util.ExpandMessageXmd over SHA-512 under dst, read
+// little-endian, modulo ℓ; this is the ristretto255 HashToScalar of RFC 9497.
+func HashToScalar(msg []byte, dst []byte) (*RistrettoScalar, error) {
+	uniform, err := util.ExpandMessageXmd(msg, dst, UniformSize, 512)
+	if err != nil {
+		return nil, err
+	}
+	return RistrettoScalarFromUniformBytes(uniform)
+}
+
+// Add returns s + o mod ℓ.
+func (s *RistrettoScalar) Add(o *RistrettoScalar) *RistrettoScalar {
+	return NewRistrettoScalar(new(big.Int).Add(s.v, o.v))
+}
+
+// Subtract returns s - o mod ℓ.
+func (s *RistrettoScalar) Subtract(o *RistrettoScalar) *RistrettoScalar {
+	return NewRistrettoScalar(new(big.Int).Sub(s.v, o.v))
+}
+
+// Multiply returns s * o mod ℓ.
+func (s *RistrettoScalar) Multiply(o *RistrettoScalar) *RistrettoScalar {
+	return NewRistrettoScalar(new(big.Int).Mul(s.v, o.v))
+}
+
+// Negate returns -s mod ℓ.
+func (s *RistrettoScalar) Negate() *RistrettoScalar {
+	return NewRistrettoScalar(new(big.Int).Neg(s.v))
+}
+
+// Invert returns s^-1 mod ℓ, or ErrZeroScalar for zero.
+func (s *RistrettoScalar) Invert() (*RistrettoScalar, error) {
+	if s.v.Sign() == 0 {
+		return nil, ErrZeroScalar
+	}
+	return &RistrettoScalar{new(big.Int).ModInverse(s.v, ristrettoOrder)}, nil
+}
+
+// Equal reports whether s and o are the same scalar.
+func (s *RistrettoScalar) Equal(o *RistrettoScalar) bool {
+	return util.BigCmp(s.v, o.v) == 0
+}
+
+// IsZero reports whether s is zero.
+func (s *RistrettoScalar) IsZero() bool {
+	return s.v.Sign() == 0
+}
+
+// BigInt returns the scalar as an integer in [0, ℓ).
+func (s *RistrettoScalar) BigInt() *big.Int {
+	return new(big.Int).Set(s.v)
+}
+
+// Bytes returns the canonical 32-byte little-endian encoding.
+func (s *RistrettoScalar) Bytes() []byte {
+	out := make([]byte, ScalarSize)
+	s.v.FillBytes(out)
+	reverse(out)
+	return out
+}
+
+// String returns the encoding as util.EncUrlSafe text.
+func (s *RistrettoScalar) String() string {
+	return util.EncUrlSafe(s.Bytes())
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// fromLittleEndian reads b as a little-endian integer.
+func fromLittleEndian(b []byte) *big.Int {
+	be := append([]byte(nil), b...)
+	reverse(be)
+	return util.BytesToBigInt(be)
+}
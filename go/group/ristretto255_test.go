@@ -0,0 +1,83 @@
+package group
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestRistretto_ScalarMultDistributes(t *testing.T) {
+	a, err := RandomRistrettoScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := RandomRistrettoScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lhs := RistrettoBaseMult(a.Add(b))
+	rhs := RistrettoBaseMult(a).Add(RistrettoBaseMult(b))
+	if !lhs.Equal(rhs) {
+		t.Fatal("(a+b)G != aG + bG")
+	}
+	p := RistrettoBaseMult(a)
+	if !p.ScalarMult(b).Equal(RistrettoBaseMult(a.Multiply(b))) {
+		t.Fatal("b(aG) != (ab)G")
+	}
+	if !p.Subtract(p).IsIdentity() || !p.Add(p.Negate()).IsIdentity() {
+		t.Fatal("P - P is not the identity")
+	}
+}
+
+func TestRistretto_ScalarInverse(t *testing.T) {
+	a, err := RandomRistrettoScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, err := a.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Multiply(inv).Equal(NewRistrettoScalar(big.NewInt(1))) {
+		t.Fatal("a * a^-1 != 1")
+	}
+	if _, err := NewRistrettoScalar(RistrettoOrder()).Invert(); !errors.Is(err, ErrZeroScalar) {
+		t.Fatalf("got %v want ErrZeroScalar", err)
+	}
+}
+
+func TestRistretto_TextRoundTrip(t *testing.T) {
+	s, err := RandomRistrettoScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseRistrettoScalar(s.String())
+	if err != nil || !parsed.Equal(s) {
+		t.Fatalf("scalar round trip: %v", err)
+	}
+	e := RistrettoBaseMult(s)
+	pe, err := ParseRistrettoElement(e.String())
+	if err != nil || !pe.Equal(e) {
+		t.Fatalf("element round trip: %v", err)
+	}
+	if _, err := ParseRistrettoElement("not base64!"); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("got %v want ErrInvalidElement", err)
+	}
+	if _, err := ParseRistrettoScalar("AAAA"); !errors.Is(err, ErrInvalidScalar) {
+		t.Fatalf("got %v want ErrInvalidScalar", err)
+	}
+}
+
+func TestRistretto_HashDomainSeparation(t *testing.T) {
+	a, err := HashToElement([]byte("msg"), []byte("dst-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := HashToElement([]byte("msg"), []byte("dst-b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Equal(b) {
+		t.Fatal("different DSTs gave the same element")
+	}
+}
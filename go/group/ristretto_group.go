@@ -0,0 +1,106 @@
+package group
+
+import "math/big"
+
+// ristrettoGroup adapts the RistrettoElement API to the generic Group interface.
+type ristrettoGroup struct {
+	scalars *scalarField
+}
+
+// Ristretto255 is the ristretto255 group (RFC 9496), hashing with hash_to_ristretto255.
+var Ristretto255 Group = newRistrettoGroup()
+
+func newRistrettoGroup() *ristrettoGroup {
+	g := &ristrettoGroup{}
+	g.scalars = &scalarField{g: g, order: ristrettoOrder, size: ScalarSize, littleEndian: true}
+	return g
+}
+
+func (g *ristrettoGroup) Name() string      { return "ristretto255" }
+func (g *ristrettoGroup) Order() *big.Int   { return RistrettoOrder() }
+func (g *ristrettoGroup) ElementSize() int  { return ElementSize }
+func (g *ristrettoGroup) ScalarSize() int   { return ScalarSize }
+func (g *ristrettoGroup) Identity() Element { return g.element(RistrettoIdentity()) }
+
+func (g *ristrettoGroup) Generator() Element            { return g.element(RistrettoGenerator()) }
+func (g *ristrettoGroup) NewScalar(v *big.Int) Scalar   { return g.scalars.newScalar(v) }
+func (g *ristrettoGroup) RandomScalar() (Scalar, error) { return g.scalars.random() }
+
+func (g *ristrettoGroup) DecodeScalar(b []byte) (Scalar, error) {
+	return g.scalars.decode(b)
+}
+
+func (g *ristrettoGroup) DecodeElement(b []byte) (Element, error) {
+	e, err := DecodeRistrettoElement(b)
+	if err != nil {
+		return nil, err
+	}
+	return g.element(e), nil
+}
+
+func (g *ristrettoGroup) HashToElement(msg []byte, dst []byte) (Element, error) {
+	e, err := HashToElement(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.element(e), nil
+}
+
+func (g *ristrettoGroup) HashToScalar(msg []byte, dst []byte) (Scalar, error) {
+	s, err := HashToScalar(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.scalars.newScalar(s.v), nil
+}
+
+func (g *ristrettoGroup) element(e *RistrettoElement) *ristrettoElement {
+	return &ristrettoElement{g: g, e: e}
+}
+
+type ristrettoElement struct {
+	g *ristrettoGroup
+	e *RistrettoElement
+}
+
+func (e *ristrettoElement) other(o Element) *ristrettoElement {
+	eo, ok := o.(*ristrettoElement)
+	if !ok || eo.g != e.g {
+		panic(ErrGroupMismatch)
+	}
+	return eo
+}
+
+func (e *ristrettoElement) Group() Group { return e.g }
+
+func (e *ristrettoElement) Add(o Element) Element {
+	return e.g.element(e.e.Add(e.other(o).e))
+}
+
+func (e *ristrettoElement) Subtract(o Element) Element {
+	return e.g.element(e.e.Subtract(e.other(o).e))
+}
+
+func (e *ristrettoElement) Negate() Element {
+	return e.g.element(e.e.Negate())
+}
+
+func (e *ristrettoElement) ScalarMult(s Scalar) Element {
+	k, ok := s.(*scalar)
+	if !ok || k.f != e.g.scalars {
+		panic(ErrGroupMismatch)
+	}
+	return e.g.element(&RistrettoElement{e.e.e.ScalarMult(k.v)})
+}
+
+func (e *ristrettoElement) Equal(o Element) bool {
+	return e.e.Equal(e.other(o).e)
+}
+
+func (e *ristrettoElement) IsIdentity() bool {
+	return e.e.IsIdentity()
+}
+
+func (e *ristrettoElement) MarshalBinary() ([]byte, error) {
+	return e.e.Bytes(), nil
+}
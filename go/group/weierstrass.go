@@ -0,0 +1,209 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/h2c"
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// weierstrass is a prime-order short Weierstrass curve. Elements are encoded as compressed
+// SEC1 points, and the identity as the single byte 0x00.
+type weierstrass struct {
+	name       string
+	curve      elliptic.Curve
+	suite      h2c.Suite
+	hashBits   int
+	secBits    int
+	decompress func(b []byte) (x, y *big.Int, ok bool)
+	scalars    *scalarField
+}
+
+func newWeierstrass(name string, c elliptic.Curve, suite h2c.Suite, hashBits, secBits int, decompress func([]byte) (*big.Int, *big.Int, bool)) *weierstrass {
+	w := &weierstrass{name: name, curve: c, suite: suite, hashBits: hashBits, secBits: secBits, decompress: decompress}
+	n := c.Params().N
+	w.scalars = &scalarField{g: w, order: n, size: (n.BitLen() + 7) / 8}
+	return w
+}
+
+var (
+	// P256 is NIST P-256 with hash_to_curve suite P256_XMD:SHA-256_SSWU_RO_.
+	P256 Group = newWeierstrass("P-256", elliptic.P256(), h2c.P256RO, 256, 128, nistDecompress(elliptic.P256()))
+	// P384 is NIST P-384 with hash_to_curve suite P384_XMD:SHA-384_SSWU_RO_.
+	P384 Group = newWeierstrass("P-384", elliptic.P384(), h2c.P384RO, 384, 192, nistDecompress(elliptic.P384()))
+	// Secp256k1 is secp256k1 with hash_to_curve suite secp256k1_XMD:SHA-256_SSWU_RO_.
+	Secp256k1 Group = newWeierstrass("secp256k1", secp256k1.S256(), h2c.Secp256k1RO, 256, 128, secp256k1Decompress)
+)
+
+func nistDecompress(c elliptic.Curve) func([]byte) (*big.Int, *big.Int, bool) {
+	return func(b []byte) (*big.Int, *big.Int, bool) {
+		x, y := elliptic.UnmarshalCompressed(c, b)
+		return x, y, x != nil
+	}
+}
+
+func secp256k1Decompress(b []byte) (*big.Int, *big.Int, bool) {
+	if len(b) != 33 || (b[0] != 0x02 && b[0] != 0x03) {
+		return nil, nil, false
+	}
+	x, y, err := secp256k1.Unmarshal(b)
+	return x, y, err == nil
+}
+
+func (w *weierstrass) Name() string      { return w.name }
+func (w *weierstrass) Order() *big.Int   { return new(big.Int).Set(w.scalars.order) }
+func (w *weierstrass) ElementSize() int  { return 1 + (w.curve.Params().BitSize+7)/8 }
+func (w *weierstrass) ScalarSize() int   { return w.scalars.size }
+func (w *weierstrass) Identity() Element { return w.point(new(big.Int), new(big.Int)) }
+
+func (w *weierstrass) Generator() Element {
+	params := w.curve.Params()
+	return w.point(params.Gx, params.Gy)
+}
+
+func (w *weierstrass) NewScalar(v *big.Int) Scalar   { return w.scalars.newScalar(v) }
+func (w *weierstrass) RandomScalar() (Scalar, error) { return w.scalars.random() }
+
+func (w *weierstrass) DecodeScalar(b []byte) (Scalar, error) {
+	return w.scalars.decode(b)
+}
+
+func (w *weierstrass) HashToElement(msg []byte, dst []byte) (Element, error) {
+	x, y, err := h2c.HashToCurve(w.suite, msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return w.point(x, y), nil
+}
+
+// HashToScalar is hash_to_field over the order with the suite's expand_message_xmd and
+// security level, as RFC 9497 specifies for the NIST curves.
+func (w *weierstrass) HashToScalar(msg []byte, dst []byte) (Scalar, error) {
+	u, err := util.HashToField(msg, dst, 1, w.scalars.order, w.secBits, util.XmdExpander(w.hashBits))
+	if err != nil {
+		return nil, err
+	}
+	return w.scalars.newScalar(u[0]), nil
+}
+
+func (w *weierstrass) DecodeElement(b []byte) (Element, error) {
+	if len(b) == 1 && b[0] == 0x00 {
+		return w.Identity(), nil
+	}
+	x, y, ok := w.decompress(b)
+	if !ok {
+		return nil, ErrInvalidElement
+	}
+	return w.point(x, y), nil
+}
+
+func (w *weierstrass) point(x, y *big.Int) *point {
+	return &point{w: w, x: x, y: y}
+}
+
+// point is an affine point; the identity is (0, 0) as in crypto/elliptic.
+type point struct {
+	w    *weierstrass
+	x, y *big.Int
+}
+
+func (p *point) other(o Element) *point {
+	po, ok := o.(*point)
+	if !ok || po.w != p.w {
+		panic(ErrGroupMismatch)
+	}
+	return po
+}
+
+func (p *point) Group() Group { return p.w }
+
+func (p *point) IsIdentity() bool {
+	return p.x.Sign() == 0 && p.y.Sign() == 0
+}
+
+func (p *point) Add(o Element) Element {
+	q := p.other(o)
+	switch {
+	case p.IsIdentity():
+		return q
+	case q.IsIdentity():
+		return p
+	}
+	return p.w.point(p.w.curve.Add(p.x, p.y, q.x, q.y))
+}
+
+func (p *point) Subtract(o Element) Element {
+	return p.Add(p.other(o).Negate())
+}
+
+func (p *point) Negate() Element {
+	if p.IsIdentity() {
+		return p
+	}
+	return p.w.point(p.x, util.BigModPos(new(big.Int).Neg(p.y), p.w.curve.Params().P))
+}
+
+func (p *point) ScalarMult(s Scalar) Element {
+	k, ok := s.(*scalar)
+	if !ok || k.f != p.w.scalars {
+		panic(ErrGroupMismatch)
+	}
+	if p.IsIdentity() || k.IsZero() {
+		return p.w.Identity()
+	}
+	return p.w.point(p.w.curve.ScalarMult(p.x, p.y, k.v.Bytes()))
+}
+
+func (p *point) Equal(o Element) bool {
+	q := p.other(o)
+	return util.BigCmp(p.x, q.x) == 0 && util.BigCmp(p.y, q.y) == 0
+}
+
+func (p *point) MarshalBinary() ([]byte, error) {
+	if p.IsIdentity() {
+		return []byte{0x00}, nil
+	}
+	size := p.w.ElementSize()
+	out := make([]byte, size)
+	out[0] = 0x02 | byte(p.y.Bit(0))
+	p.x.FillBytes(out[1:])
+	return out, nil
+}
+
+// MarshalUncompressed returns the uncompressed SEC1 encoding 0x04 || x || y of an element
+// of a Weierstrass group, which protocols such as SPAKE2 (RFC 9382) hash and send. The
+// identity and ristretto255 elements keep their MarshalBinary encoding.
+func MarshalUncompressed(e Element) ([]byte, error) {
+	p, ok := e.(*point)
+	if !ok || p.IsIdentity() {
+		return e.MarshalBinary()
+	}
+	size := (p.w.curve.Params().BitSize + 7) / 8
+	out := make([]byte, 1+2*size)
+	out[0] = 0x04
+	p.x.FillBytes(out[1 : 1+size])
+	p.y.FillBytes(out[1+size:])
+	return out, nil
+}
+
+// DecodeUncompressed parses the encoding written by MarshalUncompressed for g: an
+// uncompressed point on the curve for a Weierstrass group, or g.DecodeElement otherwise.
+func DecodeUncompressed(g Group, b []byte) (Element, error) {
+	w, ok := g.(*weierstrass)
+	if !ok {
+		return g.DecodeElement(b)
+	}
+	size := (w.curve.Params().BitSize + 7) / 8
+	if len(b) != 1+2*size || b[0] != 0x04 {
+		return nil, ErrInvalidElement
+	}
+	x := util.BytesToBigInt(b[1 : 1+size])
+	y := util.BytesToBigInt(b[1+size:])
+	p := w.curve.Params().P
+	if util.BigCmp(x, p) >= 0 || util.BigCmp(y, p) >= 0 || !w.curve.IsOnCurve(x, y) {
+		return nil, ErrInvalidElement
+	}
+	return w.point(x, y), nil
+}
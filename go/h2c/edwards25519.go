@@ -0,0 +1,79 @@
+package h2c
+
+import "math/big"
+
+// edwards25519 is hashed by Elligator 2 to curve25519, v^2 = s^3 + J*s^2 + s, followed by
+// the rational map to edwards25519, -x^2 + y^2 = 1 + d*x^2*y^2 (RFC 9380, section 6.8.2).
+var (
+	fieldP25519 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	field25519  = field{fieldP25519}
+
+	montgomeryJ = big.NewInt(486662)
+	elligatorZ  = big.NewInt(2)
+
+	// edwardsD is -121665/121666.
+	edwardsD = field25519.mul(big.NewInt(-121665), field25519.inv0(big.NewInt(121666)))
+
+	// edwardsC1 is sqrt(-486664) with sgn0 = 0, the scale of the rational map.
+	edwardsC1 = evenSqrt(field25519, big.NewInt(-486664))
+)
+
+func evenSqrt(f field, a *big.Int) *big.Int {
+	r := f.sqrt(a)
+	if sgn0(r) == 1 {
+		r = f.neg(r)
+	}
+	return r
+}
+
+// elligator2 is map_to_curve_elligator2 for curve25519 (K = 1), returning (s, t).
+func elligator2(u *big.Int) (*big.Int, *big.Int) {
+	f := field25519
+	one := big.NewInt(1)
+	x1 := f.mul(f.neg(montgomeryJ), f.inv0(f.add(one, f.mul(elligatorZ, f.mul(u, u)))))
+	if x1.Sign() == 0 {
+		x1 = f.neg(montgomeryJ)
+	}
+	g := func(x *big.Int) *big.Int {
+		return f.add(f.mul(f.add(f.mul(x, x), f.mul(montgomeryJ, x)), x), x)
+	}
+	if y := f.sqrt(g(x1)); y != nil {
+		if sgn0(y) == 0 {
+			y = f.neg(y)
+		}
+		return x1, y
+	}
+	x2 := f.sub(f.neg(x1), montgomeryJ)
+	return x2, evenSqrt(f, g(x2))
+}
+
+// edwards25519MapToCurve maps u with Elligator 2 and converts the curve25519 point (s, t)
+// to edwards25519 as (c1*s/t, (s-1)/(s+1)), sending the exceptional cases to (0, 1).
+func edwards25519MapToCurve(u *big.Int) (*big.Int, *big.Int) {
+	f := field25519
+	s, t := elligator2(u)
+	one := big.NewInt(1)
+	sPlus1 := f.add(s, one)
+	if t.Sign() == 0 || sPlus1.Sign() == 0 {
+		return new(big.Int), one
+	}
+	return f.mul(f.mul(edwardsC1, s), f.inv0(t)), f.mul(f.sub(s, one), f.inv0(sPlus1))
+}
+
+// edwardsAdd is the complete affine addition law on edwards25519.
+func edwardsAdd(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	f := field25519
+	one := big.NewInt(1)
+	dxy := f.mul(edwardsD, f.mul(f.mul(x1, x2), f.mul(y1, y2)))
+	x := f.mul(f.add(f.mul(x1, y2), f.mul(y1, x2)), f.inv0(f.add(one, dxy)))
+	y := f.mul(f.add(f.mul(y1, y2), f.mul(x1, x2)), f.inv0(f.sub(one, dxy)))
+	return x, y
+}
+
+// edwardsClearCofactor multiplies by the cofactor 8 with three doublings.
+func edwardsClearCofactor(x, y *big.Int) (*big.Int, *big.Int) {
+	for i := 0; i < 3; i++ {
+		x, y = edwardsAdd(x, y, x, y)
+	}
+	return x, y
+}
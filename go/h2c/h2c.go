@@ -0,0 +1,163 @@
+// Package h2c implements hashing to elliptic curves (RFC 9380) for the P-256, P-384, P-521,
+// secp256k1 and edwards25519 suites, in both the random-oracle (_RO_) and nonuniform (_NU_)
+// encodings. Messages are expanded with util.ExpandMessageXmd and mapped with the simplified
+// SWU map (the NIST curves, and secp256k1 through its 3-isogeny) or Elligator 2 (edwards25519).
+// The arithmetic uses math/big and is not constant time.
+package h2c
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Suite is an RFC 9380 suite ID.
+type Suite string
+
+const (
+	P256RO         Suite = "P256_XMD:SHA-256_SSWU_RO_"
+	P256NU         Suite = "P256_XMD:SHA-256_SSWU_NU_"
+	P384RO         Suite = "P384_XMD:SHA-384_SSWU_RO_"
+	P384NU         Suite = "P384_XMD:SHA-384_SSWU_NU_"
+	P521RO         Suite = "P521_XMD:SHA-512_SSWU_RO_"
+	P521NU         Suite = "P521_XMD:SHA-512_SSWU_NU_"
+	Secp256k1RO    Suite = "secp256k1_XMD:SHA-256_SSWU_RO_"
+	Secp256k1NU    Suite = "secp256k1_XMD:SHA-256_SSWU_NU_"
+	Edwards25519RO Suite = "edwards25519_XMD:SHA-512_ELL2_RO_"
+	Edwards25519NU Suite = "edwards25519_XMD:SHA-512_ELL2_NU_"
+)
+
+var (
+	ErrUnsupportedSuite = errors.New("h2c: unsupported suite")
+	ErrFieldElement     = errors.New("h2c: field element is not in [0, p)")
+)
+
+// curve is the arithmetic of one target curve. Points are affine; the identity is (0, 0)
+// on the Weierstrass curves, as in crypto/elliptic, and (0, 1) on edwards25519.
+type curve struct {
+	p            *big.Int
+	securityBits int
+	hashBits     int
+	mapToCurve   func(u *big.Int) (x, y *big.Int)
+	add          func(x1, y1, x2, y2 *big.Int) (x, y *big.Int)
+	clear        func(x, y *big.Int) (*big.Int, *big.Int)
+}
+
+// noCofactor is clear_cofactor for the prime-order curves.
+func noCofactor(x, y *big.Int) (*big.Int, *big.Int) {
+	return x, y
+}
+
+func nistCurve(c elliptic.Curve, z int64, securityBits, hashBits int) *curve {
+	params := c.Params()
+	s := newSSWU(params.P, big.NewInt(-3), params.B, big.NewInt(z))
+	return &curve{
+		p:            params.P,
+		securityBits: securityBits,
+		hashBits:     hashBits,
+		mapToCurve:   s.mapToCurve,
+		add:          c.Add,
+		clear:        noCofactor,
+	}
+}
+
+var (
+	p256Curve      = nistCurve(elliptic.P256(), -10, 128, 256)
+	p384Curve      = nistCurve(elliptic.P384(), -12, 192, 384)
+	p521Curve      = nistCurve(elliptic.P521(), -4, 256, 512)
+	secp256k1Curve = &curve{
+		p:            secp256k1.S256().Params().P,
+		securityBits: 128,
+		hashBits:     256,
+		mapToCurve:   secp256k1MapToCurve,
+		add:          secp256k1.S256().Add,
+		clear:        noCofactor,
+	}
+	edwards25519Curve = &curve{
+		p:            fieldP25519,
+		securityBits: 128,
+		hashBits:     512,
+		mapToCurve:   edwards25519MapToCurve,
+		add:          edwardsAdd,
+		clear:        edwardsClearCofactor,
+	}
+)
+
+// lookup returns the curve of s and whether s is a random-oracle suite.
+func lookup(s Suite) (*curve, bool, error) {
+	switch s {
+	case P256RO, P256NU:
+		return p256Curve, s == P256RO, nil
+	case P384RO, P384NU:
+		return p384Curve, s == P384RO, nil
+	case P521RO, P521NU:
+		return p521Curve, s == P521RO, nil
+	case Secp256k1RO, Secp256k1NU:
+		return secp256k1Curve, s == Secp256k1RO, nil
+	case Edwards25519RO, Edwards25519NU:
+		return edwards25519Curve, s == Edwards25519RO, nil
+	default:
+		return nil, false, ErrUnsupportedSuite
+	}
+}
+
+// RandomOracle reports whether s is a random-oracle (_RO_) suite, whose HashToCurve output
+// is indistinguishable from a random point; a nonuniform (_NU_) suite is cheaper but its
+// output is not uniformly distributed.
+func (s Suite) RandomOracle() bool {
+	_, ro, _ := lookup(s)
+	return ro
+}
+
+// HashToField hashes msg to count elements of the suite's base field with the suite's
+// expand_message_xmd and security level.
+func HashToField(s Suite, msg []byte, dst []byte, count int) ([]*big.Int, error) {
+	c, _, err := lookup(s)
+	if err != nil {
+		return nil, err
+	}
+	return util.HashToField(msg, dst, count, c.p, c.securityBits, util.XmdExpander(c.hashBits))
+}
+
+// MapToCurve maps the field element u to a point with the suite's map_to_curve, before
+// cofactor clearing. For secp256k1 this includes the isogeny map, and for edwards25519 the
+// map from curve25519 to edwards25519.
+func MapToCurve(s Suite, u *big.Int) (x, y *big.Int, err error) {
+	c, _, err := lookup(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u == nil || u.Sign() < 0 || u.Cmp(c.p) >= 0 {
+		return nil, nil, ErrFieldElement
+	}
+	x, y = c.mapToCurve(u)
+	return x, y, nil
+}
+
+// HashToCurve hashes msg to a point of the suite's curve under the domain separation tag
+// dst. For a random-oracle suite this is hash_to_curve, which maps two field elements and
+// adds the results; for a nonuniform suite it is encode_to_curve, which maps one.
+func HashToCurve(s Suite, msg []byte, dst []byte) (x, y *big.Int, err error) {
+	c, ro, err := lookup(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	count := 1
+	if ro {
+		count = 2
+	}
+	u, err := util.HashToField(msg, dst, count, c.p, c.securityBits, util.XmdExpander(c.hashBits))
+	if err != nil {
+		return nil, nil, err
+	}
+	x, y = c.mapToCurve(u[0])
+	if ro {
+		x1, y1 := c.mapToCurve(u[1])
+		x, y = c.add(x, y, x1, y1)
+	}
+	x, y = c.clear(x, y)
+	return x, y, nil
+}
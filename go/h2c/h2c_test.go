@@ -0,0 +1,79 @@
+package h2c
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+)
+
+func onEdwards25519(x, y *big.Int) bool {
+	f := field25519
+	x2, y2 := f.mul(x, x), f.mul(y, y)
+	lhs := f.sub(y2, x2)
+	rhs := f.add(big.NewInt(1), f.mul(edwardsD, f.mul(x2, y2)))
+	return lhs.Cmp(rhs) == 0
+}
+
+func TestHashToCurve_OnCurve(t *testing.T) {
+	onCurve := map[Suite]func(x, y *big.Int) bool{
+		P256RO:         elliptic.P256().IsOnCurve,
+		P384NU:         elliptic.P384().IsOnCurve,
+		P521RO:         elliptic.P521().IsOnCurve,
+		Secp256k1NU:    secp256k1.S256().IsOnCurve,
+		Edwards25519RO: onEdwards25519,
+	}
+	for s, check := range onCurve {
+		for _, msg := range []string{"", "a", "hash me to a point"} {
+			x, y, err := HashToCurve(s, []byte(msg), []byte("inparity-h2c-test"))
+			if err != nil {
+				t.Fatalf("%s: %v", s, err)
+			}
+			if !check(x, y) {
+				t.Fatalf("%s %q: point is not on the curve", s, msg)
+			}
+		}
+	}
+}
+
+func TestHashToCurve_DomainSeparation(t *testing.T) {
+	x1, _, err := HashToCurve(P256RO, []byte("msg"), []byte("dst-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x2, _, err := HashToCurve(P256RO, []byte("msg"), []byte("dst-b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x1.Cmp(x2) == 0 {
+		t.Fatal("different DSTs gave the same point")
+	}
+}
+
+func TestEdwards25519_ZeroInput(t *testing.T) {
+	// u = 0 reaches Elligator 2 with 1 + Z*u^2 = 1, the smallest input of the map.
+	x, y, err := MapToCurve(Edwards25519NU, new(big.Int))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !onEdwards25519(x, y) {
+		t.Fatal("map of zero is not on the curve")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, _, err := HashToCurve("P256_XMD:SHA-256_SSWU_XX_", nil, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("got %v want ErrUnsupportedSuite", err)
+	}
+	if _, err := HashToField("", nil, nil, 1); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("got %v want ErrUnsupportedSuite", err)
+	}
+	p := elliptic.P256().Params().P
+	for _, u := range []*big.Int{nil, big.NewInt(-1), p} {
+		if _, _, err := MapToCurve(P256NU, u); !errors.Is(err, ErrFieldElement) {
+			t.Fatalf("u = %v: got %v want ErrFieldElement", u, err)
+		}
+	}
+}
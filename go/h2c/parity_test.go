@@ -0,0 +1,90 @@
+package h2c
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type point struct{ X, Y string }
+
+type parityVectors struct {
+	H2c struct {
+		Suites []struct {
+			Suite string
+			Dst   string
+			Msg   string
+			U     []string
+			Q     []point
+			P     point
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func checkPoint(t *testing.T, what string, x, y *big.Int, want point) {
+	t.Helper()
+	if got := fmt.Sprintf("%x,%x", x, y); got != fmt.Sprintf("%x,%x", mustHex(want.X), mustHex(want.Y)) {
+		t.Fatalf("%s: got %s want %s,%s", what, got, want.X, want.Y)
+	}
+}
+
+func TestParity_HashToField(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.H2c.Suites {
+		u, err := HashToField(Suite(tc.Suite), []byte(tc.Msg), []byte(tc.Dst), len(tc.U))
+		if err != nil {
+			t.Fatalf("%s %q: %v", tc.Suite, tc.Msg, err)
+		}
+		for i := range u {
+			if u[i].Cmp(mustHex(tc.U[i])) != 0 {
+				t.Fatalf("%s %q u[%d]: got %x want %s", tc.Suite, tc.Msg, i, u[i], tc.U[i])
+			}
+		}
+	}
+}
+
+func TestParity_MapToCurve(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.H2c.Suites {
+		for i, q := range tc.Q {
+			x, y, err := MapToCurve(Suite(tc.Suite), mustHex(tc.U[i]))
+			if err != nil {
+				t.Fatalf("%s %q: %v", tc.Suite, tc.Msg, err)
+			}
+			checkPoint(t, fmt.Sprintf("%s %q Q%d", tc.Suite, tc.Msg, i), x, y, q)
+		}
+	}
+}
+
+func TestParity_HashToCurve(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.H2c.Suites {
+		s := Suite(tc.Suite)
+		if s.RandomOracle() != (len(tc.Q) == 2) {
+			t.Fatalf("%s: RandomOracle() = %v with %d map outputs", s, s.RandomOracle(), len(tc.Q))
+		}
+		x, y, err := HashToCurve(s, []byte(tc.Msg), []byte(tc.Dst))
+		if err != nil {
+			t.Fatalf("%s %q: %v", tc.Suite, tc.Msg, err)
+		}
+		checkPoint(t, fmt.Sprintf("%s %q", tc.Suite, tc.Msg), x, y, tc.P)
+	}
+}
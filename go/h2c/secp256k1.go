@@ -0,0 +1,80 @@
+package h2c
+
+import (
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+)
+
+// secp256k1 has A = 0, so the simplified SWU map targets the 3-isogenous curve
+// y^2 = x^3 + A'*x + B' and iso_map carries the result back (RFC 9380, appendix E.1).
+var (
+	secp256k1Field = field{secp256k1.S256().Params().P}
+	secp256k1Iso   = newSSWU(secp256k1Field.p,
+		mustHex("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533"),
+		big.NewInt(1771), big.NewInt(-11))
+
+	// Coefficients of the isogeny map, constant term first.
+	isoXNum = hexList(
+		"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7",
+		"07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581",
+		"534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262",
+		"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c",
+	)
+	isoXDen = hexList(
+		"d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b",
+		"edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14",
+		"01",
+	)
+	isoYNum = hexList(
+		"4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c",
+		"c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3",
+		"29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931",
+		"2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84",
+	)
+	isoYDen = hexList(
+		"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b",
+		"7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573",
+		"6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f",
+		"01",
+	)
+)
+
+func mustHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("h2c: bad constant")
+	}
+	return v
+}
+
+func hexList(s ...string) []*big.Int {
+	out := make([]*big.Int, len(s))
+	for i := range s {
+		out[i] = mustHex(s[i])
+	}
+	return out
+}
+
+// poly evaluates the polynomial with coefficients c (constant term first) at x.
+func (f field) poly(c []*big.Int, x *big.Int) *big.Int {
+	acc := new(big.Int)
+	for i := len(c) - 1; i >= 0; i-- {
+		acc = f.add(f.mul(acc, x), c[i])
+	}
+	return acc
+}
+
+// secp256k1MapToCurve is map_to_curve_simple_swu on the isogenous curve followed by
+// iso_map. A zero denominator maps to the identity (0, 0).
+func secp256k1MapToCurve(u *big.Int) (*big.Int, *big.Int) {
+	f := secp256k1Field
+	xp, yp := secp256k1Iso.mapToCurve(u)
+	xDen, yDen := f.poly(isoXDen, xp), f.poly(isoYDen, xp)
+	if xDen.Sign() == 0 || yDen.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	x := f.mul(f.poly(isoXNum, xp), f.inv0(xDen))
+	y := f.mul(yp, f.mul(f.poly(isoYNum, xp), f.inv0(yDen)))
+	return x, y
+}
@@ -0,0 +1,75 @@
+package h2c
+
+import (
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// field is arithmetic modulo a prime p. All results are in [0, p).
+type field struct {
+	p *big.Int
+}
+
+func (f field) mod(a *big.Int) *big.Int    { return util.BigModPos(a, f.p) }
+func (f field) add(a, b *big.Int) *big.Int { return f.mod(new(big.Int).Add(a, b)) }
+func (f field) sub(a, b *big.Int) *big.Int { return f.mod(new(big.Int).Sub(a, b)) }
+func (f field) mul(a, b *big.Int) *big.Int { return f.mod(new(big.Int).Mul(a, b)) }
+func (f field) neg(a *big.Int) *big.Int    { return f.mod(new(big.Int).Neg(a)) }
+
+// inv0 is the inverse with inv0(0) = 0.
+func (f field) inv0(a *big.Int) *big.Int {
+	a = f.mod(a)
+	if a.Sign() == 0 {
+		return a
+	}
+	return new(big.Int).ModInverse(a, f.p)
+}
+
+// sqrt returns a square root of a, or nil if a is not a square.
+func (f field) sqrt(a *big.Int) *big.Int {
+	return new(big.Int).ModSqrt(f.mod(a), f.p)
+}
+
+// sgn0 is the sign of a field element, its parity (RFC 9380, section 4.1).
+func sgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
+
+// sswu is the simplified Shallue-van de Woestijne-Ulas map to y^2 = x^3 + A*x + B with
+// A*B != 0 (RFC 9380, section 6.6.2).
+type sswu struct {
+	f       field
+	a, b, z *big.Int
+}
+
+func newSSWU(p, a, b, z *big.Int) *sswu {
+	f := field{p}
+	return &sswu{f: f, a: f.mod(a), b: f.mod(b), z: f.mod(z)}
+}
+
+func (s *sswu) g(x *big.Int) *big.Int {
+	f := s.f
+	return f.add(f.mul(f.add(f.mul(x, x), s.a), x), s.b)
+}
+
+func (s *sswu) mapToCurve(u *big.Int) (*big.Int, *big.Int) {
+	f := s.f
+	zu2 := f.mul(s.z, f.mul(u, u))
+	tv1 := f.inv0(f.add(f.mul(zu2, zu2), zu2))
+	var x1 *big.Int
+	if tv1.Sign() == 0 {
+		x1 = f.mul(s.b, f.inv0(f.mul(s.z, s.a)))
+	} else {
+		x1 = f.mul(f.mul(f.neg(s.b), f.inv0(s.a)), f.add(tv1, big.NewInt(1)))
+	}
+	x, y := x1, f.sqrt(s.g(x1))
+	if y == nil {
+		x = f.mul(zu2, x1)
+		y = f.sqrt(s.g(x))
+	}
+	if sgn0(u) != sgn0(y) {
+		y = f.neg(y)
+	}
+	return x, y
+}
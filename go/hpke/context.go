@@ -0,0 +1,180 @@
+package hpke
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+)
+
+// context is the encryption context both roles share: the AEAD key and base nonce, the
+// sequence number, and the exporter secret.
+type context struct {
+	p              *params
+	aead           cipher.AEAD
+	key            []byte
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+	// ksContext and secret are kept for the parity tests.
+	ksContext []byte
+	secret    []byte
+}
+
+// nonce is base_nonce XOR I2OSP(seq, Nn).
+func (c *context) nonce() []byte {
+	nonce := append([]byte(nil), c.baseNonce...)
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], c.seq)
+	for i := range seq {
+		nonce[len(nonce)-8+i] ^= seq[i]
+	}
+	return nonce
+}
+
+func (c *context) export(exporterContext []byte, length int) ([]byte, error) {
+	if length < 0 || length > 255*c.p.kdf.nh() {
+		return nil, ErrExportLength
+	}
+	return c.p.kdf.expand(c.exporterSecret, "sec", exporterContext, length)
+}
+
+// Sender is the sender's context: it seals messages in order and exports secrets.
+type Sender struct {
+	c *context
+}
+
+// Recipient is the recipient's context: it opens messages in the sender's order and
+// exports the same secrets.
+type Recipient struct {
+	c *context
+}
+
+// SetupSender encapsulates a fresh shared secret to the recipient's public key and returns
+// enc, which the recipient needs, with the sender's context. opts selects the mode.
+func SetupSender(suite Suite, recipientPublicKey, info []byte, opts *Options) ([]byte, *Sender, error) {
+	ikmE := make([]byte, 32)
+	if _, err := rand.Read(ikmE); err != nil {
+		return nil, nil, err
+	}
+	return setupSender(suite, recipientPublicKey, info, opts, ikmE)
+}
+
+func setupSender(suite Suite, recipientPublicKey, info []byte, opts *Options, ikmE []byte) ([]byte, *Sender, error) {
+	p, err := newParams(suite)
+	if err != nil {
+		return nil, nil, err
+	}
+	mode, err := opts.mode(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	var skS []byte
+	if mode == ModeAuth || mode == ModeAuthPSK {
+		skS = opts.SenderPrivateKey
+	}
+	sharedSecret, enc, err := p.kem.encap(recipientPublicKey, skS, ikmE)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := p.keySchedule(mode, sharedSecret, info, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, &Sender{c: c}, nil
+}
+
+// SetupRecipient decapsulates enc with the recipient's private key and returns the
+// recipient's context. opts must select the sender's mode, with the sender's public key in
+// the auth modes.
+func SetupRecipient(suite Suite, recipientPrivateKey, enc, info []byte, opts *Options) (*Recipient, error) {
+	p, err := newParams(suite)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := opts.mode(false)
+	if err != nil {
+		return nil, err
+	}
+	var pkS []byte
+	if mode == ModeAuth || mode == ModeAuthPSK {
+		pkS = opts.SenderPublicKey
+	}
+	sharedSecret, err := p.kem.decap(enc, recipientPrivateKey, pkS)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.keySchedule(mode, sharedSecret, info, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Recipient{c: c}, nil
+}
+
+// Seal encrypts the next message with aad and returns ciphertext || tag.
+func (s *Sender) Seal(aad, plaintext []byte) ([]byte, error) {
+	c := s.c
+	if c.aead == nil {
+		return nil, ErrExportOnly
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, ErrMessageLimit
+	}
+	ct := c.aead.Seal(nil, c.nonce(), plaintext, aad)
+	c.seq++
+	return ct, nil
+}
+
+// Export derives length bytes bound to exporterContext from the context's exporter secret.
+func (s *Sender) Export(exporterContext []byte, length int) ([]byte, error) {
+	return s.c.export(exporterContext, length)
+}
+
+// Open decrypts the next message. A wrong key, aad, order or a modified ciphertext fails
+// with ErrOpen, and the message can be retried.
+func (r *Recipient) Open(aad, ciphertext []byte) ([]byte, error) {
+	c := r.c
+	if c.aead == nil {
+		return nil, ErrExportOnly
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, ErrMessageLimit
+	}
+	pt, err := c.aead.Open(nil, c.nonce(), ciphertext, aad)
+	if err != nil {
+		return nil, ErrOpen
+	}
+	c.seq++
+	if pt == nil {
+		pt = []byte{}
+	}
+	return pt, nil
+}
+
+// Export derives length bytes bound to exporterContext from the context's exporter secret.
+func (r *Recipient) Export(exporterContext []byte, length int) ([]byte, error) {
+	return r.c.export(exporterContext, length)
+}
+
+// Seal is single-shot encryption: it sets up a sender context and seals one message,
+// returning enc and the ciphertext.
+func Seal(suite Suite, recipientPublicKey, info, aad, plaintext []byte, opts *Options) ([]byte, []byte, error) {
+	enc, s, err := SetupSender(suite, recipientPublicKey, info, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct, err := s.Seal(aad, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ct, nil
+}
+
+// Open is single-shot decryption of a message from Seal.
+func Open(suite Suite, recipientPrivateKey, enc, info, aad, ciphertext []byte, opts *Options) ([]byte, error) {
+	r, err := SetupRecipient(suite, recipientPrivateKey, enc, info, opts)
+	if err != nil {
+		return nil, err
+	}
+	return r.Open(aad, ciphertext)
+}
@@ -0,0 +1,237 @@
+// Package hpke implements Hybrid Public Key Encryption (RFC 9180) in the base, PSK, auth
+// and auth-PSK modes, with DHKEM over X25519 and P-256, the HKDF-SHA2 KDFs, and the
+// AES-GCM and ChaCha20-Poly1305 AEADs (or export only). A sender sets up a context from
+// the recipient's public key and sends the encapsulated key enc with its ciphertexts; the
+// recipient sets up the matching context from enc and its private key. A context seals or
+// opens any number of messages in order and exports secrets; Seal and Open are the
+// single-shot forms.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/util"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// KEM is an RFC 9180 KEM identifier.
+type KEM uint16
+
+const (
+	DHKemP256HkdfSha256   KEM = 0x0010
+	DHKemX25519HkdfSha256 KEM = 0x0020
+)
+
+// KDF is an RFC 9180 KDF identifier.
+type KDF uint16
+
+const (
+	HkdfSha256 KDF = 0x0001
+	HkdfSha384 KDF = 0x0002
+	HkdfSha512 KDF = 0x0003
+)
+
+// AEAD is an RFC 9180 AEAD identifier. ExportOnly contexts can only export secrets.
+type AEAD uint16
+
+const (
+	Aes128Gcm        AEAD = 0x0001
+	Aes256Gcm        AEAD = 0x0002
+	ChaCha20Poly1305 AEAD = 0x0003
+	ExportOnly       AEAD = 0xffff
+)
+
+// Mode is an RFC 9180 mode. It follows from Options: a PSK selects the PSK modes and a
+// sender key the auth modes.
+type Mode byte
+
+const (
+	ModeBase    Mode = 0x00
+	ModePSK     Mode = 0x01
+	ModeAuth    Mode = 0x02
+	ModeAuthPSK Mode = 0x03
+)
+
+var (
+	ErrUnsupportedSuite = errors.New("hpke: unsupported KEM, KDF or AEAD")
+	ErrInvalidKey       = errors.New("hpke: invalid public or private key")
+	ErrInvalidPSK       = errors.New("hpke: psk and psk_id must both be set or both be empty")
+	ErrDeriveKeyPair    = errors.New("hpke: no valid key derived from the seed")
+	ErrOpen             = errors.New("hpke: message authentication failed")
+	ErrMessageLimit     = errors.New("hpke: sequence number overflow")
+	ErrExportOnly       = errors.New("hpke: context is export-only")
+	ErrExportLength     = errors.New("hpke: export length too large")
+)
+
+// Suite is a KEM, KDF and AEAD combination.
+type Suite struct {
+	KEM  KEM
+	KDF  KDF
+	AEAD AEAD
+}
+
+// Options selects the mode and carries its extra inputs. A nil *Options is the base mode.
+type Options struct {
+	// PSK and PSKID switch on the PSK modes; both or neither must be set.
+	PSK   []byte
+	PSKID []byte
+	// SenderPrivateKey, on the sender, and SenderPublicKey, on the recipient, switch on the
+	// auth modes, which authenticate the sender's static key.
+	SenderPrivateKey []byte
+	SenderPublicKey  []byte
+}
+
+// labeledKDF is HKDF with the RFC 9180 "HPKE-v1" labels under one suite_id.
+type labeledKDF struct {
+	suiteID  []byte
+	hashBits int
+}
+
+func kdfHashBits(id KDF) (int, error) {
+	switch id {
+	case HkdfSha256:
+		return 256, nil
+	case HkdfSha384:
+		return 384, nil
+	case HkdfSha512:
+		return 512, nil
+	default:
+		return 0, ErrUnsupportedSuite
+	}
+}
+
+func (k labeledKDF) nh() int {
+	return k.hashBits / 8
+}
+
+// extract is LabeledExtract(salt, label, ikm).
+func (k labeledKDF) extract(salt []byte, label string, ikm []byte) []byte {
+	out, _ := util.HkdfExtract(salt, util.ConcatBytes([]byte("HPKE-v1"), k.suiteID, []byte(label), ikm), k.hashBits)
+	return out
+}
+
+// expand is LabeledExpand(prk, label, info, length).
+func (k labeledKDF) expand(prk []byte, label string, info []byte, length int) ([]byte, error) {
+	if length > 0xffff {
+		return nil, ErrExportLength
+	}
+	labeled := util.ConcatBytes(binary.BigEndian.AppendUint16(nil, uint16(length)), []byte("HPKE-v1"), k.suiteID, []byte(label), info)
+	return util.HkdfExpand(prk, labeled, length, k.hashBits)
+}
+
+// aeadParams returns Nk and Nn for an AEAD; both are 0 for ExportOnly.
+func aeadParams(id AEAD) (int, int, error) {
+	switch id {
+	case Aes128Gcm:
+		return 16, 12, nil
+	case Aes256Gcm, ChaCha20Poly1305:
+		return 32, 12, nil
+	case ExportOnly:
+		return 0, 0, nil
+	default:
+		return 0, 0, ErrUnsupportedSuite
+	}
+}
+
+func newAEAD(id AEAD, key []byte) (cipher.AEAD, error) {
+	switch id {
+	case Aes128Gcm, Aes256Gcm:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, nil
+	}
+}
+
+// params is a resolved suite: its KEM and the labeled KDF of the key schedule.
+type params struct {
+	suite  Suite
+	kem    *dhkem
+	kdf    labeledKDF
+	nk, nn int
+}
+
+func newParams(suite Suite) (*params, error) {
+	kem, err := newDHKEM(suite.KEM)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := kdfHashBits(suite.KDF)
+	if err != nil {
+		return nil, err
+	}
+	nk, nn, err := aeadParams(suite.AEAD)
+	if err != nil {
+		return nil, err
+	}
+	id := []byte("HPKE")
+	id = binary.BigEndian.AppendUint16(id, uint16(suite.KEM))
+	id = binary.BigEndian.AppendUint16(id, uint16(suite.KDF))
+	id = binary.BigEndian.AppendUint16(id, uint16(suite.AEAD))
+	return &params{suite: suite, kem: kem, kdf: labeledKDF{suiteID: id, hashBits: bits}, nk: nk, nn: nn}, nil
+}
+
+// mode returns the mode that opts select, checking that psk and psk_id come together.
+func (o *Options) mode(sender bool) (Mode, error) {
+	if o == nil {
+		return ModeBase, nil
+	}
+	if (len(o.PSK) == 0) != (len(o.PSKID) == 0) {
+		return 0, ErrInvalidPSK
+	}
+	auth := len(o.SenderPublicKey) > 0
+	if sender {
+		auth = len(o.SenderPrivateKey) > 0
+	}
+	switch {
+	case auth && len(o.PSK) > 0:
+		return ModeAuthPSK, nil
+	case auth:
+		return ModeAuth, nil
+	case len(o.PSK) > 0:
+		return ModePSK, nil
+	default:
+		return ModeBase, nil
+	}
+}
+
+// keySchedule is KeySchedule<ROLE>() of RFC 9180, section 5.1.
+func (p *params) keySchedule(mode Mode, sharedSecret, info []byte, opts *Options) (*context, error) {
+	var psk, pskID []byte
+	if opts != nil {
+		psk, pskID = opts.PSK, opts.PSKID
+	}
+	pskIDHash := p.kdf.extract(nil, "psk_id_hash", pskID)
+	infoHash := p.kdf.extract(nil, "info_hash", info)
+	ksContext := util.ConcatBytes([]byte{byte(mode)}, pskIDHash, infoHash)
+	secret := p.kdf.extract(sharedSecret, "secret", psk)
+
+	c := &context{p: p}
+	var err error
+	if p.suite.AEAD != ExportOnly {
+		key, err := p.kdf.expand(secret, "key", ksContext, p.nk)
+		if err != nil {
+			return nil, err
+		}
+		if c.baseNonce, err = p.kdf.expand(secret, "base_nonce", ksContext, p.nn); err != nil {
+			return nil, err
+		}
+		if c.aead, err = newAEAD(p.suite.AEAD, key); err != nil {
+			return nil, err
+		}
+		c.key = key
+	}
+	if c.exporterSecret, err = p.kdf.expand(secret, "exp", ksContext, p.kdf.nh()); err != nil {
+		return nil, err
+	}
+	c.ksContext, c.secret = ksContext, secret
+	return c, nil
+}
@@ -0,0 +1,167 @@
+package hpke
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var kems = []KEM{DHKemX25519HkdfSha256, DHKemP256HkdfSha256}
+
+func TestRoundTrip(t *testing.T) {
+	for _, kem := range kems {
+		skR, pkR, err := GenerateKeyPair(kem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		skS, pkS, _ := GenerateKeyPair(kem)
+		psk := bytes.Repeat([]byte{0x42}, 32)
+		for _, kdf := range []KDF{HkdfSha256, HkdfSha384, HkdfSha512} {
+			for _, aead := range []AEAD{Aes128Gcm, Aes256Gcm, ChaCha20Poly1305} {
+				suite := Suite{KEM: kem, KDF: kdf, AEAD: aead}
+				for _, opts := range []struct{ sender, recipient *Options }{
+					{nil, nil},
+					{&Options{PSK: psk, PSKID: []byte("id")}, &Options{PSK: psk, PSKID: []byte("id")}},
+					{&Options{SenderPrivateKey: skS}, &Options{SenderPublicKey: pkS}},
+					{&Options{PSK: psk, PSKID: []byte("id"), SenderPrivateKey: skS}, &Options{PSK: psk, PSKID: []byte("id"), SenderPublicKey: pkS}},
+				} {
+					enc, sender, err := SetupSender(suite, pkR, []byte("info"), opts.sender)
+					if err != nil {
+						t.Fatalf("%+v: %v", suite, err)
+					}
+					recipient, err := SetupRecipient(suite, skR, enc, []byte("info"), opts.recipient)
+					if err != nil {
+						t.Fatalf("%+v: %v", suite, err)
+					}
+					for _, msg := range []string{"first", "", "third"} {
+						ct, _ := sender.Seal([]byte("aad"), []byte(msg))
+						pt, err := recipient.Open([]byte("aad"), ct)
+						if err != nil || string(pt) != msg {
+							t.Fatalf("%+v: message %q: %v", suite, msg, err)
+						}
+					}
+					a, _ := sender.Export([]byte("ctx"), 48)
+					b, _ := recipient.Export([]byte("ctx"), 48)
+					if len(a) != 48 || !bytes.Equal(a, b) {
+						t.Fatalf("%+v: exports differ", suite)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSingleShot(t *testing.T) {
+	suite := Suite{KEM: DHKemX25519HkdfSha256, KDF: HkdfSha256, AEAD: ChaCha20Poly1305}
+	skR, pkR, _ := GenerateKeyPair(suite.KEM)
+	enc, ct, err := Seal(suite, pkR, []byte("info"), []byte("aad"), []byte("hello"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := Open(suite, skR, enc, []byte("info"), []byte("aad"), ct, nil)
+	if err != nil || string(pt) != "hello" {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := Open(suite, skR, enc, []byte("other"), []byte("aad"), ct, nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("info: got %v want ErrOpen", err)
+	}
+	if _, err := Open(suite, skR, enc, []byte("info"), []byte("other"), ct, nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("aad: got %v want ErrOpen", err)
+	}
+	otherSK, _, _ := GenerateKeyPair(suite.KEM)
+	if _, err := Open(suite, otherSK, enc, []byte("info"), []byte("aad"), ct, nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("wrong key: got %v want ErrOpen", err)
+	}
+}
+
+func TestOrderAndRetry(t *testing.T) {
+	suite := Suite{KEM: DHKemP256HkdfSha256, KDF: HkdfSha256, AEAD: Aes128Gcm}
+	skR, pkR, _ := GenerateKeyPair(suite.KEM)
+	enc, sender, _ := SetupSender(suite, pkR, nil, nil)
+	recipient, _ := SetupRecipient(suite, skR, enc, nil, nil)
+	first, _ := sender.Seal(nil, []byte("1"))
+	second, _ := sender.Seal(nil, []byte("2"))
+	if _, err := recipient.Open(nil, second); !errors.Is(err, ErrOpen) {
+		t.Fatalf("out of order: got %v want ErrOpen", err)
+	}
+	if pt, err := recipient.Open(nil, first); err != nil || string(pt) != "1" {
+		t.Fatalf("first after failure: %v", err)
+	}
+	if pt, err := recipient.Open(nil, second); err != nil || string(pt) != "2" {
+		t.Fatalf("second: %v", err)
+	}
+	sender.c.seq = ^uint64(0)
+	if _, err := sender.Seal(nil, nil); !errors.Is(err, ErrMessageLimit) {
+		t.Fatalf("overflow: got %v want ErrMessageLimit", err)
+	}
+}
+
+func TestAuthMismatch(t *testing.T) {
+	suite := Suite{KEM: DHKemX25519HkdfSha256, KDF: HkdfSha512, AEAD: Aes256Gcm}
+	skR, pkR, _ := GenerateKeyPair(suite.KEM)
+	skS, _, _ := GenerateKeyPair(suite.KEM)
+	_, pkOther, _ := GenerateKeyPair(suite.KEM)
+	enc, ct, err := Seal(suite, pkR, nil, nil, []byte("m"), &Options{SenderPrivateKey: skS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(suite, skR, enc, nil, nil, ct, &Options{SenderPublicKey: pkOther}); !errors.Is(err, ErrOpen) {
+		t.Fatalf("wrong sender: got %v want ErrOpen", err)
+	}
+	if _, err := Open(suite, skR, enc, nil, nil, ct, nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("base mode: got %v want ErrOpen", err)
+	}
+}
+
+func TestExportOnly(t *testing.T) {
+	suite := Suite{KEM: DHKemX25519HkdfSha256, KDF: HkdfSha256, AEAD: ExportOnly}
+	skR, pkR, _ := GenerateKeyPair(suite.KEM)
+	enc, sender, err := SetupSender(suite, pkR, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, _ := SetupRecipient(suite, skR, enc, nil, nil)
+	if _, err := sender.Seal(nil, nil); !errors.Is(err, ErrExportOnly) {
+		t.Fatalf("seal: got %v", err)
+	}
+	if _, err := recipient.Open(nil, nil); !errors.Is(err, ErrExportOnly) {
+		t.Fatalf("open: got %v", err)
+	}
+	if _, err := sender.Export(nil, 255*32+1); !errors.Is(err, ErrExportLength) {
+		t.Fatalf("export length: got %v", err)
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	suite := Suite{KEM: DHKemP256HkdfSha256, KDF: HkdfSha256, AEAD: Aes128Gcm}
+	skR, pkR, _ := GenerateKeyPair(suite.KEM)
+	if _, _, err := SetupSender(Suite{KEM: 0x0012, KDF: HkdfSha512, AEAD: Aes256Gcm}, pkR, nil, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("P-521: got %v want ErrUnsupportedSuite", err)
+	}
+	if _, _, err := SetupSender(Suite{KEM: suite.KEM, KDF: 0x0004, AEAD: Aes128Gcm}, pkR, nil, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("kdf: got %v want ErrUnsupportedSuite", err)
+	}
+	if _, _, err := SetupSender(suite, pkR, nil, &Options{PSK: []byte("psk")}); !errors.Is(err, ErrInvalidPSK) {
+		t.Fatalf("psk without id: got %v want ErrInvalidPSK", err)
+	}
+	compressed := append([]byte{0x02 | pkR[64]&1}, pkR[1:33]...)
+	if _, _, err := SetupSender(suite, compressed, nil, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("compressed key: got %v want ErrInvalidKey", err)
+	}
+	bad := append([]byte(nil), pkR...)
+	bad[64] ^= 1
+	if _, _, err := SetupSender(suite, bad, nil, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("off-curve key: got %v want ErrInvalidKey", err)
+	}
+	if _, err := SetupRecipient(suite, skR, bad, nil, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("off-curve enc: got %v want ErrInvalidKey", err)
+	}
+	if _, err := SetupRecipient(suite, make([]byte, 32), pkR, nil, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("zero private key: got %v want ErrInvalidKey", err)
+	}
+
+	x := Suite{KEM: DHKemX25519HkdfSha256, KDF: HkdfSha256, AEAD: Aes128Gcm}
+	if _, _, err := SetupSender(x, make([]byte, 32), nil, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("low-order X25519 key: got %v want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,184 @@
+package hpke
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/grzegorzmaniak/inparity/kex"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// dhkem is DHKEM(Group, HKDF-SHA256) over X25519 or P-256 (RFC 9180, section 4.1).
+type dhkem struct {
+	id KEM
+	// kdf is labeled with suite_id = "KEM" || I2OSP(kem_id, 2).
+	kdf labeledKDF
+	// nsecret is the shared secret length, npk the public key and enc length, and nsk the
+	// private key length.
+	nsecret, npk, nsk int
+}
+
+func newDHKEM(id KEM) (*dhkem, error) {
+	k := &dhkem{id: id, nsecret: 32, nsk: 32}
+	switch id {
+	case DHKemP256HkdfSha256:
+		k.npk = 65
+	case DHKemX25519HkdfSha256:
+		k.npk = 32
+	default:
+		return nil, ErrUnsupportedSuite
+	}
+	k.kdf = labeledKDF{suiteID: binary.BigEndian.AppendUint16([]byte("KEM"), uint16(id)), hashBits: 256}
+	return k, nil
+}
+
+// publicKey returns the serialized public key for a private key, which it validates.
+func (k *dhkem) publicKey(sk []byte) ([]byte, error) {
+	if len(sk) != k.nsk {
+		return nil, ErrInvalidKey
+	}
+	var pk []byte
+	var err error
+	if k.id == DHKemX25519HkdfSha256 {
+		pk, err = kex.X25519PublicKey(sk)
+	} else {
+		pk, err = kex.EcdhPublicKey(sk, 256, false)
+	}
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return pk, nil
+}
+
+// dh runs the Diffie-Hellman function on a private key and a serialized public key,
+// which must be the uncompressed point for P-256. A low-order X25519 key, or one giving an
+// all-zero secret, fails with ErrInvalidKey.
+func (k *dhkem) dh(sk, pk []byte) ([]byte, error) {
+	if len(pk) != k.npk {
+		return nil, ErrInvalidKey
+	}
+	var out []byte
+	var err error
+	if k.id == DHKemX25519HkdfSha256 {
+		out, err = kex.X25519SharedSecret(sk, pk)
+	} else {
+		if pk[0] != 0x04 {
+			return nil, ErrInvalidKey
+		}
+		out, err = kex.EcdhSharedSecret(sk, pk, 256)
+	}
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return out, nil
+}
+
+// deriveKeyPair is DeriveKeyPair(ikm) of RFC 9180, section 7.1.3: the X25519 private key
+// is expanded directly, and the P-256 one by rejection sampling over a counter.
+func (k *dhkem) deriveKeyPair(ikm []byte) ([]byte, []byte, error) {
+	prk := k.kdf.extract(nil, "dkp_prk", ikm)
+	if k.id == DHKemX25519HkdfSha256 {
+		sk, err := k.kdf.expand(prk, "sk", nil, k.nsk)
+		if err != nil {
+			return nil, nil, err
+		}
+		pk, err := k.publicKey(sk)
+		return sk, pk, err
+	}
+	for counter := 0; counter < 256; counter++ {
+		sk, err := k.kdf.expand(prk, "candidate", []byte{byte(counter)}, k.nsk)
+		if err != nil {
+			return nil, nil, err
+		}
+		// The P-256 bitmask is 0xff, so the candidate is used as is.
+		if pk, err := k.publicKey(sk); err == nil {
+			return sk, pk, nil
+		}
+	}
+	return nil, nil, ErrDeriveKeyPair
+}
+
+func (k *dhkem) generateKeyPair() ([]byte, []byte, error) {
+	ikm := make([]byte, k.nsk)
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, nil, err
+	}
+	return k.deriveKeyPair(ikm)
+}
+
+// extractAndExpand turns the DH output into the shared secret bound to kemContext.
+func (k *dhkem) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+	prk := k.kdf.extract(nil, "eae_prk", dh)
+	return k.kdf.expand(prk, "shared_secret", kemContext, k.nsecret)
+}
+
+// encap is Encap, or AuthEncap when skS is set, with the ephemeral key derived from ikmE.
+func (k *dhkem) encap(pkR, skS, ikmE []byte) ([]byte, []byte, error) {
+	skE, pkE, err := k.deriveKeyPair(ikmE)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh, err := k.dh(skE, pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	kemContext := util.ConcatBytes(pkE, pkR)
+	if skS != nil {
+		dhS, err := k.dh(skS, pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkS, err := k.publicKey(skS)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = util.ConcatBytes(dh, dhS)
+		kemContext = util.ConcatBytes(kemContext, pkS)
+	}
+	sharedSecret, err := k.extractAndExpand(dh, kemContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, pkE, nil
+}
+
+// decap is Decap, or AuthDecap when pkS is set.
+func (k *dhkem) decap(enc, skR, pkS []byte) ([]byte, error) {
+	pkR, err := k.publicKey(skR)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := k.dh(skR, enc)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := util.ConcatBytes(enc, pkR)
+	if pkS != nil {
+		dhS, err := k.dh(skR, pkS)
+		if err != nil {
+			return nil, err
+		}
+		dh = util.ConcatBytes(dh, dhS)
+		kemContext = util.ConcatBytes(kemContext, pkS)
+	}
+	return k.extractAndExpand(dh, kemContext)
+}
+
+// GenerateKeyPair returns a random private key and its serialized public key for kem.
+func GenerateKeyPair(kem KEM) ([]byte, []byte, error) {
+	k, err := newDHKEM(kem)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.generateKeyPair()
+}
+
+// DeriveKeyPair derives a private key and its serialized public key from ikm, which should
+// hold at least 32 bytes of entropy (RFC 9180, section 7.1.3).
+func DeriveKeyPair(kem KEM, ikm []byte) ([]byte, []byte, error) {
+	k, err := newDHKEM(kem)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.deriveKeyPair(ikm)
+}
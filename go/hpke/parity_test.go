@@ -0,0 +1,163 @@
+package hpke
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Hpke struct {
+		Vectors []struct {
+			Mode                   Mode
+			KemID                  KEM
+			KdfID                  KDF
+			AeadID                 AEAD
+			Info                   string
+			IkmR, IkmS, IkmE       string
+			SkRm, SkSm, SkEm       string
+			PkRm, PkSm, PkEm       string
+			Psk, PskID             string
+			Enc, SharedSecret      string
+			KeyScheduleContext     string
+			Secret, Key, BaseNonce string
+			ExporterSecret         string
+			Encryptions            []struct{ Aad, Ct, Nonce, Pt string }
+			Exports                []struct {
+				ExporterContext string
+				Length          int
+				ExportedValue   string
+			}
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParity_RFC9180(t *testing.T) {
+	v := loadVectors(t)
+	if len(v.Hpke.Vectors) != 128 {
+		t.Fatalf("got %d hpke vectors, want the full RFC 9180 set of 128", len(v.Hpke.Vectors))
+	}
+	ran := 0
+	for i, tc := range v.Hpke.Vectors {
+		suite := Suite{KEM: tc.KemID, KDF: tc.KdfID, AEAD: tc.AeadID}
+		if _, err := newDHKEM(tc.KemID); err != nil {
+			continue // P-521 and X448 are not implemented
+		}
+		ran++
+		check := func(name string, got, want []byte) {
+			t.Helper()
+			if !bytes.Equal(got, want) {
+				t.Fatalf("vector %d (mode %d, %+v): %s mismatch: got %x want %x", i, tc.Mode, suite, name, got, want)
+			}
+		}
+
+		skR, pkR, err := DeriveKeyPair(tc.KemID, mustHex(tc.IkmR))
+		if err != nil {
+			t.Fatal(err)
+		}
+		check("skRm", skR, mustHex(tc.SkRm))
+		check("pkRm", pkR, mustHex(tc.PkRm))
+		skE, pkE, _ := DeriveKeyPair(tc.KemID, mustHex(tc.IkmE))
+		check("skEm", skE, mustHex(tc.SkEm))
+		check("pkEm", pkE, mustHex(tc.PkEm))
+
+		senderOpts := &Options{PSK: mustHex(tc.Psk), PSKID: mustHex(tc.PskID)}
+		recipientOpts := &Options{PSK: mustHex(tc.Psk), PSKID: mustHex(tc.PskID)}
+		if tc.IkmS != "" {
+			skS, pkS, _ := DeriveKeyPair(tc.KemID, mustHex(tc.IkmS))
+			check("skSm", skS, mustHex(tc.SkSm))
+			check("pkSm", pkS, mustHex(tc.PkSm))
+			senderOpts.SenderPrivateKey = skS
+			recipientOpts.SenderPublicKey = pkS
+		}
+		if mode, _ := senderOpts.mode(true); mode != tc.Mode {
+			t.Fatalf("vector %d: options select mode %d, want %d", i, mode, tc.Mode)
+		}
+
+		p, _ := newParams(suite)
+		sharedSecret, _, err := p.kem.encap(pkR, senderOpts.SenderPrivateKey, mustHex(tc.IkmE))
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		check("sharedSecret", sharedSecret, mustHex(tc.SharedSecret))
+
+		info := mustHex(tc.Info)
+		enc, sender, err := setupSender(suite, pkR, info, senderOpts, mustHex(tc.IkmE))
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		check("enc", enc, mustHex(tc.Enc))
+		recipient, err := SetupRecipient(suite, skR, enc, info, recipientOpts)
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		c := sender.c
+		check("keyScheduleContext", c.ksContext, mustHex(tc.KeyScheduleContext))
+		check("secret", c.secret, mustHex(tc.Secret))
+		check("key", c.key, mustHex(tc.Key))
+		check("baseNonce", c.baseNonce, mustHex(tc.BaseNonce))
+		check("exporterSecret", c.exporterSecret, mustHex(tc.ExporterSecret))
+		check("recipient exporterSecret", recipient.c.exporterSecret, mustHex(tc.ExporterSecret))
+
+		for j, e := range tc.Encryptions {
+			check("nonce", c.nonce(), mustHex(e.Nonce))
+			ct, err := sender.Seal(mustHex(e.Aad), mustHex(e.Pt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			check("ct", ct, mustHex(e.Ct))
+			pt, err := recipient.Open(mustHex(e.Aad), ct)
+			if err != nil {
+				t.Fatalf("vector %d encryption %d: %v", i, j, err)
+			}
+			check("pt", pt, mustHex(e.Pt))
+		}
+		if tc.AeadID == ExportOnly {
+			if _, err := sender.Seal(nil, nil); !errors.Is(err, ErrExportOnly) {
+				t.Fatalf("vector %d: export-only seal: got %v", i, err)
+			}
+		}
+		for _, e := range tc.Exports {
+			got, err := sender.Export(mustHex(e.ExporterContext), e.Length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			check("exportedValue", got, mustHex(e.ExportedValue))
+			got, _ = recipient.Export(mustHex(e.ExporterContext), e.Length)
+			check("recipient exportedValue", got, mustHex(e.ExportedValue))
+		}
+	}
+	if ran != 64 {
+		t.Fatalf("ran %d vectors, want 64", ran)
+	}
+}
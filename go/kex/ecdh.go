@@ -0,0 +1,132 @@
+package kex
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+var ErrInvalidPoint = errors.New("kex: public key is not a valid curve point")
+
+// ecdhCurve maps a curve bit length to the matching NIST curve for both APIs.
+func ecdhCurve(curveBits int) (ecdh.Curve, elliptic.Curve, error) {
+	switch curveBits {
+	case 256:
+		return ecdh.P256(), elliptic.P256(), nil
+	case 384:
+		return ecdh.P384(), elliptic.P384(), nil
+	case 521:
+		return ecdh.P521(), elliptic.P521(), nil
+	default:
+		return nil, nil, errors.New("unsupported ECDH curve bit length")
+	}
+}
+
+// scalarLen returns the fixed byte width of scalars on curve.
+func scalarLen(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
+
+// parsePrivateKey checks that privateKey is a fixed-width scalar with 1 <= d < n.
+func parsePrivateKey(ec ecdh.Curve, curve elliptic.Curve, privateKey []byte) (*ecdh.PrivateKey, error) {
+	if len(privateKey) != scalarLen(curve) {
+		return nil, errors.New("private key has the wrong length for the curve")
+	}
+	d := util.BytesToBigInt(privateKey)
+	if d.Sign() == 0 || util.BigCmp(d, curve.Params().N) >= 0 {
+		return nil, errors.New("private key is out of range")
+	}
+	return ec.NewPrivateKey(privateKey)
+}
+
+// parsePublicKey accepts a compressed or uncompressed SEC1 point on curve.
+func parsePublicKey(ec ecdh.Curve, curve elliptic.Curve, publicKey []byte) (*ecdh.PublicKey, error) {
+	var x, y *big.Int
+	if len(publicKey) > 0 && publicKey[0] == 0x04 {
+		x, y = elliptic.Unmarshal(curve, publicKey)
+	} else {
+		x, y = elliptic.UnmarshalCompressed(curve, publicKey)
+	}
+	if x == nil {
+		return nil, ErrInvalidPoint
+	}
+	return ec.NewPublicKey(elliptic.Marshal(curve, x, y))
+}
+
+// EcdhGenerateKeyFromSeed derives a key pair on P-256, P-384 or P-521 from a seed of at least 32 bytes.
+// The seed is expanded with SHAKE256 to the scalar length plus 8 bytes and reduced as
+// d = (c mod (n-1)) + 1 (FIPS 186-5, A.2.1), so every seed gives a valid key.
+// The private key is the fixed-width scalar, the public key an uncompressed SEC1 point.
+func EcdhGenerateKeyFromSeed(seed []byte, curveBits int) ([]byte, []byte, error) {
+	_, curve, err := ecdhCurve(curveBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(seed) < 32 {
+		return nil, nil, errors.New("EcdhGenerateKeyFromSeed: seed must be at least 32 bytes")
+	}
+	size := scalarLen(curve)
+	c, err := util.ShakeHash(seed, 256, (size+8)*8)
+	if err != nil {
+		return nil, nil, err
+	}
+	nMinus1 := new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+	d := util.BigModPos(util.BytesToBigInt(c), nMinus1)
+	d.Add(d, big.NewInt(1))
+	privateKey := make([]byte, size)
+	d.FillBytes(privateKey)
+	publicKey, err := EcdhPublicKey(privateKey, curveBits, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// EcdhPublicKey returns the SEC1 public key for a fixed-width private scalar on P-256, P-384 or P-521.
+func EcdhPublicKey(privateKey []byte, curveBits int, compressed bool) ([]byte, error) {
+	ec, curve, err := ecdhCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(ec, curve, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := key.PublicKey().Bytes()
+	if compressed {
+		x, y := elliptic.Unmarshal(curve, publicKey)
+		return elliptic.MarshalCompressed(curve, x, y), nil
+	}
+	return publicKey, nil
+}
+
+// EcdhSharedSecret computes the raw ECDH shared secret (the x-coordinate, SP 800-56A)
+// with a compressed or uncompressed peer public key. Points not on the curve fail with ErrInvalidPoint.
+func EcdhSharedSecret(privateKey []byte, peerPublicKey []byte, curveBits int) ([]byte, error) {
+	ec, curve, err := ecdhCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(ec, curve, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := parsePublicKey(ec, curve, peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.ECDH(peer)
+}
+
+// EcdhSharedKey runs EcdhSharedSecret and passes the result through util.Hkdf
+// with salt and info, returning length bytes. hashBits selects SHA-2 as in util.Sha2Hash.
+func EcdhSharedKey(privateKey []byte, peerPublicKey []byte, curveBits int, salt []byte, info []byte, length int, hashBits int) ([]byte, error) {
+	secret, err := EcdhSharedSecret(privateKey, peerPublicKey, curveBits)
+	if err != nil {
+		return nil, err
+	}
+	return util.Hkdf(secret, salt, info, length, hashBits)
+}
@@ -0,0 +1,57 @@
+package kex
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEcdhKeyAgreement(t *testing.T) {
+	for _, bits := range []int{256, 384, 521} {
+		alicePriv, alicePub, err := EcdhGenerateKeyFromSeed(bytes.Repeat([]byte{0xa1}, 32), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobPriv, _, err := EcdhGenerateKeyFromSeed(bytes.Repeat([]byte{0xb0}, 32), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobPub, err := EcdhPublicKey(bobPriv, bits, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := EcdhSharedSecret(alicePriv, bobPub, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := EcdhSharedSecret(bobPriv, alicePub, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Fatalf("p%d: shared secrets differ: %x / %x", bits, a, b)
+		}
+	}
+}
+
+func TestEcdhErrors(t *testing.T) {
+	priv, pub, err := EcdhGenerateKeyFromSeed(make([]byte, 32), 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := EcdhGenerateKeyFromSeed(make([]byte, 16), 256); err == nil {
+		t.Fatal("expected error for short seed")
+	}
+	if _, err := EcdhSharedSecret(priv, pub, 224); err == nil || err.Error() != "unsupported ECDH curve bit length" {
+		t.Fatalf("expected unsupported curve error, got %v", err)
+	}
+	if _, err := EcdhSharedSecret(make([]byte, 32), pub, 256); err == nil {
+		t.Fatal("expected error for zero private key")
+	}
+	if _, err := EcdhSharedSecret(priv, []byte{0x00}, 256); !errors.Is(err, ErrInvalidPoint) {
+		t.Fatalf("expected ErrInvalidPoint for the point at infinity, got %v", err)
+	}
+	if _, err := EcdhSharedSecret(priv, pub[:33], 256); !errors.Is(err, ErrInvalidPoint) {
+		t.Fatalf("expected ErrInvalidPoint for a truncated point, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package kex
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Kex struct {
+		X25519 []struct {
+			Name          string
+			PrivateKey    string
+			PeerPublicKey string
+			Shared        string
+			Error         string
+		}
+		Ecdh []struct {
+			Name          string
+			CurveBits     int
+			PrivateKey    string
+			PublicKey     string
+			PeerPublicKey string
+			Shared        string
+			Error         string
+		}
+		EcdhKeyGen []struct {
+			CurveBits           int
+			Seed                string
+			PrivateKey          string
+			PublicKey           string
+			PublicKeyCompressed string
+		}
+		SharedKey []struct {
+			Name          string
+			Alg           string
+			CurveBits     int
+			PrivateKey    string
+			PeerPublicKey string
+			Salt          string
+			Info          string
+			Length        int
+			HashBits      int
+			Key           string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var kexErrors = map[string]error{
+	"lowOrder":     ErrLowOrderPoint,
+	"zeroShared":   ErrZeroSharedSecret,
+	"invalidPoint": ErrInvalidPoint,
+}
+
+// checkKexErr fails unless err matches the named vector error.
+func checkKexErr(t *testing.T, name string, err error, want string) {
+	t.Helper()
+	sentinel, ok := kexErrors[want]
+	if !ok {
+		t.Fatalf("%s: unknown error name %q", name, want)
+	}
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("%s: got error %v want %v", name, err, sentinel)
+	}
+}
+
+func TestParity_X25519(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Kex.X25519 {
+		got, err := X25519SharedSecret(mustHex(tc.PrivateKey), mustHex(tc.PeerPublicKey))
+		if tc.Error != "" {
+			checkKexErr(t, tc.Name, err, tc.Error)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if hex.EncodeToString(got) != tc.Shared {
+			t.Fatalf("%s: got %x want %s", tc.Name, got, tc.Shared)
+		}
+	}
+}
+
+func TestParity_Ecdh(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Kex.Ecdh {
+		pub, err := EcdhPublicKey(mustHex(tc.PrivateKey), tc.CurveBits, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("%s public key: got %x want %s", tc.Name, pub, tc.PublicKey)
+		}
+		got, err := EcdhSharedSecret(mustHex(tc.PrivateKey), mustHex(tc.PeerPublicKey), tc.CurveBits)
+		if tc.Error != "" {
+			checkKexErr(t, tc.Name, err, tc.Error)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if hex.EncodeToString(got) != tc.Shared {
+			t.Fatalf("%s: got %x want %s", tc.Name, got, tc.Shared)
+		}
+	}
+	for _, tc := range v.Kex.EcdhKeyGen {
+		priv, pub, err := EcdhGenerateKeyFromSeed(mustHex(tc.Seed), tc.CurveBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(priv) != tc.PrivateKey || hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("keygen p%d %s: got %x / %x want %s / %s", tc.CurveBits, tc.Seed, priv, pub, tc.PrivateKey, tc.PublicKey)
+		}
+		comp, err := EcdhPublicKey(priv, tc.CurveBits, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(comp) != tc.PublicKeyCompressed {
+			t.Fatalf("keygen p%d compressed: got %x want %s", tc.CurveBits, comp, tc.PublicKeyCompressed)
+		}
+	}
+}
+
+func TestParity_SharedKey(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Kex.SharedKey {
+		var got []byte
+		var err error
+		switch tc.Alg {
+		case "x25519":
+			got, err = X25519SharedKey(mustHex(tc.PrivateKey), mustHex(tc.PeerPublicKey), mustHex(tc.Salt), mustHex(tc.Info), tc.Length, tc.HashBits)
+		case "ecdh":
+			got, err = EcdhSharedKey(mustHex(tc.PrivateKey), mustHex(tc.PeerPublicKey), tc.CurveBits, mustHex(tc.Salt), mustHex(tc.Info), tc.Length, tc.HashBits)
+		default:
+			t.Fatalf("unknown alg %q", tc.Alg)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Key {
+			t.Fatalf("%s: got %x want %s", tc.Name, got, tc.Key)
+		}
+	}
+}
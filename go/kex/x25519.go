@@ -0,0 +1,91 @@
+package kex
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/util"
+	"golang.org/x/crypto/curve25519"
+)
+
+var (
+	ErrLowOrderPoint    = errors.New("kex: public key is a low-order point")
+	ErrZeroSharedSecret = errors.New("kex: shared secret is all zeros")
+)
+
+// x25519LowOrder lists the u-coordinates of small-order points on Curve25519 and their
+// non-canonical encodings, with the unused top bit cleared (as published by libsodium).
+var x25519LowOrder = [][32]byte{
+	{},
+	{0x01},
+	{0xe0, 0xeb, 0x7a, 0x7c, 0x3b, 0x41, 0xb8, 0xae, 0x16, 0x56, 0xe3, 0xfa, 0xf1, 0x9f, 0xc4, 0x6a, 0xda, 0x09, 0x8d, 0xeb, 0x9c, 0x32, 0xb1, 0xfd, 0x86, 0x62, 0x05, 0x16, 0x5f, 0x49, 0xb8, 0x00},
+	{0x5f, 0x9c, 0x95, 0xbc, 0xa3, 0x50, 0x8c, 0x24, 0xb1, 0xd0, 0xb1, 0x55, 0x9c, 0x83, 0xef, 0x5b, 0x04, 0x44, 0x5c, 0xc4, 0x58, 0x1c, 0x8e, 0x86, 0xd8, 0x22, 0x4e, 0xdd, 0xd0, 0x9f, 0x11, 0x57},
+	{0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	{0xed, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	{0xee, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+}
+
+// isX25519LowOrder reports whether u encodes a small-order point, ignoring the top bit.
+func isX25519LowOrder(u []byte) bool {
+	var masked [32]byte
+	copy(masked[:], u)
+	masked[31] &= 0x7f
+	found := 0
+	for i := range x25519LowOrder {
+		found |= subtle.ConstantTimeCompare(masked[:], x25519LowOrder[i][:])
+	}
+	return found == 1
+}
+
+// X25519GenerateKeyFromSeed derives an X25519 key pair from a 32-byte seed (RFC 7748).
+// The private key is the seed itself; clamping happens inside the scalar multiplication.
+func X25519GenerateKeyFromSeed(seed []byte) ([]byte, []byte, error) {
+	if len(seed) != curve25519.ScalarSize {
+		return nil, nil, errors.New("X25519GenerateKeyFromSeed: seed must be 32 bytes")
+	}
+	privateKey := make([]byte, curve25519.ScalarSize)
+	copy(privateKey, seed)
+	publicKey, err := X25519PublicKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// X25519PublicKey returns the 32-byte public u-coordinate for a 32-byte private key.
+func X25519PublicKey(privateKey []byte) ([]byte, error) {
+	if len(privateKey) != curve25519.ScalarSize {
+		return nil, errors.New("X25519PublicKey: private key must be 32 bytes")
+	}
+	return curve25519.X25519(privateKey, curve25519.Basepoint)
+}
+
+// X25519SharedSecret computes the raw 32-byte X25519 shared secret with a peer's public key.
+// Low-order peer keys fail with ErrLowOrderPoint and an all-zero result with ErrZeroSharedSecret.
+func X25519SharedSecret(privateKey []byte, peerPublicKey []byte) ([]byte, error) {
+	if len(privateKey) != curve25519.ScalarSize {
+		return nil, errors.New("X25519SharedSecret: private key must be 32 bytes")
+	}
+	if len(peerPublicKey) != curve25519.PointSize {
+		return nil, errors.New("X25519SharedSecret: public key must be 32 bytes")
+	}
+	if isX25519LowOrder(peerPublicKey) {
+		return nil, ErrLowOrderPoint
+	}
+	// curve25519.X25519 fails only when the output is all zeros.
+	secret, err := curve25519.X25519(privateKey, peerPublicKey)
+	if err != nil {
+		return nil, ErrZeroSharedSecret
+	}
+	return secret, nil
+}
+
+// X25519SharedKey runs X25519SharedSecret and passes the result through util.Hkdf
+// with salt and info, returning length bytes. hashBits selects SHA-2 as in util.Sha2Hash.
+func X25519SharedKey(privateKey []byte, peerPublicKey []byte, salt []byte, info []byte, length int, hashBits int) ([]byte, error) {
+	secret, err := X25519SharedSecret(privateKey, peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return util.Hkdf(secret, salt, info, length, hashBits)
+}
@@ -0,0 +1,76 @@
+package kex
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestX25519KeyAgreement(t *testing.T) {
+	// RFC 7748, section 6.1
+	alicePriv, alicePub, err := X25519GenerateKeyFromSeed(mustHex("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobPriv, bobPub, err := X25519GenerateKeyFromSeed(mustHex("5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(alicePub) != "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a" {
+		t.Fatalf("alice public key: got %x", alicePub)
+	}
+	a, err := X25519SharedSecret(alicePriv, bobPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := X25519SharedSecret(bobPriv, alicePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742"
+	if hex.EncodeToString(a) != want || !bytes.Equal(a, b) {
+		t.Fatalf("shared secret: got %x / %x want %s", a, b, want)
+	}
+}
+
+func TestX25519Iterated(t *testing.T) {
+	// RFC 7748, section 5.2: k and u start at the base point, then k, u = X25519(k, u), k.
+	k := mustHex("0900000000000000000000000000000000000000000000000000000000000000")
+	u := mustHex("0900000000000000000000000000000000000000000000000000000000000000")
+	for i := 1; i <= 1000; i++ {
+		out, err := X25519SharedSecret(k, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, u = out, k
+		if i == 1 && hex.EncodeToString(k) != "422c8e7a6227d7bca1350b3e2bb7279f7897b87bb6854b783c60e80311ae3079" {
+			t.Fatalf("after 1 iteration: got %x", k)
+		}
+	}
+	if hex.EncodeToString(k) != "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51" {
+		t.Fatalf("after 1000 iterations: got %x", k)
+	}
+}
+
+func TestX25519LowOrder(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x42}, 32)
+	for _, p := range x25519LowOrder {
+		for _, top := range []byte{0x00, 0x80} {
+			pub := p
+			pub[31] |= top
+			if _, err := X25519SharedSecret(priv, pub[:]); !errors.Is(err, ErrLowOrderPoint) {
+				t.Fatalf("%x: got %v want ErrLowOrderPoint", pub, err)
+			}
+		}
+	}
+}
+
+func TestX25519Lengths(t *testing.T) {
+	if _, _, err := X25519GenerateKeyFromSeed(make([]byte, 31)); err == nil {
+		t.Fatal("expected error for short seed")
+	}
+	if _, err := X25519SharedSecret(make([]byte, 32), make([]byte, 33)); err == nil {
+		t.Fatal("expected error for long public key")
+	}
+}
@@ -0,0 +1,241 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+)
+
+// pkcs8 is PrivateKeyInfo / OneAsymmetricKey (RFC 5208, RFC 5958).
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue  `asn1:"optional,tag:0"`
+	PublicKey  asn1.BitString `asn1:"optional,tag:1"`
+}
+
+// spki is SubjectPublicKeyInfo (RFC 5280).
+type spki struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// ecPrivateKey is the SEC1 ECPrivateKey structure (RFC 5915).
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// unmarshalStrict decodes der into out and rejects malformed input and trailing bytes.
+func unmarshalStrict(der []byte, out any) error {
+	rest, err := asn1.Unmarshal(der, out)
+	if err != nil || len(rest) != 0 {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
+
+// algorithmFor returns the AlgorithmIdentifier used in PKCS#8 and SPKI for curve.
+func algorithmFor(curve Curve) (pkix.AlgorithmIdentifier, error) {
+	switch curve {
+	case Ed25519:
+		return pkix.AlgorithmIdentifier{Algorithm: oidEd25519}, nil
+	case X25519:
+		return pkix.AlgorithmIdentifier{Algorithm: oidX25519}, nil
+	}
+	_, oid, err := ecParams(curve)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	params, err := asn1.Marshal(oid)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidEcPublicKey, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
+
+// curveFromAlgorithm maps a PKCS#8 or SPKI AlgorithmIdentifier to its key type.
+func curveFromAlgorithm(algo pkix.AlgorithmIdentifier) (Curve, error) {
+	switch {
+	case algo.Algorithm.Equal(oidEd25519):
+		if len(algo.Parameters.FullBytes) != 0 {
+			return "", ErrInvalidEncoding
+		}
+		return Ed25519, nil
+	case algo.Algorithm.Equal(oidX25519):
+		if len(algo.Parameters.FullBytes) != 0 {
+			return "", ErrInvalidEncoding
+		}
+		return X25519, nil
+	case algo.Algorithm.Equal(oidEcPublicKey):
+		var oid asn1.ObjectIdentifier
+		if err := unmarshalStrict(algo.Parameters.FullBytes, &oid); err != nil {
+			return "", err
+		}
+		return curveFromOID(oid)
+	default:
+		return "", ErrUnsupportedCurve
+	}
+}
+
+// marshalECPrivateKey encodes a SEC1 ECPrivateKey with the public key and, if withCurve, the curve OID.
+func marshalECPrivateKey(curve Curve, privateKey []byte, withCurve bool) ([]byte, error) {
+	_, oid, err := ecParams(curve)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := PublicKey(curve, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key := ecPrivateKey{
+		Version:    1,
+		PrivateKey: privateKey,
+		PublicKey:  asn1.BitString{Bytes: pub, BitLength: len(pub) * 8},
+	}
+	if withCurve {
+		key.NamedCurveOID = oid
+	}
+	return asn1.Marshal(key)
+}
+
+// parseECPrivateKey decodes a SEC1 ECPrivateKey. curve may be empty when the key must name its own curve.
+// An embedded curve OID must agree with curve, and an embedded public key must match the private key.
+func parseECPrivateKey(der []byte, curve Curve) (Curve, []byte, error) {
+	var key ecPrivateKey
+	if err := unmarshalStrict(der, &key); err != nil {
+		return "", nil, err
+	}
+	if key.Version != 1 {
+		return "", nil, ErrInvalidEncoding
+	}
+	if len(key.NamedCurveOID) != 0 {
+		named, err := curveFromOID(key.NamedCurveOID)
+		if err != nil {
+			return "", nil, err
+		}
+		if curve != "" && named != curve {
+			return "", nil, ErrInvalidEncoding
+		}
+		curve = named
+	}
+	if curve == "" {
+		return "", nil, ErrInvalidEncoding
+	}
+	pub, err := PublicKey(curve, key.PrivateKey)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(key.PublicKey.Bytes) != 0 {
+		embedded, err := NormalizePublicKey(curve, key.PublicKey.Bytes)
+		if err != nil || !bytes.Equal(embedded, pub) {
+			return "", nil, ErrInvalidKey
+		}
+	}
+	return curve, key.PrivateKey, nil
+}
+
+// PrivateKeyToPKCS8 encodes a raw private key as PKCS#8 DER.
+// Ed25519 and X25519 keys follow RFC 8410; EC keys embed a SEC1 ECPrivateKey with the
+// public key and without the curve OID, the same bytes WebCrypto and OpenSSL produce.
+func PrivateKeyToPKCS8(curve Curve, privateKey []byte) ([]byte, error) {
+	algo, err := algorithmFor(curve)
+	if err != nil {
+		return nil, err
+	}
+	var inner []byte
+	if isEC(curve) {
+		inner, err = marshalECPrivateKey(curve, privateKey, false)
+	} else {
+		if _, err = PublicKey(curve, privateKey); err != nil {
+			return nil, err
+		}
+		inner, err = asn1.Marshal(privateKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{Version: 0, Algo: algo, PrivateKey: inner})
+}
+
+// PrivateKeyFromPKCS8 decodes PKCS#8 DER and returns the key type and raw private key.
+// Version 1 keys with an embedded public key (RFC 5958) are accepted if the public key matches.
+func PrivateKeyFromPKCS8(der []byte) (Curve, []byte, error) {
+	var info pkcs8
+	if err := unmarshalStrict(der, &info); err != nil {
+		return "", nil, err
+	}
+	if info.Version != 0 && info.Version != 1 {
+		return "", nil, ErrInvalidEncoding
+	}
+	curve, err := curveFromAlgorithm(info.Algo)
+	if err != nil {
+		return "", nil, err
+	}
+	if isEC(curve) {
+		return parseECPrivateKey(info.PrivateKey, curve)
+	}
+	var priv []byte
+	if err := unmarshalStrict(info.PrivateKey, &priv); err != nil {
+		return "", nil, err
+	}
+	pub, err := PublicKey(curve, priv)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(info.PublicKey.Bytes) != 0 && !bytes.Equal(info.PublicKey.Bytes, pub) {
+		return "", nil, ErrInvalidKey
+	}
+	return curve, priv, nil
+}
+
+// PublicKeyToSPKI encodes a raw public key as SubjectPublicKeyInfo DER.
+// EC points are always written uncompressed.
+func PublicKeyToSPKI(curve Curve, publicKey []byte) ([]byte, error) {
+	algo, err := algorithmFor(curve)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := NormalizePublicKey(curve, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(spki{Algo: algo, PublicKey: asn1.BitString{Bytes: pub, BitLength: len(pub) * 8}})
+}
+
+// PublicKeyFromSPKI decodes SubjectPublicKeyInfo DER and returns the key type and raw public key.
+func PublicKeyFromSPKI(der []byte) (Curve, []byte, error) {
+	var info spki
+	if err := unmarshalStrict(der, &info); err != nil {
+		return "", nil, err
+	}
+	curve, err := curveFromAlgorithm(info.Algo)
+	if err != nil {
+		return "", nil, err
+	}
+	if info.PublicKey.BitLength != len(info.PublicKey.Bytes)*8 {
+		return "", nil, ErrInvalidEncoding
+	}
+	pub, err := NormalizePublicKey(curve, info.PublicKey.Bytes)
+	if err != nil {
+		return "", nil, err
+	}
+	return curve, pub, nil
+}
+
+// PrivateKeyToSEC1 encodes an EC private key as a standalone SEC1 ECPrivateKey (RFC 5915),
+// including the curve OID and the public key.
+func PrivateKeyToSEC1(curve Curve, privateKey []byte) ([]byte, error) {
+	if !isEC(curve) {
+		return nil, ErrUnsupportedCurve
+	}
+	return marshalECPrivateKey(curve, privateKey, true)
+}
+
+// PrivateKeyFromSEC1 decodes a standalone SEC1 ECPrivateKey, which must name its curve.
+func PrivateKeyFromSEC1(der []byte) (Curve, []byte, error) {
+	return parseECPrivateKey(der, "")
+}
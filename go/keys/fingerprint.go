@@ -0,0 +1,166 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/json"
+	"strings"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+const didKeyPrefix = "did:key:"
+
+// multicodecs are the unsigned-varint multicodec prefixes for raw public keys.
+var multicodecs = []struct {
+	curve  Curve
+	prefix []byte
+}{
+	{Ed25519, []byte{0xed, 0x01}},
+	{X25519, []byte{0xec, 0x01}},
+	{Secp256k1, []byte{0xe7, 0x01}},
+	{P256, []byte{0x80, 0x24}},
+	{P384, []byte{0x81, 0x24}},
+	{P521, []byte{0x82, 0x24}},
+}
+
+// thumbprintJWK holds the RFC 7638 required members in lexicographic order.
+type thumbprintJWK struct {
+	Crv string `json:"crv"`
+	Kty string `json:"kty"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+// ThumbprintInput returns the canonical JSON hashed by Thumbprint: only the required
+// public members, in lexicographic order, without whitespace.
+func (k *JWK) ThumbprintInput() ([]byte, error) {
+	curve, pub, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := PublicKeyToJWK(curve, pub)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(thumbprintJWK{Crv: canonical.Crv, Kty: canonical.Kty, X: canonical.X, Y: canonical.Y})
+}
+
+// Thumbprint computes the RFC 7638 thumbprint with SHA-2 of 256, 384 or 512 bits.
+// Private members are ignored, so a private JWK and its public JWK share a thumbprint.
+func (k *JWK) Thumbprint(hashBits int) ([]byte, error) {
+	input, err := k.ThumbprintInput()
+	if err != nil {
+		return nil, err
+	}
+	return util.Sha2Hash(input, hashBits)
+}
+
+// ThumbprintString returns Thumbprint encoded with util.EncUrlSafe, the form used for "kid".
+func (k *JWK) ThumbprintString(hashBits int) (string, error) {
+	sum, err := k.Thumbprint(hashBits)
+	if err != nil {
+		return "", err
+	}
+	return util.EncUrlSafe(sum), nil
+}
+
+// PublicKeyThumbprint returns the encoded RFC 7638 thumbprint of a raw public key.
+func PublicKeyThumbprint(curve Curve, publicKey []byte, hashBits int) (string, error) {
+	jwk, err := PublicKeyToJWK(curve, publicKey)
+	if err != nil {
+		return "", err
+	}
+	return jwk.ThumbprintString(hashBits)
+}
+
+// compressPublicKey returns the compressed SEC1 form of an EC public key.
+func compressPublicKey(curve Curve, publicKey []byte) ([]byte, error) {
+	pub, err := NormalizePublicKey(curve, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if curve == Secp256k1 {
+		x, y, _ := secp256k1.Unmarshal(pub)
+		return secp256k1.MarshalCompressed(x, y), nil
+	}
+	c, _, _ := ecParams(curve)
+	x, y := elliptic.Unmarshal(c, pub)
+	return elliptic.MarshalCompressed(c, x, y), nil
+}
+
+// Multicodec returns the multicodec-prefixed public key used by did:key.
+// EC points are compressed as the did:key method requires.
+func Multicodec(curve Curve, publicKey []byte) ([]byte, error) {
+	var pub []byte
+	var err error
+	if isEC(curve) {
+		pub, err = compressPublicKey(curve, publicKey)
+	} else {
+		pub, err = NormalizePublicKey(curve, publicKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range multicodecs {
+		if m.curve == curve {
+			return util.ConcatBytes(m.prefix, pub), nil
+		}
+	}
+	return nil, ErrUnsupportedCurve
+}
+
+// ParseMulticodec decodes a multicodec-prefixed public key and returns the key type and
+// canonical raw public key.
+func ParseMulticodec(data []byte) (Curve, []byte, error) {
+	for _, m := range multicodecs {
+		if bytes.HasPrefix(data, m.prefix) {
+			pub, err := NormalizePublicKey(m.curve, data[len(m.prefix):])
+			if err != nil {
+				return "", nil, err
+			}
+			return m.curve, pub, nil
+		}
+	}
+	return "", nil, ErrUnsupportedCurve
+}
+
+// Fingerprint returns the multibase (base58btc, 'z' prefix) encoding of Multicodec,
+// the method-specific identifier of a did:key.
+func Fingerprint(curve Curve, publicKey []byte) (string, error) {
+	data, err := Multicodec(curve, publicKey)
+	if err != nil {
+		return "", err
+	}
+	return "z" + util.EncBase58(data), nil
+}
+
+// ParseFingerprint decodes a base58btc multibase fingerprint.
+func ParseFingerprint(s string) (Curve, []byte, error) {
+	if !strings.HasPrefix(s, "z") {
+		return "", nil, ErrInvalidEncoding
+	}
+	data, err := util.DecBase58(s[1:])
+	if err != nil {
+		return "", nil, ErrInvalidEncoding
+	}
+	return ParseMulticodec(data)
+}
+
+// DidKey returns the did:key identifier for a raw public key.
+func DidKey(curve Curve, publicKey []byte) (string, error) {
+	fp, err := Fingerprint(curve, publicKey)
+	if err != nil {
+		return "", err
+	}
+	return didKeyPrefix + fp, nil
+}
+
+// ParseDidKey decodes a did:key identifier. DID URL fragments such as "#z6Mk..." are rejected.
+func ParseDidKey(s string) (Curve, []byte, error) {
+	if !strings.HasPrefix(s, didKeyPrefix) || strings.ContainsAny(s, "#?/") {
+		return "", nil, ErrInvalidEncoding
+	}
+	return ParseFingerprint(s[len(didKeyPrefix):])
+}
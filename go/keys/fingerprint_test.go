@@ -0,0 +1,53 @@
+package keys
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/sign"
+)
+
+func TestFingerprint_CompressedInput(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x05}, 32)
+	full, err := PublicKey(P256, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comp, err := sign.EcdsaPublicKey(priv, 256, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := DidKey(P256, full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DidKey(P256, comp)
+	if err != nil || a != b {
+		t.Fatalf("got %s, %v want %s", b, err, a)
+	}
+	ta, _ := PublicKeyThumbprint(P256, full, 256)
+	tb, _ := PublicKeyThumbprint(P256, comp, 256)
+	if ta != tb {
+		t.Fatalf("thumbprints differ: %s vs %s", ta, tb)
+	}
+}
+
+func TestThumbprint_IgnoresPrivateAndExtraMembers(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x03}, 32)
+	jwk, err := PrivateKeyToJWK(Ed25519, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := jwk.ThumbprintString(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withKid, err := ParseJWK([]byte(`{"kid":"k1","use":"sig","kty":"OKP","crv":"Ed25519","x":"` + jwk.X + `"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := withKid.ThumbprintString(256)
+	if err != nil || got != want {
+		t.Fatalf("got %s, %v want %s", got, err, want)
+	}
+}
@@ -0,0 +1,130 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// JWK is a JSON Web Key (RFC 7517) for the OKP (RFC 8037) and EC (RFC 7518) key types.
+// Fields are declared in lexicographic member order so Marshal output is stable.
+type JWK struct {
+	Crv string `json:"crv"`
+	D   string `json:"d,omitempty"`
+	Kty string `json:"kty"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKeyToJWK builds the public JWK for a raw public key.
+// Coordinates are fixed width and encoded with util.EncUrlSafe.
+func PublicKeyToJWK(curve Curve, publicKey []byte) (*JWK, error) {
+	if isEC(curve) {
+		x, y, err := splitPoint(curve, publicKey)
+		if err != nil {
+			return nil, err
+		}
+		return &JWK{Crv: string(curve), Kty: "EC", X: util.EncUrlSafe(x), Y: util.EncUrlSafe(y)}, nil
+	}
+	pub, err := NormalizePublicKey(curve, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &JWK{Crv: string(curve), Kty: "OKP", X: util.EncUrlSafe(pub)}, nil
+}
+
+// PrivateKeyToJWK builds the private JWK, including the public members, for a raw private key.
+func PrivateKeyToJWK(curve Curve, privateKey []byte) (*JWK, error) {
+	pub, err := PublicKey(curve, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := PublicKeyToJWK(curve, pub)
+	if err != nil {
+		return nil, err
+	}
+	jwk.D = util.EncUrlSafe(privateKey)
+	return jwk, nil
+}
+
+// ParseJWK decodes a JWK from JSON. Unknown members such as "kid" or "use" are ignored.
+func ParseJWK(data []byte) (*JWK, error) {
+	var jwk JWK
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, ErrInvalidEncoding
+	}
+	return &jwk, nil
+}
+
+// Marshal encodes the JWK as compact JSON with members in lexicographic order.
+func (k *JWK) Marshal() ([]byte, error) {
+	return json.Marshal(k)
+}
+
+// curve checks kty against crv and returns the key type.
+func (k *JWK) curve() (Curve, error) {
+	curve := Curve(k.Crv)
+	switch curve {
+	case Ed25519, X25519:
+		if k.Kty != "OKP" || k.Y != "" {
+			return "", ErrInvalidKey
+		}
+	case P256, P384, P521, Secp256k1:
+		if k.Kty != "EC" {
+			return "", ErrInvalidKey
+		}
+	default:
+		return "", ErrUnsupportedCurve
+	}
+	return curve, nil
+}
+
+// PublicKey returns the key type and raw public key of the JWK.
+func (k *JWK) PublicKey() (Curve, []byte, error) {
+	curve, err := k.curve()
+	if err != nil {
+		return "", nil, err
+	}
+	x, err := util.DecUrlSafe(k.X)
+	if err != nil {
+		return "", nil, ErrInvalidEncoding
+	}
+	if !isEC(curve) {
+		pub, err := NormalizePublicKey(curve, x)
+		if err != nil {
+			return "", nil, err
+		}
+		return curve, pub, nil
+	}
+	y, err := util.DecUrlSafe(k.Y)
+	if err != nil {
+		return "", nil, ErrInvalidEncoding
+	}
+	pub, err := joinPoint(curve, x, y)
+	if err != nil {
+		return "", nil, err
+	}
+	return curve, pub, nil
+}
+
+// PrivateKey returns the key type and raw private key of the JWK and checks that
+// the public members match the private key.
+func (k *JWK) PrivateKey() (Curve, []byte, error) {
+	curve, pub, err := k.PublicKey()
+	if err != nil {
+		return "", nil, err
+	}
+	d, err := util.DecUrlSafe(k.D)
+	if err != nil || len(d) == 0 {
+		return "", nil, ErrInvalidEncoding
+	}
+	derived, err := PublicKey(curve, d)
+	if err != nil {
+		return "", nil, err
+	}
+	if !bytes.Equal(derived, pub) {
+		return "", nil, ErrInvalidKey
+	}
+	return curve, d, nil
+}
@@ -0,0 +1,177 @@
+package keys
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/kex"
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/sign"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Curve names a key type. The values are the JWK "crv" names (RFC 7518, 8037, 8812).
+type Curve string
+
+const (
+	Ed25519   Curve = "Ed25519"
+	X25519    Curve = "X25519"
+	P256      Curve = "P-256"
+	P384      Curve = "P-384"
+	P521      Curve = "P-521"
+	Secp256k1 Curve = "secp256k1"
+)
+
+var (
+	ErrUnsupportedCurve = errors.New("keys: unsupported curve")
+	ErrInvalidKey       = errors.New("keys: invalid key")
+	ErrInvalidEncoding  = errors.New("keys: invalid encoding")
+)
+
+var (
+	oidEd25519        = asn1.ObjectIdentifier{1, 3, 101, 112}
+	oidX25519         = asn1.ObjectIdentifier{1, 3, 101, 110}
+	oidEcPublicKey    = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// isEC reports whether curve is a short Weierstrass curve encoded with SEC1 points.
+func isEC(curve Curve) bool {
+	switch curve {
+	case P256, P384, P521, Secp256k1:
+		return true
+	}
+	return false
+}
+
+// ecParams returns the elliptic.Curve and named-curve OID for an EC key type.
+func ecParams(curve Curve) (elliptic.Curve, asn1.ObjectIdentifier, error) {
+	switch curve {
+	case P256:
+		return elliptic.P256(), oidNamedCurveP256, nil
+	case P384:
+		return elliptic.P384(), oidNamedCurveP384, nil
+	case P521:
+		return elliptic.P521(), oidNamedCurveP521, nil
+	case Secp256k1:
+		return secp256k1.S256(), oidSecp256k1, nil
+	default:
+		return nil, nil, ErrUnsupportedCurve
+	}
+}
+
+// curveFromOID maps a named-curve OID back to its key type.
+func curveFromOID(oid asn1.ObjectIdentifier) (Curve, error) {
+	switch {
+	case oid.Equal(oidNamedCurveP256):
+		return P256, nil
+	case oid.Equal(oidNamedCurveP384):
+		return P384, nil
+	case oid.Equal(oidNamedCurveP521):
+		return P521, nil
+	case oid.Equal(oidSecp256k1):
+		return Secp256k1, nil
+	default:
+		return "", ErrUnsupportedCurve
+	}
+}
+
+// coordLen returns the byte width of field elements and scalars on curve.
+func coordLen(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// PublicKey derives the public key for a raw private key: 32 bytes for Ed25519 and X25519,
+// an uncompressed SEC1 point for the EC curves.
+func PublicKey(curve Curve, privateKey []byte) ([]byte, error) {
+	var pub []byte
+	var err error
+	switch curve {
+	case Ed25519:
+		_, pub, err = sign.Ed25519KeyFromSeed(privateKey)
+	case X25519:
+		pub, err = kex.X25519PublicKey(privateKey)
+	case P256:
+		pub, err = sign.EcdsaPublicKey(privateKey, 256, false)
+	case P384:
+		pub, err = sign.EcdsaPublicKey(privateKey, 384, false)
+	case P521:
+		pub, err = sign.EcdsaPublicKey(privateKey, 521, false)
+	case Secp256k1:
+		pub, err = sign.Secp256k1PublicKey(privateKey, false)
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return pub, nil
+}
+
+// NormalizePublicKey checks a raw public key and returns its canonical form:
+// the 32 bytes for Ed25519 and X25519, the uncompressed SEC1 point for the EC curves.
+// Compressed SEC1 points are accepted and expanded.
+func NormalizePublicKey(curve Curve, publicKey []byte) ([]byte, error) {
+	switch curve {
+	case Ed25519, X25519:
+		if len(publicKey) != 32 {
+			return nil, ErrInvalidKey
+		}
+		out := make([]byte, 32)
+		copy(out, publicKey)
+		return out, nil
+	case Secp256k1:
+		x, y, err := secp256k1.Unmarshal(publicKey)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		return secp256k1.Marshal(x, y), nil
+	case P256, P384, P521:
+		c, _, _ := ecParams(curve)
+		if len(publicKey) > 0 && publicKey[0] == 0x04 {
+			x, y := elliptic.Unmarshal(c, publicKey)
+			if x == nil {
+				return nil, ErrInvalidKey
+			}
+			return elliptic.Marshal(c, x, y), nil
+		}
+		x, y := elliptic.UnmarshalCompressed(c, publicKey)
+		if x == nil {
+			return nil, ErrInvalidKey
+		}
+		return elliptic.Marshal(c, x, y), nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+// splitPoint returns the fixed-width x and y coordinates of an uncompressed SEC1 point.
+func splitPoint(curve Curve, publicKey []byte) ([]byte, []byte, error) {
+	c, _, err := ecParams(curve)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := NormalizePublicKey(curve, publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	size := coordLen(c)
+	return pub[1 : 1+size], pub[1+size:], nil
+}
+
+// joinPoint builds an uncompressed SEC1 point from fixed-width coordinates and validates it.
+func joinPoint(curve Curve, x, y []byte) ([]byte, error) {
+	c, _, err := ecParams(curve)
+	if err != nil {
+		return nil, err
+	}
+	size := coordLen(c)
+	if len(x) != size || len(y) != size {
+		return nil, ErrInvalidKey
+	}
+	return NormalizePublicKey(curve, util.ConcatBytes([]byte{0x04}, x, y))
+}
@@ -0,0 +1,78 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/sign"
+)
+
+func TestNormalizePublicKey_Compressed(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x07}, 32)
+	for _, curve := range []Curve{P256, Secp256k1} {
+		full, err := PublicKey(curve, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var comp []byte
+		if curve == Secp256k1 {
+			comp, err = sign.Secp256k1PublicKey(priv, true)
+		} else {
+			comp, err = sign.EcdsaPublicKey(priv, 256, true)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NormalizePublicKey(curve, comp)
+		if err != nil || !bytes.Equal(got, full) {
+			t.Fatalf("%s: got %x, %v want %x", curve, got, err, full)
+		}
+	}
+}
+
+func TestPrivateKeyFromPKCS8_V2PublicKey(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x01}, 32)
+	pub, err := PublicKey(Ed25519, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, _ := asn1.Marshal(priv)
+	algo, _ := algorithmFor(Ed25519)
+	build := func(pub []byte) []byte {
+		der, err := asn1.Marshal(pkcs8{Version: 1, Algo: algo, PrivateKey: inner, PublicKey: asn1.BitString{Bytes: pub, BitLength: len(pub) * 8}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+	curve, got, err := PrivateKeyFromPKCS8(build(pub))
+	if err != nil || curve != Ed25519 || !bytes.Equal(got, priv) {
+		t.Fatalf("got %s %x, %v", curve, got, err)
+	}
+	wrong := bytes.Clone(pub)
+	wrong[0] ^= 1
+	if _, _, err := PrivateKeyFromPKCS8(build(wrong)); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("mismatched public key: got %v", err)
+	}
+}
+
+func TestDecodePEM_RejectsExtraData(t *testing.T) {
+	s, err := PublicKeyToPEM(X25519, bytes.Repeat([]byte{0x09}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := PublicKeyFromPEM("\n" + s + "\n"); err != nil {
+		t.Fatalf("surrounding whitespace: %v", err)
+	}
+	if _, _, err := PublicKeyFromPEM(s + s); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("two blocks: got %v", err)
+	}
+}
+
+func TestSEC1_RejectsOKP(t *testing.T) {
+	if _, err := PrivateKeyToSEC1(Ed25519, make([]byte, 32)); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Keys struct {
+		Encode []struct {
+			Name       string
+			Curve      string
+			PrivateKey string
+			PublicKey  string
+			Pkcs8      string
+			Spki       string
+			Sec1       string
+			PrivatePem string
+			PublicPem  string
+			JwkPublic  string
+			JwkPrivate string
+		}
+		Decode []struct {
+			Name   string
+			Format string
+			Input  string
+			Error  string
+		}
+		Thumbprint []struct {
+			Name       string
+			Curve      string
+			PublicKey  string
+			HashBits   int
+			Input      string
+			Thumbprint string
+		}
+		DidKey []struct {
+			Name       string
+			Curve      string
+			PublicKey  string
+			Multicodec string
+			DidKey     string
+		}
+		DidKeyDecode []struct {
+			Name   string
+			DidKey string
+			Error  string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var keysErrors = map[string]error{
+	"unsupportedCurve": ErrUnsupportedCurve,
+	"invalidKey":       ErrInvalidKey,
+	"encoding":         ErrInvalidEncoding,
+}
+
+func TestParity_Encode(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Keys.Encode {
+		curve := Curve(tc.Curve)
+		priv := mustHex(tc.PrivateKey)
+
+		pub, err := PublicKey(curve, priv)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("%s public key: got %x want %s", tc.Name, pub, tc.PublicKey)
+		}
+
+		pkcs8, err := PrivateKeyToPKCS8(curve, priv)
+		if err != nil || hex.EncodeToString(pkcs8) != tc.Pkcs8 {
+			t.Fatalf("%s pkcs8: got %x, %v want %s", tc.Name, pkcs8, err, tc.Pkcs8)
+		}
+		spki, err := PublicKeyToSPKI(curve, pub)
+		if err != nil || hex.EncodeToString(spki) != tc.Spki {
+			t.Fatalf("%s spki: got %x, %v want %s", tc.Name, spki, err, tc.Spki)
+		}
+		if tc.Sec1 != "" {
+			sec1, err := PrivateKeyToSEC1(curve, priv)
+			if err != nil || hex.EncodeToString(sec1) != tc.Sec1 {
+				t.Fatalf("%s sec1: got %x, %v want %s", tc.Name, sec1, err, tc.Sec1)
+			}
+			c, k, err := PrivateKeyFromSEC1(sec1)
+			if err != nil || c != curve || hex.EncodeToString(k) != tc.PrivateKey {
+				t.Fatalf("%s sec1 decode: got %s %x, %v", tc.Name, c, k, err)
+			}
+		}
+		privPem, err := PrivateKeyToPEM(curve, priv)
+		if err != nil || privPem != tc.PrivatePem {
+			t.Fatalf("%s private pem: got %q, %v want %q", tc.Name, privPem, err, tc.PrivatePem)
+		}
+		pubPem, err := PublicKeyToPEM(curve, pub)
+		if err != nil || pubPem != tc.PublicPem {
+			t.Fatalf("%s public pem: got %q, %v want %q", tc.Name, pubPem, err, tc.PublicPem)
+		}
+
+		jwkPub, err := PublicKeyToJWK(curve, pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := jwkPub.Marshal(); string(b) != tc.JwkPublic {
+			t.Fatalf("%s public jwk: got %s want %s", tc.Name, b, tc.JwkPublic)
+		}
+		jwkPriv, err := PrivateKeyToJWK(curve, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := jwkPriv.Marshal(); string(b) != tc.JwkPrivate {
+			t.Fatalf("%s private jwk: got %s want %s", tc.Name, b, tc.JwkPrivate)
+		}
+
+		for format, decode := range map[string]func() (Curve, []byte, error){
+			"pkcs8": func() (Curve, []byte, error) { return PrivateKeyFromPKCS8(mustHex(tc.Pkcs8)) },
+			"pem":   func() (Curve, []byte, error) { return PrivateKeyFromPEM(tc.PrivatePem) },
+			"jwk": func() (Curve, []byte, error) {
+				jwk, err := ParseJWK([]byte(tc.JwkPrivate))
+				if err != nil {
+					return "", nil, err
+				}
+				return jwk.PrivateKey()
+			},
+		} {
+			c, k, err := decode()
+			if err != nil || c != curve || hex.EncodeToString(k) != tc.PrivateKey {
+				t.Fatalf("%s %s private decode: got %s %x, %v", tc.Name, format, c, k, err)
+			}
+		}
+		for format, decode := range map[string]func() (Curve, []byte, error){
+			"spki": func() (Curve, []byte, error) { return PublicKeyFromSPKI(mustHex(tc.Spki)) },
+			"pem":  func() (Curve, []byte, error) { return PublicKeyFromPEM(tc.PublicPem) },
+			"jwk": func() (Curve, []byte, error) {
+				jwk, err := ParseJWK([]byte(tc.JwkPublic))
+				if err != nil {
+					return "", nil, err
+				}
+				return jwk.PublicKey()
+			},
+		} {
+			c, k, err := decode()
+			if err != nil || c != curve || hex.EncodeToString(k) != tc.PublicKey {
+				t.Fatalf("%s %s public decode: got %s %x, %v", tc.Name, format, c, k, err)
+			}
+		}
+	}
+}
+
+func TestParity_Decode(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Keys.Decode {
+		var err error
+		switch tc.Format {
+		case "pkcs8":
+			_, _, err = PrivateKeyFromPKCS8(mustHex(tc.Input))
+		case "spki":
+			_, _, err = PublicKeyFromSPKI(mustHex(tc.Input))
+		case "sec1":
+			_, _, err = PrivateKeyFromSEC1(mustHex(tc.Input))
+		case "pem":
+			_, _, err = PrivateKeyFromPEM(tc.Input)
+		case "jwkPublic", "jwkPrivate":
+			var jwk *JWK
+			jwk, err = ParseJWK([]byte(tc.Input))
+			if err == nil && tc.Format == "jwkPublic" {
+				_, _, err = jwk.PublicKey()
+			} else if err == nil {
+				_, _, err = jwk.PrivateKey()
+			}
+		default:
+			t.Fatalf("unknown format %q", tc.Format)
+		}
+		sentinel, ok := keysErrors[tc.Error]
+		if !ok {
+			t.Fatalf("%s: unknown error name %q", tc.Name, tc.Error)
+		}
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("%s: got error %v want %v", tc.Name, err, sentinel)
+		}
+	}
+}
+
+func TestParity_Thumbprint(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Keys.Thumbprint {
+		jwk, err := PublicKeyToJWK(Curve(tc.Curve), mustHex(tc.PublicKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		input, err := jwk.ThumbprintInput()
+		if err != nil || string(input) != tc.Input {
+			t.Fatalf("%s input: got %s, %v want %s", tc.Name, input, err, tc.Input)
+		}
+		got, err := PublicKeyThumbprint(Curve(tc.Curve), mustHex(tc.PublicKey), tc.HashBits)
+		if err != nil || got != tc.Thumbprint {
+			t.Fatalf("%s sha%d: got %s, %v want %s", tc.Name, tc.HashBits, got, err, tc.Thumbprint)
+		}
+	}
+}
+
+func TestParity_DidKey(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Keys.DidKey {
+		curve := Curve(tc.Curve)
+		mc, err := Multicodec(curve, mustHex(tc.PublicKey))
+		if err != nil || hex.EncodeToString(mc) != tc.Multicodec {
+			t.Fatalf("%s multicodec: got %x, %v want %s", tc.Name, mc, err, tc.Multicodec)
+		}
+		did, err := DidKey(curve, mustHex(tc.PublicKey))
+		if err != nil || did != tc.DidKey {
+			t.Fatalf("%s: got %s, %v want %s", tc.Name, did, err, tc.DidKey)
+		}
+		c, pub, err := ParseDidKey(tc.DidKey)
+		if err != nil || c != curve || hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("%s decode: got %s %x, %v", tc.Name, c, pub, err)
+		}
+	}
+	for _, tc := range v.Keys.DidKeyDecode {
+		_, _, err := ParseDidKey(tc.DidKey)
+		sentinel, ok := keysErrors[tc.Error]
+		if !ok {
+			t.Fatalf("%s: unknown error name %q", tc.Name, tc.Error)
+		}
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("%s: got error %v want %v", tc.Name, err, sentinel)
+		}
+	}
+}
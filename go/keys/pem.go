@@ -0,0 +1,73 @@
+package keys
+
+import (
+	"encoding/pem"
+	"strings"
+)
+
+const (
+	pemPrivateKey   = "PRIVATE KEY"
+	pemPublicKey    = "PUBLIC KEY"
+	pemECPrivateKey = "EC PRIVATE KEY"
+)
+
+// EncodePEM wraps der in a PEM block of blockType with 64-character lines and a trailing newline.
+func EncodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+// DecodePEM returns the type and contents of the single PEM block in s.
+// Headers and any data besides surrounding whitespace are rejected.
+func DecodePEM(s string) (string, []byte, error) {
+	block, rest := pem.Decode([]byte(s))
+	if block == nil || len(block.Headers) != 0 || strings.TrimSpace(string(rest)) != "" {
+		return "", nil, ErrInvalidEncoding
+	}
+	return block.Type, block.Bytes, nil
+}
+
+// PrivateKeyToPEM encodes a raw private key as a PKCS#8 "PRIVATE KEY" PEM block.
+func PrivateKeyToPEM(curve Curve, privateKey []byte) (string, error) {
+	der, err := PrivateKeyToPKCS8(curve, privateKey)
+	if err != nil {
+		return "", err
+	}
+	return EncodePEM(pemPrivateKey, der), nil
+}
+
+// PrivateKeyFromPEM decodes a "PRIVATE KEY" (PKCS#8) or "EC PRIVATE KEY" (SEC1) PEM block.
+func PrivateKeyFromPEM(s string) (Curve, []byte, error) {
+	blockType, der, err := DecodePEM(s)
+	if err != nil {
+		return "", nil, err
+	}
+	switch blockType {
+	case pemPrivateKey:
+		return PrivateKeyFromPKCS8(der)
+	case pemECPrivateKey:
+		return PrivateKeyFromSEC1(der)
+	default:
+		return "", nil, ErrInvalidEncoding
+	}
+}
+
+// PublicKeyToPEM encodes a raw public key as an SPKI "PUBLIC KEY" PEM block.
+func PublicKeyToPEM(curve Curve, publicKey []byte) (string, error) {
+	der, err := PublicKeyToSPKI(curve, publicKey)
+	if err != nil {
+		return "", err
+	}
+	return EncodePEM(pemPublicKey, der), nil
+}
+
+// PublicKeyFromPEM decodes an SPKI "PUBLIC KEY" PEM block.
+func PublicKeyFromPEM(s string) (Curve, []byte, error) {
+	blockType, der, err := DecodePEM(s)
+	if err != nil {
+		return "", nil, err
+	}
+	if blockType != pemPublicKey {
+		return "", nil, ErrInvalidEncoding
+	}
+	return PublicKeyFromSPKI(der)
+}
@@ -0,0 +1,42 @@
+package opaque
+
+import (
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// preamble is the 3DH transcript: "OPAQUEv1-" || framed(context) || framed(client_identity)
+// || ke1 || framed(server_identity) || credential_response || server_nonce ||
+// server_public_keyshare, with the identities taken from the cleartext credentials.
+func (p *params) preamble(creds *credentials, ke1 *KE1, ke2 *KE2) []byte {
+	context, _ := frame(p.context)
+	clientIdentity, _ := frame(creds.clientIdentity)
+	serverIdentity, _ := frame(creds.serverIdentity)
+	return util.ConcatBytes([]byte("OPAQUEv1-"), context, clientIdentity, ke1.serialize(), serverIdentity,
+		ke2.credentialResponse(), ke2.ServerNonce, ke2.ServerPublicKeyshare)
+}
+
+// sessionKeys are the outputs of DeriveKeys: the two MAC keys and the session key.
+type sessionKeys struct {
+	km2        []byte
+	km3        []byte
+	sessionKey []byte
+}
+
+func (p *params) deriveKeys(ikm []byte, preamble []byte) sessionKeys {
+	prk := p.extract(ikm)
+	transcript := p.hash(preamble)
+	handshakeSecret := p.deriveSecret(prk, "HandshakeSecret", transcript)
+	return sessionKeys{
+		km2:        p.deriveSecret(handshakeSecret, "ServerMAC", nil),
+		km3:        p.deriveSecret(handshakeSecret, "ClientMAC", nil),
+		sessionKey: p.deriveSecret(prk, "SessionKey", transcript),
+	}
+}
+
+// macs returns the server MAC over Hash(preamble) and the client MAC over
+// Hash(preamble || server_mac).
+func (p *params) macs(keys sessionKeys, preamble []byte) (serverMAC []byte, clientMAC []byte) {
+	serverMAC = p.mac(keys.km2, p.hash(preamble))
+	clientMAC = p.mac(keys.km3, p.hash(util.ConcatBytes(preamble, serverMAC)))
+	return serverMAC, clientMAC
+}
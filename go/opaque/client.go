@@ -0,0 +1,208 @@
+package opaque
+
+import (
+	"crypto/hmac"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/oprf"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// stage tracks a state machine: every Start and Finish runs once, in that order.
+type stage int
+
+const (
+	stageNew stage = iota
+	stageStarted
+	stageDone
+)
+
+// clientOPRF blinds the password and later unblinds the evaluation into the randomized
+// password, which both client flows share.
+type clientOPRF struct {
+	client *oprf.Client
+	state  *oprf.BlindState
+}
+
+func (c *clientOPRF) blind(p *params, ent entropy, password []byte) ([]byte, error) {
+	client, err := oprf.NewClient(p.suite, oprf.ModeOPRF, nil)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ent.blind(p.suite)
+	if err != nil {
+		return nil, err
+	}
+	st, err := client.BlindWithScalars([][]byte{password}, nil, []group.Scalar{b})
+	if err != nil {
+		return nil, err
+	}
+	c.client, c.state = client, st
+	blinded, _ := st.BlindedElements()[0].MarshalBinary()
+	return blinded, nil
+}
+
+func (c *clientOPRF) finalize(p *params, evaluatedMessage []byte) ([]byte, error) {
+	evaluated, err := p.decodeElement(evaluatedMessage)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := c.client.Finalize(c.state, []group.Element{evaluated}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.randomizedPassword(outputs[0])
+}
+
+// ClientRegistration is the client side of registration: Start sends a
+// RegistrationRequest, and Finish turns the server's response into the record to upload.
+type ClientRegistration struct {
+	p     *params
+	ent   entropy
+	stage stage
+	oprf  clientOPRF
+}
+
+// NewClientRegistration returns a registration state machine for cfg.
+func NewClientRegistration(cfg Config) (*ClientRegistration, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientRegistration{p: p, ent: systemEntropy{}}, nil
+}
+
+// Start blinds the password into a RegistrationRequest.
+func (r *ClientRegistration) Start(password []byte) (*RegistrationRequest, error) {
+	if r.stage != stageNew {
+		return nil, ErrState
+	}
+	r.stage = stageDone
+	blinded, err := r.oprf.blind(r.p, r.ent, password)
+	if err != nil {
+		return nil, err
+	}
+	r.stage = stageStarted
+	return &RegistrationRequest{BlindedMessage: blinded}, nil
+}
+
+// Finish seals the envelope and returns the RegistrationRecord to upload and the export
+// key, an application secret the server never learns. Empty identities default to the
+// public keys; the same identities must be passed at login.
+func (r *ClientRegistration) Finish(resp *RegistrationResponse, serverIdentity, clientIdentity []byte) (*RegistrationRecord, []byte, error) {
+	if r.stage != stageStarted {
+		return nil, nil, ErrState
+	}
+	r.stage = stageDone
+	if resp == nil {
+		return nil, nil, ErrInvalidMessage
+	}
+	if _, err := r.p.decodeElement(resp.ServerPublicKey); err != nil {
+		return nil, nil, err
+	}
+	randomizedPassword, err := r.oprf.finalize(r.p, resp.EvaluatedMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := r.ent.bytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.p.store(randomizedPassword, nonce, resp.ServerPublicKey, serverIdentity, clientIdentity)
+}
+
+// ClientLogin is the client side of login: Start sends KE1, and Finish checks KE2,
+// returning KE3 and the session key.
+type ClientLogin struct {
+	p        *params
+	ent      entropy
+	stage    stage
+	oprf     clientOPRF
+	ke1      *KE1
+	keyshare group.Scalar
+}
+
+// NewClientLogin returns a login state machine for cfg.
+func NewClientLogin(cfg Config) (*ClientLogin, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientLogin{p: p, ent: systemEntropy{}}, nil
+}
+
+// Start blinds the password and generates the client's ephemeral key share.
+func (l *ClientLogin) Start(password []byte) (*KE1, error) {
+	if l.stage != stageNew {
+		return nil, ErrState
+	}
+	l.stage = stageDone
+	blinded, err := l.oprf.blind(l.p, l.ent, password)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := l.ent.bytes(NonceSize)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := l.ent.bytes(SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	sk, pk, err := l.p.deriveDHKeyPair(seed)
+	if err != nil {
+		return nil, err
+	}
+	l.keyshare = sk
+	l.ke1 = &KE1{BlindedMessage: blinded, ClientNonce: nonce, ClientPublicKeyshare: pk}
+	l.stage = stageStarted
+	return l.ke1, nil
+}
+
+// Finish recovers the client's credentials from KE2 and authenticates the server. It
+// returns KE3 for the server, the session key and the export key from registration. A
+// wrong password or an unregistered user fails with ErrEnvelopeRecovery, and a server
+// that does not hold the expected private key with ErrServerAuthentication.
+func (l *ClientLogin) Finish(ke2 *KE2, serverIdentity, clientIdentity []byte) (*KE3, []byte, []byte, error) {
+	if l.stage != stageStarted {
+		return nil, nil, nil, ErrState
+	}
+	l.stage = stageDone
+	if err := ke2.check(l.p); err != nil {
+		return nil, nil, nil, err
+	}
+	randomizedPassword, err := l.oprf.finalize(l.p, ke2.EvaluatedMessage)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pad := l.p.credentialResponsePad(l.p.maskingKey(randomizedPassword), ke2.MaskingNonce)
+	unmasked := xor(pad, ke2.MaskedResponse)
+	serverPublicKey := unmasked[:l.p.npk]
+	env := Envelope{Nonce: unmasked[l.p.npk : l.p.npk+NonceSize], AuthTag: unmasked[l.p.npk+NonceSize:]}
+	clientPrivateKey, creds, exportKey, err := l.p.recover(randomizedPassword, serverPublicKey, env, serverIdentity, clientIdentity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	serverPK, err := l.p.decodeElement(serverPublicKey)
+	if err != nil {
+		return nil, nil, nil, ErrServerAuthentication
+	}
+	serverKeyshare, err := l.p.decodeElement(ke2.ServerPublicKeyshare)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ikm := util.ConcatBytes(
+		diffieHellman(l.keyshare, serverKeyshare),
+		diffieHellman(l.keyshare, serverPK),
+		diffieHellman(clientPrivateKey, serverKeyshare),
+	)
+	preamble := l.p.preamble(creds, l.ke1, ke2)
+	keys := l.p.deriveKeys(ikm, preamble)
+	serverMAC, clientMAC := l.p.macs(keys, preamble)
+	if !hmac.Equal(serverMAC, ke2.ServerMAC) {
+		return nil, nil, nil, ErrServerAuthentication
+	}
+	l.keyshare = nil
+	return &KE3{ClientMAC: clientMAC}, keys.sessionKey, exportKey, nil
+}
@@ -0,0 +1,98 @@
+package opaque
+
+import (
+	"crypto/hmac"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// credentials are the CleartextCredentials of RFC 9807: the server public key and both
+// identities, each identity defaulting to the corresponding public key.
+type credentials struct {
+	serverPublicKey []byte
+	serverIdentity  []byte
+	clientIdentity  []byte
+}
+
+func newCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity []byte) (*credentials, error) {
+	if len(serverIdentity) == 0 {
+		serverIdentity = serverPublicKey
+	}
+	if len(clientIdentity) == 0 {
+		clientIdentity = clientPublicKey
+	}
+	if len(serverIdentity) > 0xffff || len(clientIdentity) > 0xffff {
+		return nil, ErrInvalidMessage
+	}
+	return &credentials{serverPublicKey: serverPublicKey, serverIdentity: serverIdentity, clientIdentity: clientIdentity}, nil
+}
+
+func (c *credentials) serialize() []byte {
+	identities, _ := frame(c.serverIdentity, c.clientIdentity)
+	return util.ConcatBytes(c.serverPublicKey, identities)
+}
+
+// envelopeKeys are the keys Expand derives from the randomized password and envelope nonce.
+type envelopeKeys struct {
+	authKey   []byte
+	exportKey []byte
+	seed      []byte
+}
+
+func (p *params) envelopeKeys(randomizedPassword []byte, nonce []byte) envelopeKeys {
+	return envelopeKeys{
+		authKey:   p.expand(randomizedPassword, util.ConcatBytes(nonce, []byte("AuthKey")), p.nh),
+		exportKey: p.expand(randomizedPassword, util.ConcatBytes(nonce, []byte("ExportKey")), p.nh),
+		seed:      p.expand(randomizedPassword, util.ConcatBytes(nonce, []byte("PrivateKey")), SeedSize),
+	}
+}
+
+func (p *params) maskingKey(randomizedPassword []byte) []byte {
+	return p.expand(randomizedPassword, []byte("MaskingKey"), p.nh)
+}
+
+// store seals a new envelope under nonce and returns it with the record fields and the
+// export key.
+func (p *params) store(randomizedPassword, nonce, serverPublicKey, serverIdentity, clientIdentity []byte) (*RegistrationRecord, []byte, error) {
+	keys := p.envelopeKeys(randomizedPassword, nonce)
+	_, clientPublicKey, err := p.deriveDHKeyPair(keys.seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	creds, err := newCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity)
+	if err != nil {
+		return nil, nil, err
+	}
+	record := &RegistrationRecord{
+		ClientPublicKey: clientPublicKey,
+		MaskingKey:      p.maskingKey(randomizedPassword),
+		Envelope:        Envelope{Nonce: nonce, AuthTag: p.mac(keys.authKey, util.ConcatBytes(nonce, creds.serialize()))},
+	}
+	return record, keys.exportKey, nil
+}
+
+// recover opens an envelope, returning the client private key, the credentials it was
+// bound to and the export key, or ErrEnvelopeRecovery if the tag does not match.
+func (p *params) recover(randomizedPassword, serverPublicKey []byte, env Envelope, serverIdentity, clientIdentity []byte) (group.Scalar, *credentials, []byte, error) {
+	keys := p.envelopeKeys(randomizedPassword, env.Nonce)
+	clientPrivateKey, clientPublicKey, err := p.deriveDHKeyPair(keys.seed)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	creds, err := newCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	expected := p.mac(keys.authKey, util.ConcatBytes(env.Nonce, creds.serialize()))
+	if !hmac.Equal(expected, env.AuthTag) {
+		return nil, nil, nil, ErrEnvelopeRecovery
+	}
+	return clientPrivateKey, creds, keys.exportKey, nil
+}
+
+// credentialResponsePad is Expand(masking_key, masking_nonce || "CredentialResponsePad",
+// Npk + Nn + Nm), the pad that hides the server public key and envelope in KE2.
+func (p *params) credentialResponsePad(maskingKey []byte, maskingNonce []byte) []byte {
+	return p.expand(maskingKey, util.ConcatBytes(maskingNonce, []byte("CredentialResponsePad")), p.npk+NonceSize+p.nh)
+}
@@ -0,0 +1,205 @@
+package opaque
+
+import (
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// RegistrationRequest is the client's first registration message.
+type RegistrationRequest struct {
+	BlindedMessage []byte
+}
+
+// RegistrationResponse is the server's registration message.
+type RegistrationResponse struct {
+	EvaluatedMessage []byte
+	ServerPublicKey  []byte
+}
+
+// Envelope lets the client recover its private key: the nonce it was derived under and a
+// MAC over the nonce and the cleartext credentials.
+type Envelope struct {
+	Nonce   []byte
+	AuthTag []byte
+}
+
+// RegistrationRecord is what the server stores per client in place of a password hash.
+type RegistrationRecord struct {
+	ClientPublicKey []byte
+	MaskingKey      []byte
+	Envelope        Envelope
+}
+
+// KE1 is the client's first login message: a credential request and its key share.
+type KE1 struct {
+	BlindedMessage       []byte
+	ClientNonce          []byte
+	ClientPublicKeyshare []byte
+}
+
+// KE2 is the server's login response: the masked credentials, its key share and its MAC.
+type KE2 struct {
+	EvaluatedMessage     []byte
+	MaskingNonce         []byte
+	MaskedResponse       []byte
+	ServerNonce          []byte
+	ServerPublicKeyshare []byte
+	ServerMAC            []byte
+}
+
+// KE3 is the client's final login message.
+type KE3 struct {
+	ClientMAC []byte
+}
+
+// frame writes fields with util.FramedConcat and 2-byte length prefixes.
+func frame(fields ...[]byte) ([]byte, error) {
+	framed := make([]util.FramedField, len(fields))
+	for i, f := range fields {
+		framed[i] = util.FieldOf(f)
+	}
+	return util.FramedConcat(lengthPrefix, framed...)
+}
+
+// unframe splits a message written by frame into exactly n fields.
+func unframe(b []byte, n int) ([][]byte, error) {
+	fields, err := util.ParseFramed(b, lengthPrefix)
+	if err != nil || len(fields) != n {
+		return nil, ErrInvalidMessage
+	}
+	return fields, nil
+}
+
+// Bytes frames the blinded message.
+func (m *RegistrationRequest) Bytes() ([]byte, error) {
+	return frame(m.BlindedMessage)
+}
+
+// ParseRegistrationRequest decodes a message written by RegistrationRequest.Bytes. Field
+// sizes are checked by the state machine, which knows the suite.
+func ParseRegistrationRequest(b []byte) (*RegistrationRequest, error) {
+	f, err := unframe(b, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationRequest{BlindedMessage: f[0]}, nil
+}
+
+// Bytes frames the evaluated message and the server public key.
+func (m *RegistrationResponse) Bytes() ([]byte, error) {
+	return frame(m.EvaluatedMessage, m.ServerPublicKey)
+}
+
+// ParseRegistrationResponse decodes a message written by RegistrationResponse.Bytes.
+func ParseRegistrationResponse(b []byte) (*RegistrationResponse, error) {
+	f, err := unframe(b, 2)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationResponse{EvaluatedMessage: f[0], ServerPublicKey: f[1]}, nil
+}
+
+// Bytes frames the client public key, masking key, envelope nonce and auth tag.
+func (m *RegistrationRecord) Bytes() ([]byte, error) {
+	return frame(m.ClientPublicKey, m.MaskingKey, m.Envelope.Nonce, m.Envelope.AuthTag)
+}
+
+// ParseRegistrationRecord decodes a record written by RegistrationRecord.Bytes.
+func ParseRegistrationRecord(b []byte) (*RegistrationRecord, error) {
+	f, err := unframe(b, 4)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationRecord{ClientPublicKey: f[0], MaskingKey: f[1], Envelope: Envelope{Nonce: f[2], AuthTag: f[3]}}, nil
+}
+
+// Bytes frames the blinded message, client nonce and client key share.
+func (m *KE1) Bytes() ([]byte, error) {
+	return frame(m.BlindedMessage, m.ClientNonce, m.ClientPublicKeyshare)
+}
+
+// ParseKE1 decodes a message written by KE1.Bytes.
+func ParseKE1(b []byte) (*KE1, error) {
+	f, err := unframe(b, 3)
+	if err != nil {
+		return nil, err
+	}
+	return &KE1{BlindedMessage: f[0], ClientNonce: f[1], ClientPublicKeyshare: f[2]}, nil
+}
+
+// Bytes frames the six KE2 fields in RFC 9807 order.
+func (m *KE2) Bytes() ([]byte, error) {
+	return frame(m.EvaluatedMessage, m.MaskingNonce, m.MaskedResponse, m.ServerNonce, m.ServerPublicKeyshare, m.ServerMAC)
+}
+
+// ParseKE2 decodes a message written by KE2.Bytes.
+func ParseKE2(b []byte) (*KE2, error) {
+	f, err := unframe(b, 6)
+	if err != nil {
+		return nil, err
+	}
+	return &KE2{EvaluatedMessage: f[0], MaskingNonce: f[1], MaskedResponse: f[2], ServerNonce: f[3],
+		ServerPublicKeyshare: f[4], ServerMAC: f[5]}, nil
+}
+
+// Bytes frames the client MAC.
+func (m *KE3) Bytes() ([]byte, error) {
+	return frame(m.ClientMAC)
+}
+
+// ParseKE3 decodes a message written by KE3.Bytes.
+func ParseKE3(b []byte) (*KE3, error) {
+	f, err := unframe(b, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &KE3{ClientMAC: f[0]}, nil
+}
+
+// The serialize methods return the fixed-width RFC 9807 encodings that the key schedule
+// hashes; they are also what the parity vectors record.
+
+func (m *RegistrationResponse) serialize() []byte {
+	return util.ConcatBytes(m.EvaluatedMessage, m.ServerPublicKey)
+}
+
+func (m *RegistrationRecord) serialize() []byte {
+	return util.ConcatBytes(m.ClientPublicKey, m.MaskingKey, m.Envelope.Nonce, m.Envelope.AuthTag)
+}
+
+func (m *KE1) serialize() []byte {
+	return util.ConcatBytes(m.BlindedMessage, m.ClientNonce, m.ClientPublicKeyshare)
+}
+
+// credentialResponse is the CredentialResponse prefix of KE2.
+func (m *KE2) credentialResponse() []byte {
+	return util.ConcatBytes(m.EvaluatedMessage, m.MaskingNonce, m.MaskedResponse)
+}
+
+func (m *KE2) serialize() []byte {
+	return util.ConcatBytes(m.credentialResponse(), m.ServerNonce, m.ServerPublicKeyshare, m.ServerMAC)
+}
+
+// check validates the fixed field sizes of a KE1 for the suite.
+func (m *KE1) check(p *params) error {
+	if m == nil || len(m.BlindedMessage) != p.npk || len(m.ClientNonce) != NonceSize || len(m.ClientPublicKeyshare) != p.npk {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+func (m *KE2) check(p *params) error {
+	if m == nil || len(m.EvaluatedMessage) != p.npk || len(m.MaskingNonce) != NonceSize ||
+		len(m.MaskedResponse) != p.npk+NonceSize+p.nh || len(m.ServerNonce) != NonceSize ||
+		len(m.ServerPublicKeyshare) != p.npk || len(m.ServerMAC) != p.nh {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+func (m *RegistrationRecord) check(p *params) error {
+	if m == nil || len(m.ClientPublicKey) != p.npk || len(m.MaskingKey) != p.nh ||
+		len(m.Envelope.Nonce) != NonceSize || len(m.Envelope.AuthTag) != p.nh {
+		return ErrInvalidMessage
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Package opaque implements the OPAQUE-3DH asymmetric PAKE (RFC 9807) as explicit client and
+// server state machines. The server stores a registration record instead of a password hash
+// and never sees the password, not even at registration. Everything is built on the oprf
+// package, util.HkdfExtract/HkdfExpand and util.HmacSha2; messages travel in the framed-bytes
+// format with 2-byte length prefixes, while the key schedule hashes the fixed-width RFC 9807
+// encodings so that transcripts match other implementations.
+package opaque
+
+import (
+	"crypto/rand"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/oprf"
+	"github.com/grzegorzmaniak/inparity/util"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// NonceSize is Nn, the length of the envelope, masking and key exchange nonces.
+	NonceSize = 32
+	// SeedSize is Nseed, the length of the seeds behind derived key pairs.
+	SeedSize = 32
+	// lengthPrefix is the length prefix of the framed wire encodings and the preamble.
+	lengthPrefix = 2
+)
+
+var (
+	ErrUnsupportedSuite     = errors.New("opaque: unsupported suite")
+	ErrInvalidMessage       = errors.New("opaque: malformed message")
+	ErrEnvelopeRecovery     = errors.New("opaque: envelope recovery failed")
+	ErrServerAuthentication = errors.New("opaque: server authentication failed")
+	ErrClientAuthentication = errors.New("opaque: client authentication failed")
+	ErrState                = errors.New("opaque: state machine used out of order or reused")
+)
+
+// KSF is a key stretching function applied to the OPRF output, such as a memory-hard hash
+// with fixed parameters. Both sides of a deployment must use the same one.
+type KSF func(oprfOutput []byte) ([]byte, error)
+
+// IdentityKSF returns the OPRF output unchanged. RFC 9807 allows it only for testing: a
+// stolen record can then be attacked offline at the cost of one hash per guess.
+func IdentityKSF(oprfOutput []byte) ([]byte, error) {
+	return oprfOutput, nil
+}
+
+// argon2idKSF is the default KSF: Argon2id with the cost of util.Argon2idParams, the
+// all-zero 16-byte salt of RFC 9807 section 4.3.1, and an Nh-byte output.
+func argon2idKSF(nh int) KSF {
+	params := util.Argon2idParams()
+	return func(oprfOutput []byte) ([]byte, error) {
+		salt := make([]byte, params.SaltLen)
+		return argon2.IDKey(oprfOutput, salt, params.Iterations, params.Memory, uint8(params.Parallelism), uint32(nh)), nil
+	}
+}
+
+// Config is an OPAQUE-3DH configuration. Suite selects the OPRF and the group, with
+// HKDF, HMAC and SHA-2 at the suite's hash size (SHA-512 for ristretto255, SHA-256 for
+// P-256). Context is bound into every login transcript. A nil KSF is Argon2id with the
+// util.Argon2idParams cost; IdentityKSF must be chosen explicitly.
+type Config struct {
+	Suite   oprf.Suite
+	Context []byte
+	KSF     KSF
+}
+
+// params is a validated Config with the RFC 9807 sizes of its suite.
+type params struct {
+	suite    oprf.Suite
+	context  []byte
+	ksf      KSF
+	hashBits int
+	nh       int // Nh = Nm = Nx, the hash, MAC and KDF output size
+	npk      int // Npk = Noe, the encoded element size
+	nok      int // Nok, the OPRF private key size
+}
+
+func newParams(cfg Config) (*params, error) {
+	p := &params{suite: cfg.Suite, context: append([]byte(nil), cfg.Context...), ksf: cfg.KSF, nok: 32}
+	switch cfg.Suite {
+	case oprf.Ristretto255Sha512:
+		p.hashBits, p.nh, p.npk = 512, 64, group.Ristretto255.ElementSize()
+	case oprf.P256Sha256:
+		p.hashBits, p.nh, p.npk = 256, 32, group.P256.ElementSize()
+	default:
+		return nil, ErrUnsupportedSuite
+	}
+	if len(p.context) > 0xffff {
+		return nil, ErrInvalidMessage
+	}
+	if p.ksf == nil {
+		p.ksf = argon2idKSF(p.nh)
+	}
+	return p, nil
+}
+
+func (p *params) hash(msg []byte) []byte {
+	out, _ := util.Sha2Hash(msg, p.hashBits)
+	return out
+}
+
+func (p *params) mac(key []byte, msg []byte) []byte {
+	out, _ := util.HmacSha2(key, msg, p.hashBits)
+	return out
+}
+
+func (p *params) extract(ikm []byte) []byte {
+	out, _ := util.HkdfExtract(nil, ikm, p.hashBits)
+	return out
+}
+
+func (p *params) expand(prk []byte, info []byte, length int) []byte {
+	out, _ := util.HkdfExpand(prk, info, length, p.hashBits)
+	return out
+}
+
+// expandLabel is Expand(secret, I2OSP(length, 2) || I2OSP(len(label), 1) || label ||
+// I2OSP(len(context), 1) || context, length), with label prefixed by "OPAQUE-".
+func (p *params) expandLabel(secret []byte, label string, context []byte, length int) []byte {
+	full := "OPAQUE-" + label
+	info := util.ConcatBytes([]byte{byte(length >> 8), byte(length)}, []byte{byte(len(full))}, []byte(full),
+		[]byte{byte(len(context))}, context)
+	return p.expand(secret, info, length)
+}
+
+func (p *params) deriveSecret(secret []byte, label string, transcriptHash []byte) []byte {
+	return p.expandLabel(secret, label, transcriptHash, p.nh)
+}
+
+// randomizedPassword is Extract("", oprf_output || Stretch(oprf_output)).
+func (p *params) randomizedPassword(oprfOutput []byte) ([]byte, error) {
+	stretched, err := p.ksf(oprfOutput)
+	if err != nil {
+		return nil, err
+	}
+	return p.extract(util.ConcatBytes(oprfOutput, stretched)), nil
+}
+
+// deriveDHKeyPair is DeriveDiffieHellmanKeyPair: the OPRF DeriveKeyPair under the
+// "OPAQUE-DeriveDiffieHellmanKeyPair" info string.
+func (p *params) deriveDHKeyPair(seed []byte) (group.Scalar, []byte, error) {
+	sk, pk, err := oprf.DeriveKeyPair(p.suite, oprf.ModeOPRF, seed, []byte("OPAQUE-DeriveDiffieHellmanKeyPair"))
+	if err != nil {
+		return nil, nil, err
+	}
+	pkBytes, _ := pk.MarshalBinary()
+	return sk, pkBytes, nil
+}
+
+// oprfServer derives the per-client OPRF key from the server's oprf_seed.
+func (p *params) oprfServer(oprfSeed []byte, credentialIdentifier []byte) (*oprf.Server, error) {
+	seed := p.expand(oprfSeed, util.ConcatBytes(credentialIdentifier, []byte("OprfKey")), p.nok)
+	sk, _, err := oprf.DeriveKeyPair(p.suite, oprf.ModeOPRF, seed, []byte("OPAQUE-DeriveKeyPair"))
+	if err != nil {
+		return nil, err
+	}
+	return oprf.NewServer(p.suite, oprf.ModeOPRF, sk)
+}
+
+func (p *params) decodeElement(b []byte) (group.Element, error) {
+	if len(b) != p.npk {
+		return nil, ErrInvalidMessage
+	}
+	e, err := oprf.DecodeElement(p.suite, b)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return e, nil
+}
+
+// diffieHellman is SerializeElement(sk * pk).
+func diffieHellman(sk group.Scalar, pk group.Element) []byte {
+	b, _ := pk.ScalarMult(sk).MarshalBinary()
+	return b
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// entropy supplies the fresh values of a protocol run. Tests replace it with the fixed
+// inputs of the parity vectors.
+type entropy interface {
+	bytes(n int) ([]byte, error)
+	blind(suite oprf.Suite) (group.Scalar, error)
+}
+
+type systemEntropy struct{}
+
+func (systemEntropy) bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (systemEntropy) blind(suite oprf.Suite) (group.Scalar, error) {
+	sk, _, err := oprf.GenerateKeyPair(suite)
+	return sk, err
+}
+
+// GenerateServerKeys returns a fresh server key pair, encoded as a scalar and an element of
+// the suite's group, and a random oprf_seed from which every client's OPRF key is derived.
+// All three are long-term secrets except the public key, which clients may pin.
+func GenerateServerKeys(cfg Config) (privateKey []byte, publicKey []byte, oprfSeed []byte, err error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	seed, err := systemEntropy{}.bytes(SeedSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sk, pk, err := p.deriveDHKeyPair(seed)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if oprfSeed, err = (systemEntropy{}).bytes(p.nh); err != nil {
+		return nil, nil, nil, err
+	}
+	privateKey, _ = sk.MarshalBinary()
+	return privateKey, pk, oprfSeed, nil
+}
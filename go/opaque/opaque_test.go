@@ -0,0 +1,258 @@
+package opaque
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/oprf"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+var suites = []oprf.Suite{oprf.Ristretto255Sha512, oprf.P256Sha256}
+
+func newTestServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+	sk, _, seed, err := GenerateServerKeys(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(cfg, sk, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func register(t *testing.T, cfg Config, server *Server, password, credentialIdentifier []byte) (*RegistrationRecord, []byte) {
+	t.Helper()
+	reg, err := NewClientRegistration(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := reg.Start(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.RegistrationResponse(req, credentialIdentifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, exportKey, err := reg.Finish(resp, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record, exportKey
+}
+
+// login runs a login and returns the client's error from Finish, or the server's.
+func login(t *testing.T, clientCfg Config, server *Server, password []byte, record *RegistrationRecord, credentialIdentifier []byte) ([]byte, []byte, error) {
+	t.Helper()
+	client, err := NewClientLogin(clientCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke1, err := client.Start(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverLogin, ke2, err := server.LoginStart(ke1, record, credentialIdentifier, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke3, clientKey, exportKey, err := client.Finish(ke2, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	serverKey, err := serverLogin.Finish(ke3)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(clientKey, serverKey) {
+		t.Fatal("session keys differ")
+	}
+	return clientKey, exportKey, nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, suite := range suites {
+		cfg := Config{Suite: suite, Context: []byte("test")}
+		server := newTestServer(t, cfg)
+		record, exportKey := register(t, cfg, server, []byte("hunter2"), []byte("alice"))
+		key1, loginExport, err := login(t, cfg, server, []byte("hunter2"), record, []byte("alice"))
+		if err != nil {
+			t.Fatalf("%s: %v", suite, err)
+		}
+		if !bytes.Equal(exportKey, loginExport) {
+			t.Fatalf("%s: export keys differ", suite)
+		}
+		key2, _, err := login(t, cfg, server, []byte("hunter2"), record, []byte("alice"))
+		if err != nil || bytes.Equal(key1, key2) {
+			t.Fatalf("%s: second login: %v", suite, err)
+		}
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	cfg := Config{Suite: oprf.Ristretto255Sha512}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("hunter2"), []byte("alice"))
+	if _, _, err := login(t, cfg, server, []byte("hunter3"), record, []byte("alice")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("got %v want ErrEnvelopeRecovery", err)
+	}
+	if _, _, err := login(t, cfg, server, []byte("hunter2"), record, []byte("bob")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("wrong credential identifier: got %v want ErrEnvelopeRecovery", err)
+	}
+}
+
+func TestUnknownClientGetsFakeRecord(t *testing.T) {
+	cfg := Config{Suite: oprf.P256Sha256}
+	server := newTestServer(t, cfg)
+	if _, _, err := login(t, cfg, server, []byte("hunter2"), nil, []byte("nobody")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("got %v want ErrEnvelopeRecovery", err)
+	}
+}
+
+func TestConfigMismatch(t *testing.T) {
+	cfg := Config{Suite: oprf.Ristretto255Sha512, Context: []byte("app-1")}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+
+	other := cfg
+	other.Context = []byte("app-2")
+	if _, _, err := login(t, other, server, []byte("pw"), record, []byte("alice")); !errors.Is(err, ErrServerAuthentication) {
+		t.Fatalf("context: got %v want ErrServerAuthentication", err)
+	}
+	other = cfg
+	other.KSF = func(b []byte) ([]byte, error) { return util.Sha2Hash(b, 512) }
+	if _, _, err := login(t, other, server, []byte("pw"), record, []byte("alice")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("ksf: got %v want ErrEnvelopeRecovery", err)
+	}
+}
+
+func TestDefaultKSF(t *testing.T) {
+	cfg := Config{Suite: oprf.Ristretto255Sha512}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+	identity := cfg
+	identity.KSF = IdentityKSF
+	if _, _, err := login(t, identity, server, []byte("pw"), record, []byte("alice")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("nil KSF should not be the identity: got %v", err)
+	}
+	if _, _, err := login(t, cfg, server, []byte("pw"), record, []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKSF(t *testing.T) {
+	cfg := Config{Suite: oprf.P256Sha256, KSF: func(b []byte) ([]byte, error) { return util.Sha2Hash(b, 256) }}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+	if _, _, err := login(t, cfg, server, []byte("pw"), record, []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdentities(t *testing.T) {
+	cfg := Config{Suite: oprf.Ristretto255Sha512}
+	server := newTestServer(t, cfg)
+	reg, _ := NewClientRegistration(cfg)
+	req, _ := reg.Start([]byte("pw"))
+	resp, _ := server.RegistrationResponse(req, []byte("alice"))
+	record, _, err := reg.Finish(resp, []byte("example.com"), []byte("alice@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _ := NewClientLogin(cfg)
+	ke1, _ := client.Start([]byte("pw"))
+	_, ke2, err := server.LoginStart(ke1, record, []byte("alice"), []byte("example.com"), []byte("alice@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := client.Finish(ke2, []byte("example.com"), []byte("mallory@example.com")); !errors.Is(err, ErrEnvelopeRecovery) {
+		t.Fatalf("got %v want ErrEnvelopeRecovery", err)
+	}
+}
+
+func TestTampering(t *testing.T) {
+	cfg := Config{Suite: oprf.Ristretto255Sha512}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+
+	client, _ := NewClientLogin(cfg)
+	ke1, _ := client.Start([]byte("pw"))
+	serverLogin, ke2, err := server.LoginStart(ke1, record, []byte("alice"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke2.ServerNonce[0] ^= 1
+	if _, _, _, err := client.Finish(ke2, nil, nil); !errors.Is(err, ErrServerAuthentication) {
+		t.Fatalf("server nonce: got %v want ErrServerAuthentication", err)
+	}
+	if _, err := serverLogin.Finish(&KE3{ClientMAC: make([]byte, 64)}); !errors.Is(err, ErrClientAuthentication) {
+		t.Fatalf("client mac: got %v want ErrClientAuthentication", err)
+	}
+}
+
+func TestStateReuse(t *testing.T) {
+	cfg := Config{Suite: oprf.P256Sha256}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+
+	reg, _ := NewClientRegistration(cfg)
+	if _, _, err := reg.Finish(&RegistrationResponse{}, nil, nil); !errors.Is(err, ErrState) {
+		t.Fatalf("finish before start: got %v", err)
+	}
+	reg, _ = NewClientRegistration(cfg)
+	if _, err := reg.Start([]byte("pw")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Start([]byte("pw")); !errors.Is(err, ErrState) {
+		t.Fatalf("second start: got %v", err)
+	}
+
+	client, _ := NewClientLogin(cfg)
+	ke1, _ := client.Start([]byte("pw"))
+	serverLogin, ke2, err := server.LoginStart(ke1, record, []byte("alice"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke3, _, _, err := client.Finish(ke2, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := client.Finish(ke2, nil, nil); !errors.Is(err, ErrState) {
+		t.Fatalf("second client finish: got %v", err)
+	}
+	if _, err := serverLogin.Finish(ke3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := serverLogin.Finish(ke3); !errors.Is(err, ErrState) {
+		t.Fatalf("second server finish: got %v", err)
+	}
+}
+
+func TestMalformedMessages(t *testing.T) {
+	if _, err := NewClientLogin(Config{Suite: "decaf448-SHAKE256"}); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("suite: got %v", err)
+	}
+	if _, err := ParseKE1([]byte{0x00, 0x01, 0xaa}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("short KE1: got %v", err)
+	}
+	if _, err := ParseKE2([]byte{0x00, 0x05}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("truncated KE2: got %v", err)
+	}
+	cfg := Config{Suite: oprf.Ristretto255Sha512}
+	server := newTestServer(t, cfg)
+	record, _ := register(t, cfg, server, []byte("pw"), []byte("alice"))
+	ke1 := &KE1{BlindedMessage: make([]byte, 32), ClientNonce: make([]byte, NonceSize), ClientPublicKeyshare: make([]byte, 32)}
+	if _, _, err := server.LoginStart(ke1, record, []byte("alice"), nil, nil); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("identity elements: got %v", err)
+	}
+	if _, err := server.RegistrationResponse(&RegistrationRequest{BlindedMessage: []byte{1}}, nil); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("short request: got %v", err)
+	}
+	if _, err := NewServer(cfg, make([]byte, 32), make([]byte, 64)); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("zero private key: got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package opaque
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/oprf"
+)
+
+type parityVector struct {
+	Name                 string
+	Suite                oprf.Suite
+	Context              string
+	OprfSeed             string
+	CredentialIdentifier string
+	Password             string
+	ServerPrivateKey     string
+	ServerPublicKey      string
+	ServerIdentity       string
+	ClientIdentity       string
+	BlindRegistration    string
+	EnvelopeNonce        string
+	BlindLogin           string
+	ClientNonce          string
+	ClientKeyshareSeed   string
+	MaskingNonce         string
+	ServerNonce          string
+	ServerKeyshareSeed   string
+	RegistrationRequest  string
+	RegistrationResponse string
+	RegistrationRecord   string
+	KE1, KE2, KE3        string
+	SessionKey           string
+	ExportKey            string
+	Wire                 struct {
+		RegistrationRequest  string
+		RegistrationResponse string
+		RegistrationRecord   string
+		KE1, KE2, KE3        string
+	}
+}
+
+type parityVectors struct {
+	Opaque struct {
+		Vectors []parityVector
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// fixedEntropy replays the blinds, nonces and seeds of a vector in protocol order.
+type fixedEntropy struct {
+	blinds []string
+	values []string
+}
+
+func (f *fixedEntropy) bytes(n int) ([]byte, error) {
+	b := mustHex(f.values[0])
+	f.values = f.values[1:]
+	if len(b) != n {
+		panic("fixed entropy: wrong length")
+	}
+	return b, nil
+}
+
+func (f *fixedEntropy) blind(suite oprf.Suite) (group.Scalar, error) {
+	b := mustHex(f.blinds[0])
+	f.blinds = f.blinds[1:]
+	return oprf.DecodeScalar(suite, b)
+}
+
+func check(t *testing.T, name, field string, got []byte, want string) {
+	t.Helper()
+	if g := hex.EncodeToString(got); g != want {
+		t.Fatalf("%s %s: got %s want %s", name, field, g, want)
+	}
+}
+
+func mustBytes(t *testing.T, m interface{ Bytes() ([]byte, error) }) []byte {
+	t.Helper()
+	b, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestParity_Opaque(t *testing.T) {
+	for _, tc := range loadVectors(t).Opaque.Vectors {
+		cfg := Config{Suite: tc.Suite, Context: mustHex(tc.Context), KSF: IdentityKSF}
+		password, credentialIdentifier := mustHex(tc.Password), mustHex(tc.CredentialIdentifier)
+		serverIdentity, clientIdentity := mustHex(tc.ServerIdentity), mustHex(tc.ClientIdentity)
+
+		server, err := NewServer(cfg, mustHex(tc.ServerPrivateKey), mustHex(tc.OprfSeed))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "serverPublicKey", server.PublicKey(), tc.ServerPublicKey)
+
+		reg, err := NewClientRegistration(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reg.ent = &fixedEntropy{blinds: []string{tc.BlindRegistration}, values: []string{tc.EnvelopeNonce}}
+		req, err := reg.Start(password)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "registrationRequest", req.BlindedMessage, tc.RegistrationRequest)
+		check(t, tc.Name, "wire registrationRequest", mustBytes(t, req), tc.Wire.RegistrationRequest)
+		resp, err := server.RegistrationResponse(req, credentialIdentifier)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "registrationResponse", resp.serialize(), tc.RegistrationResponse)
+		check(t, tc.Name, "wire registrationResponse", mustBytes(t, resp), tc.Wire.RegistrationResponse)
+		record, exportKey, err := reg.Finish(resp, serverIdentity, clientIdentity)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "registrationRecord", record.serialize(), tc.RegistrationRecord)
+		check(t, tc.Name, "wire registrationRecord", mustBytes(t, record), tc.Wire.RegistrationRecord)
+		check(t, tc.Name, "exportKey", exportKey, tc.ExportKey)
+
+		login, err := NewClientLogin(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		login.ent = &fixedEntropy{blinds: []string{tc.BlindLogin}, values: []string{tc.ClientNonce, tc.ClientKeyshareSeed}}
+		ke1, err := login.Start(password)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "ke1", ke1.serialize(), tc.KE1)
+		check(t, tc.Name, "wire ke1", mustBytes(t, ke1), tc.Wire.KE1)
+
+		// The server side reads every message back from its wire encoding, as a TS peer sends it.
+		parsedKE1, err := ParseKE1(mustHex(tc.Wire.KE1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsedRecord, err := ParseRegistrationRecord(mustHex(tc.Wire.RegistrationRecord))
+		if err != nil {
+			t.Fatal(err)
+		}
+		server.ent = &fixedEntropy{values: []string{tc.MaskingNonce, tc.ServerNonce, tc.ServerKeyshareSeed}}
+		serverLogin, ke2, err := server.LoginStart(parsedKE1, parsedRecord, credentialIdentifier, serverIdentity, clientIdentity)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "ke2", ke2.serialize(), tc.KE2)
+		check(t, tc.Name, "wire ke2", mustBytes(t, ke2), tc.Wire.KE2)
+
+		parsedKE2, err := ParseKE2(mustHex(tc.Wire.KE2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ke3, sessionKey, loginExportKey, err := login.Finish(parsedKE2, serverIdentity, clientIdentity)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "ke3", ke3.ClientMAC, tc.KE3)
+		check(t, tc.Name, "wire ke3", mustBytes(t, ke3), tc.Wire.KE3)
+		check(t, tc.Name, "sessionKey", sessionKey, tc.SessionKey)
+		check(t, tc.Name, "login exportKey", loginExportKey, tc.ExportKey)
+
+		parsedKE3, err := ParseKE3(mustHex(tc.Wire.KE3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		serverKey, err := serverLogin.Finish(parsedKE3)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		check(t, tc.Name, "server sessionKey", serverKey, tc.SessionKey)
+	}
+}
@@ -0,0 +1,188 @@
+package opaque
+
+import (
+	"crypto/hmac"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/oprf"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Server holds the long-term server secrets: its private key and the oprf_seed from
+// which each client's OPRF key is derived. It is safe for concurrent use; each login runs
+// in its own ServerLogin.
+type Server struct {
+	p          *params
+	ent        entropy
+	privateKey group.Scalar
+	publicKey  []byte
+	oprfSeed   []byte
+}
+
+// NewServer returns a server for cfg with the keys from GenerateServerKeys.
+func NewServer(cfg Config, privateKey []byte, oprfSeed []byte) (*Server, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := oprf.DecodeScalar(p.suite, privateKey)
+	if err != nil || sk.IsZero() {
+		return nil, ErrInvalidMessage
+	}
+	if len(oprfSeed) != p.nh {
+		return nil, ErrInvalidMessage
+	}
+	pk, _ := oprf.NewServer(p.suite, oprf.ModeOPRF, sk)
+	publicKey, _ := pk.PublicKey().MarshalBinary()
+	return &Server{p: p, ent: systemEntropy{}, privateKey: sk, publicKey: publicKey, oprfSeed: append([]byte(nil), oprfSeed...)}, nil
+}
+
+// PublicKey returns the encoded server public key.
+func (s *Server) PublicKey() []byte {
+	return append([]byte(nil), s.publicKey...)
+}
+
+// evaluate runs the OPRF for credentialIdentifier on a blinded message.
+func (s *Server) evaluate(blindedMessage []byte, credentialIdentifier []byte) ([]byte, error) {
+	blinded, err := s.p.decodeElement(blindedMessage)
+	if err != nil {
+		return nil, err
+	}
+	server, err := s.p.oprfServer(s.oprfSeed, credentialIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	evaluated, _, err := server.BlindEvaluate([]group.Element{blinded}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return evaluated[0].MarshalBinary()
+}
+
+// RegistrationResponse answers a RegistrationRequest. credentialIdentifier names the
+// client's record, such as a user ID, and must be the same at login; registration keeps no
+// server state.
+func (s *Server) RegistrationResponse(req *RegistrationRequest, credentialIdentifier []byte) (*RegistrationResponse, error) {
+	if req == nil {
+		return nil, ErrInvalidMessage
+	}
+	evaluated, err := s.evaluate(req.BlindedMessage, credentialIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationResponse{EvaluatedMessage: evaluated, ServerPublicKey: s.PublicKey()}, nil
+}
+
+// fakeRecord is the record used for an unknown client, so that KE2 does not reveal whether
+// the client is registered; the login then fails at the client.
+func (s *Server) fakeRecord() (*RegistrationRecord, error) {
+	seed, err := s.ent.bytes(SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	_, clientPublicKey, err := s.p.deriveDHKeyPair(seed)
+	if err != nil {
+		return nil, err
+	}
+	maskingKey, err := s.ent.bytes(s.p.nh)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationRecord{
+		ClientPublicKey: clientPublicKey,
+		MaskingKey:      maskingKey,
+		Envelope:        Envelope{Nonce: make([]byte, NonceSize), AuthTag: make([]byte, s.p.nh)},
+	}, nil
+}
+
+// ServerLogin is the server side of one login: created by Server.LoginStart with KE2,
+// and completed once by Finish with the client's KE3.
+type ServerLogin struct {
+	stage             stage
+	expectedClientMAC []byte
+	sessionKey        []byte
+}
+
+// LoginStart answers KE1 for the client's stored record, returning the login state and
+// KE2. A nil record answers with a fake record, so that unknown clients are
+// indistinguishable until Finish. The identities must match the ones used at registration;
+// empty identities default to the public keys.
+func (s *Server) LoginStart(ke1 *KE1, record *RegistrationRecord, credentialIdentifier, serverIdentity, clientIdentity []byte) (*ServerLogin, *KE2, error) {
+	if err := ke1.check(s.p); err != nil {
+		return nil, nil, err
+	}
+	if record == nil {
+		var err error
+		if record, err = s.fakeRecord(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := record.check(s.p); err != nil {
+		return nil, nil, err
+	}
+	clientPublicKey, err := s.p.decodeElement(record.ClientPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientKeyshare, err := s.p.decodeElement(ke1.ClientPublicKeyshare)
+	if err != nil {
+		return nil, nil, err
+	}
+	creds, err := newCredentials(s.publicKey, record.ClientPublicKey, serverIdentity, clientIdentity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evaluated, err := s.evaluate(ke1.BlindedMessage, credentialIdentifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	maskingNonce, err := s.ent.bytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	pad := s.p.credentialResponsePad(record.MaskingKey, maskingNonce)
+	masked := xor(pad, util.ConcatBytes(s.publicKey, record.Envelope.Nonce, record.Envelope.AuthTag))
+
+	serverNonce, err := s.ent.bytes(NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	seed, err := s.ent.bytes(SeedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyshare, keysharePublic, err := s.p.deriveDHKeyPair(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	ke2 := &KE2{EvaluatedMessage: evaluated, MaskingNonce: maskingNonce, MaskedResponse: masked,
+		ServerNonce: serverNonce, ServerPublicKeyshare: keysharePublic}
+
+	ikm := util.ConcatBytes(
+		diffieHellman(keyshare, clientKeyshare),
+		diffieHellman(s.privateKey, clientKeyshare),
+		diffieHellman(keyshare, clientPublicKey),
+	)
+	preamble := s.p.preamble(creds, ke1, ke2)
+	keys := s.p.deriveKeys(ikm, preamble)
+	serverMAC, clientMAC := s.p.macs(keys, preamble)
+	ke2.ServerMAC = serverMAC
+	login := &ServerLogin{stage: stageStarted, expectedClientMAC: clientMAC, sessionKey: keys.sessionKey}
+	return login, ke2, nil
+}
+
+// Finish checks the client's MAC and returns the session key, or ErrClientAuthentication
+// if the client did not know the password or the record was fake.
+func (l *ServerLogin) Finish(ke3 *KE3) ([]byte, error) {
+	if l.stage != stageStarted {
+		return nil, ErrState
+	}
+	l.stage = stageDone
+	sessionKey := l.sessionKey
+	l.sessionKey = nil
+	if ke3 == nil || !hmac.Equal(ke3.ClientMAC, l.expectedClientMAC) {
+		return nil, ErrClientAuthentication
+	}
+	return sessionKey, nil
+}
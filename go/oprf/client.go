@@ -0,0 +1,130 @@
+package oprf
+
+import (
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Client blinds inputs and finalizes the server's evaluations in one mode.
+type Client struct {
+	p  *params
+	pk group.Element
+}
+
+// NewClient returns a client for the suite and mode. pk is the server's public key, used
+// to verify proofs; it is required in VOPRF and POPRF modes and ignored in OPRF mode.
+func NewClient(suite Suite, mode Mode, pk group.Element) (*Client, error) {
+	p, err := newParams(suite, mode)
+	if err != nil {
+		return nil, err
+	}
+	if mode != ModeOPRF {
+		if err := p.checkElement(pk); err != nil {
+			return nil, err
+		}
+	}
+	return &Client{p: p, pk: pk}, nil
+}
+
+// BlindState is what the client keeps between Blind and Finalize. It holds the blinds,
+// so it must stay secret and be used for one Finalize only.
+type BlindState struct {
+	inputs     [][]byte
+	info       []byte
+	blinds     []group.Scalar
+	blinded    []group.Element
+	tweakedKey group.Element
+}
+
+// BlindedElements returns the elements to send to the server, one per input.
+func (st *BlindState) BlindedElements() []group.Element {
+	return append([]group.Element(nil), st.blinded...)
+}
+
+// Blind hashes each input to the group and multiplies it by a fresh random blind. info is
+// the POPRF public input and must be empty in the other modes.
+func (c *Client) Blind(inputs [][]byte, info []byte) (*BlindState, error) {
+	blinds := make([]group.Scalar, len(inputs))
+	for i := range blinds {
+		var err error
+		if blinds[i], err = randomNonZero(c.p.g); err != nil {
+			return nil, err
+		}
+	}
+	return c.BlindWithScalars(inputs, info, blinds)
+}
+
+// BlindWithScalars is Blind with the blinds given, one per input.
+// It exists for test vectors with fixed blinds, so prefer Blind.
+func (c *Client) BlindWithScalars(inputs [][]byte, info []byte, blinds []group.Scalar) (*BlindState, error) {
+	if c.p.mode != ModePOPRF && len(info) > 0 {
+		return nil, ErrUnsupportedMode
+	}
+	if len(inputs) == 0 || len(blinds) != len(inputs) {
+		return nil, ErrInvalidInput
+	}
+	st := &BlindState{info: info, blinds: blinds, blinded: make([]group.Element, len(inputs))}
+	for i, input := range inputs {
+		if blinds[i] == nil || blinds[i].Group() != c.p.g || blinds[i].IsZero() {
+			return nil, ErrInvalidInput
+		}
+		e, err := c.p.hashToGroup(input)
+		if err != nil {
+			return nil, err
+		}
+		if e.IsIdentity() {
+			return nil, ErrInvalidInput
+		}
+		st.inputs = append(st.inputs, append([]byte(nil), input...))
+		st.blinded[i] = e.ScalarMult(blinds[i])
+	}
+	if c.p.mode == ModePOPRF {
+		framedInfo, err := framed(info)
+		if err != nil {
+			return nil, err
+		}
+		m, err := c.p.hashToScalar(util.ConcatBytes([]byte("Info"), framedInfo))
+		if err != nil {
+			return nil, err
+		}
+		st.tweakedKey = c.p.g.Generator().ScalarMult(m).Add(c.pk)
+		if st.tweakedKey.IsIdentity() {
+			return nil, ErrInvalidInput
+		}
+	}
+	return st, nil
+}
+
+// Finalize verifies the server's proof (VOPRF and POPRF modes) and unblinds each evaluated
+// element, returning one PRF output per input.
+func (c *Client) Finalize(st *BlindState, evaluated []group.Element, proof *Proof) ([][]byte, error) {
+	if st == nil || len(evaluated) != len(st.blinded) {
+		return nil, ErrInvalidInput
+	}
+	for _, e := range evaluated {
+		if err := c.p.checkElement(e); err != nil {
+			return nil, err
+		}
+	}
+	switch c.p.mode {
+	case ModeVOPRF:
+		if err := c.p.verifyProof(c.p.g.Generator(), c.pk, st.blinded, evaluated, proof); err != nil {
+			return nil, err
+		}
+	case ModePOPRF:
+		if err := c.p.verifyProof(c.p.g.Generator(), st.tweakedKey, evaluated, st.blinded, proof); err != nil {
+			return nil, err
+		}
+	}
+	outputs := make([][]byte, len(evaluated))
+	for i, e := range evaluated {
+		inv, err := st.blinds[i].Invert()
+		if err != nil {
+			return nil, ErrInvalidInput
+		}
+		if outputs[i], err = c.p.finalizeHash(st.inputs[i], st.info, e.ScalarMult(inv)); err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
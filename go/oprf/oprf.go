@@ -0,0 +1,184 @@
+// Package oprf implements oblivious pseudorandom functions (RFC 9497) in the OPRF, VOPRF
+// and POPRF modes over ristretto255 and P-256. A Client blinds its inputs, a Server
+// evaluates them with its private key without learning them, and the client unblinds and
+// hashes the result; in VOPRF and POPRF modes the server also proves, with a batched DLEQ
+// proof, that it used the key behind its public key. POPRF adds a public info string that
+// both sides bind into the evaluation.
+package oprf
+
+import (
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Mode is an RFC 9497 protocol variant.
+type Mode byte
+
+const (
+	ModeOPRF  Mode = 0x00
+	ModeVOPRF Mode = 0x01
+	ModePOPRF Mode = 0x02
+)
+
+// Suite is an RFC 9497 ciphersuite identifier.
+type Suite string
+
+const (
+	Ristretto255Sha512 Suite = "ristretto255-SHA512"
+	P256Sha256         Suite = "P256-SHA256"
+)
+
+// lengthPrefix is the I2OSP(len, 2) prefix in front of every variable-length hash input.
+const lengthPrefix = 2
+
+var (
+	ErrUnsupportedSuite = errors.New("oprf: unsupported suite")
+	ErrUnsupportedMode  = errors.New("oprf: unsupported mode")
+	ErrInvalidInput     = errors.New("oprf: input hashes to the identity or is malformed")
+	ErrDeriveKeyPair    = errors.New("oprf: no valid key derived from the seed")
+	ErrInverse          = errors.New("oprf: tweaked key has no inverse")
+	ErrVerify           = errors.New("oprf: proof verification failed")
+	ErrInvalidElement   = errors.New("oprf: invalid element encoding")
+)
+
+// params is a suite in one mode, with the contextString that separates their hashes.
+type params struct {
+	suite    Suite
+	mode     Mode
+	g        group.Group
+	hashBits int
+	context  []byte
+}
+
+func newParams(suite Suite, mode Mode) (*params, error) {
+	p := &params{suite: suite, mode: mode}
+	switch suite {
+	case Ristretto255Sha512:
+		p.g, p.hashBits = group.Ristretto255, 512
+	case P256Sha256:
+		p.g, p.hashBits = group.P256, 256
+	default:
+		return nil, ErrUnsupportedSuite
+	}
+	if mode > ModePOPRF {
+		return nil, ErrUnsupportedMode
+	}
+	// contextString = "OPRFV1-" || I2OSP(mode, 1) || "-" || identifier
+	p.context = util.ConcatBytes([]byte("OPRFV1-"), []byte{byte(mode)}, []byte("-"+string(suite)))
+	return p, nil
+}
+
+func (p *params) dst(prefix string) []byte {
+	return util.ConcatBytes([]byte(prefix), p.context)
+}
+
+func (p *params) hash(data []byte) []byte {
+	out, _ := util.Sha2Hash(data, p.hashBits)
+	return out
+}
+
+func (p *params) hashToGroup(input []byte) (group.Element, error) {
+	return p.g.HashToElement(input, p.dst("HashToGroup-"))
+}
+
+func (p *params) hashToScalar(data []byte) (group.Scalar, error) {
+	return p.g.HashToScalar(data, p.dst("HashToScalar-"))
+}
+
+// framed concatenates each part as I2OSP(len(part), 2) || part.
+func framed(parts ...[]byte) ([]byte, error) {
+	var out []byte
+	for _, part := range parts {
+		f, err := util.FramedBytesFromUint8Array(part, lengthPrefix)
+		if err != nil {
+			return nil, ErrInvalidInput
+		}
+		out = append(out, f...)
+	}
+	return out, nil
+}
+
+func serialize(e group.Element) []byte {
+	b, _ := e.MarshalBinary()
+	return b
+}
+
+// checkElement rejects an element of another group or the identity, which cannot be
+// serialized in RFC 9497.
+func (p *params) checkElement(e group.Element) error {
+	if e == nil || e.Group() != p.g || e.IsIdentity() {
+		return ErrInvalidElement
+	}
+	return nil
+}
+
+// DecodeElement parses a serialized element of the suite's group, rejecting the identity.
+func DecodeElement(suite Suite, b []byte) (group.Element, error) {
+	p, err := newParams(suite, ModeOPRF)
+	if err != nil {
+		return nil, err
+	}
+	e, err := p.g.DecodeElement(b)
+	if err != nil || e.IsIdentity() {
+		return nil, ErrInvalidElement
+	}
+	return e, nil
+}
+
+// DecodeScalar parses a serialized scalar of the suite's group, such as a private key.
+func DecodeScalar(suite Suite, b []byte) (group.Scalar, error) {
+	p, err := newParams(suite, ModeOPRF)
+	if err != nil {
+		return nil, err
+	}
+	return p.g.DecodeScalar(b)
+}
+
+// DeriveKeyPair derives a private key and its public key from a seed and public info
+// string, deterministically for the suite and mode (RFC 9497, section 3.2.1).
+func DeriveKeyPair(suite Suite, mode Mode, seed []byte, info []byte) (group.Scalar, group.Element, error) {
+	p, err := newParams(suite, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	framedInfo, err := framed(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	deriveInput := util.ConcatBytes(seed, framedInfo)
+	dst := p.dst("DeriveKeyPair")
+	for counter := 0; counter < 256; counter++ {
+		sk, err := p.g.HashToScalar(util.ConcatBytes(deriveInput, []byte{byte(counter)}), dst)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !sk.IsZero() {
+			return sk, p.g.Generator().ScalarMult(sk), nil
+		}
+	}
+	return nil, nil, ErrDeriveKeyPair
+}
+
+// GenerateKeyPair returns a random private key and its public key.
+func GenerateKeyPair(suite Suite) (group.Scalar, group.Element, error) {
+	p, err := newParams(suite, ModeOPRF)
+	if err != nil {
+		return nil, nil, err
+	}
+	sk, err := randomNonZero(p.g)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sk, p.g.Generator().ScalarMult(sk), nil
+}
+
+func randomNonZero(g group.Group) (group.Scalar, error) {
+	for {
+		s, err := g.RandomScalar()
+		if err != nil || !s.IsZero() {
+			return s, err
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package oprf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/group"
+)
+
+var (
+	suites = []Suite{Ristretto255Sha512, P256Sha256}
+	modes  = []Mode{ModeOPRF, ModeVOPRF, ModePOPRF}
+)
+
+func setup(t *testing.T, suite Suite, mode Mode) (*Client, *Server) {
+	t.Helper()
+	sk, pk, err := GenerateKeyPair(suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(suite, mode, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClient(suite, mode, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func TestRoundTrip(t *testing.T) {
+	inputs := [][]byte{[]byte("alpha"), []byte("beta"), {}}
+	for _, suite := range suites {
+		for _, mode := range modes {
+			client, server := setup(t, suite, mode)
+			var info []byte
+			if mode == ModePOPRF {
+				info = []byte("public info")
+			}
+			st, err := client.Blind(inputs, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			evaluated, proof, err := server.BlindEvaluate(st.BlindedElements(), info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outputs, err := client.Finalize(st, evaluated, proof)
+			if err != nil {
+				t.Fatalf("%s/%d: %v", suite, mode, err)
+			}
+			for i, out := range outputs {
+				direct, err := server.Evaluate(inputs[i], info)
+				if err != nil || !bytes.Equal(out, direct) {
+					t.Fatalf("%s/%d input %d: finalize and evaluate disagree", suite, mode, i)
+				}
+			}
+		}
+	}
+}
+
+func TestWrongKeyFailsVerification(t *testing.T) {
+	for _, suite := range suites {
+		for _, mode := range []Mode{ModeVOPRF, ModePOPRF} {
+			client, _ := setup(t, suite, mode)
+			_, other := setup(t, suite, mode)
+			st, err := client.Blind([][]byte{[]byte("input")}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			evaluated, proof, err := other.BlindEvaluate(st.BlindedElements(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.Finalize(st, evaluated, proof); !errors.Is(err, ErrVerify) {
+				t.Fatalf("%s/%d: got %v want ErrVerify", suite, mode, err)
+			}
+			if _, err := client.Finalize(st, evaluated, nil); !errors.Is(err, ErrVerify) {
+				t.Fatalf("%s/%d missing proof: got %v want ErrVerify", suite, mode, err)
+			}
+		}
+	}
+}
+
+func TestTamperedProof(t *testing.T) {
+	client, server := setup(t, Ristretto255Sha512, ModeVOPRF)
+	st, err := client.Blind([][]byte{[]byte("a"), []byte("b")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluated, proof, err := server.BlindEvaluate(st.BlindedElements(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluated[0], evaluated[1] = evaluated[1], evaluated[0]
+	if _, err := client.Finalize(st, evaluated, proof); !errors.Is(err, ErrVerify) {
+		t.Fatalf("swapped evaluations: got %v want ErrVerify", err)
+	}
+}
+
+func TestPOPRFInfoMismatch(t *testing.T) {
+	client, server := setup(t, P256Sha256, ModePOPRF)
+	st, err := client.Blind([][]byte{[]byte("input")}, []byte("client info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	evaluated, proof, err := server.BlindEvaluate(st.BlindedElements(), []byte("server info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Finalize(st, evaluated, proof); !errors.Is(err, ErrVerify) {
+		t.Fatalf("got %v want ErrVerify", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewClient("decaf448-SHAKE256", ModeOPRF, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("suite: got %v", err)
+	}
+	if _, _, err := DeriveKeyPair(P256Sha256, 3, nil, nil); !errors.Is(err, ErrUnsupportedMode) {
+		t.Fatalf("mode: got %v", err)
+	}
+	if _, err := NewClient(P256Sha256, ModeVOPRF, nil); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("missing key: got %v", err)
+	}
+	if _, err := NewClient(P256Sha256, ModeVOPRF, group.P256.Identity()); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("identity key: got %v", err)
+	}
+	if _, err := NewClient(P256Sha256, ModeVOPRF, group.Ristretto255.Generator()); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("wrong group key: got %v", err)
+	}
+	if _, err := DecodeElement(Ristretto255Sha512, make([]byte, 32)); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("identity encoding: got %v", err)
+	}
+	if _, err := ParseProof(P256Sha256, make([]byte, 63)); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("short proof: got %v", err)
+	}
+
+	client, server := setup(t, Ristretto255Sha512, ModeOPRF)
+	if _, err := client.Blind([][]byte{[]byte("x")}, []byte("info")); !errors.Is(err, ErrUnsupportedMode) {
+		t.Fatalf("info outside POPRF: got %v", err)
+	}
+	if _, err := client.Blind(nil, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("empty batch: got %v", err)
+	}
+	if _, _, err := server.BlindEvaluate([]group.Element{group.Ristretto255.Identity()}, nil); !errors.Is(err, ErrInvalidElement) {
+		t.Fatalf("identity blinded element: got %v", err)
+	}
+	st, err := client.Blind([][]byte{[]byte("x")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Finalize(st, nil, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("count mismatch: got %v", err)
+	}
+}
+
+func TestPOPRFInverseFailure(t *testing.T) {
+	// A key equal to -HashToScalar("Info" || I2OSP(len(info), 2) || info) makes the tweaked
+	// key zero, which the server must refuse to invert.
+	p, _ := newParams(P256Sha256, ModePOPRF)
+	info := []byte("inverse")
+	framedInfo, _ := framed(info)
+	m, err := p.hashToScalar(append([]byte("Info"), framedInfo...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(P256Sha256, ModePOPRF, m.Negate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Evaluate([]byte("x"), info); !errors.Is(err, ErrInverse) {
+		t.Fatalf("got %v want ErrInverse", err)
+	}
+}
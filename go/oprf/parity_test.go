@@ -0,0 +1,160 @@
+package oprf
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/group"
+)
+
+type parityVectors struct {
+	Oprf struct {
+		Suites []struct {
+			Suite   Suite
+			Mode    Mode
+			Seed    string
+			KeyInfo string
+			SkSm    string
+			PkSm    string
+			Vectors []struct {
+				Batch             int
+				Input             []string
+				Info              string
+				Blind             []string
+				BlindedElement    []string
+				EvaluationElement []string
+				Proof             string
+				ProofRandomScalar string
+				Output            []string
+			}
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func hexOf(e group.Element) string {
+	return hex.EncodeToString(serialize(e))
+}
+
+func TestParity_DeriveKeyPair(t *testing.T) {
+	for _, s := range loadVectors(t).Oprf.Suites {
+		sk, pk, err := DeriveKeyPair(s.Suite, s.Mode, mustHex(s.Seed), mustHex(s.KeyInfo))
+		if err != nil {
+			t.Fatalf("%s/%d: %v", s.Suite, s.Mode, err)
+		}
+		b, _ := sk.MarshalBinary()
+		if got := hex.EncodeToString(b); got != s.SkSm {
+			t.Fatalf("%s/%d skSm: got %s want %s", s.Suite, s.Mode, got, s.SkSm)
+		}
+		if s.PkSm != "" && hexOf(pk) != s.PkSm {
+			t.Fatalf("%s/%d pkSm: got %s want %s", s.Suite, s.Mode, hexOf(pk), s.PkSm)
+		}
+	}
+}
+
+func TestParity_Protocol(t *testing.T) {
+	for _, s := range loadVectors(t).Oprf.Suites {
+		sk, err := DecodeScalar(s.Suite, mustHex(s.SkSm))
+		if err != nil {
+			t.Fatal(err)
+		}
+		server, err := NewServer(s.Suite, s.Mode, sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client, err := NewClient(s.Suite, s.Mode, server.PublicKey())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, tc := range s.Vectors {
+			name := fmt.Sprintf("%s/%d/%d", s.Suite, s.Mode, i)
+			inputs := make([][]byte, tc.Batch)
+			blinds := make([]group.Scalar, tc.Batch)
+			for j := range inputs {
+				inputs[j] = mustHex(tc.Input[j])
+				if blinds[j], err = DecodeScalar(s.Suite, mustHex(tc.Blind[j])); err != nil {
+					t.Fatal(err)
+				}
+			}
+			info := mustHex(tc.Info)
+			st, err := client.BlindWithScalars(inputs, info, blinds)
+			if err != nil {
+				t.Fatalf("%s blind: %v", name, err)
+			}
+			for j, b := range st.BlindedElements() {
+				if hexOf(b) != tc.BlindedElement[j] {
+					t.Fatalf("%s blindedElement[%d]: got %s want %s", name, j, hexOf(b), tc.BlindedElement[j])
+				}
+			}
+
+			var r group.Scalar
+			if tc.ProofRandomScalar != "" {
+				if r, err = DecodeScalar(s.Suite, mustHex(tc.ProofRandomScalar)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			evaluated, proof, err := server.BlindEvaluateWithScalar(st.BlindedElements(), info, r)
+			if err != nil {
+				t.Fatalf("%s evaluate: %v", name, err)
+			}
+			for j, e := range evaluated {
+				if hexOf(e) != tc.EvaluationElement[j] {
+					t.Fatalf("%s evaluationElement[%d]: got %s want %s", name, j, hexOf(e), tc.EvaluationElement[j])
+				}
+			}
+			if tc.Proof != "" {
+				if got := hex.EncodeToString(proof.Bytes()); got != tc.Proof {
+					t.Fatalf("%s proof: got %s want %s", name, got, tc.Proof)
+				}
+				if proof, err = ParseProof(s.Suite, mustHex(tc.Proof)); err != nil {
+					t.Fatalf("%s parse proof: %v", name, err)
+				}
+			} else if proof != nil {
+				t.Fatalf("%s: unexpected proof in mode %d", name, s.Mode)
+			}
+
+			outputs, err := client.Finalize(st, evaluated, proof)
+			if err != nil {
+				t.Fatalf("%s finalize: %v", name, err)
+			}
+			for j, out := range outputs {
+				if got := hex.EncodeToString(out); got != tc.Output[j] {
+					t.Fatalf("%s output[%d]: got %s want %s", name, j, got, tc.Output[j])
+				}
+				direct, err := server.Evaluate(inputs[j], info)
+				if err != nil || hex.EncodeToString(direct) != tc.Output[j] {
+					t.Fatalf("%s Evaluate[%d]: got %x, %v", name, j, direct, err)
+				}
+			}
+		}
+	}
+}
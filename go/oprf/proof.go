@@ -0,0 +1,118 @@
+package oprf
+
+import (
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Proof is a batched DLEQ proof (RFC 9497, section 2.2) that log_A(B) = log_C(D) for every
+// pair of elements C[i], D[i]: the server's key k satisfies B = k*A and D[i] = k*C[i].
+type Proof struct {
+	C group.Scalar
+	S group.Scalar
+}
+
+// Bytes serializes the proof as SerializeScalar(C) || SerializeScalar(S).
+func (pr *Proof) Bytes() []byte {
+	c, _ := pr.C.MarshalBinary()
+	s, _ := pr.S.MarshalBinary()
+	return util.ConcatBytes(c, s)
+}
+
+// ParseProof decodes a proof written by Proof.Bytes.
+func ParseProof(suite Suite, b []byte) (*Proof, error) {
+	p, err := newParams(suite, ModeVOPRF)
+	if err != nil {
+		return nil, err
+	}
+	n := p.g.ScalarSize()
+	if len(b) != 2*n {
+		return nil, ErrInvalidInput
+	}
+	c, err := p.g.DecodeScalar(b[:n])
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	s, err := p.g.DecodeScalar(b[n:])
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	return &Proof{C: c, S: s}, nil
+}
+
+// composites folds C and D into M = sum d_i*C[i] and Z = sum d_i*D[i], with each d_i
+// hashed from a seed bound to B. With k non-nil it computes Z = k*M instead, as the prover.
+func (p *params) composites(k group.Scalar, b group.Element, c, d []group.Element) (group.Element, group.Element, error) {
+	h1, err := framed(serialize(b), p.dst("Seed-"))
+	if err != nil {
+		return nil, nil, err
+	}
+	seed := p.hash(h1)
+	m, z := p.g.Identity(), p.g.Identity()
+	for i := range c {
+		h2, err := framed(seed)
+		if err != nil {
+			return nil, nil, err
+		}
+		index, _ := util.IntToBytes(int64(i), lengthPrefix)
+		pair, err := framed(serialize(c[i]), serialize(d[i]))
+		if err != nil {
+			return nil, nil, err
+		}
+		di, err := p.hashToScalar(util.ConcatBytes(h2, index, pair, []byte("Composite")))
+		if err != nil {
+			return nil, nil, err
+		}
+		m = c[i].ScalarMult(di).Add(m)
+		if k == nil {
+			z = d[i].ScalarMult(di).Add(z)
+		}
+	}
+	if k != nil {
+		z = m.ScalarMult(k)
+	}
+	return m, z, nil
+}
+
+func (p *params) challenge(b, m, z, t2, t3 group.Element) (group.Scalar, error) {
+	transcript, err := framed(serialize(b), serialize(m), serialize(z), serialize(t2), serialize(t3))
+	if err != nil {
+		return nil, err
+	}
+	return p.hashToScalar(util.ConcatBytes(transcript, []byte("Challenge")))
+}
+
+// generateProof proves B = k*A and D[i] = k*C[i] with the random scalar r.
+func (p *params) generateProof(k group.Scalar, a, b group.Element, c, d []group.Element, r group.Scalar) (*Proof, error) {
+	m, z, err := p.composites(k, b, c, d)
+	if err != nil {
+		return nil, err
+	}
+	t2 := a.ScalarMult(r)
+	t3 := m.ScalarMult(r)
+	ch, err := p.challenge(b, m, z, t2, t3)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{C: ch, S: r.Subtract(ch.Multiply(k))}, nil
+}
+
+func (p *params) verifyProof(a, b group.Element, c, d []group.Element, pr *Proof) error {
+	if pr == nil || pr.C == nil || pr.S == nil || pr.C.Group() != p.g || pr.S.Group() != p.g {
+		return ErrVerify
+	}
+	m, z, err := p.composites(nil, b, c, d)
+	if err != nil {
+		return err
+	}
+	t2 := a.ScalarMult(pr.S).Add(b.ScalarMult(pr.C))
+	t3 := m.ScalarMult(pr.S).Add(z.ScalarMult(pr.C))
+	ch, err := p.challenge(b, m, z, t2, t3)
+	if err != nil {
+		return err
+	}
+	if !ch.Equal(pr.C) {
+		return ErrVerify
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package oprf
+
+import (
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Server holds a private key and evaluates blinded elements in one mode.
+type Server struct {
+	p  *params
+	sk group.Scalar
+	pk group.Element
+}
+
+// NewServer returns a server for the suite and mode with the private key sk, as returned
+// by DeriveKeyPair, GenerateKeyPair or DecodeScalar.
+func NewServer(suite Suite, mode Mode, sk group.Scalar) (*Server, error) {
+	p, err := newParams(suite, mode)
+	if err != nil {
+		return nil, err
+	}
+	if sk == nil || sk.Group() != p.g || sk.IsZero() {
+		return nil, ErrInvalidInput
+	}
+	return &Server{p: p, sk: sk, pk: p.g.Generator().ScalarMult(sk)}, nil
+}
+
+// PublicKey returns the public key clients use to verify proofs.
+func (s *Server) PublicKey() group.Element {
+	return s.pk
+}
+
+// tweak returns the POPRF key t = sk + HashToScalar("Info" || I2OSP(len(info), 2) || info).
+func (s *Server) tweak(info []byte) (group.Scalar, error) {
+	framedInfo, err := framed(info)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.p.hashToScalar(util.ConcatBytes([]byte("Info"), framedInfo))
+	if err != nil {
+		return nil, err
+	}
+	t := s.sk.Add(m)
+	if t.IsZero() {
+		return nil, ErrInverse
+	}
+	return t, nil
+}
+
+func (s *Server) checkInfo(info []byte) error {
+	if s.p.mode != ModePOPRF && len(info) > 0 {
+		return ErrUnsupportedMode
+	}
+	return nil
+}
+
+// BlindEvaluate evaluates the client's blinded elements. In VOPRF and POPRF modes it
+// returns a proof covering the whole batch; in OPRF mode the proof is nil. info is the
+// POPRF public input and must be empty in the other modes.
+func (s *Server) BlindEvaluate(blinded []group.Element, info []byte) ([]group.Element, *Proof, error) {
+	var r group.Scalar
+	if s.p.mode != ModeOPRF {
+		var err error
+		if r, err = s.p.g.RandomScalar(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return s.BlindEvaluateWithScalar(blinded, info, r)
+}
+
+// BlindEvaluateWithScalar is BlindEvaluate with the proof's random scalar r given.
+// It exists for test vectors with a fixed scalar, so prefer BlindEvaluate.
+func (s *Server) BlindEvaluateWithScalar(blinded []group.Element, info []byte, r group.Scalar) ([]group.Element, *Proof, error) {
+	if err := s.checkInfo(info); err != nil {
+		return nil, nil, err
+	}
+	if len(blinded) == 0 {
+		return nil, nil, ErrInvalidInput
+	}
+	for _, b := range blinded {
+		if err := s.p.checkElement(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	k, pk := s.sk, s.pk
+	if s.p.mode == ModePOPRF {
+		t, err := s.tweak(info)
+		if err != nil {
+			return nil, nil, err
+		}
+		if k, err = t.Invert(); err != nil {
+			return nil, nil, ErrInverse
+		}
+		pk = s.p.g.Generator().ScalarMult(t)
+	}
+	evaluated := make([]group.Element, len(blinded))
+	for i, b := range blinded {
+		evaluated[i] = b.ScalarMult(k)
+	}
+	switch s.p.mode {
+	case ModeVOPRF:
+		proof, err := s.p.generateProof(k, s.p.g.Generator(), pk, blinded, evaluated, r)
+		return evaluated, proof, err
+	case ModePOPRF:
+		// The tweaked key t satisfies blinded = t*evaluated and tweakedKey = t*G.
+		t, _ := k.Invert()
+		proof, err := s.p.generateProof(t, s.p.g.Generator(), pk, evaluated, blinded, r)
+		return evaluated, proof, err
+	}
+	return evaluated, nil, nil
+}
+
+// Evaluate computes the PRF output for input directly, without blinding; the result
+// equals the client's Finalize output for the same input and info.
+func (s *Server) Evaluate(input []byte, info []byte) ([]byte, error) {
+	if err := s.checkInfo(info); err != nil {
+		return nil, err
+	}
+	e, err := s.p.hashToGroup(input)
+	if err != nil {
+		return nil, err
+	}
+	if e.IsIdentity() {
+		return nil, ErrInvalidInput
+	}
+	k := s.sk
+	if s.p.mode == ModePOPRF {
+		t, err := s.tweak(info)
+		if err != nil {
+			return nil, err
+		}
+		if k, err = t.Invert(); err != nil {
+			return nil, ErrInverse
+		}
+	}
+	return s.p.finalizeHash(input, info, e.ScalarMult(k))
+}
+
+// finalizeHash is Hash(I2OSP(len(input), 2) || input [|| I2OSP(len(info), 2) || info] ||
+// I2OSP(len(element), 2) || element || "Finalize"), with info only in POPRF mode.
+func (p *params) finalizeHash(input []byte, info []byte, e group.Element) ([]byte, error) {
+	parts := [][]byte{input}
+	if p.mode == ModePOPRF {
+		parts = append(parts, info)
+	}
+	h, err := framed(append(parts, serialize(e))...)
+	if err != nil {
+		return nil, err
+	}
+	return p.hash(util.ConcatBytes(h, []byte("Finalize"))), nil
+}
@@ -0,0 +1,235 @@
+// Package ristretto255 implements the ristretto255 prime-order group (RFC 9496) on top of
+// edwards25519, using math/big. The arithmetic is not constant time.
+package ristretto255
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// EncodedSize is the length of an encoded element.
+const EncodedSize = 32
+
+var ErrInvalidEncoding = errors.New("ristretto255: invalid element encoding")
+
+var (
+	fieldP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	order  = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 252), mustDecimal("27742317777372353535851937790883648493"))
+
+	one = big.NewInt(1)
+	two = big.NewInt(2)
+
+	// edwardsD is -121665/121666, the d of edwards25519.
+	edwardsD = mul(big.NewInt(-121665), inv(big.NewInt(121666)))
+
+	sqrtM1          = mustDecimal("19681161376707505956807079304988542015446066515923890162744021073123829784752")
+	sqrtADMinusOne  = mustDecimal("25063068953384623474111414158702152701244531502492656460079210482610430750235")
+	invsqrtAMinusD  = mustDecimal("54469307008909316920995813868745141605393597292927456921205312896311721017578")
+	oneMinusDSq     = mustDecimal("1159843021668779879193775521855586647937357759715417654439879720876111806838")
+	dMinusOneSq     = mustDecimal("40440834346308536858101042469323190826248399146238708352240133220865137265952")
+	generatorX      = mustDecimal("15112221349535400772501151409588531511454012693041857206046113283949847762202")
+	generatorY      = mul(big.NewInt(4), inv(big.NewInt(5)))
+	sqrtRatioExpo   = new(big.Int).Rsh(new(big.Int).Sub(fieldP, big.NewInt(5)), 3)
+	identityElement = &Element{x: new(big.Int), y: big.NewInt(1), z: big.NewInt(1), t: new(big.Int)}
+)
+
+func mustDecimal(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("ristretto255: bad constant")
+	}
+	return v
+}
+
+// Field helpers modulo 2^255 - 19. All results are in [0, p).
+
+func mod(a *big.Int) *big.Int    { return util.BigModPos(a, fieldP) }
+func add(a, b *big.Int) *big.Int { return mod(new(big.Int).Add(a, b)) }
+func sub(a, b *big.Int) *big.Int { return mod(new(big.Int).Sub(a, b)) }
+func mul(a, b *big.Int) *big.Int { return mod(new(big.Int).Mul(a, b)) }
+func neg(a *big.Int) *big.Int    { return mod(new(big.Int).Neg(a)) }
+func inv(a *big.Int) *big.Int    { return new(big.Int).ModInverse(mod(a), fieldP) }
+func isNegative(a *big.Int) bool { return a.Bit(0) == 1 }
+func abs(a *big.Int) *big.Int {
+	if isNegative(a) {
+		return neg(a)
+	}
+	return a
+}
+
+// sqrtRatioM1 returns (true, +sqrt(u/v)) if u/v is square, and (false, +sqrt(i*u/v)) otherwise.
+func sqrtRatioM1(u, v *big.Int) (bool, *big.Int) {
+	v3 := mul(mul(v, v), v)
+	v7 := mul(mul(v3, v3), v)
+	r := mul(mul(u, v3), new(big.Int).Exp(mul(u, v7), sqrtRatioExpo, fieldP))
+	check := mul(v, mul(r, r))
+	correctSign := check.Cmp(mod(u)) == 0
+	flippedSign := check.Cmp(neg(u)) == 0
+	flippedSignI := check.Cmp(neg(mul(u, sqrtM1))) == 0
+	if flippedSign || flippedSignI {
+		r = mul(r, sqrtM1)
+	}
+	return correctSign || flippedSign, abs(r)
+}
+
+// Element is a ristretto255 group element, held as an edwards25519 point in extended
+// coordinates (X:Y:Z:T) with x = X/Z, y = Y/Z and xy = T/Z.
+type Element struct {
+	x, y, z, t *big.Int
+}
+
+// Order returns the group order 2^252 + 27742317777372353535851937790883648493.
+func Order() *big.Int {
+	return new(big.Int).Set(order)
+}
+
+// Identity returns the identity element.
+func Identity() *Element {
+	return identityElement
+}
+
+// Generator returns the canonical generator, the edwards25519 base point.
+func Generator() *Element {
+	return &Element{x: generatorX, y: generatorY, z: one, t: mul(generatorX, generatorY)}
+}
+
+// Add returns e + o using the complete extended-coordinate formula for a = -1.
+func (e *Element) Add(o *Element) *Element {
+	a := mul(sub(e.y, e.x), sub(o.y, o.x))
+	b := mul(add(e.y, e.x), add(o.y, o.x))
+	c := mul(mul(e.t, o.t), mul(two, edwardsD))
+	d := mul(mul(e.z, o.z), two)
+	ee, f, g, h := sub(b, a), sub(d, c), add(d, c), add(b, a)
+	return &Element{x: mul(ee, f), y: mul(g, h), z: mul(f, g), t: mul(ee, h)}
+}
+
+// Negate returns -e.
+func (e *Element) Negate() *Element {
+	return &Element{x: neg(e.x), y: e.y, z: e.z, t: neg(e.t)}
+}
+
+// ScalarMult returns k*e. k is reduced modulo the group order.
+func (e *Element) ScalarMult(k *big.Int) *Element {
+	k = util.BigModPos(k, order)
+	acc := Identity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		acc = acc.Add(acc)
+		if k.Bit(i) == 1 {
+			acc = acc.Add(e)
+		}
+	}
+	return acc
+}
+
+// ScalarBaseMult returns k*G for the generator G.
+func ScalarBaseMult(k *big.Int) *Element {
+	return Generator().ScalarMult(k)
+}
+
+// Equal reports whether e and o are the same group element. Different edwards25519
+// points in the same coset compare equal.
+func (e *Element) Equal(o *Element) bool {
+	return mul(e.x, o.y).Cmp(mul(e.y, o.x)) == 0 || mul(e.y, o.y).Cmp(mul(e.x, o.x)) == 0
+}
+
+// Bytes returns the canonical 32-byte encoding of e.
+func (e *Element) Bytes() []byte {
+	u1 := mul(add(e.z, e.y), sub(e.z, e.y))
+	u2 := mul(e.x, e.y)
+	_, invsqrt := sqrtRatioM1(one, mul(u1, mul(u2, u2)))
+	den1 := mul(invsqrt, u1)
+	den2 := mul(invsqrt, u2)
+	zInv := mul(mul(den1, den2), e.t)
+
+	x, y, denInv := e.x, e.y, den2
+	if isNegative(mul(e.t, zInv)) {
+		x, y = mul(e.y, sqrtM1), mul(e.x, sqrtM1)
+		denInv = mul(den1, invsqrtAMinusD)
+	}
+	if isNegative(mul(x, zInv)) {
+		y = neg(y)
+	}
+	s := abs(mul(denInv, sub(e.z, y)))
+	return littleEndian(s)
+}
+
+// littleEndian returns v as 32 little-endian bytes.
+func littleEndian(v *big.Int) []byte {
+	out := make([]byte, EncodedSize)
+	v.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// fromLittleEndian reads b as a little-endian integer.
+func fromLittleEndian(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return util.BytesToBigInt(be)
+}
+
+// Decode parses a canonical 32-byte encoding, rejecting every non-canonical form.
+func Decode(b []byte) (*Element, error) {
+	if len(b) != EncodedSize {
+		return nil, ErrInvalidEncoding
+	}
+	s := fromLittleEndian(b)
+	if s.Cmp(fieldP) >= 0 || isNegative(s) {
+		return nil, ErrInvalidEncoding
+	}
+	ss := mul(s, s)
+	u1 := sub(one, ss)
+	u2 := add(one, ss)
+	u2Sqr := mul(u2, u2)
+	v := sub(neg(mul(edwardsD, mul(u1, u1))), u2Sqr)
+	wasSquare, invsqrt := sqrtRatioM1(one, mul(v, u2Sqr))
+	denX := mul(invsqrt, u2)
+	denY := mul(mul(invsqrt, denX), v)
+	x := abs(mul(mul(two, s), denX))
+	y := mul(u1, denY)
+	t := mul(x, y)
+	if !wasSquare || isNegative(t) || y.Sign() == 0 {
+		return nil, ErrInvalidEncoding
+	}
+	return &Element{x: x, y: y, z: one, t: t}, nil
+}
+
+// mapToPoint is the ristretto255 Elligator map from a field element.
+func mapToPoint(t *big.Int) *Element {
+	r := mul(sqrtM1, mul(t, t))
+	u := mul(add(r, one), oneMinusDSq)
+	v := mul(sub(neg(one), mul(r, edwardsD)), add(r, edwardsD))
+	wasSquare, s := sqrtRatioM1(u, v)
+	c := neg(one)
+	if !wasSquare {
+		s = neg(abs(mul(s, t)))
+		c = r
+	}
+	n := sub(mul(mul(c, sub(r, one)), dMinusOneSq), v)
+	ss := mul(s, s)
+	w0 := mul(mul(two, s), v)
+	w1 := mul(n, sqrtADMinusOne)
+	w2 := sub(one, ss)
+	w3 := add(one, ss)
+	return &Element{x: mul(w0, w3), y: mul(w2, w1), z: mul(w1, w3), t: mul(w0, w2)}
+}
+
+// FromUniformBytes maps 64 uniformly random bytes to an element with no known discrete
+// logarithm, as the one-way map of RFC 9496.
+func FromUniformBytes(b []byte) (*Element, error) {
+	if len(b) != 64 {
+		return nil, ErrInvalidEncoding
+	}
+	half := func(h []byte) *big.Int {
+		c := append([]byte(nil), h...)
+		c[31] &= 0x7f
+		return mod(fromLittleEndian(c))
+	}
+	return mapToPoint(half(b[:32])).Add(mapToPoint(half(b[32:]))), nil
+}
@@ -0,0 +1,75 @@
+package ristretto255
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// Multiples of the generator from RFC 9496, Appendix A.1.
+var generatorMultiples = []string{
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"e2f2ae0a6abc4e71a884a961c500515f58e30b6aa582dd8db6a65945e08d2d76",
+	"6a493210f7499cd17fecb510ae0cea23a110e8d5b901f8acadd3095c73a3b919",
+	"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	for i, want := range generatorMultiples {
+		got := ScalarBaseMult(big.NewInt(int64(i)))
+		if hex.EncodeToString(got.Bytes()) != want {
+			t.Fatalf("%d*G: got %x want %s", i, got.Bytes(), want)
+		}
+		b, _ := hex.DecodeString(want)
+		dec, err := Decode(b)
+		if err != nil || !dec.Equal(got) {
+			t.Fatalf("%d*G decode: %v", i, err)
+		}
+	}
+	if !ScalarBaseMult(Order()).Equal(Identity()) {
+		t.Fatal("order*G is not the identity")
+	}
+}
+
+func TestAddNegate(t *testing.T) {
+	g := Generator()
+	if !g.Add(g).Equal(ScalarBaseMult(big.NewInt(2))) {
+		t.Fatal("G+G != 2G")
+	}
+	if !g.Add(g.Negate()).Equal(Identity()) {
+		t.Fatal("G + -G != 0")
+	}
+}
+
+func TestDecodeRejectsNonCanonical(t *testing.T) {
+	// Selected bad encodings from RFC 9496, Appendix A.2.
+	for _, enc := range []string{
+		"00ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", // non-canonical field element
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f", // p itself
+		"0100000000000000000000000000000000000000000000000000000000000000", // negative s
+		"26948d35ca62e643e26a83177332e6b6afeb9d08e4268b650f1f5bbd8d81d371", // non-square
+		"0000000000000000000000000000000000000000000000000000000000000080", // top bit set
+		"00",
+	} {
+		b, _ := hex.DecodeString(enc)
+		if _, err := Decode(b); !errors.Is(err, ErrInvalidEncoding) {
+			t.Fatalf("%s: got %v", enc, err)
+		}
+	}
+}
+
+func TestFromUniformBytes(t *testing.T) {
+	// RFC 9496, Appendix A.3.
+	in, _ := hex.DecodeString("5d1be09e3d0c82fc538112490e35701979d99e06ca3e2b5b54bffe8b4dc772c14d98b696a1bbfb5ca32c436cc61c16563790306c79eaca7705668b47dffe5bb6")
+	e, err := FromUniformBytes(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(e.Bytes()); got != "3066f82a1a747d45120d1740f14358531a8f04bbffe6a819f86dfe50f44a0a46" {
+		t.Fatalf("got %s", got)
+	}
+	if _, err := FromUniformBytes(in[:32]); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("short input: got %v", err)
+	}
+}
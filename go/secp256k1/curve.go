@@ -0,0 +1,247 @@
+package secp256k1
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Curve is the secp256k1 curve y² = x³ + 7 (SEC 2) implemented on math/big.
+// It satisfies elliptic.Curve, with (0, 0) standing for the point at infinity.
+// The arithmetic is not constant time.
+type Curve struct {
+	params *elliptic.CurveParams
+}
+
+var secp256k1 *Curve
+
+func init() {
+	p := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+	p.P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	p.N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	p.B = big.NewInt(7)
+	p.Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	p.Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	secp256k1 = &Curve{params: p}
+}
+
+// S256 returns the secp256k1 curve.
+func S256() *Curve {
+	return secp256k1
+}
+
+// Params returns the curve parameters. Note that A is 0 for secp256k1, unlike the
+// a = -3 assumed by the generic elliptic.CurveParams methods.
+func (c *Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// IsOnCurve reports whether (x, y) is a point on the curve with coordinates in [0, p).
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x.Sign() < 0 || y.Sign() < 0 || util.BigCmp(x, p) >= 0 || util.BigCmp(y, p) >= 0 {
+		return false
+	}
+	y2 := util.BigModPos(new(big.Int).Mul(y, y), p)
+	return y2.Cmp(c.rhs(x)) == 0
+}
+
+// rhs returns x³ + 7 mod p.
+func (c *Curve) rhs(x *big.Int) *big.Int {
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, c.params.B)
+	return util.BigModPos(x3, c.params.P)
+}
+
+// DecompressY returns the y coordinate for x with the requested parity.
+func (c *Curve) DecompressY(x *big.Int, odd bool) (*big.Int, error) {
+	p := c.params.P
+	if x.Sign() < 0 || util.BigCmp(x, p) >= 0 {
+		return nil, errors.New("secp256k1: x coordinate out of range")
+	}
+	y2 := c.rhs(x)
+	// p ≡ 3 (mod 4), so a square root is y2^((p+1)/4)
+	e := new(big.Int).Add(p, big.NewInt(1))
+	e.Rsh(e, 2)
+	y := new(big.Int).Exp(y2, e, p)
+	if util.BigModPos(new(big.Int).Mul(y, y), p).Cmp(y2) != 0 {
+		return nil, errors.New("secp256k1: x coordinate is not on the curve")
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(p, y)
+	}
+	return y, nil
+}
+
+// jacobian is a point in Jacobian coordinates (X/Z², Y/Z³); Z = 0 is infinity.
+type jacobian struct {
+	x, y, z *big.Int
+}
+
+func (c *Curve) toJacobian(x, y *big.Int) jacobian {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return jacobian{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (c *Curve) toAffine(j jacobian) (*big.Int, *big.Int) {
+	if j.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	p := c.params.P
+	zInv := new(big.Int).ModInverse(j.z, p)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x := util.BigModPos(new(big.Int).Mul(j.x, zInv2), p)
+	zInv2.Mul(zInv2, zInv)
+	y := util.BigModPos(new(big.Int).Mul(j.y, zInv2), p)
+	return x, y
+}
+
+func (c *Curve) double(a jacobian) jacobian {
+	p := c.params.P
+	if a.z.Sign() == 0 || a.y.Sign() == 0 {
+		return jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	y2 := util.BigModPos(new(big.Int).Mul(a.y, a.y), p)
+	s := new(big.Int).Mul(a.x, y2)
+	s.Lsh(s, 2)
+	s = util.BigModPos(s, p)
+	m := new(big.Int).Mul(a.x, a.x)
+	m.Mul(m, big.NewInt(3))
+	m = util.BigModPos(m, p)
+
+	x3 := new(big.Int).Mul(m, m)
+	x3.Sub(x3, new(big.Int).Lsh(s, 1))
+	x3 = util.BigModPos(x3, p)
+
+	y4 := new(big.Int).Mul(y2, y2)
+	y4.Lsh(y4, 3)
+	y3 := new(big.Int).Sub(s, x3)
+	y3.Mul(y3, m)
+	y3.Sub(y3, y4)
+	y3 = util.BigModPos(y3, p)
+
+	z3 := new(big.Int).Mul(a.y, a.z)
+	z3.Lsh(z3, 1)
+	z3 = util.BigModPos(z3, p)
+	return jacobian{x3, y3, z3}
+}
+
+func (c *Curve) add(a, b jacobian) jacobian {
+	if a.z.Sign() == 0 {
+		return b
+	}
+	if b.z.Sign() == 0 {
+		return a
+	}
+	p := c.params.P
+	z1z1 := util.BigModPos(new(big.Int).Mul(a.z, a.z), p)
+	z2z2 := util.BigModPos(new(big.Int).Mul(b.z, b.z), p)
+	u1 := util.BigModPos(new(big.Int).Mul(a.x, z2z2), p)
+	u2 := util.BigModPos(new(big.Int).Mul(b.x, z1z1), p)
+	s1 := new(big.Int).Mul(a.y, b.z)
+	s1 = util.BigModPos(s1.Mul(s1, z2z2), p)
+	s2 := new(big.Int).Mul(b.y, a.z)
+	s2 = util.BigModPos(s2.Mul(s2, z1z1), p)
+
+	if u1.Cmp(u2) == 0 {
+		if s1.Cmp(s2) != 0 {
+			return jacobian{new(big.Int), new(big.Int), new(big.Int)}
+		}
+		return c.double(a)
+	}
+
+	h := util.BigModPos(new(big.Int).Sub(u2, u1), p)
+	r := util.BigModPos(new(big.Int).Sub(s2, s1), p)
+	h2 := util.BigModPos(new(big.Int).Mul(h, h), p)
+	h3 := util.BigModPos(new(big.Int).Mul(h2, h), p)
+	u1h2 := util.BigModPos(new(big.Int).Mul(u1, h2), p)
+
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, h3)
+	x3.Sub(x3, new(big.Int).Lsh(u1h2, 1))
+	x3 = util.BigModPos(x3, p)
+
+	y3 := new(big.Int).Sub(u1h2, x3)
+	y3.Mul(y3, r)
+	y3.Sub(y3, new(big.Int).Mul(s1, h3))
+	y3 = util.BigModPos(y3, p)
+
+	z3 := new(big.Int).Mul(a.z, b.z)
+	z3 = util.BigModPos(z3.Mul(z3, h), p)
+	return jacobian{x3, y3, z3}
+}
+
+// Add returns the sum of (x1, y1) and (x2, y2).
+func (c *Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.add(c.toJacobian(x1, y1), c.toJacobian(x2, y2)))
+}
+
+// Double returns 2*(x1, y1).
+func (c *Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.double(c.toJacobian(x1, y1)))
+}
+
+// ScalarMult returns k*(x1, y1) where k is a big-endian integer.
+func (c *Curve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	base := c.toJacobian(x1, y1)
+	acc := jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			acc = c.double(acc)
+			if (b>>uint(bit))&1 == 1 {
+				acc = c.add(acc, base)
+			}
+		}
+	}
+	return c.toAffine(acc)
+}
+
+// ScalarBaseMult returns k*G where G is the generator and k is a big-endian integer.
+func (c *Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
+
+// Marshal encodes a point in the uncompressed SEC1 form 0x04 || x || y.
+func Marshal(x, y *big.Int) []byte {
+	out := make([]byte, 65)
+	out[0] = 0x04
+	x.FillBytes(out[1:33])
+	y.FillBytes(out[33:])
+	return out
+}
+
+// MarshalCompressed encodes a point in the compressed SEC1 form (0x02 | y parity) || x.
+func MarshalCompressed(x, y *big.Int) []byte {
+	out := make([]byte, 33)
+	out[0] = 0x02 | byte(y.Bit(0))
+	x.FillBytes(out[1:])
+	return out
+}
+
+// Unmarshal decodes a compressed or uncompressed SEC1 point and checks it is on the curve.
+func Unmarshal(b []byte) (*big.Int, *big.Int, error) {
+	c := S256()
+	switch {
+	case len(b) == 65 && b[0] == 0x04:
+		x := util.BytesToBigInt(b[1:33])
+		y := util.BytesToBigInt(b[33:])
+		if !c.IsOnCurve(x, y) {
+			return nil, nil, errors.New("secp256k1: point is not on the curve")
+		}
+		return x, y, nil
+	case len(b) == 33 && (b[0] == 0x02 || b[0] == 0x03):
+		x := util.BytesToBigInt(b[1:])
+		y, err := c.DecompressY(x, b[0] == 0x03)
+		if err != nil {
+			return nil, nil, err
+		}
+		return x, y, nil
+	default:
+		return nil, nil, errors.New("secp256k1: invalid point encoding")
+	}
+}
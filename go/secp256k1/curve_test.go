@@ -0,0 +1,88 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestGeneratorOnCurve(t *testing.T) {
+	c := S256()
+	if !c.IsOnCurve(c.Params().Gx, c.Params().Gy) {
+		t.Fatalf("generator is not on the curve")
+	}
+	if c.IsOnCurve(c.Params().Gx, new(big.Int).Add(c.Params().Gy, big.NewInt(1))) {
+		t.Fatalf("off-curve point accepted")
+	}
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	c := S256()
+	cases := []struct {
+		k, x, y string
+	}{
+		{"01", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+		{"02", "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+		{"03", "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9", "388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+	}
+	for _, tc := range cases {
+		k, _ := hex.DecodeString(tc.k)
+		x, y := c.ScalarBaseMult(k)
+		if hex.EncodeToString(x.Bytes()) != tc.x || hex.EncodeToString(y.Bytes()) != tc.y {
+			t.Fatalf("%s*G: got (%x, %x)", tc.k, x, y)
+		}
+	}
+
+	// n*G is the point at infinity
+	x, y := c.ScalarBaseMult(c.Params().N.Bytes())
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatalf("n*G should be infinity, got (%x, %x)", x, y)
+	}
+}
+
+func TestAddDouble(t *testing.T) {
+	c := S256()
+	gx, gy := c.Params().Gx, c.Params().Gy
+	dx, dy := c.Double(gx, gy)
+	ax, ay := c.Add(gx, gy, gx, gy)
+	if dx.Cmp(ax) != 0 || dy.Cmp(ay) != 0 {
+		t.Fatalf("G+G != 2G")
+	}
+	// P + (-P) = infinity
+	ny := new(big.Int).Sub(c.Params().P, gy)
+	x, y := c.Add(gx, gy, gx, ny)
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatalf("G + -G should be infinity")
+	}
+	// infinity is the identity
+	x, y = c.Add(new(big.Int), new(big.Int), gx, gy)
+	if x.Cmp(gx) != 0 || y.Cmp(gy) != 0 {
+		t.Fatalf("0 + G should be G")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	c := S256()
+	x, y := c.ScalarBaseMult([]byte{0x03})
+	for _, enc := range [][]byte{Marshal(x, y), MarshalCompressed(x, y)} {
+		gx, gy, err := Unmarshal(enc)
+		if err != nil {
+			t.Fatalf("unmarshal %x: %v", enc, err)
+		}
+		if gx.Cmp(x) != 0 || gy.Cmp(y) != 0 {
+			t.Fatalf("round trip mismatch for %x", enc)
+		}
+	}
+	if hex.EncodeToString(MarshalCompressed(x, y)) != "02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9" {
+		t.Fatalf("compressed encoding wrong: %x", MarshalCompressed(x, y))
+	}
+
+	// x = p is out of range
+	bad, _ := hex.DecodeString("02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+	if _, _, err := Unmarshal(bad); err == nil {
+		t.Fatalf("expected error for x >= p")
+	}
+	if _, _, err := Unmarshal([]byte{0x04}); err == nil {
+		t.Fatalf("expected error for truncated point")
+	}
+}
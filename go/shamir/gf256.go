@@ -0,0 +1,139 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// gfMul multiplies in GF(2^8) modulo the AES polynomial x^8 + x^4 + x^3 + x + 1,
+// without branching on its operands.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns a^-1 in GF(2^8) as a^254; a must be non-zero.
+func gfInv(a byte) byte {
+	// a^254 = a^(2+4+8+16+32+64+128)
+	result := byte(1)
+	sq := a
+	for i := 1; i < 8; i++ {
+		sq = gfMul(sq, sq)
+		result = gfMul(result, sq)
+	}
+	return result
+}
+
+// ByteShare is one share of a byte-string secret: Y[i] is the polynomial of secret byte i
+// evaluated at X.
+type ByteShare struct {
+	X byte
+	Y []byte
+}
+
+// SplitBytes divides secret into n shares of which any k recover it, byte-wise over
+// GF(2^8), using crypto/rand.
+func SplitBytes(secret []byte, n, k int) ([]ByteShare, error) {
+	return SplitBytesWithReader(secret, n, k, rand.Reader)
+}
+
+// SplitBytesWithReader is SplitBytes with the coefficients read from r: k-1 bytes per secret
+// byte, a_1..a_{k-1} of byte 0 first. It exists for test vectors with a deterministic
+// source, so prefer SplitBytes.
+func SplitBytesWithReader(secret []byte, n, k int, r io.Reader) ([]ByteShare, error) {
+	if err := checkThreshold(n, k); err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, ErrSecret
+	}
+	coeffs := make([]byte, len(secret)*(k-1))
+	if _, err := io.ReadFull(r, coeffs); err != nil {
+		return nil, err
+	}
+	shares := make([]ByteShare, n)
+	for i := range shares {
+		x := byte(i + 1)
+		y := make([]byte, len(secret))
+		for b, s := range secret {
+			poly := coeffs[b*(k-1) : (b+1)*(k-1)]
+			var acc byte
+			for j := k - 2; j >= 0; j-- {
+				acc = gfMul(acc, x) ^ poly[j]
+			}
+			y[b] = gfMul(acc, x) ^ s
+		}
+		shares[i] = ByteShare{X: x, Y: y}
+	}
+	return shares, nil
+}
+
+// CombineBytes recovers the secret from at least k distinct shares of equal length by
+// Lagrange interpolation at zero. Fewer than k shares yield unrelated bytes, not an error.
+func CombineBytes(shares []ByteShare) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrShares
+	}
+	size := len(shares[0].Y)
+	var seen [MaxShares + 1]bool
+	for _, s := range shares {
+		if s.X == 0 || seen[s.X] || size == 0 || len(s.Y) != size {
+			return nil, ErrShares
+		}
+		seen[s.X] = true
+	}
+	secret := make([]byte, size)
+	for i, si := range shares {
+		// In characteristic 2, x_j / (x_j - x_i) is x_j / (x_j ^ x_i).
+		basis := byte(1)
+		for j, sj := range shares {
+			if i != j {
+				basis = gfMul(basis, gfMul(sj.X, gfInv(sj.X^si.X)))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(si.Y[b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// Bytes serializes the share as FramedBytesFromBigInt(X) || FramedBytesFromUint8Array(Y),
+// each with a 4-byte length prefix.
+func (s ByteShare) Bytes() ([]byte, error) {
+	x, err := util.FramedBytesFromBigInt(big.NewInt(int64(s.X)), shareLengthPrefix)
+	if err != nil {
+		return nil, err
+	}
+	y, err := util.FramedBytesFromUint8Array(s.Y, shareLengthPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes(x, y), nil
+}
+
+// ParseByteShare decodes a share written by ByteShare.Bytes.
+func ParseByteShare(b []byte) (ByteShare, error) {
+	r := util.NewFramedReader(b, shareLengthPrefix)
+	x, err := r.ReadBigInt()
+	if err != nil {
+		return ByteShare{}, ErrShareEncoding
+	}
+	y, err := r.ReadBytes()
+	if err != nil || r.Finish() != nil {
+		return ByteShare{}, ErrShareEncoding
+	}
+	if !x.IsInt64() || x.Int64() < 1 || x.Int64() > MaxShares || len(y) == 0 {
+		return ByteShare{}, ErrShareEncoding
+	}
+	return ByteShare{X: byte(x.Int64()), Y: y}, nil
+}
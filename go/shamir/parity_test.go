@@ -0,0 +1,154 @@
+package shamir
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+type parityVectors struct {
+	Shamir struct {
+		Prime []struct {
+			Name   string
+			Secret string
+			N, K   int
+			Seed   string
+			Shares []string
+		}
+		Gf256 []struct {
+			Name   string
+			Secret string
+			N, K   int
+			Seed   string
+			Shares []string
+		}
+		Decode []struct {
+			Name   string
+			Flavor string
+			Share  string
+			Error  string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// seededReader returns the deterministic randomness source of the vectors, SHAKE128(seed).
+func seededReader(t *testing.T, seed string) util.Xof {
+	t.Helper()
+	h, err := util.NewShake(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write(mustHex(seed))
+	return h
+}
+
+var shamirErrors = map[string]error{
+	"encoding": ErrShareEncoding,
+}
+
+func TestParity_Prime(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Shamir.Prime {
+		secret, _ := new(big.Int).SetString(tc.Secret, 10)
+		shares, err := SplitWithReader(secret, tc.N, tc.K, seededReader(t, tc.Seed))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		parsed := make([]Share, len(shares))
+		for i, s := range shares {
+			b, err := s.Bytes()
+			if err != nil || hex.EncodeToString(b) != tc.Shares[i] {
+				t.Fatalf("%s share %d: got %x, %v want %s", tc.Name, i+1, b, err, tc.Shares[i])
+			}
+			if parsed[i], err = ParseShare(mustHex(tc.Shares[i])); err != nil {
+				t.Fatalf("%s share %d: %v", tc.Name, i+1, err)
+			}
+		}
+		for _, subset := range [][]Share{parsed[:tc.K], parsed[tc.N-tc.K:], parsed} {
+			got, err := Combine(subset)
+			if err != nil || got.Cmp(secret) != 0 {
+				t.Fatalf("%s combine: got %v, %v want %s", tc.Name, got, err, tc.Secret)
+			}
+		}
+	}
+}
+
+func TestParity_Gf256(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Shamir.Gf256 {
+		shares, err := SplitBytesWithReader(mustHex(tc.Secret), tc.N, tc.K, seededReader(t, tc.Seed))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		parsed := make([]ByteShare, len(shares))
+		for i, s := range shares {
+			b, err := s.Bytes()
+			if err != nil || hex.EncodeToString(b) != tc.Shares[i] {
+				t.Fatalf("%s share %d: got %x, %v want %s", tc.Name, i+1, b, err, tc.Shares[i])
+			}
+			if parsed[i], err = ParseByteShare(mustHex(tc.Shares[i])); err != nil {
+				t.Fatalf("%s share %d: %v", tc.Name, i+1, err)
+			}
+		}
+		for _, subset := range [][]ByteShare{parsed[:tc.K], parsed[tc.N-tc.K:], parsed} {
+			got, err := CombineBytes(subset)
+			if err != nil || hex.EncodeToString(got) != tc.Secret {
+				t.Fatalf("%s combine: got %x, %v want %s", tc.Name, got, err, tc.Secret)
+			}
+		}
+	}
+}
+
+func TestParity_Decode(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Shamir.Decode {
+		var err error
+		switch tc.Flavor {
+		case "prime":
+			_, err = ParseShare(mustHex(tc.Share))
+		case "gf256":
+			_, err = ParseByteShare(mustHex(tc.Share))
+		default:
+			t.Fatalf("unknown flavor %q", tc.Flavor)
+		}
+		sentinel, ok := shamirErrors[tc.Error]
+		if !ok {
+			t.Fatalf("%s: unknown error name %q", tc.Name, tc.Error)
+		}
+		if !errors.Is(err, sentinel) {
+			t.Fatalf("%s: got error %v want %v", tc.Name, err, sentinel)
+		}
+	}
+}
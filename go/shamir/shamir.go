@@ -0,0 +1,172 @@
+// Package shamir implements Shamir secret sharing in two flavors: Split and Combine
+// over the prime field GF(2^521 - 1) using math/big, and SplitBytes and CombineBytes
+// byte-wise over GF(2^8). Share i is the polynomial evaluated at x = i for i = 1..n.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// MaxShares is the largest number of shares, bounded by the non-zero elements of GF(2^8).
+const MaxShares = 255
+
+// shareLengthPrefix is the length-prefix width of the frames in a serialized share.
+const shareLengthPrefix = 4
+
+var (
+	ErrThreshold     = errors.New("shamir: threshold must satisfy 2 <= k <= n <= 255")
+	ErrSecret        = errors.New("shamir: secret is out of range")
+	ErrShares        = errors.New("shamir: shares are too few, duplicated or inconsistent")
+	ErrShareEncoding = errors.New("shamir: malformed share encoding")
+)
+
+// prime is the field modulus, the Mersenne prime 2^521 - 1.
+var prime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 521), big.NewInt(1))
+
+// primeBytes is the number of bytes read from the random source per coefficient.
+const primeBytes = 66
+
+// Prime returns the field modulus used by Split and Combine, 2^521 - 1.
+// Secrets must be in [0, Prime()).
+func Prime() *big.Int {
+	return new(big.Int).Set(prime)
+}
+
+// Share is one share of a prime-field secret: the polynomial value Y at X.
+type Share struct {
+	X int
+	Y *big.Int
+}
+
+func checkThreshold(n, k int) error {
+	if k < 2 || k > n || n > MaxShares {
+		return ErrThreshold
+	}
+	return nil
+}
+
+// randomCoefficient reads a uniform element of [0, prime) from r by rejection sampling:
+// 66 bytes big-endian with all but the lowest bit of the first byte cleared, retried while >= prime.
+func randomCoefficient(r io.Reader) (*big.Int, error) {
+	buf := make([]byte, primeBytes)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		buf[0] &= 0x01
+		c := util.BytesToBigInt(buf)
+		if c.Cmp(prime) < 0 {
+			return c, nil
+		}
+	}
+}
+
+// Split divides secret into n shares of which any k recover it, using crypto/rand.
+func Split(secret *big.Int, n, k int) ([]Share, error) {
+	return SplitWithReader(secret, n, k, rand.Reader)
+}
+
+// SplitWithReader is Split with the polynomial coefficients a_1..a_{k-1} drawn in order
+// from r. It exists for test vectors with a deterministic source, so prefer Split.
+func SplitWithReader(secret *big.Int, n, k int, r io.Reader) ([]Share, error) {
+	if err := checkThreshold(n, k); err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Sign() < 0 || secret.Cmp(prime) >= 0 {
+		return nil, ErrSecret
+	}
+	coeffs := make([]*big.Int, k)
+	coeffs[0] = new(big.Int).Set(secret)
+	for i := 1; i < k; i++ {
+		c, err := randomCoefficient(r)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = c
+	}
+	shares := make([]Share, n)
+	for i := range shares {
+		x := big.NewInt(int64(i + 1))
+		// Horner's rule from the highest coefficient down.
+		y := new(big.Int)
+		for j := k - 1; j >= 0; j-- {
+			y.Mul(y, x)
+			y.Add(y, coeffs[j])
+			y.Mod(y, prime)
+		}
+		shares[i] = Share{X: i + 1, Y: y}
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from at least k distinct shares by Lagrange interpolation
+// at zero. Fewer than k shares yield an unrelated value, not an error.
+func Combine(shares []Share) (*big.Int, error) {
+	if len(shares) < 2 || len(shares) > MaxShares {
+		return nil, ErrShares
+	}
+	var seen [MaxShares + 1]bool
+	for _, s := range shares {
+		if s.X < 1 || s.X > MaxShares || seen[s.X] || s.Y == nil || s.Y.Sign() < 0 || s.Y.Cmp(prime) >= 0 {
+			return nil, ErrShares
+		}
+		seen[s.X] = true
+	}
+	secret := new(big.Int)
+	for i, si := range shares {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(sj.X)))
+			num.Mod(num, prime)
+			den.Mul(den, big.NewInt(int64(sj.X-si.X)))
+			den = util.BigModPos(den, prime)
+		}
+		term := new(big.Int).Mul(si.Y, num)
+		term.Mul(term, den.ModInverse(den, prime))
+		secret.Add(secret, term)
+		secret.Mod(secret, prime)
+	}
+	return secret, nil
+}
+
+// Bytes serializes the share as FramedBytesFromBigInt(X) || FramedBytesFromBigInt(Y),
+// each with a 4-byte length prefix.
+func (s Share) Bytes() ([]byte, error) {
+	if s.Y == nil {
+		return nil, ErrShares
+	}
+	x, err := util.FramedBytesFromBigInt(big.NewInt(int64(s.X)), shareLengthPrefix)
+	if err != nil {
+		return nil, err
+	}
+	y, err := util.FramedBytesFromBigInt(s.Y, shareLengthPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes(x, y), nil
+}
+
+// ParseShare decodes a share written by Share.Bytes.
+func ParseShare(b []byte) (Share, error) {
+	r := util.NewFramedReader(b, shareLengthPrefix)
+	x, err := r.ReadBigInt()
+	if err != nil {
+		return Share{}, ErrShareEncoding
+	}
+	y, err := r.ReadBigInt()
+	if err != nil || r.Finish() != nil {
+		return Share{}, ErrShareEncoding
+	}
+	if !x.IsInt64() || x.Int64() < 1 || x.Int64() > MaxShares || y.Sign() < 0 || y.Cmp(prime) >= 0 {
+		return Share{}, ErrShareEncoding
+	}
+	return Share{X: int(x.Int64()), Y: y}, nil
+}
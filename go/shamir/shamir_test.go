@@ -0,0 +1,72 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestGfInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if got := gfMul(byte(a), gfInv(byte(a))); got != 1 {
+			t.Fatalf("%d * inv(%d) = %d", a, a, got)
+		}
+	}
+}
+
+func TestSplitCombine_RoundTrip(t *testing.T) {
+	secret := new(big.Int).Sub(Prime(), big.NewInt(12345))
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Combine([]Share{shares[4], shares[0], shares[2]})
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, _ := Combine(shares[:2]); got.Cmp(secret) == 0 {
+		t.Fatal("two of three shares recovered the secret")
+	}
+
+	byteShares, err := SplitBytes([]byte("recovery key"), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := CombineBytes([]ByteShare{byteShares[3], byteShares[1], byteShares[4]})
+	if err != nil || !bytes.Equal(out, []byte("recovery key")) {
+		t.Fatalf("got %q, %v", out, err)
+	}
+}
+
+func TestSplitCombine_Errors(t *testing.T) {
+	for _, nk := range [][2]int{{3, 1}, {2, 3}, {256, 2}} {
+		if _, err := Split(big.NewInt(1), nk[0], nk[1]); !errors.Is(err, ErrThreshold) {
+			t.Fatalf("n=%d k=%d: got %v", nk[0], nk[1], err)
+		}
+		if _, err := SplitBytes([]byte{1}, nk[0], nk[1]); !errors.Is(err, ErrThreshold) {
+			t.Fatalf("bytes n=%d k=%d: got %v", nk[0], nk[1], err)
+		}
+	}
+	for _, secret := range []*big.Int{nil, big.NewInt(-1), Prime()} {
+		if _, err := Split(secret, 3, 2); !errors.Is(err, ErrSecret) {
+			t.Fatalf("secret %v: got %v", secret, err)
+		}
+	}
+	if _, err := SplitBytes(nil, 3, 2); !errors.Is(err, ErrSecret) {
+		t.Fatalf("empty secret: got %v", err)
+	}
+
+	shares, _ := Split(big.NewInt(7), 3, 2)
+	if _, err := Combine([]Share{shares[0], shares[0]}); !errors.Is(err, ErrShares) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+	if _, err := Combine(shares[:1]); !errors.Is(err, ErrShares) {
+		t.Fatalf("single share: got %v", err)
+	}
+	byteShares, _ := SplitBytes([]byte{1, 2}, 3, 2)
+	byteShares[1].Y = byteShares[1].Y[:1]
+	if _, err := CombineBytes(byteShares); !errors.Is(err, ErrShares) {
+		t.Fatalf("length mismatch: got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package sign
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// derLength encodes an ASN.1 DER length using the minimal short or long form.
+func derLength(n int) ([]byte, error) {
+	if n < 0x80 {
+		return util.IntToBytes(int64(n), 1)
+	}
+	size := 1
+	for n>>(8*size) > 0 {
+		size++
+	}
+	b, err := util.IntToBytes(int64(n), size)
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes([]byte{0x80 | byte(size)}, b), nil
+}
+
+// derInteger encodes a non-negative integer as an ASN.1 DER INTEGER.
+func derInteger(v *big.Int) ([]byte, error) {
+	b, err := util.BigIntToByteArray(v)
+	if err != nil {
+		return nil, err
+	}
+	if b[0]&0x80 != 0 {
+		b = util.ConcatBytes([]byte{0x00}, b)
+	}
+	l, err := derLength(len(b))
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes([]byte{0x02}, l, b), nil
+}
+
+// readDERElement reads one tag-length-value element and returns its content and the remaining bytes.
+// Only minimal (DER) length encodings are accepted.
+func readDERElement(b []byte, tag byte) ([]byte, []byte, error) {
+	if len(b) < 2 || b[0] != tag {
+		return nil, nil, errors.New("DER: unexpected tag")
+	}
+	n := int(b[1])
+	b = b[2:]
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 2 || len(b) < size {
+			return nil, nil, errors.New("DER: invalid length")
+		}
+		if b[0] == 0 {
+			return nil, nil, errors.New("DER: non-minimal length")
+		}
+		n = 0
+		for i := 0; i < size; i++ {
+			n = n<<8 | int(b[i])
+		}
+		if n < 0x80 {
+			return nil, nil, errors.New("DER: non-minimal length")
+		}
+		b = b[size:]
+	}
+	if len(b) < n {
+		return nil, nil, errors.New("DER: truncated input")
+	}
+	return b[:n], b[n:], nil
+}
+
+// readDERInteger reads a positive, minimally encoded ASN.1 INTEGER.
+func readDERInteger(b []byte) (*big.Int, []byte, error) {
+	content, rest, err := readDERElement(b, 0x02)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(content) == 0 {
+		return nil, nil, errors.New("DER: empty integer")
+	}
+	if content[0]&0x80 != 0 {
+		return nil, nil, errors.New("DER: negative integer")
+	}
+	if len(content) > 1 && content[0] == 0 && content[1]&0x80 == 0 {
+		return nil, nil, errors.New("DER: non-minimal integer")
+	}
+	return util.BytesToBigInt(content), rest, nil
+}
+
+// EcdsaRawToDER converts a fixed-width r||s signature to an ASN.1 DER SEQUENCE { r INTEGER, s INTEGER }.
+func EcdsaRawToDER(sig []byte, curveBits int) ([]byte, error) {
+	curve, err := ecdsaCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := decodeRawSignature(sig, curve.Params().N)
+	if err != nil {
+		return nil, err
+	}
+	return encodeDERSignature(r, s)
+}
+
+// EcdsaDERToRaw converts an ASN.1 DER signature to the fixed-width r||s form for the curve.
+// Trailing bytes, non-minimal encodings and out-of-range values are rejected.
+func EcdsaDERToRaw(der []byte, curveBits int) ([]byte, error) {
+	curve, err := ecdsaCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := decodeDERSignature(der)
+	if err != nil {
+		return nil, err
+	}
+	n := curve.Params().N
+	if util.BigCmp(r, n) >= 0 || util.BigCmp(s, n) >= 0 {
+		return nil, errors.New("DER: signature value out of range")
+	}
+	return encodeRawSignature(r, s, n)
+}
+
+func encodeDERSignature(r, s *big.Int) ([]byte, error) {
+	rb, err := derInteger(r)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := derInteger(s)
+	if err != nil {
+		return nil, err
+	}
+	l, err := derLength(len(rb) + len(sb))
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes([]byte{0x30}, l, rb, sb), nil
+}
+
+func decodeDERSignature(der []byte) (*big.Int, *big.Int, error) {
+	seq, rest, err := readDERElement(der, 0x30)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, errors.New("DER: trailing data")
+	}
+	r, seq, err := readDERInteger(seq)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, seq, err := readDERInteger(seq)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(seq) != 0 {
+		return nil, nil, errors.New("DER: trailing data")
+	}
+	return r, s, nil
+}
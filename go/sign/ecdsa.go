@@ -0,0 +1,303 @@
+package sign
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// ecdsaCurve maps a curve bit length to the matching NIST curve.
+func ecdsaCurve(curveBits int) (elliptic.Curve, error) {
+	switch curveBits {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	default:
+		return nil, errors.New("unsupported ECDSA curve bit length")
+	}
+}
+
+// sha2New returns the SHA-2 constructor selected by the same bits as util.Sha2Hash.
+func sha2New(bits int) (func() hash.Hash, error) {
+	if _, err := util.NewSha2(bits); err != nil {
+		return nil, err
+	}
+	return func() hash.Hash {
+		h, _ := util.NewSha2(bits)
+		return h
+	}, nil
+}
+
+// scalarLen returns the fixed byte width of scalars modulo n.
+func scalarLen(n *big.Int) int {
+	return (n.BitLen() + 7) / 8
+}
+
+// bits2int converts a digest to an integer using the leftmost qlen bits (RFC 6979 2.3.2).
+func bits2int(b []byte, qlen int) *big.Int {
+	v := util.BytesToBigInt(b)
+	if blen := len(b) * 8; blen > qlen {
+		v.Rsh(v, uint(blen-qlen))
+	}
+	return v
+}
+
+// bits2octets converts a digest to a fixed-width octet string reduced modulo n (RFC 6979 2.3.4).
+func bits2octets(b []byte, n *big.Int) []byte {
+	z := util.BigModPos(bits2int(b, n.BitLen()), n)
+	out, _ := util.BigIntToBytes(z, scalarLen(n))
+	return out
+}
+
+// rfc6979 is the HMAC_DRBG nonce generator described in RFC 6979 section 3.2.
+type rfc6979 struct {
+	newHash func() hash.Hash
+	n       *big.Int
+	k, v    []byte
+	started bool
+}
+
+func newRFC6979(newHash func() hash.Hash, n *big.Int, x *big.Int, digest []byte) (*rfc6979, error) {
+	xOctets, err := util.BigIntToBytes(x, scalarLen(n))
+	if err != nil {
+		return nil, err
+	}
+	hOctets := bits2octets(digest, n)
+	size := newHash().Size()
+	g := &rfc6979{newHash: newHash, n: n, k: make([]byte, size), v: make([]byte, size)}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.mac(g.k, g.v, []byte{0x00}, xOctets, hOctets)
+	g.v = g.mac(g.k, g.v)
+	g.k = g.mac(g.k, g.v, []byte{0x01}, xOctets, hOctets)
+	g.v = g.mac(g.k, g.v)
+	return g, nil
+}
+
+func (g *rfc6979) mac(key []byte, parts ...[]byte) []byte {
+	m := hmac.New(g.newHash, key)
+	for _, p := range parts {
+		m.Write(p)
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next candidate nonce in [1, n-1].
+func (g *rfc6979) next() *big.Int {
+	if g.started {
+		g.k = g.mac(g.k, g.v, []byte{0x00})
+		g.v = g.mac(g.k, g.v)
+	}
+	g.started = true
+	qlen := g.n.BitLen()
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			g.v = g.mac(g.k, g.v)
+			t = append(t, g.v...)
+		}
+		k := bits2int(t, qlen)
+		if k.Sign() > 0 && k.Cmp(g.n) < 0 {
+			return k
+		}
+		g.k = g.mac(g.k, g.v, []byte{0x00})
+		g.v = g.mac(g.k, g.v)
+	}
+}
+
+// parseScalar decodes a fixed-width private scalar and checks 1 <= d < n.
+func parseScalar(b []byte, n *big.Int) (*big.Int, error) {
+	if len(b) != scalarLen(n) {
+		return nil, errors.New("private key has the wrong length for the curve")
+	}
+	d := util.BytesToBigInt(b)
+	if d.Sign() == 0 || util.BigCmp(d, n) >= 0 {
+		return nil, errors.New("private key is out of range")
+	}
+	return d, nil
+}
+
+// ecdsaSignDigest produces a deterministic (r, s) pair for digest using RFC 6979 nonces.
+// It also returns the recovery id: bit 0 is the parity of R.y, bit 1 is set when R.x >= n.
+// With lowS the signature is normalized to s <= n/2 and the recovery id adjusted to match.
+func ecdsaSignDigest(curve elliptic.Curve, d *big.Int, digest []byte, newHash func() hash.Hash, lowS bool) (*big.Int, *big.Int, byte, error) {
+	n := curve.Params().N
+	g, err := newRFC6979(newHash, n, d, digest)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	e := bits2int(digest, n.BitLen())
+	for {
+		k := g.next()
+		x, y := curve.ScalarBaseMult(k.Bytes())
+		r := util.BigModPos(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		s := new(big.Int).Mul(r, d)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, n))
+		s = util.BigModPos(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+		recID := byte(y.Bit(0))
+		if util.BigCmp(x, n) >= 0 {
+			recID |= 2
+		}
+		if lowS && util.BigCmp(s, new(big.Int).Rsh(n, 1)) > 0 {
+			s.Sub(n, s)
+			recID ^= 1
+		}
+		return r, s, recID, nil
+	}
+}
+
+// ecdsaVerifyDigest checks (r, s) against digest for the public point (qx, qy).
+func ecdsaVerifyDigest(curve elliptic.Curve, qx, qy *big.Int, digest []byte, r, s *big.Int) bool {
+	n := curve.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || util.BigCmp(r, n) >= 0 || util.BigCmp(s, n) >= 0 {
+		return false
+	}
+	e := bits2int(digest, n.BitLen())
+	w := new(big.Int).ModInverse(s, n)
+	u1 := util.BigModPos(new(big.Int).Mul(e, w), n)
+	u2 := util.BigModPos(new(big.Int).Mul(r, w), n)
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(qx, qy, u2.Bytes())
+	x, y := curve.Add(x1, y1, x2, y2)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return false
+	}
+	return util.BigModPos(x, n).Cmp(r) == 0
+}
+
+// decodeRawSignature splits a fixed-width r||s signature.
+func decodeRawSignature(sig []byte, n *big.Int) (*big.Int, *big.Int, error) {
+	size := scalarLen(n)
+	if len(sig) != 2*size {
+		return nil, nil, errors.New("signature has the wrong length for the curve")
+	}
+	return util.BytesToBigInt(sig[:size]), util.BytesToBigInt(sig[size:]), nil
+}
+
+// encodeRawSignature joins r and s into a fixed-width r||s signature.
+func encodeRawSignature(r, s *big.Int, n *big.Int) ([]byte, error) {
+	size := scalarLen(n)
+	rb, err := util.BigIntToBytes(r, size)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := util.BigIntToBytes(s, size)
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes(rb, sb), nil
+}
+
+// EcdsaPublicKey derives the SEC1 public key for a fixed-width private scalar.
+// curveBits selects P-256, P-384 or P-521; compressed selects the 0x02/0x03 form over 0x04.
+func EcdsaPublicKey(privateKey []byte, curveBits int, compressed bool) ([]byte, error) {
+	curve, err := ecdsaCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	d, err := parseScalar(privateKey, curve.Params().N)
+	if err != nil {
+		return nil, err
+	}
+	x, y := curve.ScalarBaseMult(d.Bytes())
+	if compressed {
+		return elliptic.MarshalCompressed(curve, x, y), nil
+	}
+	return elliptic.Marshal(curve, x, y), nil
+}
+
+// EcdsaSign signs msg with a deterministic RFC 6979 nonce and returns the fixed-width r||s signature.
+// curveBits selects P-256, P-384 or P-521; hashBits selects SHA-256, SHA-384 or SHA-512 as in util.Sha2Hash.
+func EcdsaSign(privateKey []byte, msg []byte, curveBits int, hashBits int) ([]byte, error) {
+	curve, err := ecdsaCurve(curveBits)
+	if err != nil {
+		return nil, err
+	}
+	newHash, err := sha2New(hashBits)
+	if err != nil {
+		return nil, err
+	}
+	n := curve.Params().N
+	d, err := parseScalar(privateKey, n)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := util.Sha2Hash(msg, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	r, s, _, err := ecdsaSignDigest(curve, d, digest, newHash, false)
+	if err != nil {
+		return nil, err
+	}
+	return encodeRawSignature(r, s, n)
+}
+
+// EcdsaSignDER is EcdsaSign with the signature encoded as an ASN.1 DER SEQUENCE.
+func EcdsaSignDER(privateKey []byte, msg []byte, curveBits int, hashBits int) ([]byte, error) {
+	sig, err := EcdsaSign(privateKey, msg, curveBits, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	return EcdsaRawToDER(sig, curveBits)
+}
+
+// EcdsaVerify reports whether the fixed-width r||s signature is valid for msg under publicKey.
+// publicKey may be compressed or uncompressed SEC1. Returns an error only for malformed inputs.
+func EcdsaVerify(publicKey []byte, msg []byte, sig []byte, curveBits int, hashBits int) (bool, error) {
+	curve, err := ecdsaCurve(curveBits)
+	if err != nil {
+		return false, err
+	}
+	digest, err := util.Sha2Hash(msg, hashBits)
+	if err != nil {
+		return false, err
+	}
+	qx, qy, err := decodePublicKey(curve, publicKey)
+	if err != nil {
+		return false, err
+	}
+	r, s, err := decodeRawSignature(sig, curve.Params().N)
+	if err != nil {
+		return false, err
+	}
+	return ecdsaVerifyDigest(curve, qx, qy, digest, r, s), nil
+}
+
+// EcdsaVerifyDER is EcdsaVerify for an ASN.1 DER encoded signature.
+func EcdsaVerifyDER(publicKey []byte, msg []byte, der []byte, curveBits int, hashBits int) (bool, error) {
+	sig, err := EcdsaDERToRaw(der, curveBits)
+	if err != nil {
+		return false, err
+	}
+	return EcdsaVerify(publicKey, msg, sig, curveBits, hashBits)
+}
+
+// decodePublicKey parses a compressed or uncompressed SEC1 point on curve.
+func decodePublicKey(curve elliptic.Curve, publicKey []byte) (*big.Int, *big.Int, error) {
+	var x, y *big.Int
+	if len(publicKey) > 0 && publicKey[0] == 0x04 {
+		x, y = elliptic.Unmarshal(curve, publicKey)
+	} else {
+		x, y = elliptic.UnmarshalCompressed(curve, publicKey)
+	}
+	if x == nil {
+		return nil, nil, errors.New("invalid public key")
+	}
+	return x, y, nil
+}
@@ -0,0 +1,97 @@
+package sign
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEcdsaPublicKey_Compressed(t *testing.T) {
+	priv, _ := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	pub, err := EcdsaPublicKey(priv, 256, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// y is odd (...2299), so the prefix is 0x03
+	if hex.EncodeToString(pub) != "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6" {
+		t.Fatalf("compressed key wrong: %x", pub)
+	}
+}
+
+func TestEcdsaSignVerify_RoundTrip(t *testing.T) {
+	for _, curveBits := range []int{256, 384, 521} {
+		size := (curveBits + 7) / 8
+		priv := bytes.Repeat([]byte{0x01}, size)
+		for _, compressed := range []bool{false, true} {
+			pub, err := EcdsaPublicKey(priv, curveBits, compressed)
+			if err != nil {
+				t.Fatalf("P-%d public key: %v", curveBits, err)
+			}
+			sig, err := EcdsaSign(priv, []byte("hello"), curveBits, 256)
+			if err != nil {
+				t.Fatalf("P-%d sign: %v", curveBits, err)
+			}
+			if len(sig) != 2*size {
+				t.Fatalf("P-%d signature length %d", curveBits, len(sig))
+			}
+			ok, err := EcdsaVerify(pub, []byte("hello"), sig, curveBits, 256)
+			if err != nil || !ok {
+				t.Fatalf("P-%d verify failed: %v %v", curveBits, ok, err)
+			}
+			ok, err = EcdsaVerify(pub, []byte("hello"), sig, curveBits, 384)
+			if err != nil || ok {
+				t.Fatalf("P-%d verify with wrong hash should fail: %v %v", curveBits, ok, err)
+			}
+		}
+	}
+}
+
+func TestEcdsa_BadInputs(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x01}, 32)
+	if _, err := EcdsaSign(priv, nil, 255, 256); err == nil {
+		t.Fatalf("expected error for unsupported curve")
+	}
+	if _, err := EcdsaSign(priv, nil, 256, 224); err == nil {
+		t.Fatalf("expected error for unsupported hash")
+	}
+	if _, err := EcdsaSign(priv[:31], nil, 256, 256); err == nil {
+		t.Fatalf("expected error for short private key")
+	}
+	if _, err := EcdsaSign(make([]byte, 32), nil, 256, 256); err == nil {
+		t.Fatalf("expected error for zero private key")
+	}
+	if _, err := EcdsaSign(bytes.Repeat([]byte{0xff}, 32), nil, 256, 256); err == nil {
+		t.Fatalf("expected error for private key >= n")
+	}
+	pub, _ := EcdsaPublicKey(priv, 256, false)
+	if _, err := EcdsaVerify(pub, nil, make([]byte, 63), 256, 256); err == nil {
+		t.Fatalf("expected error for short signature")
+	}
+	if _, err := EcdsaVerify(pub[:64], nil, make([]byte, 64), 256, 256); err == nil {
+		t.Fatalf("expected error for truncated public key")
+	}
+	// zero r and s never verify
+	ok, err := EcdsaVerify(pub, nil, make([]byte, 64), 256, 256)
+	if err != nil || ok {
+		t.Fatalf("zero signature should not verify: %v %v", ok, err)
+	}
+}
+
+func TestEcdsaRawToDER_HighBit(t *testing.T) {
+	raw := make([]byte, 64)
+	raw[0] = 0x80
+	raw[63] = 0x01
+	der, err := EcdsaRawToDER(raw, 256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// r gets a leading zero so it stays positive; s is a single byte
+	want := "30260221008000000000000000000000000000000000000000000000000000000000000000020101"
+	if hex.EncodeToString(der) != want {
+		t.Fatalf("der wrong: %x", der)
+	}
+	back, err := EcdsaDERToRaw(der, 256)
+	if err != nil || !bytes.Equal(back, raw) {
+		t.Fatalf("round trip failed: %v %x", err, back)
+	}
+}
@@ -0,0 +1,41 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"errors"
+)
+
+// Ed25519KeyFromSeed derives an Ed25519 key pair from a 32-byte seed (RFC 8032).
+// The private key is the seed itself, the public key is the 32-byte encoded point.
+func Ed25519KeyFromSeed(seed []byte) ([]byte, []byte, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, errors.New("Ed25519KeyFromSeed: seed must be 32 bytes")
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	privateKey := make([]byte, ed25519.SeedSize)
+	copy(privateKey, seed)
+	publicKey := make([]byte, ed25519.PublicKeySize)
+	copy(publicKey, key[ed25519.SeedSize:])
+	return privateKey, publicKey, nil
+}
+
+// Ed25519Sign signs msg with a 32-byte private key (seed) and returns a 64-byte signature.
+func Ed25519Sign(privateKey []byte, msg []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.SeedSize {
+		return nil, errors.New("Ed25519Sign: private key must be 32 bytes")
+	}
+	key := ed25519.NewKeyFromSeed(privateKey)
+	return ed25519.Sign(key, msg), nil
+}
+
+// Ed25519Verify reports whether sig is a valid signature of msg by publicKey.
+// Returns an error only for malformed key or signature lengths.
+func Ed25519Verify(publicKey []byte, msg []byte, sig []byte) (bool, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false, errors.New("Ed25519Verify: public key must be 32 bytes")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return false, errors.New("Ed25519Verify: signature must be 64 bytes")
+	}
+	return ed25519.Verify(publicKey, msg, sig), nil
+}
@@ -0,0 +1,70 @@
+package sign
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEd25519KeyFromSeed(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	priv, pub, err := Ed25519KeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(priv, seed) {
+		t.Fatalf("private key should equal seed: %x", priv)
+	}
+	if hex.EncodeToString(pub) != "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a" {
+		t.Fatalf("public key wrong: %x", pub)
+	}
+
+	// wrong seed length
+	if _, _, err := Ed25519KeyFromSeed(seed[:31]); err == nil {
+		t.Fatalf("expected error for short seed")
+	}
+}
+
+func TestEd25519SignVerify_RoundTrip(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, 32)
+	_, pub, err := Ed25519KeyFromSeed(seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := []byte("hello")
+	sig, err := Ed25519Sign(seed, msg)
+	if err != nil {
+		t.Fatalf("sign error: %v", err)
+	}
+	if len(sig) != 64 {
+		t.Fatalf("signature length %d", len(sig))
+	}
+	ok, err := Ed25519Verify(pub, msg, sig)
+	if err != nil || !ok {
+		t.Fatalf("verify failed: %v %v", ok, err)
+	}
+
+	// deterministic
+	sig2, _ := Ed25519Sign(seed, msg)
+	if !bytes.Equal(sig, sig2) {
+		t.Fatalf("signatures differ: %x %x", sig, sig2)
+	}
+
+	// different message
+	ok, err = Ed25519Verify(pub, []byte("hellp"), sig)
+	if err != nil || ok {
+		t.Fatalf("expected verify to fail: %v %v", ok, err)
+	}
+}
+
+func TestEd25519_BadLengths(t *testing.T) {
+	if _, err := Ed25519Sign(make([]byte, 64), nil); err == nil {
+		t.Fatalf("expected error for 64-byte private key")
+	}
+	if _, err := Ed25519Verify(make([]byte, 31), nil, make([]byte, 64)); err == nil {
+		t.Fatalf("expected error for short public key")
+	}
+	if _, err := Ed25519Verify(make([]byte, 32), nil, make([]byte, 63)); err == nil {
+		t.Fatalf("expected error for short signature")
+	}
+}
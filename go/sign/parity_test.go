@@ -0,0 +1,235 @@
+package sign
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Sign struct {
+		Ed25519 []struct {
+			Name      string
+			Seed      string
+			PublicKey string
+			Msg       string
+			Sig       string
+			Valid     bool
+		}
+		Ecdsa []struct {
+			CurveBits  int
+			HashBits   int
+			PrivateKey string
+			PublicKey  string
+			Msg        string
+			Sig        string
+			Der        string
+			Valid      bool
+		}
+		EcdsaDer []struct {
+			CurveBits int
+			Der       string
+			Sig       string
+			WantErr   bool
+		}
+		Secp256k1 []struct {
+			HashBits   int
+			PrivateKey string
+			PublicKey  string
+			Msg        string
+			Sig        string
+			Valid      bool
+		}
+		Schnorr []struct {
+			Index     int
+			SecretKey string
+			PublicKey string
+			AuxRand   string
+			Msg       string
+			Sig       string
+			Valid     bool
+			Comment   string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParity_Ed25519(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Sign.Ed25519 {
+		_, pub, err := Ed25519KeyFromSeed(mustHex(tc.Seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("ed25519 %s: public key got %x want %s", tc.Name, pub, tc.PublicKey)
+		}
+		if tc.Valid {
+			sig, err := Ed25519Sign(mustHex(tc.Seed), mustHex(tc.Msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(sig) != tc.Sig {
+				t.Fatalf("ed25519 %s: sig got %x want %s", tc.Name, sig, tc.Sig)
+			}
+		}
+		ok, err := Ed25519Verify(mustHex(tc.PublicKey), mustHex(tc.Msg), mustHex(tc.Sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.Valid {
+			t.Fatalf("ed25519 %s: verify got %v want %v", tc.Name, ok, tc.Valid)
+		}
+	}
+}
+
+func TestParity_Ecdsa(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Sign.Ecdsa {
+		pub, err := EcdsaPublicKey(mustHex(tc.PrivateKey), tc.CurveBits, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("ecdsa P-%d: public key got %x want %s", tc.CurveBits, pub, tc.PublicKey)
+		}
+		if tc.Valid {
+			sig, err := EcdsaSign(mustHex(tc.PrivateKey), []byte(tc.Msg), tc.CurveBits, tc.HashBits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(sig) != tc.Sig {
+				t.Fatalf("ecdsa P-%d sha-%d %q: sig got %x want %s", tc.CurveBits, tc.HashBits, tc.Msg, sig, tc.Sig)
+			}
+			der, err := EcdsaSignDER(mustHex(tc.PrivateKey), []byte(tc.Msg), tc.CurveBits, tc.HashBits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(der) != tc.Der {
+				t.Fatalf("ecdsa P-%d sha-%d %q: der got %x want %s", tc.CurveBits, tc.HashBits, tc.Msg, der, tc.Der)
+			}
+		}
+		ok, err := EcdsaVerify(mustHex(tc.PublicKey), []byte(tc.Msg), mustHex(tc.Sig), tc.CurveBits, tc.HashBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.Valid {
+			t.Fatalf("ecdsa P-%d sha-%d %q: verify got %v want %v", tc.CurveBits, tc.HashBits, tc.Msg, ok, tc.Valid)
+		}
+	}
+	for _, tc := range v.Sign.EcdsaDer {
+		raw, err := EcdsaDERToRaw(mustHex(tc.Der), tc.CurveBits)
+		if tc.WantErr {
+			if err == nil {
+				t.Fatalf("ecdsa der %s: expected error", tc.Der)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(raw) != tc.Sig {
+			t.Fatalf("ecdsa der->raw %s: got %x want %s", tc.Der, raw, tc.Sig)
+		}
+		der, err := EcdsaRawToDER(raw, tc.CurveBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(der) != tc.Der {
+			t.Fatalf("ecdsa raw->der %s: got %x want %s", tc.Sig, der, tc.Der)
+		}
+	}
+}
+
+func TestParity_Secp256k1(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Sign.Secp256k1 {
+		pub, err := Secp256k1PublicKey(mustHex(tc.PrivateKey), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pub) != tc.PublicKey {
+			t.Fatalf("secp256k1 %s: public key got %x want %s", tc.PrivateKey, pub, tc.PublicKey)
+		}
+		sig := mustHex(tc.Sig)
+		if tc.Valid {
+			got, err := Secp256k1SignRecoverable(mustHex(tc.PrivateKey), []byte(tc.Msg), tc.HashBits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != tc.Sig {
+				t.Fatalf("secp256k1 %s %q: sig got %x want %s", tc.PrivateKey, tc.Msg, got, tc.Sig)
+			}
+			rec, err := Secp256k1Recover([]byte(tc.Msg), sig, tc.HashBits, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(rec) != tc.PublicKey {
+				t.Fatalf("secp256k1 %s %q: recovered %x want %s", tc.PrivateKey, tc.Msg, rec, tc.PublicKey)
+			}
+		}
+		ok, err := Secp256k1Verify(pub, []byte(tc.Msg), sig[:64], tc.HashBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.Valid {
+			t.Fatalf("secp256k1 %s %q: verify got %v want %v", tc.PrivateKey, tc.Msg, ok, tc.Valid)
+		}
+	}
+}
+
+func TestParity_Schnorr(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Sign.Schnorr {
+		if tc.SecretKey != "" {
+			pub, err := SchnorrPublicKey(mustHex(tc.SecretKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(pub) != tc.PublicKey {
+				t.Fatalf("bip340 #%d: public key got %x want %s", tc.Index, pub, tc.PublicKey)
+			}
+			sig, err := SchnorrSign(mustHex(tc.SecretKey), mustHex(tc.Msg), mustHex(tc.AuxRand))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(sig) != tc.Sig {
+				t.Fatalf("bip340 #%d: sig got %x want %s", tc.Index, sig, tc.Sig)
+			}
+		}
+		ok, err := SchnorrVerify(mustHex(tc.PublicKey), mustHex(tc.Msg), mustHex(tc.Sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tc.Valid {
+			t.Fatalf("bip340 #%d (%s): verify got %v want %v", tc.Index, tc.Comment, ok, tc.Valid)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package sign
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// TaggedHash computes the BIP-340 tagged hash SHA-256(SHA-256(tag) || SHA-256(tag) || data).
+func TaggedHash(tag string, data []byte) ([]byte, error) {
+	tagHash, err := util.Sha2Hash([]byte(tag), 256)
+	if err != nil {
+		return nil, err
+	}
+	return util.Sha2Hash(util.ConcatBytes(tagHash, tagHash, data), 256)
+}
+
+// xOnly returns the 32-byte x coordinate of a point.
+func xOnly(x *big.Int) []byte {
+	out := make([]byte, 32)
+	x.FillBytes(out)
+	return out
+}
+
+// SchnorrPublicKey returns the 32-byte x-only BIP-340 public key for a 32-byte private key.
+func SchnorrPublicKey(privateKey []byte) ([]byte, error) {
+	curve := secp256k1.S256()
+	d, err := parseScalar(privateKey, curve.Params().N)
+	if err != nil {
+		return nil, err
+	}
+	x, _ := curve.ScalarBaseMult(d.Bytes())
+	return xOnly(x), nil
+}
+
+// SchnorrSign produces a 64-byte BIP-340 signature of msg (any length) using 32 bytes of auxRand.
+// The same key, message and auxRand always give the same signature.
+func SchnorrSign(privateKey []byte, msg []byte, auxRand []byte) ([]byte, error) {
+	if len(auxRand) != 32 {
+		return nil, errors.New("SchnorrSign: auxRand must be 32 bytes")
+	}
+	curve := secp256k1.S256()
+	n := curve.Params().N
+	d, err := parseScalar(privateKey, n)
+	if err != nil {
+		return nil, err
+	}
+	px, py := curve.ScalarBaseMult(d.Bytes())
+	if py.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+	pBytes := xOnly(px)
+
+	aux, err := TaggedHash("BIP0340/aux", auxRand)
+	if err != nil {
+		return nil, err
+	}
+	t := xOnly(d)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+	nonce, err := TaggedHash("BIP0340/nonce", util.ConcatBytes(t, pBytes, msg))
+	if err != nil {
+		return nil, err
+	}
+	k := util.BigModPos(util.BytesToBigInt(nonce), n)
+	if k.Sign() == 0 {
+		return nil, errors.New("SchnorrSign: nonce is zero")
+	}
+	rx, ry := curve.ScalarBaseMult(k.Bytes())
+	if ry.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+	rBytes := xOnly(rx)
+
+	eHash, err := TaggedHash("BIP0340/challenge", util.ConcatBytes(rBytes, pBytes, msg))
+	if err != nil {
+		return nil, err
+	}
+	e := util.BigModPos(util.BytesToBigInt(eHash), n)
+	s := util.BigModPos(new(big.Int).Add(k, new(big.Int).Mul(e, d)), n)
+	sig := util.ConcatBytes(rBytes, xOnly(s))
+
+	ok, err := SchnorrVerify(pBytes, msg, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("SchnorrSign: produced signature does not verify")
+	}
+	return sig, nil
+}
+
+// SchnorrVerify reports whether sig is a valid BIP-340 signature of msg under the x-only publicKey.
+// Returns an error only for malformed key or signature lengths.
+func SchnorrVerify(publicKey []byte, msg []byte, sig []byte) (bool, error) {
+	if len(publicKey) != 32 {
+		return false, errors.New("SchnorrVerify: public key must be 32 bytes")
+	}
+	if len(sig) != 64 {
+		return false, errors.New("SchnorrVerify: signature must be 64 bytes")
+	}
+	curve := secp256k1.S256()
+	params := curve.Params()
+	px, py, err := liftX(publicKey)
+	if err != nil {
+		return false, nil
+	}
+	r := util.BytesToBigInt(sig[:32])
+	s := util.BytesToBigInt(sig[32:])
+	if util.BigCmp(r, params.P) >= 0 || util.BigCmp(s, params.N) >= 0 {
+		return false, nil
+	}
+	eHash, err := TaggedHash("BIP0340/challenge", util.ConcatBytes(sig[:32], publicKey, msg))
+	if err != nil {
+		return false, err
+	}
+	e := util.BigModPos(util.BytesToBigInt(eHash), params.N)
+
+	// R = sG - eP
+	x1, y1 := curve.ScalarBaseMult(s.Bytes())
+	negE := util.BigModPos(new(big.Int).Neg(e), params.N)
+	x2, y2 := curve.ScalarMult(px, py, negE.Bytes())
+	rx, ry := curve.Add(x1, y1, x2, y2)
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false, nil
+	}
+	return ry.Bit(0) == 0 && rx.Cmp(r) == 0, nil
+}
+
+// liftX returns the point with the given x coordinate and an even y (BIP-340 lift_x).
+func liftX(b []byte) (*big.Int, *big.Int, error) {
+	x := util.BytesToBigInt(b)
+	y, err := secp256k1.S256().DecompressY(x, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, y, nil
+}
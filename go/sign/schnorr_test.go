@@ -0,0 +1,65 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestTaggedHash(t *testing.T) {
+	tag := sha256.Sum256([]byte("BIP0340/challenge"))
+	want := sha256.Sum256(append(append(tag[:], tag[:]...), 0x01, 0x02))
+	got, err := TaggedHash("BIP0340/challenge", []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, want[:]) {
+		t.Fatalf("tagged hash got %x want %x", got, want)
+	}
+}
+
+func TestSchnorrSignVerify_RoundTrip(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x05}, 32)
+	pub, err := SchnorrPublicKey(priv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	aux := make([]byte, 32)
+	sig, err := SchnorrSign(priv, []byte("hello"), aux)
+	if err != nil {
+		t.Fatalf("sign error: %v", err)
+	}
+	ok, err := SchnorrVerify(pub, []byte("hello"), sig)
+	if err != nil || !ok {
+		t.Fatalf("verify failed: %v %v", ok, err)
+	}
+	// a different auxRand gives a different, still valid, signature
+	aux[0] = 1
+	sig2, err := SchnorrSign(priv, []byte("hello"), aux)
+	if err != nil {
+		t.Fatalf("sign error: %v", err)
+	}
+	if bytes.Equal(sig, sig2) {
+		t.Fatalf("auxRand did not change the signature")
+	}
+	ok, err = SchnorrVerify(pub, []byte("hellp"), sig2)
+	if err != nil || ok {
+		t.Fatalf("expected verify to fail: %v %v", ok, err)
+	}
+}
+
+func TestSchnorr_BadLengths(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x01}, 32)
+	if _, err := SchnorrSign(priv, nil, make([]byte, 31)); err == nil {
+		t.Fatalf("expected error for short auxRand")
+	}
+	if _, err := SchnorrSign(make([]byte, 32), nil, make([]byte, 32)); err == nil {
+		t.Fatalf("expected error for zero private key")
+	}
+	if _, err := SchnorrVerify(make([]byte, 33), nil, make([]byte, 64)); err == nil {
+		t.Fatalf("expected error for 33-byte public key")
+	}
+	if _, err := SchnorrVerify(make([]byte, 32), nil, make([]byte, 65)); err == nil {
+		t.Fatalf("expected error for 65-byte signature")
+	}
+}
@@ -0,0 +1,133 @@
+package sign
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/secp256k1"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Secp256k1PublicKey derives the SEC1 public key for a 32-byte secp256k1 private key.
+func Secp256k1PublicKey(privateKey []byte, compressed bool) ([]byte, error) {
+	curve := secp256k1.S256()
+	d, err := parseScalar(privateKey, curve.Params().N)
+	if err != nil {
+		return nil, err
+	}
+	x, y := curve.ScalarBaseMult(d.Bytes())
+	if compressed {
+		return secp256k1.MarshalCompressed(x, y), nil
+	}
+	return secp256k1.Marshal(x, y), nil
+}
+
+// Secp256k1Sign signs msg with a deterministic RFC 6979 nonce and returns the 64-byte r||s signature.
+// s is always normalized to the lower half of the group order.
+func Secp256k1Sign(privateKey []byte, msg []byte, hashBits int) ([]byte, error) {
+	sig, err := Secp256k1SignRecoverable(privateKey, msg, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:64], nil
+}
+
+// Secp256k1SignRecoverable is Secp256k1Sign with the recovery id (0-3) appended as a 65th byte.
+func Secp256k1SignRecoverable(privateKey []byte, msg []byte, hashBits int) ([]byte, error) {
+	curve := secp256k1.S256()
+	newHash, err := sha2New(hashBits)
+	if err != nil {
+		return nil, err
+	}
+	n := curve.Params().N
+	d, err := parseScalar(privateKey, n)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := util.Sha2Hash(msg, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	r, s, recID, err := ecdsaSignDigest(curve, d, digest, newHash, true)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := encodeRawSignature(r, s, n)
+	if err != nil {
+		return nil, err
+	}
+	return util.ConcatBytes(sig, []byte{recID}), nil
+}
+
+// Secp256k1Verify reports whether the 64-byte r||s signature is valid for msg under publicKey.
+// Signatures with a high s value are rejected, as they are never produced by Secp256k1Sign.
+func Secp256k1Verify(publicKey []byte, msg []byte, sig []byte, hashBits int) (bool, error) {
+	curve := secp256k1.S256()
+	digest, err := util.Sha2Hash(msg, hashBits)
+	if err != nil {
+		return false, err
+	}
+	qx, qy, err := secp256k1.Unmarshal(publicKey)
+	if err != nil {
+		return false, err
+	}
+	n := curve.Params().N
+	r, s, err := decodeRawSignature(sig, n)
+	if err != nil {
+		return false, err
+	}
+	if util.BigCmp(s, new(big.Int).Rsh(n, 1)) > 0 {
+		return false, nil
+	}
+	return ecdsaVerifyDigest(curve, qx, qy, digest, r, s), nil
+}
+
+// Secp256k1Recover recovers the signer's SEC1 public key from a 65-byte r||s||v signature of msg.
+func Secp256k1Recover(msg []byte, sig []byte, hashBits int, compressed bool) ([]byte, error) {
+	curve := secp256k1.S256()
+	if len(sig) != 65 {
+		return nil, errors.New("Secp256k1Recover: signature must be 65 bytes")
+	}
+	recID := sig[64]
+	if recID > 3 {
+		return nil, errors.New("Secp256k1Recover: invalid recovery id")
+	}
+	digest, err := util.Sha2Hash(msg, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	params := curve.Params()
+	n := params.N
+	r, s, err := decodeRawSignature(sig[:64], n)
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() == 0 || s.Sign() == 0 || util.BigCmp(r, n) >= 0 || util.BigCmp(s, n) >= 0 {
+		return nil, errors.New("Secp256k1Recover: signature value out of range")
+	}
+
+	rx := new(big.Int).Set(r)
+	if recID&2 != 0 {
+		rx.Add(rx, n)
+	}
+	ry, err := curve.DecompressY(rx, recID&1 == 1)
+	if err != nil {
+		return nil, errors.New("Secp256k1Recover: no point for recovery id")
+	}
+
+	// Q = r⁻¹ (sR - eG)
+	e := bits2int(digest, n.BitLen())
+	rInv := new(big.Int).ModInverse(r, n)
+	u1 := util.BigModPos(new(big.Int).Mul(new(big.Int).Neg(e), rInv), n)
+	u2 := util.BigModPos(new(big.Int).Mul(s, rInv), n)
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(rx, ry, u2.Bytes())
+	qx, qy := curve.Add(x1, y1, x2, y2)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, errors.New("Secp256k1Recover: recovered point at infinity")
+	}
+	if compressed {
+		return secp256k1.MarshalCompressed(qx, qy), nil
+	}
+	return secp256k1.Marshal(qx, qy), nil
+}
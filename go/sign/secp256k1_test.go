@@ -0,0 +1,63 @@
+package sign
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestSecp256k1SignVerify_LowS(t *testing.T) {
+	priv := bytes.Repeat([]byte{0x07}, 32)
+	pub, err := Secp256k1PublicKey(priv, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	half := new(big.Int).Rsh(n, 1)
+	for _, msg := range []string{"", "a", "hello", "low-s everywhere"} {
+		sig, err := Secp256k1Sign(priv, []byte(msg), 256)
+		if err != nil {
+			t.Fatalf("sign error: %v", err)
+		}
+		if new(big.Int).SetBytes(sig[32:]).Cmp(half) > 0 {
+			t.Fatalf("%q: s is not low: %x", msg, sig[32:])
+		}
+		ok, err := Secp256k1Verify(pub, []byte(msg), sig, 256)
+		if err != nil || !ok {
+			t.Fatalf("%q: verify failed: %v %v", msg, ok, err)
+		}
+	}
+}
+
+func TestSecp256k1Recover(t *testing.T) {
+	priv, _ := hex.DecodeString("b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef")
+	for _, compressed := range []bool{false, true} {
+		want, _ := Secp256k1PublicKey(priv, compressed)
+		sig, err := Secp256k1SignRecoverable(priv, []byte("recover me"), 256)
+		if err != nil {
+			t.Fatalf("sign error: %v", err)
+		}
+		got, err := Secp256k1Recover([]byte("recover me"), sig, 256, compressed)
+		if err != nil {
+			t.Fatalf("recover error: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("recovered %x want %x", got, want)
+		}
+		// a different message recovers a different key
+		other, err := Secp256k1Recover([]byte("recover you"), sig, 256, compressed)
+		if err == nil && bytes.Equal(other, want) {
+			t.Fatalf("recovered the signer from the wrong message")
+		}
+	}
+
+	sig := make([]byte, 65)
+	sig[64] = 4
+	if _, err := Secp256k1Recover(nil, sig, 256, true); err == nil {
+		t.Fatalf("expected error for recovery id 4")
+	}
+	if _, err := Secp256k1Recover(nil, sig[:64], 256, true); err == nil {
+		t.Fatalf("expected error for 64-byte signature")
+	}
+}
@@ -0,0 +1,156 @@
+package spake2
+
+import (
+	"crypto/hmac"
+
+	"github.com/grzegorzmaniak/inparity/group"
+)
+
+// Initiator is party A: Start sends pA, and Finish checks the responder's pB and MAC,
+// returning A's MAC and the session key.
+type Initiator struct {
+	p     *params
+	stage stage
+	w, x  group.Scalar
+	pA    group.Element
+}
+
+// NewInitiator returns the initiator for cfg and the shared password.
+func NewInitiator(cfg Config, password []byte) (*Initiator, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	w, err := p.passwordScalar(password)
+	if err != nil {
+		return nil, err
+	}
+	return &Initiator{p: p, w: w}, nil
+}
+
+// Start draws x and returns the first message, pA.
+func (a *Initiator) Start() ([]byte, error) {
+	x, err := a.p.g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return a.start(x)
+}
+
+func (a *Initiator) start(x group.Scalar) ([]byte, error) {
+	if a.stage != stageNew {
+		return nil, ErrState
+	}
+	a.stage = stageStarted
+	a.x = x
+	a.pA = a.p.share(x, a.w, a.p.m)
+	return frame(encode(a.pA))
+}
+
+// Finish takes the responder's message, pB and its MAC, and returns the last message, A's
+// MAC, with the session key Ke. A wrong password fails with ErrAuthentication.
+func (a *Initiator) Finish(msg []byte) ([]byte, []byte, error) {
+	if a.stage != stageStarted {
+		return nil, nil, ErrState
+	}
+	a.stage = stageDone
+	x := a.x
+	a.x = nil
+	fields, err := unframe(msg, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	pB, err := a.p.decodeShare(fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := a.p.sharedElement(x, a.w, pB, a.p.n)
+	if err != nil {
+		return nil, nil, err
+	}
+	ks, err := a.p.keySchedule(a.pA, pB, k, a.w)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hmac.Equal(fields[1], ks.macB) {
+		return nil, nil, ErrAuthentication
+	}
+	out, err := frame(ks.macA)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, ks.ke, nil
+}
+
+// Responder is party B: Respond answers pA with pB and B's MAC, and Finish checks A's MAC
+// and returns the session key.
+type Responder struct {
+	p     *params
+	stage stage
+	w     group.Scalar
+	keys  *keys
+}
+
+// NewResponder returns the responder for cfg and the shared password.
+func NewResponder(cfg Config, password []byte) (*Responder, error) {
+	p, err := newParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	w, err := p.passwordScalar(password)
+	if err != nil {
+		return nil, err
+	}
+	return &Responder{p: p, w: w}, nil
+}
+
+// Respond takes the initiator's first message and returns pB with B's MAC.
+func (b *Responder) Respond(msg []byte) ([]byte, error) {
+	y, err := b.p.g.RandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	return b.respond(msg, y)
+}
+
+func (b *Responder) respond(msg []byte, y group.Scalar) ([]byte, error) {
+	if b.stage != stageNew {
+		return nil, ErrState
+	}
+	b.stage = stageStarted
+	fields, err := unframe(msg, 1)
+	if err != nil {
+		return nil, err
+	}
+	pA, err := b.p.decodeShare(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	pB := b.p.share(y, b.w, b.p.n)
+	k, err := b.p.sharedElement(y, b.w, pA, b.p.m)
+	if err != nil {
+		return nil, err
+	}
+	if b.keys, err = b.p.keySchedule(pA, pB, k, b.w); err != nil {
+		return nil, err
+	}
+	return frame(encode(pB), b.keys.macB)
+}
+
+// Finish checks the initiator's MAC and returns the session key Ke, or ErrAuthentication.
+func (b *Responder) Finish(msg []byte) ([]byte, error) {
+	if b.stage != stageStarted || b.keys == nil {
+		return nil, ErrState
+	}
+	b.stage = stageDone
+	ks := b.keys
+	b.keys = nil
+	fields, err := unframe(msg, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(fields[0], ks.macA) {
+		return nil, ErrAuthentication
+	}
+	return ks.ke, nil
+}
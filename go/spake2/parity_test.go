@@ -0,0 +1,124 @@
+package spake2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type parityVectors struct {
+	Spake2 struct {
+		Vectors []struct {
+			Name                 string
+			Suite                Suite
+			IdentityA, IdentityB string
+			Aad, Password        string
+			W, X, Y              string
+			PA, PB, K            string
+			Ke, Ka, KcA, KcB     string
+			MacA, MacB           string
+			Msg1, Msg2, Msg3     string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParity_Vectors(t *testing.T) {
+	v := loadVectors(t)
+	if len(v.Spake2.Vectors) == 0 {
+		t.Fatal("no spake2 vectors")
+	}
+	for _, tc := range v.Spake2.Vectors {
+		cfg := Config{Suite: tc.Suite, IdentityA: mustHex(tc.IdentityA), IdentityB: mustHex(tc.IdentityB), AAD: mustHex(tc.Aad)}
+		p, err := newParams(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		w, err := p.g.DecodeScalar(mustHex(tc.W))
+		if err != nil {
+			t.Fatalf("%s: w: %v", tc.Name, err)
+		}
+		if tc.Password != "" {
+			got, err := p.passwordScalar(mustHex(tc.Password))
+			if err != nil || !got.Equal(w) {
+				t.Fatalf("%s: password scalar mismatch", tc.Name)
+			}
+		}
+		x, _ := p.g.DecodeScalar(mustHex(tc.X))
+		y, _ := p.g.DecodeScalar(mustHex(tc.Y))
+
+		// Key schedule.
+		pA, pB := p.share(x, w, p.m), p.share(y, w, p.n)
+		k, err := p.sharedElement(x, w, pB, p.n)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		ks, err := p.keySchedule(pA, pB, k, w)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		for _, f := range []struct {
+			name      string
+			got, want []byte
+		}{
+			{"pA", encode(pA), mustHex(tc.PA)}, {"pB", encode(pB), mustHex(tc.PB)}, {"K", encode(k), mustHex(tc.K)},
+			{"Ke", ks.ke, mustHex(tc.Ke)}, {"Ka", ks.ka, mustHex(tc.Ka)},
+			{"KcA", ks.kcA, mustHex(tc.KcA)}, {"KcB", ks.kcB, mustHex(tc.KcB)},
+			{"macA", ks.macA, mustHex(tc.MacA)}, {"macB", ks.macB, mustHex(tc.MacB)},
+		} {
+			if !bytes.Equal(f.got, f.want) {
+				t.Fatalf("%s: %s mismatch: got %x want %x", tc.Name, f.name, f.got, f.want)
+			}
+		}
+
+		// The Go responder answers the recorded initiator messages.
+		responder := &Responder{p: p, w: w}
+		msg2, err := responder.respond(mustHex(tc.Msg1), y)
+		if err != nil || !bytes.Equal(msg2, mustHex(tc.Msg2)) {
+			t.Fatalf("%s: msg2 mismatch: %v", tc.Name, err)
+		}
+		key, err := responder.Finish(mustHex(tc.Msg3))
+		if err != nil || !bytes.Equal(key, mustHex(tc.Ke)) {
+			t.Fatalf("%s: responder key mismatch: %v", tc.Name, err)
+		}
+
+		// The initiator side, as the TS implementation plays it.
+		initiator := &Initiator{p: p, w: w}
+		msg1, err := initiator.start(x)
+		if err != nil || !bytes.Equal(msg1, mustHex(tc.Msg1)) {
+			t.Fatalf("%s: msg1 mismatch: %v", tc.Name, err)
+		}
+		msg3, key, err := initiator.Finish(mustHex(tc.Msg2))
+		if err != nil || !bytes.Equal(msg3, mustHex(tc.Msg3)) || !bytes.Equal(key, mustHex(tc.Ke)) {
+			t.Fatalf("%s: initiator mismatch: %v", tc.Name, err)
+		}
+	}
+}
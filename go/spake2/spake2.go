@@ -0,0 +1,220 @@
+// Package spake2 implements the SPAKE2 balanced PAKE (RFC 9382) over ristretto255 and
+// P-256, for two parties that share a low-entropy password such as a pairing code. The
+// initiator (A in the RFC) sends pA = x*G + w*M, the responder (B) answers with
+// pB = y*G + w*N and its key confirmation MAC, and the initiator finishes with its own MAC.
+// Each side learns the session key Ke only after checking the other's MAC.
+//
+// The transcript TT is the RFC 9382 one: A, B, pA, pB, K and w, each framed with an 8-byte
+// little-endian length. Ke || Ka = Hash(TT), KcA || KcB = HKDF(Ka, nil, "ConfirmationKeys"
+// || AAD), and the MACs are HMAC(KcA, TT) and HMAC(KcB, TT). P-256 elements are
+// uncompressed SEC1 points, as in the RFC. Messages on the wire are framed with
+// util.FramedConcat and 2-byte length prefixes.
+package spake2
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Suite is a SPAKE2 ciphersuite identifier.
+type Suite string
+
+const (
+	Ristretto255Sha512 Suite = "SPAKE2-ristretto255-SHA512-HKDF-HMAC"
+	P256Sha256         Suite = "SPAKE2-P256-SHA256-HKDF-HMAC"
+)
+
+// lengthPrefix is the length prefix of every frame in a wire message.
+const lengthPrefix = 2
+
+var (
+	ErrUnsupportedSuite = errors.New("spake2: unsupported suite")
+	ErrInvalidMessage   = errors.New("spake2: malformed message or invalid element")
+	ErrAuthentication   = errors.New("spake2: key confirmation failed")
+	ErrState            = errors.New("spake2: state machine used out of order or reused")
+)
+
+// Config is what both parties must agree on before the exchange.
+type Config struct {
+	Suite Suite
+	// IdentityA and IdentityB name the initiator and the responder. Either may be empty.
+	IdentityA []byte
+	IdentityB []byte
+	// AAD is bound into the confirmation keys, so both sides must use the same value.
+	AAD []byte
+	// KSF stretches the password before it is hashed to the scalar w; nil is the identity.
+	// RFC 9382 recommends a memory-hard function, such as Argon2id with fixed parameters
+	// and salt, for short codes.
+	KSF func(password []byte) ([]byte, error)
+}
+
+// The fixed elements M and N. For P-256 they are the RFC 9382 constants. For ristretto255,
+// which the RFC does not cover, each is the ristretto255 one-way map of SHA-512 of the seed
+// "SPAKE2 ristretto255 point generation seed (M)" or "(N)", so that no discrete logarithm
+// is known, as for the RFC's own constants.
+var (
+	p256M = mustP256("02886e2f97ace46e55ba9dd7242579f2993b64e16ef3dcab95afd497333d8fa12f")
+	p256N = mustP256("03d8bbd6c639c62937b04d997f38c3770719c629d7014d49a24b4f98baa1292b49")
+
+	ristrettoM = mustRistretto("SPAKE2 ristretto255 point generation seed (M)")
+	ristrettoN = mustRistretto("SPAKE2 ristretto255 point generation seed (N)")
+)
+
+func mustP256(s string) group.Element {
+	b, _ := hex.DecodeString(s)
+	e, err := group.P256.DecodeElement(b)
+	if err != nil {
+		panic("spake2: bad P-256 constant")
+	}
+	return e
+}
+
+func mustRistretto(seed string) group.Element {
+	h := sha512.Sum512([]byte(seed))
+	r, err := group.RistrettoElementFromUniformBytes(h[:])
+	if err != nil {
+		panic(err)
+	}
+	e, err := group.Ristretto255.DecodeElement(r.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// params is a Config with its suite resolved.
+type params struct {
+	cfg      Config
+	g        group.Group
+	hashBits int
+	m, n     group.Element
+}
+
+func newParams(cfg Config) (*params, error) {
+	p := &params{cfg: cfg}
+	switch cfg.Suite {
+	case Ristretto255Sha512:
+		p.g, p.hashBits, p.m, p.n = group.Ristretto255, 512, ristrettoM, ristrettoN
+	case P256Sha256:
+		p.g, p.hashBits, p.m, p.n = group.P256, 256, p256M, p256N
+	default:
+		return nil, ErrUnsupportedSuite
+	}
+	return p, nil
+}
+
+// passwordScalar is w = HashToScalar(KSF(password)) under the DST "SPAKE2-w-" || suite.
+func (p *params) passwordScalar(password []byte) (group.Scalar, error) {
+	if p.cfg.KSF != nil {
+		stretched, err := p.cfg.KSF(password)
+		if err != nil {
+			return nil, err
+		}
+		password = stretched
+	}
+	return p.g.HashToScalar(password, []byte("SPAKE2-w-"+string(p.cfg.Suite)))
+}
+
+func encode(e group.Element) []byte {
+	b, _ := group.MarshalUncompressed(e)
+	return b
+}
+
+// decodeShare parses pA or pB, rejecting the identity.
+func (p *params) decodeShare(b []byte) (group.Element, error) {
+	e, err := group.DecodeUncompressed(p.g, b)
+	if err != nil || e.IsIdentity() {
+		return nil, ErrInvalidMessage
+	}
+	return e, nil
+}
+
+// share is x*G + w*blind, the public share of one side.
+func (p *params) share(x, w group.Scalar, blind group.Element) group.Element {
+	return p.g.Generator().ScalarMult(x).Add(blind.ScalarMult(w))
+}
+
+// sharedElement is K = x*(peer - w*blind); the cofactor of both groups is 1.
+func (p *params) sharedElement(x, w group.Scalar, peer, blind group.Element) (group.Element, error) {
+	k := peer.Subtract(blind.ScalarMult(w)).ScalarMult(x)
+	if k.IsIdentity() {
+		return nil, ErrInvalidMessage
+	}
+	return k, nil
+}
+
+// keys is the RFC 9382 key schedule for one exchange.
+type keys struct {
+	ke, ka   []byte
+	kcA, kcB []byte
+	macA     []byte
+	macB     []byte
+}
+
+// transcript is TT: each field prefixed with its length as 8 little-endian bytes.
+func transcript(fields ...[]byte) []byte {
+	var out []byte
+	for _, f := range fields {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(f)))
+		out = append(out, f...)
+	}
+	return out
+}
+
+func (p *params) keySchedule(pA, pB, k group.Element, w group.Scalar) (*keys, error) {
+	wb, _ := w.MarshalBinary()
+	tt := transcript(p.cfg.IdentityA, p.cfg.IdentityB, encode(pA), encode(pB), encode(k), wb)
+	h, err := util.Sha2Hash(tt, p.hashBits)
+	if err != nil {
+		return nil, err
+	}
+	ks := &keys{ke: h[:len(h)/2], ka: h[len(h)/2:]}
+	kc, err := util.Hkdf(ks.ka, nil, util.ConcatBytes([]byte("ConfirmationKeys"), p.cfg.AAD), len(h), p.hashBits)
+	if err != nil {
+		return nil, err
+	}
+	ks.kcA, ks.kcB = kc[:len(kc)/2], kc[len(kc)/2:]
+	if ks.macA, err = util.HmacSha2(ks.kcA, tt, p.hashBits); err != nil {
+		return nil, err
+	}
+	if ks.macB, err = util.HmacSha2(ks.kcB, tt, p.hashBits); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// frame and unframe are the wire encoding of a message: its fields framed with 2-byte
+// length prefixes.
+func frame(fields ...[]byte) ([]byte, error) {
+	ff := make([]util.FramedField, len(fields))
+	for i, f := range fields {
+		ff[i] = util.FieldOf(f)
+	}
+	out, err := util.FramedConcat(lengthPrefix, ff...)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return out, nil
+}
+
+func unframe(msg []byte, count int) ([][]byte, error) {
+	fields, err := util.ParseFramed(msg, lengthPrefix)
+	if err != nil || len(fields) != count {
+		return nil, ErrInvalidMessage
+	}
+	return fields, nil
+}
+
+// stage tracks a state machine: each step runs once, in order.
+type stage int
+
+const (
+	stageNew stage = iota
+	stageStarted
+	stageDone
+)
@@ -0,0 +1,166 @@
+package spake2
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+var suites = []Suite{Ristretto255Sha512, P256Sha256}
+
+// run performs a full exchange and returns the initiator's and responder's keys, or the
+// first error.
+func run(t *testing.T, cfgA, cfgB Config, pwA, pwB []byte) ([]byte, []byte, error) {
+	t.Helper()
+	a, err := NewInitiator(cfgA, pwA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewResponder(cfgB, pwB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg1, err := a.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := b.Respond(msg1)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg3, keyA, err := a.Finish(msg2)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyB, err := b.Finish(msg3)
+	return keyA, keyB, err
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, suite := range suites {
+		cfg := Config{Suite: suite, IdentityA: []byte("phone"), IdentityB: []byte("tv"), AAD: []byte("pairing")}
+		keyA, keyB, err := run(t, cfg, cfg, []byte("493817"), []byte("493817"))
+		if err != nil {
+			t.Fatalf("%s: %v", suite, err)
+		}
+		if !bytes.Equal(keyA, keyB) {
+			t.Fatalf("%s: keys differ", suite)
+		}
+		again, _, err := run(t, cfg, cfg, []byte("493817"), []byte("493817"))
+		if err != nil || bytes.Equal(keyA, again) {
+			t.Fatalf("%s: second run: %v", suite, err)
+		}
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	cfg := Config{Suite: Ristretto255Sha512}
+	if _, _, err := run(t, cfg, cfg, []byte("493817"), []byte("493818")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("password: got %v want ErrAuthentication", err)
+	}
+	other := cfg
+	other.IdentityB = []byte("mallory")
+	if _, _, err := run(t, cfg, other, []byte("pw"), []byte("pw")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("identity: got %v want ErrAuthentication", err)
+	}
+	other = cfg
+	other.AAD = []byte("other")
+	if _, _, err := run(t, cfg, other, []byte("pw"), []byte("pw")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("aad: got %v want ErrAuthentication", err)
+	}
+}
+
+func TestKSF(t *testing.T) {
+	cfg := Config{Suite: P256Sha256, KSF: func(b []byte) ([]byte, error) { return util.Sha2Hash(b, 256) }}
+	if _, _, err := run(t, cfg, cfg, []byte("pw"), []byte("pw")); err != nil {
+		t.Fatal(err)
+	}
+	plain := Config{Suite: P256Sha256}
+	if _, _, err := run(t, cfg, plain, []byte("pw"), []byte("pw")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestForgedInitiatorMAC(t *testing.T) {
+	cfg := Config{Suite: P256Sha256}
+	a, _ := NewInitiator(cfg, []byte("pw"))
+	b, _ := NewResponder(cfg, []byte("pw"))
+	msg1, _ := a.Start()
+	if _, err := b.Respond(msg1); err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := frame(make([]byte, 32))
+	if _, err := b.Finish(forged); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestStateReuse(t *testing.T) {
+	cfg := Config{Suite: Ristretto255Sha512}
+	a, _ := NewInitiator(cfg, []byte("pw"))
+	if _, _, err := a.Finish(nil); !errors.Is(err, ErrState) {
+		t.Fatalf("finish before start: got %v", err)
+	}
+	a, _ = NewInitiator(cfg, []byte("pw"))
+	b, _ := NewResponder(cfg, []byte("pw"))
+	if _, err := b.Finish(nil); !errors.Is(err, ErrState) {
+		t.Fatalf("responder finish first: got %v", err)
+	}
+	b, _ = NewResponder(cfg, []byte("pw"))
+	msg1, _ := a.Start()
+	if _, err := a.Start(); !errors.Is(err, ErrState) {
+		t.Fatalf("second start: got %v", err)
+	}
+	msg2, err := b.Respond(msg1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Respond(msg1); !errors.Is(err, ErrState) {
+		t.Fatalf("second respond: got %v", err)
+	}
+	msg3, _, err := a.Finish(msg2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := a.Finish(msg2); !errors.Is(err, ErrState) {
+		t.Fatalf("second initiator finish: got %v", err)
+	}
+	if _, err := b.Finish(msg3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Finish(msg3); !errors.Is(err, ErrState) {
+		t.Fatalf("second responder finish: got %v", err)
+	}
+}
+
+func TestMalformedMessages(t *testing.T) {
+	if _, err := NewInitiator(Config{Suite: "SPAKE2-edwards25519-SHA256-HKDF-HMAC"}, nil); !errors.Is(err, ErrUnsupportedSuite) {
+		t.Fatalf("suite: got %v", err)
+	}
+	for _, suite := range suites {
+		cfg := Config{Suite: suite}
+		p, _ := newParams(cfg)
+		identity, _ := frame(encode(p.g.Identity()))
+		compressed, _ := p.g.Generator().MarshalBinary()
+		generator, _ := frame(encode(p.g.Generator()))
+		for name, msg := range map[string][]byte{
+			"identity":   identity,
+			"truncated":  {0x00, 0x20, 0x01},
+			"two frames": append(append([]byte(nil), generator...), generator...),
+		} {
+			b, _ := NewResponder(cfg, []byte("pw"))
+			if _, err := b.Respond(msg); !errors.Is(err, ErrInvalidMessage) {
+				t.Fatalf("%s %s: got %v want ErrInvalidMessage", suite, name, err)
+			}
+		}
+		if suite == P256Sha256 {
+			msg, _ := frame(compressed)
+			b, _ := NewResponder(cfg, []byte("pw"))
+			if _, err := b.Respond(msg); !errors.Is(err, ErrInvalidMessage) {
+				t.Fatalf("compressed point: got %v want ErrInvalidMessage", err)
+			}
+		}
+	}
+}
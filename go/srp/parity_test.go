@@ -0,0 +1,165 @@
+package srp
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type parityVectors struct {
+	Srp struct {
+		Groups []struct {
+			Bits int
+			G    int64
+			N    string
+		}
+		Vectors []struct {
+			Name               string
+			Group              int
+			Hash               string
+			Identity, Password string
+			Salt               string
+			SecretA, SecretB   string
+			K, X, V, U         string
+			PublicA, PublicB   string
+			Premaster          string
+			Key, M1, M2        string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func mustInt(s string) *big.Int {
+	return new(big.Int).SetBytes(mustHex(s))
+}
+
+func TestParity_Groups(t *testing.T) {
+	for _, tc := range loadVectors(t).Srp.Groups {
+		g, err := GroupByBits(tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.G.Int64() != tc.G || g.N.Cmp(mustInt(tc.N)) != 0 || g.Size()*8 != tc.Bits {
+			t.Fatalf("group %d does not match RFC 5054", tc.Bits)
+		}
+	}
+}
+
+// sha1Params swaps in SHA-1, the hash of the RFC 5054 appendix B vector; the package itself
+// only offers SHA-2.
+func sha1Params(g *Group) *params {
+	p, _ := newParams(g, 256)
+	p.hash = func(data []byte) []byte {
+		h := sha1.Sum(data)
+		return h[:]
+	}
+	p.k = p.hashInt(g.N.Bytes(), p.pad(g.G))
+	return p
+}
+
+func TestParity_Vectors(t *testing.T) {
+	for _, tc := range loadVectors(t).Srp.Vectors {
+		g, err := GroupByBits(tc.Group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p *params
+		if tc.Hash == "SHA-1" {
+			p = sha1Params(g)
+		} else {
+			bits, err := strconv.Atoi(strings.TrimPrefix(tc.Hash, "SHA-"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p, err = newParams(g, bits); err != nil {
+				t.Fatal(err)
+			}
+		}
+		identity, password, salt := mustHex(tc.Identity), mustHex(tc.Password), mustHex(tc.Salt)
+		check := func(field string, got *big.Int, want string) {
+			t.Helper()
+			if got.Cmp(mustInt(want)) != 0 {
+				t.Fatalf("%s %s: got %x want %s", tc.Name, field, got, want)
+			}
+		}
+		check("k", p.k, tc.K)
+		x := p.x(identity, password, salt)
+		check("x", x, tc.X)
+		verifier := p.pad(p.exp(g.G, x))
+		if hex.EncodeToString(verifier) != tc.V {
+			t.Fatalf("%s v: got %x want %s", tc.Name, verifier, tc.V)
+		}
+
+		client := &Client{p: p, identity: identity, password: password}
+		server := &Server{p: p, identity: identity, salt: salt, v: mustInt(tc.V)}
+		A, err := client.start(mustInt(tc.SecretA))
+		if err != nil || hex.EncodeToString(A) != tc.PublicA {
+			t.Fatalf("%s A: got %x want %s", tc.Name, A, tc.PublicA)
+		}
+		B, err := server.start(mustInt(tc.SecretB))
+		if err != nil || hex.EncodeToString(B) != tc.PublicB {
+			t.Fatalf("%s B: got %x want %s", tc.Name, B, tc.PublicB)
+		}
+		check("u", p.u(client.A, server.B), tc.U)
+
+		m1, err := client.Finish(salt, B)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		m2, serverKey, err := server.Finish(A, m1)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		clientKey, err := client.Verify(m2)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		premaster := p.pad(p.exp(new(big.Int).Mul(client.A, p.exp(server.v, p.u(client.A, server.B))), mustInt(tc.SecretB)))
+		if hex.EncodeToString(premaster) != tc.Premaster {
+			t.Fatalf("%s S: got %x want %s", tc.Name, premaster, tc.Premaster)
+		}
+		if tc.Key == "" {
+			continue
+		}
+		for _, f := range []struct {
+			name string
+			got  []byte
+			want string
+		}{{"K", clientKey, tc.Key}, {"server K", serverKey, tc.Key}, {"M1", m1, tc.M1}, {"M2", m2, tc.M2}} {
+			if hex.EncodeToString(f.got) != f.want {
+				t.Fatalf("%s %s: got %x want %s", tc.Name, f.name, f.got, f.want)
+			}
+		}
+	}
+}
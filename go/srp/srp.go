@@ -0,0 +1,330 @@
+// Package srp implements the SRP-6a password-authenticated key exchange (RFC 2945, with the
+// RFC 5054 groups and hashing conventions) on math/big, for clients that already speak SRP.
+// The hash is SHA-2 selected by a util.Sha2Hash bit size. Every group element that is
+// hashed or sent — g, A, B, v and the premaster secret S — is left-padded with
+// util.BigIntToBytes to the byte length of N, so implementations agree on every input.
+//
+// With H the chosen hash and PAD the padding above:
+//
+//	k  = H(N || PAD(g))                x = H(s || H(I || ":" || P))
+//	v  = g^x                           A = g^a, B = k*v + g^b
+//	u  = H(PAD(A) || PAD(B))           S = (B - k*g^x)^(a + u*x) = (A*v^u)^b
+//	K  = H(PAD(S))
+//	M1 = H(H(N) xor H(PAD(g)) || H(I) || s || PAD(A) || PAD(B) || K)
+//	M2 = H(PAD(A) || M1 || K)
+package srp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+const (
+	// SaltSize is the length of salts from GenerateSalt.
+	SaltSize = 32
+	// ephemeralSize is the length of the secret exponents a and b (RFC 5054 asks for at
+	// least 256 bits).
+	ephemeralSize = 32
+)
+
+var (
+	ErrUnsupportedGroup = errors.New("srp: unsupported group")
+	ErrInvalidPublicKey = errors.New("srp: invalid public ephemeral")
+	ErrAuthentication   = errors.New("srp: proof verification failed")
+	ErrState            = errors.New("srp: state machine used out of order or reused")
+)
+
+// Group is an SRP group: a safe prime N and a generator g.
+type Group struct {
+	Bits int
+	N    *big.Int
+	G    *big.Int
+}
+
+func newGroup(bits int, g int64, n string) *Group {
+	N, ok := new(big.Int).SetString(n, 16)
+	if !ok || N.BitLen() != bits {
+		panic("srp: bad group constant")
+	}
+	return &Group{Bits: bits, N: N, G: big.NewInt(g)}
+}
+
+// GroupByBits returns the RFC 5054 group with an N of the given size.
+func GroupByBits(bits int) (*Group, error) {
+	for _, g := range []*Group{Group1024, Group1536, Group2048, Group3072, Group4096, Group6144, Group8192} {
+		if g.Bits == bits {
+			return g, nil
+		}
+	}
+	return nil, ErrUnsupportedGroup
+}
+
+// Size returns the byte length of N, to which every padded value is extended.
+func (g *Group) Size() int {
+	return (g.N.BitLen() + 7) / 8
+}
+
+// params is a group with a hash, and the multiplier k derived from both.
+type params struct {
+	g    *Group
+	hash func(data []byte) []byte
+	k    *big.Int
+}
+
+func newParams(g *Group, hashBits int) (*params, error) {
+	if g == nil || g.N == nil || g.G == nil || g.N.Sign() <= 0 {
+		return nil, ErrUnsupportedGroup
+	}
+	if _, err := util.Sha2Hash(nil, hashBits); err != nil {
+		return nil, err
+	}
+	p := &params{g: g, hash: func(data []byte) []byte {
+		out, _ := util.Sha2Hash(data, hashBits)
+		return out
+	}}
+	p.k = p.hashInt(g.N.Bytes(), p.pad(g.G))
+	return p, nil
+}
+
+func (p *params) pad(v *big.Int) []byte {
+	out, err := util.BigIntToBytes(v, p.g.Size())
+	if err != nil {
+		panic(err) // every padded value is reduced modulo N
+	}
+	return out
+}
+
+func (p *params) hashInt(parts ...[]byte) *big.Int {
+	return util.BytesToBigInt(p.hash(util.ConcatBytes(parts...)))
+}
+
+func (p *params) exp(base, e *big.Int) *big.Int {
+	return new(big.Int).Exp(base, e, p.g.N)
+}
+
+// x is H(s || H(I || ":" || P)).
+func (p *params) x(identity, password, salt []byte) *big.Int {
+	inner := p.hash(util.ConcatBytes(identity, []byte(":"), password))
+	return p.hashInt(salt, inner)
+}
+
+// publicKey decodes a padded A or B, rejecting values that are 0 modulo N (RFC 5054,
+// section 2.5.4) or not exactly Size bytes.
+func (p *params) publicKey(b []byte) (*big.Int, error) {
+	if len(b) != p.g.Size() {
+		return nil, ErrInvalidPublicKey
+	}
+	v := util.BytesToBigInt(b)
+	if util.BigModPos(v, p.g.N).Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	return v, nil
+}
+
+// session is what both sides derive once the ephemerals and S are known.
+type session struct {
+	key []byte
+	m1  []byte
+	m2  []byte
+}
+
+func (p *params) u(A, B *big.Int) *big.Int {
+	return p.hashInt(p.pad(A), p.pad(B))
+}
+
+func (p *params) session(identity, salt []byte, A, B, S *big.Int) session {
+	key := p.hash(p.pad(S))
+	hn := p.hash(p.g.N.Bytes())
+	hg := p.hash(p.pad(p.g.G))
+	for i := range hn {
+		hn[i] ^= hg[i]
+	}
+	m1 := p.hash(util.ConcatBytes(hn, p.hash(identity), salt, p.pad(A), p.pad(B), key))
+	m2 := p.hash(util.ConcatBytes(p.pad(A), m1, key))
+	return session{key: key, m1: m1, m2: m2}
+}
+
+func randomExponent() (*big.Int, error) {
+	b := make([]byte, ephemeralSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return util.BytesToBigInt(b), nil
+}
+
+// GenerateSalt returns SaltSize random bytes for a new verifier.
+func GenerateSalt() ([]byte, error) {
+	b := make([]byte, SaltSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ComputeVerifier returns the padded verifier v = g^x for identity and password under salt.
+// The server stores it with the salt in place of the password.
+func ComputeVerifier(g *Group, hashBits int, identity, password, salt []byte) ([]byte, error) {
+	p, err := newParams(g, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	return p.pad(p.exp(g.G, p.x(identity, password, salt))), nil
+}
+
+// stage tracks a state machine: each step runs once, in order.
+type stage int
+
+const (
+	stageNew stage = iota
+	stageStarted
+	stageProved
+	stageDone
+)
+
+// Client is the client side of one SRP-6a exchange: Start sends A, Finish answers the
+// server's salt and B with M1, and Verify checks M2 and releases the session key.
+type Client struct {
+	p        *params
+	stage    stage
+	identity []byte
+	password []byte
+	a, A     *big.Int
+	sess     session
+}
+
+// NewClient returns a client for identity and password in group g with hash SHA-2/hashBits.
+func NewClient(g *Group, hashBits int, identity, password []byte) (*Client, error) {
+	p, err := newParams(g, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{p: p, identity: append([]byte(nil), identity...), password: append([]byte(nil), password...)}, nil
+}
+
+// Start draws the secret a and returns PAD(A).
+func (c *Client) Start() ([]byte, error) {
+	a, err := randomExponent()
+	if err != nil {
+		return nil, err
+	}
+	return c.start(a)
+}
+
+func (c *Client) start(a *big.Int) ([]byte, error) {
+	if c.stage != stageNew {
+		return nil, ErrState
+	}
+	c.stage = stageStarted
+	c.a = a
+	c.A = c.p.exp(c.p.g.G, a)
+	return c.p.pad(c.A), nil
+}
+
+// Finish computes the premaster secret from the server's salt and PAD(B) and returns M1.
+func (c *Client) Finish(salt, serverPublic []byte) ([]byte, error) {
+	if c.stage != stageStarted {
+		return nil, ErrState
+	}
+	c.stage = stageDone
+	B, err := c.p.publicKey(serverPublic)
+	if err != nil {
+		return nil, err
+	}
+	u := c.p.u(c.A, B)
+	if u.Sign() == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	x := c.p.x(c.identity, c.password, salt)
+	N := c.p.g.N
+	// S = (B - k*g^x) ^ (a + u*x) mod N
+	base := util.BigModPos(new(big.Int).Sub(B, new(big.Int).Mul(c.p.k, c.p.exp(c.p.g.G, x))), N)
+	S := c.p.exp(base, new(big.Int).Add(c.a, new(big.Int).Mul(u, x)))
+	c.sess = c.p.session(c.identity, salt, c.A, B, S)
+	c.password, c.a = nil, nil
+	c.stage = stageProved
+	return c.sess.m1, nil
+}
+
+// Verify checks the server's M2 and returns the session key K, or ErrAuthentication.
+func (c *Client) Verify(serverProof []byte) ([]byte, error) {
+	if c.stage != stageProved {
+		return nil, ErrState
+	}
+	c.stage = stageDone
+	if !hmac.Equal(serverProof, c.sess.m2) {
+		return nil, ErrAuthentication
+	}
+	return c.sess.key, nil
+}
+
+// Server is the server side of one SRP-6a exchange: Start sends B, and Finish checks the
+// client's A and M1, returning M2 and the session key.
+type Server struct {
+	p        *params
+	stage    stage
+	identity []byte
+	salt     []byte
+	v        *big.Int
+	b, B     *big.Int
+}
+
+// NewServer returns a server for the stored identity, salt and padded verifier.
+func NewServer(g *Group, hashBits int, identity, salt, verifier []byte) (*Server, error) {
+	p, err := newParams(g, hashBits)
+	if err != nil {
+		return nil, err
+	}
+	v, err := p.publicKey(verifier)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{p: p, identity: append([]byte(nil), identity...), salt: append([]byte(nil), salt...), v: v}, nil
+}
+
+// Start draws the secret b and returns PAD(B); the salt goes to the client alongside it.
+func (s *Server) Start() ([]byte, error) {
+	b, err := randomExponent()
+	if err != nil {
+		return nil, err
+	}
+	return s.start(b)
+}
+
+func (s *Server) start(b *big.Int) ([]byte, error) {
+	if s.stage != stageNew {
+		return nil, ErrState
+	}
+	s.stage = stageStarted
+	s.b = b
+	// B = (k*v + g^b) mod N
+	s.B = util.BigModPos(new(big.Int).Add(new(big.Int).Mul(s.p.k, s.v), s.p.exp(s.p.g.G, b)), s.p.g.N)
+	return s.p.pad(s.B), nil
+}
+
+// Finish checks the client's PAD(A) and M1 and returns M2 and the session key K.
+func (s *Server) Finish(clientPublic, clientProof []byte) ([]byte, []byte, error) {
+	if s.stage != stageStarted {
+		return nil, nil, ErrState
+	}
+	s.stage = stageDone
+	A, err := s.p.publicKey(clientPublic)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := s.p.u(A, s.B)
+	if u.Sign() == 0 {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	// S = (A * v^u) ^ b mod N
+	S := s.p.exp(util.BigModPos(new(big.Int).Mul(A, s.p.exp(s.v, u)), s.p.g.N), s.b)
+	sess := s.p.session(s.identity, s.salt, A, s.B, S)
+	s.b = nil
+	if !hmac.Equal(clientProof, sess.m1) {
+		return nil, nil, ErrAuthentication
+	}
+	return sess.m2, sess.key, nil
+}
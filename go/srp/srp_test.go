@@ -0,0 +1,161 @@
+package srp
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+func exchange(t *testing.T, g *Group, hashBits int, registered, attempted []byte) ([]byte, []byte, error) {
+	t.Helper()
+	identity := []byte("alice")
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := ComputeVerifier(g, hashBits, identity, registered, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewClient(g, hashBits, identity, attempted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(g, hashBits, identity, salt, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A, err := client.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	B, err := server.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(A) != g.Size() || len(B) != g.Size() {
+		t.Fatalf("ephemerals are not padded to %d bytes", g.Size())
+	}
+	m1, err := client.Finish(salt, B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, serverKey, err := server.Finish(A, m1)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientKey, err := client.Verify(m2)
+	return clientKey, serverKey, err
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		bits, hashBits int
+	}{{1024, 256}, {2048, 256}, {3072, 384}, {4096, 512}, {8192, 512}} {
+		g, err := GroupByBits(tc.bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientKey, serverKey, err := exchange(t, g, tc.hashBits, []byte("password123"), []byte("password123"))
+		if err != nil {
+			t.Fatalf("%d/%d: %v", tc.bits, tc.hashBits, err)
+		}
+		if !bytes.Equal(clientKey, serverKey) || len(clientKey) != tc.hashBits/8 {
+			t.Fatalf("%d/%d: session keys differ", tc.bits, tc.hashBits)
+		}
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	if _, _, err := exchange(t, Group2048, 256, []byte("password123"), []byte("password124")); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestForgedServerProof(t *testing.T) {
+	salt, _ := GenerateSalt()
+	client, _ := NewClient(Group1024, 256, []byte("alice"), []byte("pw"))
+	verifier, _ := ComputeVerifier(Group1024, 256, []byte("alice"), []byte("pw"), salt)
+	server, _ := NewServer(Group1024, 256, []byte("alice"), salt, verifier)
+	A, _ := client.Start()
+	B, _ := server.Start()
+	m1, err := client.Finish(salt, B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := server.Finish(A, m1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Verify(make([]byte, 32)); !errors.Is(err, ErrAuthentication) {
+		t.Fatalf("got %v want ErrAuthentication", err)
+	}
+}
+
+func TestInvalidPublicEphemerals(t *testing.T) {
+	g := Group1024
+	salt, _ := GenerateSalt()
+	verifier, _ := ComputeVerifier(g, 256, []byte("alice"), []byte("pw"), salt)
+	zero := make([]byte, g.Size())
+	n := g.N.FillBytes(make([]byte, g.Size()))
+
+	for _, bad := range [][]byte{zero, n, zero[1:]} {
+		client, _ := NewClient(g, 256, []byte("alice"), []byte("pw"))
+		if _, err := client.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Finish(salt, bad); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Fatalf("client: got %v want ErrInvalidPublicKey", err)
+		}
+		server, _ := NewServer(g, 256, []byte("alice"), salt, verifier)
+		if _, err := server.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := server.Finish(bad, make([]byte, 32)); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Fatalf("server: got %v want ErrInvalidPublicKey", err)
+		}
+	}
+}
+
+func TestStateReuse(t *testing.T) {
+	client, _ := NewClient(Group1024, 256, []byte("alice"), []byte("pw"))
+	if _, err := client.Finish(nil, nil); !errors.Is(err, ErrState) {
+		t.Fatalf("finish before start: got %v", err)
+	}
+	client, _ = NewClient(Group1024, 256, []byte("alice"), []byte("pw"))
+	if _, err := client.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Start(); !errors.Is(err, ErrState) {
+		t.Fatalf("second start: got %v", err)
+	}
+	if _, err := client.Verify(nil); !errors.Is(err, ErrState) {
+		t.Fatalf("verify before finish: got %v", err)
+	}
+
+	salt, _ := GenerateSalt()
+	verifier, _ := ComputeVerifier(Group1024, 256, []byte("alice"), []byte("pw"), salt)
+	server, _ := NewServer(Group1024, 256, []byte("alice"), salt, verifier)
+	if _, err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	A, _ := client.start(big.NewInt(7))
+	_, _, _ = server.Finish(A, nil)
+	if _, _, err := server.Finish(A, nil); !errors.Is(err, ErrState) {
+		t.Fatalf("second server finish: got %v", err)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	if _, err := GroupByBits(512); !errors.Is(err, ErrUnsupportedGroup) {
+		t.Fatalf("group: got %v", err)
+	}
+	if _, err := ComputeVerifier(Group1024, 160, nil, nil, nil); !errors.Is(err, util.ErrUnsupportedBits) {
+		t.Fatalf("hash bits: got %v", err)
+	}
+	if _, err := NewServer(Group1024, 256, nil, nil, []byte{1}); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Fatalf("short verifier: got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"errors"
 	"math/big"
+	"unicode/utf8"
 )
 
 // BytesToBigInt converts a slice of bytes into a big.Int (big-endian).
@@ -16,10 +17,10 @@ func BytesToBigInt(b []byte) *big.Int {
 // Returns []byte{0} for zero. Returns an error for negative or nil input.
 func BigIntToByteArray(v *big.Int) ([]byte, error) {
 	if v == nil {
-		return nil, errors.New("BigIntToByteArray: input is nil")
+		return nil, newError("BigIntToByteArray", ErrNilInput)
 	}
 	if v.Sign() < 0 {
-		return nil, errors.New("BigIntToByteArray only supports non-negative values")
+		return nil, newError("BigIntToByteArray", ErrNegative)
 	}
 	if v.Sign() == 0 {
 		return []byte{0}, nil
@@ -31,24 +32,34 @@ func BigIntToByteArray(v *big.Int) ([]byte, error) {
 // Returns an error if the integer is negative or doesn't fit into the specified byte length.
 func IntToBytes(i int64, byteLen int) ([]byte, error) {
 	if byteLen <= 0 {
-		return nil, errors.New("IntToBytes: byteLen must be positive")
+		return nil, newError("IntToBytes", ErrInvalidArgument, "byteLen", byteLen)
 	}
 	if i < 0 {
-		return nil, errors.New("IntToBytes only supports non-negative integers")
+		return nil, newError("IntToBytes", ErrNegative, "i", i)
 	}
-
-	v := big.NewInt(i)
-	limit := new(big.Int).Lsh(big.NewInt(1), uint(8*byteLen))
-	if v.Cmp(limit) >= 0 {
-		return nil, errors.New("IntToBytes: input does not fit in the requested byte length")
+	out, err := BigIntToBytes(big.NewInt(i), byteLen)
+	if err != nil {
+		return nil, newError("IntToBytes", ErrOverflow, "i", i, "byteLen", byteLen)
 	}
+	return out, nil
+}
 
-	out := make([]byte, byteLen)
-	for b := byteLen - 1; b >= 0; b-- {
-		out[b] = byte(new(big.Int).And(v, big.NewInt(0xff)).Uint64())
-		v.Rsh(v, 8)
+// BigIntToBytes is IntToBytes for a *big.Int: v left-padded with zeros to exactly byteLen
+// big-endian bytes. Returns an error if v is nil or negative or doesn't fit.
+func BigIntToBytes(v *big.Int, byteLen int) ([]byte, error) {
+	if v == nil {
+		return nil, newError("BigIntToBytes", ErrNilInput)
 	}
-	return out, nil
+	if byteLen <= 0 {
+		return nil, newError("BigIntToBytes", ErrInvalidArgument, "byteLen", byteLen)
+	}
+	if v.Sign() < 0 {
+		return nil, newError("BigIntToBytes", ErrNegative)
+	}
+	if (v.BitLen()+7)/8 > byteLen {
+		return nil, newError("BigIntToBytes", ErrOverflow, "byteLen", byteLen)
+	}
+	return v.FillBytes(make([]byte, byteLen)), nil
 }
 
 // ConcatBytes concatenates multiple byte slices into one.
@@ -79,7 +90,7 @@ func FramedBytesFromUint8Array(data []byte, lengthPrefixBytes int) ([]byte, erro
 // Format: [length (lengthPrefixBytes)][sign byte (0 or 1)][magnitude bytes]
 func FramedBytesFromBigInt(value *big.Int, lengthPrefixBytes int) ([]byte, error) {
 	if value == nil {
-		return nil, errors.New("FramedBytesFromBigInt: value is nil")
+		return nil, newError("FramedBytesFromBigInt", ErrNilInput)
 	}
 	isNegative := byte(0)
 	v := new(big.Int).Set(value)
@@ -104,7 +115,8 @@ func FramedBytesFromString(s string, lengthPrefixBytes int) ([]byte, error) {
 	return FramedBytesFromUint8Array(bytes, lengthPrefixBytes)
 }
 
-// FramedBytes is a generic dispatcher. Input can be []byte, *big.Int, or string.
+// FramedBytes is a generic dispatcher. Input can be []byte, *big.Int, string, or any other
+// Frameable type, encoded as FramedBytesOf. Prefer FramedBytesOf, which is checked at compile time.
 // lengthPrefixBytes specifies number of bytes used for the length prefix.
 func FramedBytes(input interface{}, lengthPrefixBytes int) ([]byte, error) {
 	switch v := input.(type) {
@@ -114,7 +126,184 @@ func FramedBytes(input interface{}, lengthPrefixBytes int) ([]byte, error) {
 		return FramedBytesFromBigInt(v, lengthPrefixBytes)
 	case string:
 		return FramedBytesFromString(v, lengthPrefixBytes)
+	case bool:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case int8:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case int16:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case int32:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case int64:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case uint8:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case uint16:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case uint32:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case uint64:
+		return FramedBytesOf(v, lengthPrefixBytes)
+	case [][]byte:
+		return FramedBytesOf(v, lengthPrefixBytes)
 	default:
-		return nil, errors.New("FramedBytes: unsupported input type")
+		return nil, newError("FramedBytes", ErrInvalidArgument)
+	}
+}
+
+// Errors returned by the framed-bytes decoders. Use errors.Is to match them.
+var (
+	ErrFrameTruncated   = errors.New("framed bytes: truncated input")
+	ErrFrameSign        = errors.New("framed bytes: invalid sign byte")
+	ErrFrameNonMinimal  = errors.New("framed bytes: non-minimal magnitude")
+	ErrFrameTrailing    = errors.New("framed bytes: trailing data")
+	ErrFrameInvalidUTF8 = errors.New("framed bytes: invalid UTF-8")
+)
+
+// FramedReader reads consecutive frames from a buffer, e.g. one built with ConcatBytes
+// from several FramedBytes outputs. All frames must use the same lengthPrefixBytes.
+type FramedReader struct {
+	buf               []byte
+	off               int
+	lengthPrefixBytes int
+}
+
+// NewFramedReader returns a reader over buf using lengthPrefixBytes for every length prefix.
+func NewFramedReader(buf []byte, lengthPrefixBytes int) *FramedReader {
+	return &FramedReader{buf: buf, lengthPrefixBytes: lengthPrefixBytes}
+}
+
+// Remaining returns the number of unread bytes.
+func (r *FramedReader) Remaining() int {
+	return len(r.buf) - r.off
+}
+
+// Finish returns ErrFrameTrailing if any bytes are left unread.
+func (r *FramedReader) Finish() error {
+	if r.Remaining() != 0 {
+		return ErrFrameTrailing
+	}
+	return nil
+}
+
+// readLength reads a big-endian length prefix and checks that many bytes (plus extra) follow.
+func (r *FramedReader) readLength(extra int) (int, error) {
+	if r.lengthPrefixBytes <= 0 {
+		return 0, newError("FramedReader", ErrInvalidArgument, "lengthPrefixBytes", r.lengthPrefixBytes)
+	}
+	if r.Remaining() < r.lengthPrefixBytes {
+		return 0, ErrFrameTruncated
+	}
+	n := BytesToBigInt(r.buf[r.off : r.off+r.lengthPrefixBytes])
+	avail := big.NewInt(int64(r.Remaining() - r.lengthPrefixBytes - extra))
+	if avail.Sign() < 0 || BigCmp(n, avail) > 0 {
+		return 0, ErrFrameTruncated
+	}
+	r.off += r.lengthPrefixBytes
+	return int(n.Int64()), nil
+}
+
+// ReadBytes reads one [length][data] frame and returns a copy of data.
+func (r *FramedReader) ReadBytes() ([]byte, error) {
+	n, err := r.readLength(0)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, n)
+	copy(out, r.buf[r.off:r.off+n])
+	r.off += n
+	return out, nil
+}
+
+// ReadBigInt reads one [length][sign byte][magnitude] frame as produced by FramedBytesFromBigInt.
+// The magnitude must be minimal: no leading zero bytes, zero encoded as a single 0x00 with sign 0.
+func (r *FramedReader) ReadBigInt() (*big.Int, error) {
+	start := r.off
+	n, err := r.readLength(1)
+	if err != nil {
+		return nil, err
+	}
+	sign := r.buf[r.off]
+	magnitude := r.buf[r.off+1 : r.off+1+n]
+	if sign > 1 {
+		r.off = start
+		return nil, ErrFrameSign
+	}
+	if n == 0 || (n > 1 && magnitude[0] == 0) || (n == 1 && magnitude[0] == 0 && sign == 1) {
+		r.off = start
+		return nil, ErrFrameNonMinimal
+	}
+	v := BytesToBigInt(magnitude)
+	if sign == 1 {
+		v.Neg(v)
+	}
+	r.off += 1 + n
+	return v, nil
+}
+
+// ReadString reads one [length][data] frame and returns data as a UTF-8 string.
+func (r *FramedReader) ReadString() (string, error) {
+	start := r.off
+	b, err := r.ReadBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		r.off = start
+		return "", ErrFrameInvalidUTF8
 	}
+	return string(b), nil
+}
+
+// UnframeBytes decodes a single frame produced by FramedBytesFromUint8Array.
+func UnframeBytes(frame []byte, lengthPrefixBytes int) ([]byte, error) {
+	r := NewFramedReader(frame, lengthPrefixBytes)
+	out, err := r.ReadBytes()
+	if err != nil {
+		return nil, withOp("UnframeBytes", err)
+	}
+	if err := r.Finish(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnframeBigInt decodes a single frame produced by FramedBytesFromBigInt.
+func UnframeBigInt(frame []byte, lengthPrefixBytes int) (*big.Int, error) {
+	r := NewFramedReader(frame, lengthPrefixBytes)
+	out, err := r.ReadBigInt()
+	if err != nil {
+		return nil, withOp("UnframeBigInt", err)
+	}
+	if err := r.Finish(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnframeString decodes a single frame produced by FramedBytesFromString.
+func UnframeString(frame []byte, lengthPrefixBytes int) (string, error) {
+	r := NewFramedReader(frame, lengthPrefixBytes)
+	out, err := r.ReadString()
+	if err != nil {
+		return "", withOp("UnframeString", err)
+	}
+	if err := r.Finish(); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// ParseFramed splits a buffer of consecutive [length][data] frames into their payloads.
+func ParseFramed(buf []byte, lengthPrefixBytes int) ([][]byte, error) {
+	r := NewFramedReader(buf, lengthPrefixBytes)
+	var out [][]byte
+	for r.Remaining() > 0 {
+		b, err := r.ReadBytes()
+		if err != nil {
+			return nil, withOp("ParseFramed", err)
+		}
+		out = append(out, b)
+	}
+	return out, nil
 }
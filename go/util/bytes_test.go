@@ -2,6 +2,7 @@ package util
 
 import (
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 )
@@ -123,3 +124,96 @@ func TestFramedBytesDispatcher(t *testing.T) {
 		t.Fatalf("string dispatch failed: %v %x", err, b)
 	}
 }
+
+func TestUnframeRoundTrip(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 255, -256, 1 << 40} {
+		fr, err := FramedBytesFromBigInt(big.NewInt(v), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := UnframeBigInt(fr, 2)
+		if err != nil {
+			t.Fatalf("unframe %d: %v", v, err)
+		}
+		if got.Int64() != v {
+			t.Fatalf("round trip %d: got %s", v, got)
+		}
+	}
+
+	fr, _ := FramedBytesFromString("héllo", 1)
+	s, err := UnframeString(fr, 1)
+	if err != nil || s != "héllo" {
+		t.Fatalf("string round trip failed: %v %q", err, s)
+	}
+}
+
+func TestUnframeErrors(t *testing.T) {
+	if _, err := UnframeBytes([]byte{0x02, 0xaa}, 1); !errors.Is(err, ErrFrameTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+	if _, err := UnframeBytes([]byte{0x01, 0xaa, 0xbb}, 1); !errors.Is(err, ErrFrameTrailing) {
+		t.Fatalf("expected trailing error, got %v", err)
+	}
+	if _, err := UnframeBigInt([]byte{0x01, 0x02, 0x01}, 1); !errors.Is(err, ErrFrameSign) {
+		t.Fatalf("expected sign error, got %v", err)
+	}
+	if _, err := UnframeBigInt([]byte{0x02, 0x00, 0x00, 0x01}, 1); !errors.Is(err, ErrFrameNonMinimal) {
+		t.Fatalf("expected non-minimal error, got %v", err)
+	}
+	if _, err := UnframeBytes([]byte{0x00}, 0); err == nil {
+		t.Fatalf("expected error for lengthPrefixBytes 0")
+	}
+}
+
+func TestFramedReader(t *testing.T) {
+	a, _ := FramedBytes([]byte{0xAA}, 1)
+	b, _ := FramedBytes(big.NewInt(-5), 1)
+	c, _ := FramedBytes("A", 1)
+	r := NewFramedReader(ConcatBytes(a, b, c), 1)
+
+	gotA, err := r.ReadBytes()
+	if err != nil || hex.EncodeToString(gotA) != "aa" {
+		t.Fatalf("read bytes failed: %v %x", err, gotA)
+	}
+	gotB, err := r.ReadBigInt()
+	if err != nil || gotB.Int64() != -5 {
+		t.Fatalf("read bigint failed: %v %v", err, gotB)
+	}
+	if r.Remaining() != len(c) {
+		t.Fatalf("remaining %d want %d", r.Remaining(), len(c))
+	}
+	gotC, err := r.ReadString()
+	if err != nil || gotC != "A" {
+		t.Fatalf("read string failed: %v %q", err, gotC)
+	}
+	if err := r.Finish(); err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	if _, err := r.ReadBytes(); !errors.Is(err, ErrFrameTruncated) {
+		t.Fatalf("expected truncated error past the end, got %v", err)
+	}
+}
+
+func TestParseFramed(t *testing.T) {
+	buf := ConcatBytes([]byte{0x01, 0xAA}, []byte{0x00}, []byte{0x02, 0xBB, 0xCC})
+	parts, err := ParseFramed(buf, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parts) != 3 || hex.EncodeToString(parts[0]) != "aa" || len(parts[1]) != 0 || hex.EncodeToString(parts[2]) != "bbcc" {
+		t.Fatalf("parse wrong: %x", parts)
+	}
+}
+
+func TestBigIntToBytes(t *testing.T) {
+	b, err := BigIntToBytes(big.NewInt(258), 4)
+	if err != nil || hex.EncodeToString(b) != "00000102" {
+		t.Fatalf("258 -> 4 bytes failed: %v %x", err, b)
+	}
+	if _, err := BigIntToBytes(big.NewInt(256), 1); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+	if _, err := BigIntToBytes(nil, 1); !errors.Is(err, ErrNilInput) {
+		t.Fatalf("expected nil input error, got %v", err)
+	}
+}
@@ -50,3 +50,31 @@ func TestUrlSafeBase64_NonAsciiAndUrlReplacements(t *testing.T) {
 		t.Fatalf("unexpected decode: %x", dec)
 	}
 }
+
+func TestBase58_RoundTrip(t *testing.T) {
+	cases := []struct {
+		hex string
+		enc string
+	}{
+		{"", ""},
+		{"00", "1"},
+		{"0000ff", "115Q"},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+	}
+	for _, tc := range cases {
+		data, _ := hex.DecodeString(tc.hex)
+		if got := EncBase58(data); got != tc.enc {
+			t.Fatalf("encode %s: got %q want %q", tc.hex, got, tc.enc)
+		}
+		dec, err := DecBase58(tc.enc)
+		if err != nil || hex.EncodeToString(dec) != tc.hex {
+			t.Fatalf("decode %q: got %x, %v want %s", tc.enc, dec, err, tc.hex)
+		}
+	}
+	if _, err := DecBase58("0OIl"); err == nil {
+		t.Fatal("expected error for characters outside the alphabet")
+	}
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors for invalid arguments to the util functions. They are returned wrapped
+// in a *ParityError; use errors.Is to match them.
+var (
+	ErrUnsupportedBits = errors.New("unsupported bit length")
+	ErrNegative        = errors.New("negative input")
+	ErrOverflow        = errors.New("input does not fit in the requested length")
+	ErrNilInput        = errors.New("nil input")
+	ErrOutputLen       = errors.New("invalid output length")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrInvalidEncoding = errors.New("invalid encoding")
+)
+
+// errorCodes maps each util sentinel to the stable code shared with the TS implementation
+// and used in the "error" fields of testdata/parity.json.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrUnsupportedBits, "unsupportedBits"},
+	{ErrNegative, "negative"},
+	{ErrOverflow, "overflow"},
+	{ErrNilInput, "nilInput"},
+	{ErrOutputLen, "outputLen"},
+	{ErrInvalidArgument, "invalidArgument"},
+	{ErrInvalidEncoding, "encoding"},
+	{ErrFrameTruncated, "truncated"},
+	{ErrFrameSign, "sign"},
+	{ErrFrameNonMinimal, "nonMinimal"},
+	{ErrFrameTrailing, "trailing"},
+	{ErrFrameInvalidUTF8, "utf8"},
+	{ErrPasswordFormat, "passwordFormat"},
+	{ErrPasswordAlgorithm, "passwordAlgorithm"},
+	{ErrPasswordParams, "passwordParams"},
+}
+
+// ErrorCode returns the stable code of the first util sentinel in err's chain,
+// or "" if err is nil or not a util error.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// ParityError records the util function that failed, the numeric parameters it was
+// called with, and the sentinel describing the failure.
+type ParityError struct {
+	Op     string           // function name, e.g. "Sha2Hash"
+	Params map[string]int64 // offending parameters by argument name, e.g. {"bits": 224}
+	Err    error            // one of the Err* sentinels
+}
+
+// newError returns a *ParityError for op. params alternates argument names and values;
+// a pair whose name is not a string, or a trailing unpaired entry, is left out.
+func newError(op string, err error, params ...any) *ParityError {
+	e := &ParityError{Op: op, Err: err}
+	if len(params) > 0 {
+		e.Params = make(map[string]int64, len(params)/2)
+		for i := 0; i+1 < len(params); i += 2 {
+			name, ok := params[i].(string)
+			if !ok {
+				continue
+			}
+			switch v := params[i+1].(type) {
+			case int:
+				e.Params[name] = int64(v)
+			case int64:
+				e.Params[name] = v
+			case uint32:
+				e.Params[name] = int64(v)
+			}
+		}
+	}
+	return e
+}
+
+// withOp re-attributes a *ParityError from a helper such as NewShake to op, the exported
+// function the caller invoked, keeping its parameters and sentinel. Other errors pass through.
+func withOp(op string, err error) error {
+	var pe *ParityError
+	if !errors.As(err, &pe) {
+		return err
+	}
+	return &ParityError{Op: op, Params: pe.Params, Err: pe.Err}
+}
+
+// Error formats the error as "util.Op(name=value, ...): message" with parameters sorted by name.
+func (e *ParityError) Error() string {
+	names := make([]string, 0, len(e.Params))
+	for name := range e.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, len(names))
+	for i, name := range names {
+		args[i] = fmt.Sprintf("%s=%d", name, e.Params[name])
+	}
+	return fmt.Sprintf("util.%s(%s): %v", e.Op, strings.Join(args, ", "), e.Err)
+}
+
+// Unwrap returns the sentinel so errors.Is works through a *ParityError.
+func (e *ParityError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the stable error code of the wrapped sentinel.
+func (e *ParityError) Code() string {
+	return ErrorCode(e.Err)
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParityError_Format(t *testing.T) {
+	_, err := IntToBytes(300, 1)
+	var pe *ParityError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %T", err)
+	}
+	if pe.Op != "IntToBytes" || pe.Params["i"] != 300 || pe.Params["byteLen"] != 1 {
+		t.Fatalf("unexpected fields %+v", pe)
+	}
+	want := "util.IntToBytes(byteLen=1, i=300): input does not fit in the requested length"
+	if err.Error() != want {
+		t.Fatalf("got %q want %q", err.Error(), want)
+	}
+	if !errors.Is(err, ErrOverflow) || pe.Code() != "overflow" {
+		t.Fatalf("errors.Is or Code mismatch: %v", pe.Code())
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	if ErrorCode(nil) != "" || ErrorCode(errors.New("other")) != "" {
+		t.Fatal("expected no code for nil and foreign errors")
+	}
+	_, err := BigIntToByteArray(nil)
+	if ErrorCode(fmt.Errorf("wrapped: %w", err)) != "nilInput" {
+		t.Fatalf("got %q", ErrorCode(err))
+	}
+	if _, err := UnframeBytes([]byte{0x05}, 1); ErrorCode(err) != "truncated" {
+		t.Fatalf("frame error: got %q", ErrorCode(err))
+	}
+	if _, err := DecBase58("abc0"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("base58: got %v", err)
+	}
+}
+
+func TestParityError_CallerOp(t *testing.T) {
+	for op, f := range map[string]func() error{
+		"ShakeHash":  func() error { _, err := ShakeHash(nil, 100, 256); return err },
+		"CShakeHash": func() error { _, err := CShakeHash(nil, 100, 256, "N", ""); return err },
+		"HmacSha2":   func() error { _, err := HmacSha2(nil, nil, 160); return err },
+		"HkdfExpand": func() error { _, err := HkdfExpand(nil, nil, 32, 160); return err },
+	} {
+		var pe *ParityError
+		if err := f(); !errors.As(err, &pe) || pe.Op != op || pe.Params["bits"] == 0 || !errors.Is(err, ErrUnsupportedBits) {
+			t.Fatalf("%s: got %v", op, err)
+		}
+	}
+	if _, err := ShakeHash(nil, 128, -8); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("negative SHAKE length: got %v", err)
+	}
+	if _, err := CShakeHash(nil, 128, -8, "N", "S"); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("negative cSHAKE length: got %v", err)
+	}
+}
+
+func TestNewError_MalformedParams(t *testing.T) {
+	e := newError("Op", ErrInvalidArgument, 1, 2, "n", 3, "dangling")
+	if len(e.Params) != 1 || e.Params["n"] != 3 {
+		t.Fatalf("got params %v", e.Params)
+	}
+}
@@ -0,0 +1,139 @@
+package util
+
+import "math/big"
+
+// oversizeDstPrefix is prepended to a domain separation tag longer than 255 bytes before
+// it is hashed down (RFC 9380, section 5.3.3).
+const oversizeDstPrefix = "H2C-OVERSIZE-DST-"
+
+// maxExpandLen is the largest lenInBytes of expand_message, fixed by its 2-byte length field.
+const maxExpandLen = 65535
+
+// xmdDstPrime returns DST_prime = DST || I2OSP(len(DST), 1), hashing an oversize DST with SHA-2.
+func xmdDstPrime(dst []byte, bits int) ([]byte, error) {
+	if len(dst) > 255 {
+		var err error
+		if dst, err = Sha2Hash(ConcatBytes([]byte(oversizeDstPrefix), dst), bits); err != nil {
+			return nil, err
+		}
+	}
+	return ConcatBytes(dst, []byte{byte(len(dst))}), nil
+}
+
+// xofDstPrime returns DST_prime for expand_message_xof, hashing an oversize DST to
+// 2k bits with SHAKE, where k is the capacity bits of the XOF.
+func xofDstPrime(dst []byte, bits int) ([]byte, error) {
+	if len(dst) > 255 {
+		var err error
+		if dst, err = ShakeHash(ConcatBytes([]byte(oversizeDstPrefix), dst), bits, 2*bits); err != nil {
+			return nil, err
+		}
+	}
+	return ConcatBytes(dst, []byte{byte(len(dst))}), nil
+}
+
+// ExpandMessageXmd is expand_message_xmd (RFC 9380, section 5.3.1) over SHA-2 with 256, 384
+// or 512 bits. It returns lenInBytes uniform bytes; lenInBytes must be at most 255 hash
+// lengths and in [1, 65535]. A DST longer than 255 bytes is hashed down first.
+func ExpandMessageXmd(msg []byte, dst []byte, lenInBytes int, bits int) ([]byte, error) {
+	h, err := NewSha2(bits)
+	if err != nil {
+		return nil, newError("ExpandMessageXmd", ErrUnsupportedBits, "bits", bits)
+	}
+	hashLen := h.Size()
+	ell := (lenInBytes + hashLen - 1) / hashLen
+	if lenInBytes < 1 || lenInBytes > maxExpandLen || ell > 255 {
+		return nil, newError("ExpandMessageXmd", ErrOutputLen, "lenInBytes", lenInBytes)
+	}
+	dstPrime, err := xmdDstPrime(dst, bits)
+	if err != nil {
+		return nil, err
+	}
+	lenField := []byte{byte(lenInBytes >> 8), byte(lenInBytes)}
+
+	// b_0 = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime)
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write(lenField)
+	h.Write([]byte{0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime), with b_1 = H(b_0 || 1 || DST_prime)
+	out := make([]byte, 0, ell*hashLen)
+	prev := make([]byte, hashLen)
+	for i := 1; i <= ell; i++ {
+		x := make([]byte, hashLen)
+		for j := range x {
+			x[j] = b0[j] ^ prev[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		prev = h.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:lenInBytes], nil
+}
+
+// ExpandMessageXof is expand_message_xof (RFC 9380, section 5.3.2) over SHAKE128 or SHAKE256
+// (bits 128 or 256). It returns lenInBytes uniform bytes, in [1, 65535]. A DST longer than
+// 255 bytes is hashed down to 2*bits bits first.
+func ExpandMessageXof(msg []byte, dst []byte, lenInBytes int, bits int) ([]byte, error) {
+	if lenInBytes < 1 || lenInBytes > maxExpandLen {
+		return nil, newError("ExpandMessageXof", ErrOutputLen, "lenInBytes", lenInBytes)
+	}
+	if bits != 128 && bits != 256 {
+		return nil, newError("ExpandMessageXof", ErrUnsupportedBits, "bits", bits)
+	}
+	dstPrime, err := xofDstPrime(dst, bits)
+	if err != nil {
+		return nil, err
+	}
+	msgPrime := ConcatBytes(msg, []byte{byte(lenInBytes >> 8), byte(lenInBytes)}, dstPrime)
+	return ShakeHash(msgPrime, bits, lenInBytes*8)
+}
+
+// Expander is an expand_message function with its hash fixed, as used by HashToField.
+type Expander func(msg []byte, dst []byte, lenInBytes int) ([]byte, error)
+
+// XmdExpander returns ExpandMessageXmd over SHA-2 with bits 256, 384 or 512.
+func XmdExpander(bits int) Expander {
+	return func(msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+		return ExpandMessageXmd(msg, dst, lenInBytes, bits)
+	}
+}
+
+// XofExpander returns ExpandMessageXof over SHAKE with bits 128 or 256.
+func XofExpander(bits int) Expander {
+	return func(msg []byte, dst []byte, lenInBytes int) ([]byte, error) {
+		return ExpandMessageXof(msg, dst, lenInBytes, bits)
+	}
+}
+
+// HashToField is hash_to_field (RFC 9380, section 5.2) for a prime field (m = 1): it returns
+// count elements of [0, modulus), each reduced from L = ceil((ceil(log2(modulus)) + k) / 8)
+// expanded bytes, where k is the target security level in bits. With modulus set to a group
+// order it is also the hash_to_scalar of protocols such as RFC 9497.
+func HashToField(msg []byte, dst []byte, count int, modulus *big.Int, securityBits int, expand Expander) ([]*big.Int, error) {
+	if modulus == nil || expand == nil {
+		return nil, newError("HashToField", ErrNilInput)
+	}
+	if modulus.Cmp(big.NewInt(2)) < 0 {
+		return nil, newError("HashToField", ErrInvalidArgument)
+	}
+	if count < 1 || securityBits < 1 {
+		return nil, newError("HashToField", ErrInvalidArgument, "count", count, "securityBits", securityBits)
+	}
+	l := (modulus.BitLen() + securityBits + 7) / 8
+	uniform, err := expand(msg, dst, count*l)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*big.Int, count)
+	for i := range out {
+		out[i] = new(big.Int).Mod(BytesToBigInt(uniform[i*l:(i+1)*l]), modulus)
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestExpandMessage_Prefix(t *testing.T) {
+	// Shorter outputs are not prefixes of longer ones: the length is part of the input.
+	dst := []byte("test-dst")
+	for _, expand := range []Expander{XmdExpander(256), XofExpander(128)} {
+		short, err := expand([]byte("msg"), dst, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		long, err := expand([]byte("msg"), dst, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(short, long[:32]) {
+			t.Fatal("output of one length is a prefix of another")
+		}
+	}
+}
+
+func TestExpandMessageXmd_MaxLength(t *testing.T) {
+	out, err := ExpandMessageXmd(nil, []byte("dst"), 255*32, 256)
+	if err != nil || len(out) != 255*32 {
+		t.Fatalf("got %d bytes, %v", len(out), err)
+	}
+	if _, err := ExpandMessageXmd(nil, []byte("dst"), 255*32+1, 256); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("got %v want ErrOutputLen", err)
+	}
+}
+
+func TestHashToField_Range(t *testing.T) {
+	p := big.NewInt(65521)
+	out, err := HashToField([]byte("msg"), []byte("dst"), 5, p, 128, XmdExpander(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 5 {
+		t.Fatalf("got %d elements", len(out))
+	}
+	for _, e := range out {
+		if e.Sign() < 0 || e.Cmp(p) >= 0 {
+			t.Fatalf("element %s out of range", e)
+		}
+	}
+}
+
+func TestHashToField_Errors(t *testing.T) {
+	p := big.NewInt(65521)
+	cases := []struct {
+		name    string
+		count   int
+		modulus *big.Int
+		expand  Expander
+		want    error
+	}{
+		{"nil modulus", 1, nil, XmdExpander(256), ErrNilInput},
+		{"nil expander", 1, p, nil, ErrNilInput},
+		{"modulus one", 1, big.NewInt(1), XmdExpander(256), ErrInvalidArgument},
+		{"zero count", 0, p, XmdExpander(256), ErrInvalidArgument},
+		{"bad bits", 1, p, XmdExpander(224), ErrUnsupportedBits},
+	}
+	for _, tc := range cases {
+		if _, err := HashToField(nil, nil, tc.count, tc.modulus, 128, tc.expand); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: got %v want %v", tc.name, err, tc.want)
+		}
+	}
+}
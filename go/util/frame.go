@@ -0,0 +1,107 @@
+package util
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// Frameable lists the types FramedBytesOf accepts. Fixed-size integers are framed as their
+// big-endian two's-complement bytes at the type's width, so int and uint are excluded.
+type Frameable interface {
+	[]byte | string | *big.Int | bool |
+		int8 | int16 | int32 | int64 |
+		uint8 | uint16 | uint32 | uint64 |
+		[][]byte
+}
+
+// framePayload returns the bytes FramedBytesOf places after the length prefix, except for
+// *big.Int, which keeps the FramedBytesFromBigInt layout and is handled by the caller.
+func framePayload[T Frameable](v T, lengthPrefixBytes int) ([]byte, error) {
+	switch x := any(v).(type) {
+	case []byte:
+		return x, nil
+	case string:
+		return []byte(x), nil
+	case bool:
+		if x {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case int8:
+		return []byte{byte(x)}, nil
+	case uint8:
+		return []byte{x}, nil
+	case int16:
+		return binary.BigEndian.AppendUint16(nil, uint16(x)), nil
+	case uint16:
+		return binary.BigEndian.AppendUint16(nil, x), nil
+	case int32:
+		return binary.BigEndian.AppendUint32(nil, uint32(x)), nil
+	case uint32:
+		return binary.BigEndian.AppendUint32(nil, x), nil
+	case int64:
+		return binary.BigEndian.AppendUint64(nil, uint64(x)), nil
+	case uint64:
+		return binary.BigEndian.AppendUint64(nil, x), nil
+	case [][]byte:
+		var out []byte
+		for _, item := range x {
+			frame, err := FramedBytesFromUint8Array(item, lengthPrefixBytes)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, frame...)
+		}
+		return out, nil
+	}
+	panic("unreachable")
+}
+
+// FramedBytesOf frames v with a lengthPrefixBytes big-endian length prefix.
+//   - []byte, string: the bytes, as FramedBytesFromUint8Array and FramedBytesFromString
+//   - *big.Int: [length][sign byte][magnitude], as FramedBytesFromBigInt
+//   - bool: one byte, 0x00 or 0x01
+//   - int8..int64, uint8..uint64: big-endian two's complement at the type's width (1, 2, 4 or 8 bytes)
+//   - [][]byte: the concatenated frames of the items, each with the same lengthPrefixBytes
+func FramedBytesOf[T Frameable](v T, lengthPrefixBytes int) ([]byte, error) {
+	if n, ok := any(v).(*big.Int); ok {
+		return FramedBytesFromBigInt(n, lengthPrefixBytes)
+	}
+	payload, err := framePayload(v, lengthPrefixBytes)
+	if err != nil {
+		return nil, err
+	}
+	return FramedBytesFromUint8Array(payload, lengthPrefixBytes)
+}
+
+// FramedField is one field of a FramedConcat transcript, created with FieldOf.
+type FramedField struct {
+	frame func(lengthPrefixBytes int) ([]byte, error)
+}
+
+// FieldOf wraps v for FramedConcat. It is encoded exactly like FramedBytesOf(v, ...).
+func FieldOf[T Frameable](v T) FramedField {
+	return FramedField{frame: func(lengthPrefixBytes int) ([]byte, error) {
+		return FramedBytesOf(v, lengthPrefixBytes)
+	}}
+}
+
+// FramedConcat frames each field with lengthPrefixBytes and concatenates the frames,
+// so that ParseFramed or a FramedReader can split the transcript again.
+func FramedConcat(lengthPrefixBytes int, fields ...FramedField) ([]byte, error) {
+	var out []byte
+	for _, f := range fields {
+		if f.frame == nil {
+			return nil, newError("FramedConcat", ErrNilInput)
+		}
+		frame, err := f.frame(lengthPrefixBytes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, frame...)
+	}
+	if out == nil {
+		out = []byte{}
+	}
+	return out, nil
+}
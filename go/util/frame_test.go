@@ -0,0 +1,72 @@
+package util
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFramedBytesOf_MatchesDispatcher(t *testing.T) {
+	cases := []struct {
+		generic func() ([]byte, error)
+		dynamic any
+	}{
+		{func() ([]byte, error) { return FramedBytesOf([]byte{0xaa}, 1) }, []byte{0xaa}},
+		{func() ([]byte, error) { return FramedBytesOf("A", 1) }, "A"},
+		{func() ([]byte, error) { return FramedBytesOf(big.NewInt(-5), 1) }, big.NewInt(-5)},
+		{func() ([]byte, error) { return FramedBytesOf(int16(-2), 1) }, int16(-2)},
+		{func() ([]byte, error) { return FramedBytesOf(true, 1) }, true},
+		{func() ([]byte, error) { return FramedBytesOf([][]byte{{1}, {2, 3}}, 1) }, [][]byte{{1}, {2, 3}}},
+	}
+	for i, tc := range cases {
+		want, err := tc.generic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := FramedBytes(tc.dynamic, 1)
+		if err != nil || hex.EncodeToString(got) != hex.EncodeToString(want) {
+			t.Fatalf("case %d: got %x, %v want %x", i, got, err, want)
+		}
+	}
+}
+
+func TestFramedConcat_RoundTrip(t *testing.T) {
+	out, err := FramedConcat(2, FieldOf("id"), FieldOf(uint64(1)<<40), FieldOf(big.NewInt(-7)), FieldOf([][]byte{{0xaa}, {}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewFramedReader(out, 2)
+	if s, err := r.ReadString(); err != nil || s != "id" {
+		t.Fatalf("string: %q, %v", s, err)
+	}
+	if b, err := r.ReadBytes(); err != nil || hex.EncodeToString(b) != "0000010000000000" {
+		t.Fatalf("uint64: %x, %v", b, err)
+	}
+	if n, err := r.ReadBigInt(); err != nil || n.Int64() != -7 {
+		t.Fatalf("bigint: %v, %v", n, err)
+	}
+	nested, err := r.ReadBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := ParseFramed(nested, 2)
+	if err != nil || len(items) != 2 || hex.EncodeToString(items[0]) != "aa" || len(items[1]) != 0 {
+		t.Fatalf("nested: %x, %v", items, err)
+	}
+	if err := r.Finish(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFramedConcat_Errors(t *testing.T) {
+	if _, err := FramedConcat(1, FieldOf(make([]byte, 256))); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("oversized field: got %v", err)
+	}
+	if _, err := FramedConcat(1, FramedField{}); !errors.Is(err, ErrNilInput) {
+		t.Fatalf("zero field: got %v", err)
+	}
+	if _, err := FramedBytesOf((*big.Int)(nil), 1); !errors.Is(err, ErrNilInput) {
+		t.Fatalf("nil big.Int: got %v", err)
+	}
+}
@@ -3,7 +3,7 @@ package util
 import (
 	"crypto/sha256"
 	"crypto/sha512"
-	"errors"
+	"hash"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -27,7 +27,7 @@ func Sha2Hash(data []byte, bits int) ([]byte, error) {
 		copy(out, h[:])
 		return out, nil
 	default:
-		return nil, errors.New("unsupported SHA-2 bit length")
+		return nil, newError("Sha2Hash", ErrUnsupportedBits, "bits", bits)
 	}
 }
 
@@ -55,71 +55,116 @@ func Sha3Hash(data []byte, bits int) ([]byte, error) {
 		copy(out, h[:])
 		return out, nil
 	default:
-		return nil, errors.New("unsupported SHA-3 bit length")
+		return nil, newError("Sha3Hash", ErrUnsupportedBits, "bits", bits)
 	}
 }
 
 // ShakeHash computes SHAKE with 128 or 256 capacity and outputLenBits length.
 func ShakeHash(data []byte, bits int, outputLenBits int) ([]byte, error) {
-	if outputLenBits%8 != 0 {
-		return nil, errors.New("output length must be a multiple of 8 bits")
+	outLen, err := outputLen("ShakeHash", outputLenBits)
+	if err != nil {
+		return nil, err
 	}
-	outLen := outputLenBits / 8
-	out := make([]byte, outLen)
-	switch bits {
-	case 128:
-		h := sha3.NewShake128()
-		if _, err := h.Write(data); err != nil {
-			return nil, err
-		}
-		if _, err := h.Read(out); err != nil {
-			return nil, err
-		}
-		return out, nil
-	case 256:
-		h := sha3.NewShake256()
-		if _, err := h.Write(data); err != nil {
-			return nil, err
-		}
-		if _, err := h.Read(out); err != nil {
-			return nil, err
-		}
-		return out, nil
-	default:
-		return nil, errors.New("unsupported SHAKE bit length")
+	h, err := NewShake(bits)
+	if err != nil {
+		return nil, withOp("ShakeHash", err)
 	}
+	return readXof(h, data, outLen)
 }
 
 // CShakeHash computes cSHAKE with 128 or 256 capacity and outputLenBits length.
 // functionName is provided by caller (N), customization is S.
 func CShakeHash(data []byte, bits int, outputLenBits int, functionName string, customization string) ([]byte, error) {
-	if outputLenBits%8 != 0 {
-		return nil, errors.New("output length must be a multiple of 8 bits")
+	outLen, err := outputLen("CShakeHash", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	h, err := NewCShake(bits, functionName, customization)
+	if err != nil {
+		return nil, withOp("CShakeHash", err)
+	}
+	return readXof(h, data, outLen)
+}
+
+// outputLen validates an XOF output length in bits for op and returns it in bytes:
+// it must be a non-negative multiple of 8.
+func outputLen(op string, outputLenBits int) (int, error) {
+	if outputLenBits < 0 || outputLenBits%8 != 0 {
+		return 0, newError(op, ErrOutputLen, "outputLenBits", outputLenBits)
 	}
-	outLen := outputLenBits / 8
+	return outputLenBits / 8, nil
+}
+
+// readXof absorbs data into h and squeezes outLen bytes.
+func readXof(h Xof, data []byte, outLen int) ([]byte, error) {
 	out := make([]byte, outLen)
+	if _, err := h.Write(data); err != nil {
+		return nil, err
+	}
+	if _, err := h.Read(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Xof is an extendable-output hash: write the input, then read as many output bytes as needed.
+// Writing after the first Read panics; use Reset to start over.
+type Xof = sha3.ShakeHash
+
+// NewSha2 returns a streaming SHA-2 hash with 256, 384, or 512 bits.
+// Writing data in chunks and calling Sum gives the same result as Sha2Hash.
+func NewSha2(bits int) (hash.Hash, error) {
+	switch bits {
+	case 256:
+		return sha256.New(), nil
+	case 384:
+		return sha512.New384(), nil
+	case 512:
+		return sha512.New(), nil
+	default:
+		return nil, newError("NewSha2", ErrUnsupportedBits, "bits", bits)
+	}
+}
+
+// NewSha3 returns a streaming SHA-3 hash with 224, 256, 384, or 512 bits.
+func NewSha3(bits int) (hash.Hash, error) {
+	switch bits {
+	case 224:
+		return sha3.New224(), nil
+	case 256:
+		return sha3.New256(), nil
+	case 384:
+		return sha3.New384(), nil
+	case 512:
+		return sha3.New512(), nil
+	default:
+		return nil, newError("NewSha3", ErrUnsupportedBits, "bits", bits)
+	}
+}
+
+// NewShake returns a streaming SHAKE XOF with 128 or 256 capacity.
+func NewShake(bits int) (Xof, error) {
+	switch bits {
+	case 128:
+		return sha3.NewShake128(), nil
+	case 256:
+		return sha3.NewShake256(), nil
+	default:
+		return nil, newError("NewShake", ErrUnsupportedBits, "bits", bits)
+	}
+}
+
+// NewCShake returns a streaming cSHAKE XOF with 128 or 256 capacity.
+// functionName is N and customization is S, as in CShakeHash.
+func NewCShake(bits int, functionName string, customization string) (Xof, error) {
 	n := []byte(functionName)
 	s := []byte(customization)
 	switch bits {
 	case 128:
-		h := sha3.NewCShake128(n, s)
-		if _, err := h.Write(data); err != nil {
-			return nil, err
-		}
-		if _, err := h.Read(out); err != nil {
-			return nil, err
-		}
-		return out, nil
+		return sha3.NewCShake128(n, s), nil
 	case 256:
-		h := sha3.NewCShake256(n, s)
-		if _, err := h.Write(data); err != nil {
-			return nil, err
-		}
-		if _, err := h.Read(out); err != nil {
-			return nil, err
-		}
-		return out, nil
+		return sha3.NewCShake256(n, s), nil
 	default:
-		return nil, errors.New("unsupported cSHAKE bit length")
+		return nil, newError("NewCShake", ErrUnsupportedBits, "bits", bits)
 	}
 }
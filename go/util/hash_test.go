@@ -2,6 +2,7 @@ package util
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 )
 
@@ -92,3 +93,46 @@ func TestCShakeHash(t *testing.T) {
 		}
 	}
 }
+
+func TestNewHash_UnsupportedBits(t *testing.T) {
+	if _, err := NewSha2(224); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("sha2 224: got %v", err)
+	}
+	if _, err := NewSha3(128); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("sha3 128: got %v", err)
+	}
+	if _, err := NewShake(512); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("shake 512: got %v", err)
+	}
+	if _, err := NewCShake(512, "", ""); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("cshake 512: got %v", err)
+	}
+}
+
+func TestNewSha2_ResetAndReuse(t *testing.T) {
+	h, err := NewSha2(256)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h.Write([]byte("garbage"))
+	h.Reset()
+	h.Write([]byte("a"))
+	h.Write([]byte("bc"))
+	if hexStr(h.Sum(nil)) != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Fatalf("sha256(abc) after reset: %x", h.Sum(nil))
+	}
+}
+
+func TestNewShake_IncrementalRead(t *testing.T) {
+	x, err := NewShake(128)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := make([]byte, 32)
+	for i := 0; i < len(out); i += 4 {
+		x.Read(out[i : i+4])
+	}
+	if hexStr(out) != "7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26" {
+		t.Fatalf("shake128 empty: %x", out)
+	}
+}
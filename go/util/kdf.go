@@ -0,0 +1,90 @@
+package util
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// HkdfExtract computes the RFC 5869 pseudorandom key HMAC-SHA2(salt, ikm) with 256, 384, or 512 bits.
+// An empty salt is replaced by a string of hash-length zeros.
+func HkdfExtract(salt []byte, ikm []byte, bits int) ([]byte, error) {
+	newHash, err := sha2Func(bits)
+	if err != nil {
+		return nil, withOp("HkdfExtract", err)
+	}
+	return hkdfExtract(newHash, salt, ikm), nil
+}
+
+// HkdfExpand expands prk into length bytes of output keying material bound to info (RFC 5869).
+// length must be at most 255 times the hash length.
+func HkdfExpand(prk []byte, info []byte, length int, bits int) ([]byte, error) {
+	newHash, err := sha2Func(bits)
+	if err != nil {
+		return nil, withOp("HkdfExpand", err)
+	}
+	return hkdfExpand("HkdfExpand", newHash, prk, info, length, bits)
+}
+
+// Hkdf runs HkdfExtract followed by HkdfExpand.
+func Hkdf(ikm []byte, salt []byte, info []byte, length int, bits int) ([]byte, error) {
+	prk, err := HkdfExtract(salt, ikm, bits)
+	if err != nil {
+		return nil, err
+	}
+	return HkdfExpand(prk, info, length, bits)
+}
+
+// HkdfSha3Extract is HkdfExtract over HMAC-SHA3 with 224, 256, 384, or 512 bits.
+func HkdfSha3Extract(salt []byte, ikm []byte, bits int) ([]byte, error) {
+	newHash, err := sha3Func(bits)
+	if err != nil {
+		return nil, withOp("HkdfSha3Extract", err)
+	}
+	return hkdfExtract(newHash, salt, ikm), nil
+}
+
+// HkdfSha3Expand is HkdfExpand over HMAC-SHA3 with 224, 256, 384, or 512 bits.
+func HkdfSha3Expand(prk []byte, info []byte, length int, bits int) ([]byte, error) {
+	newHash, err := sha3Func(bits)
+	if err != nil {
+		return nil, withOp("HkdfSha3Expand", err)
+	}
+	return hkdfExpand("HkdfSha3Expand", newHash, prk, info, length, bits)
+}
+
+// HkdfSha3 runs HkdfSha3Extract followed by HkdfSha3Expand.
+func HkdfSha3(ikm []byte, salt []byte, info []byte, length int, bits int) ([]byte, error) {
+	prk, err := HkdfSha3Extract(salt, ikm, bits)
+	if err != nil {
+		return nil, err
+	}
+	return HkdfSha3Expand(prk, info, length, bits)
+}
+
+func hkdfExtract(newHash func() hash.Hash, salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, newHash().Size())
+	}
+	m := hmac.New(newHash, salt)
+	m.Write(ikm)
+	return m.Sum(nil)
+}
+
+func hkdfExpand(op string, newHash func() hash.Hash, prk, info []byte, length, bits int) ([]byte, error) {
+	size := newHash().Size()
+	if length < 0 || length > 255*size {
+		return nil, newError(op, ErrOutputLen, "length", length, "bits", bits)
+	}
+	m := hmac.New(newHash, prk)
+	out := make([]byte, 0, length+size)
+	var t []byte
+	for counter := byte(1); len(out) < length; counter++ {
+		m.Reset()
+		m.Write(t)
+		m.Write(info)
+		m.Write([]byte{counter})
+		t = m.Sum(nil)
+		out = append(out, t...)
+	}
+	return out[:length], nil
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHkdf(t *testing.T) {
+	// RFC 5869 test case 3: empty salt and info
+	ikm := make([]byte, 22)
+	for i := range ikm {
+		ikm[i] = 0x0b
+	}
+	got, err := Hkdf(ikm, nil, nil, 42, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8"
+	if hexStr(got) != want {
+		t.Fatalf("hkdf: got %s want %s", hexStr(got), want)
+	}
+}
+
+func TestHkdfExpandLength(t *testing.T) {
+	prk := make([]byte, 32)
+	got, err := HkdfExpand(prk, nil, 255*32, 256)
+	if err != nil || len(got) != 255*32 {
+		t.Fatalf("max length: got %d bytes, err %v", len(got), err)
+	}
+	if _, err := HkdfExpand(prk, nil, 255*32+1, 256); err == nil {
+		t.Fatal("expected error for length over 255 * hash length")
+	}
+	if _, err := HkdfExpand(prk, nil, -1, 256); err == nil {
+		t.Fatal("expected error for negative length")
+	}
+	if _, err := HkdfExtract(nil, nil, 160); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported SHA-2 error, got %v", err)
+	}
+}
+
+func TestHkdfSha3ExpandLength(t *testing.T) {
+	prk := make([]byte, 28)
+	got, err := HkdfSha3Expand(prk, nil, 255*28, 224)
+	if err != nil || len(got) != 255*28 {
+		t.Fatalf("max length: got %d bytes, err %v", len(got), err)
+	}
+	if _, err := HkdfSha3Expand(prk, nil, 255*28+1, 224); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("expected output length error, got %v", err)
+	}
+	if _, err := HkdfSha3Extract(nil, nil, 128); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported SHA-3 error, got %v", err)
+	}
+}
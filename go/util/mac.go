@@ -0,0 +1,50 @@
+package util
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// sha2Func returns a SHA-2 constructor for bits, validated like Sha2Hash.
+func sha2Func(bits int) (func() hash.Hash, error) {
+	if _, err := NewSha2(bits); err != nil {
+		return nil, err
+	}
+	return func() hash.Hash {
+		h, _ := NewSha2(bits)
+		return h
+	}, nil
+}
+
+// sha3Func returns a SHA-3 constructor for bits, validated like Sha3Hash.
+func sha3Func(bits int) (func() hash.Hash, error) {
+	if _, err := NewSha3(bits); err != nil {
+		return nil, err
+	}
+	return func() hash.Hash {
+		h, _ := NewSha3(bits)
+		return h
+	}, nil
+}
+
+// HmacSha2 computes HMAC (RFC 2104) over SHA-2 with 256, 384, or 512 bits.
+func HmacSha2(key []byte, msg []byte, bits int) ([]byte, error) {
+	newHash, err := sha2Func(bits)
+	if err != nil {
+		return nil, withOp("HmacSha2", err)
+	}
+	m := hmac.New(newHash, key)
+	m.Write(msg)
+	return m.Sum(nil), nil
+}
+
+// HmacSha3 computes HMAC (RFC 2104) over SHA-3 with 224, 256, 384, or 512 bits.
+func HmacSha3(key []byte, msg []byte, bits int) ([]byte, error) {
+	newHash, err := sha3Func(bits)
+	if err != nil {
+		return nil, withOp("HmacSha3", err)
+	}
+	m := hmac.New(newHash, key)
+	m.Write(msg)
+	return m.Sum(nil), nil
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHmacSha2(t *testing.T) {
+	// RFC 4231 test case 2
+	got, err := HmacSha2([]byte("Jefe"), []byte("what do ya want for nothing?"), 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if hexStr(got) != want {
+		t.Fatalf("hmac-sha256: got %s want %s", hexStr(got), want)
+	}
+	if _, err := HmacSha2(nil, nil, 224); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported SHA-2 error, got %v", err)
+	}
+}
+
+func TestHmacSha3(t *testing.T) {
+	for _, bits := range []int{224, 256, 384, 512} {
+		got, err := HmacSha3([]byte("key"), []byte("msg"), bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != bits/8 {
+			t.Fatalf("hmac-sha3-%d: got %d bytes", bits, len(got))
+		}
+	}
+	if _, err := HmacSha3(nil, nil, 128); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported SHA-3 error, got %v", err)
+	}
+}
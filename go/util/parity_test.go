@@ -3,9 +3,11 @@ package util
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"math/big"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 )
 
@@ -22,12 +24,21 @@ type parityVectors struct {
 		BigIntToByteArray []struct {
 			Bigint, Bytes string
 			WantErr       bool
+			Error         string
 		}
 		IntToBytes []struct {
 			I       int64
 			Len     int
 			Bytes   string
 			WantErr bool
+			Error   string
+		}
+		BigIntToBytes []struct {
+			Bigint  string
+			Len     int
+			Bytes   string
+			WantErr bool
+			Error   string
 		}
 		Concat          []struct{ A, B, Out string }
 		FramedFromBytes []struct {
@@ -35,6 +46,7 @@ type parityVectors struct {
 			LenBytes int
 			Frame    string
 			WantErr  bool
+			Error    string
 		}
 		FramedFromBigInt []struct {
 			Value    string
@@ -46,11 +58,58 @@ type parityVectors struct {
 			LenBytes int
 			Frame    string
 			WantErr  bool
+			Error    string
+		}
+		FramedRules map[string]string
+		FramedOf    []struct {
+			Type     string
+			Value    json.RawMessage
+			LenBytes int
+			Frame    string
+		}
+		FramedConcat []struct {
+			LenBytes int
+			Fields   []struct {
+				Type  string
+				Value json.RawMessage
+			}
+			Out string
+		}
+		UnframeBytes []struct {
+			Frame    string
+			LenBytes int
+			Data     string
+			Error    string
+		}
+		UnframeBigInt []struct {
+			Frame    string
+			LenBytes int
+			Value    string
+			Error    string
+		}
+		UnframeString []struct {
+			Frame    string
+			LenBytes int
+			Str      string
+			Error    string
+		}
+		FramedReader []struct {
+			Buf      string
+			LenBytes int
+			Kinds    []string
+			Values   []string
+			Error    string
 		}
 	}
 	Coding struct {
-		Encode []struct{ Bytes, B64 string }
-		Decode []struct{ B64, Bytes string }
+		Encode        []struct{ Bytes, B64 string }
+		Decode        []struct{ B64, Bytes string }
+		Base58        []struct{ Bytes, B58 string }
+		Base58Invalid []struct {
+			B58    string
+			Offset int64
+			Error  string
+		}
 	}
 	Hash struct {
 		Sha2 []struct {
@@ -77,6 +136,120 @@ type parityVectors struct {
 			Msg     string
 			Hash    string
 		}
+		Streaming []struct {
+			Alg     string
+			Bits    int
+			OutBits int
+			Fn      string
+			Cust    string
+			Chunks  []string
+			Hash    string
+		}
+		HmacSha2 []struct {
+			Name          string
+			Bits          int
+			Key, Msg, Mac string
+		}
+		HmacSha3 []struct {
+			Name          string
+			Bits          int
+			Key, Msg, Mac string
+		}
+		Hkdf []struct {
+			Name            string
+			Bits            int
+			Ikm, Salt, Info string
+			Length          int
+			Prk, Okm        string
+		}
+		HkdfSha3 []struct {
+			Name            string
+			Bits            int
+			Ikm, Salt, Info string
+			Length          int
+			Prk, Okm        string
+		}
+		Kmac []struct {
+			Name          string
+			Bits, OutBits int
+			Xof           bool
+			Key, Msg      string
+			Cust          string
+			Out           string
+		}
+		TupleHash []struct {
+			Name          string
+			Bits, OutBits int
+			Xof           bool
+			Items         []string
+			Cust          string
+			Out           string
+		}
+		ParallelHash []struct {
+			Name          string
+			Bits, OutBits int
+			Xof           bool
+			BlockSize     int
+			Msg           string
+			Cust          string
+			Out           string
+		}
+	}
+	Password struct {
+		Hash []struct {
+			Name        string
+			Alg         string
+			Memory      uint32
+			Iterations  uint32
+			Parallelism uint32
+			LogN        uint32
+			BlockSize   uint32
+			HashBits    int
+			KeyLen      int
+			Password    string
+			Salt        string
+			Phc         string
+		}
+		Verify []struct {
+			Name     string
+			Phc      string
+			Password string
+			Valid    bool
+			Error    string
+		}
+	}
+	Transcript struct {
+		Runs []struct {
+			Name   string
+			Domain string
+			Bits   int
+			Steps  []struct {
+				Op       string
+				Label    string
+				Data     string
+				Value    string
+				N        int
+				Modulus  string
+				Absorbed string
+				Out      string
+			}
+		}
+	}
+	H2c struct {
+		ExpandMessage []struct {
+			Expander   string
+			Bits       int
+			Dst        string
+			Msg        string
+			LenInBytes int
+			DstPrime   string
+			Uniform    string
+		}
+	}
+	Errors []struct {
+		Op     string
+		Params map[string]int
+		Code   string
 	}
 }
 
@@ -140,8 +313,8 @@ func TestParity_Bytes(t *testing.T) {
 	for _, tc := range v.Bytes.BigIntToByteArray {
 		b, err := BigIntToByteArray(mustBigInt(tc.Bigint))
 		if tc.WantErr {
-			if err == nil {
-				t.Fatalf("bigIntToByteArray %s: expected error", tc.Bigint)
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("bigIntToByteArray %s: got error %v want %s", tc.Bigint, err, tc.Error)
 			}
 			continue
 		}
@@ -152,11 +325,26 @@ func TestParity_Bytes(t *testing.T) {
 			t.Fatalf("bigIntToByteArray %s: got %x want %s", tc.Bigint, b, tc.Bytes)
 		}
 	}
+	for _, tc := range v.Bytes.BigIntToBytes {
+		b, err := BigIntToBytes(mustBigInt(tc.Bigint), tc.Len)
+		if tc.WantErr {
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("bigIntToBytes %s,%d: got error %v want %s", tc.Bigint, tc.Len, err, tc.Error)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(b) != tc.Bytes {
+			t.Fatalf("bigIntToBytes %s,%d: got %x want %s", tc.Bigint, tc.Len, b, tc.Bytes)
+		}
+	}
 	for _, tc := range v.Bytes.IntToBytes {
 		b, err := IntToBytes(tc.I, tc.Len)
 		if tc.WantErr {
-			if err == nil {
-				t.Fatalf("intToBytes %d,%d: expected error", tc.I, tc.Len)
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("intToBytes %d,%d: got error %v want %s", tc.I, tc.Len, err, tc.Error)
 			}
 			continue
 		}
@@ -176,8 +364,8 @@ func TestParity_Bytes(t *testing.T) {
 	for _, tc := range v.Bytes.FramedFromBytes {
 		fr, err := FramedBytesFromUint8Array(mustHex(tc.Data), tc.LenBytes)
 		if tc.WantErr {
-			if err == nil {
-				t.Fatalf("framedFromBytes lenBytes=%d: expected error", tc.LenBytes)
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("framedFromBytes lenBytes=%d: got error %v want %s", tc.LenBytes, err, tc.Error)
 			}
 			continue
 		}
@@ -200,8 +388,8 @@ func TestParity_Bytes(t *testing.T) {
 	for _, tc := range v.Bytes.FramedFromString {
 		fr, err := FramedBytesFromString(tc.Str, tc.LenBytes)
 		if tc.WantErr {
-			if err == nil {
-				t.Fatalf("framedFromString lenBytes=%d: expected error", tc.LenBytes)
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("framedFromString lenBytes=%d: got error %v want %s", tc.LenBytes, err, tc.Error)
 			}
 			continue
 		}
@@ -214,6 +402,188 @@ func TestParity_Bytes(t *testing.T) {
 	}
 }
 
+// vectorField builds the FramedField for a typed vector value. Integers, bools and big
+// integers are decimal strings; bytes are hex; bytesList is an array of hex strings.
+func vectorField(t *testing.T, typ string, raw json.RawMessage) FramedField {
+	t.Helper()
+	if typ == "bytesList" {
+		var items []string
+		if err := json.Unmarshal(raw, &items); err != nil {
+			t.Fatal(err)
+		}
+		list := make([][]byte, len(items))
+		for i, item := range items {
+			list[i] = mustHex(item)
+		}
+		return FieldOf(list)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		t.Fatal(err)
+	}
+	parseInt := func(bits int) int64 {
+		n, err := strconv.ParseInt(s, 10, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	parseUint := func(bits int) uint64 {
+		n, err := strconv.ParseUint(s, 10, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	switch typ {
+	case "bytes":
+		return FieldOf(mustHex(s))
+	case "string":
+		return FieldOf(s)
+	case "bigint":
+		return FieldOf(mustBigInt(s))
+	case "bool":
+		return FieldOf(s == "true")
+	case "int8":
+		return FieldOf(int8(parseInt(8)))
+	case "int16":
+		return FieldOf(int16(parseInt(16)))
+	case "int32":
+		return FieldOf(int32(parseInt(32)))
+	case "int64":
+		return FieldOf(parseInt(64))
+	case "uint8":
+		return FieldOf(uint8(parseUint(8)))
+	case "uint16":
+		return FieldOf(uint16(parseUint(16)))
+	case "uint32":
+		return FieldOf(uint32(parseUint(32)))
+	case "uint64":
+		return FieldOf(parseUint(64))
+	}
+	t.Fatalf("unknown field type %q", typ)
+	return FramedField{}
+}
+
+func TestParity_FramedOf(t *testing.T) {
+	v := loadVectors(t)
+	if len(v.Bytes.FramedRules) == 0 {
+		t.Fatal("vector file is missing framedRules")
+	}
+	for _, tc := range v.Bytes.FramedOf {
+		got, err := FramedConcat(tc.LenBytes, vectorField(t, tc.Type, tc.Value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Frame {
+			t.Fatalf("framedOf %s %s: got %x want %s", tc.Type, tc.Value, got, tc.Frame)
+		}
+	}
+	for _, tc := range v.Bytes.FramedConcat {
+		fields := make([]FramedField, len(tc.Fields))
+		for i, f := range tc.Fields {
+			fields[i] = vectorField(t, f.Type, f.Value)
+		}
+		got, err := FramedConcat(tc.LenBytes, fields...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Out {
+			t.Fatalf("framedConcat lenBytes=%d: got %x want %s", tc.LenBytes, got, tc.Out)
+		}
+	}
+}
+
+// frameErrors maps the error names used in the vector file to the Go sentinels.
+var frameErrors = map[string]error{
+	"truncated":  ErrFrameTruncated,
+	"sign":       ErrFrameSign,
+	"nonMinimal": ErrFrameNonMinimal,
+	"trailing":   ErrFrameTrailing,
+	"utf8":       ErrFrameInvalidUTF8,
+}
+
+func checkFrameErr(t *testing.T, what string, err error, want string) bool {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", what, err)
+		}
+		return false
+	}
+	if !errors.Is(err, frameErrors[want]) || ErrorCode(err) != want {
+		t.Fatalf("%s: got error %v want %s", what, err, want)
+	}
+	return true
+}
+
+func TestParity_Unframe(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Bytes.UnframeBytes {
+		got, err := UnframeBytes(mustHex(tc.Frame), tc.LenBytes)
+		if checkFrameErr(t, "unframeBytes "+tc.Frame, err, tc.Error) {
+			continue
+		}
+		if hex.EncodeToString(got) != tc.Data {
+			t.Fatalf("unframeBytes %s: got %x want %s", tc.Frame, got, tc.Data)
+		}
+	}
+	for _, tc := range v.Bytes.UnframeBigInt {
+		got, err := UnframeBigInt(mustHex(tc.Frame), tc.LenBytes)
+		if checkFrameErr(t, "unframeBigInt "+tc.Frame, err, tc.Error) {
+			continue
+		}
+		if got.Cmp(mustBigInt(tc.Value)) != 0 {
+			t.Fatalf("unframeBigInt %s: got %s want %s", tc.Frame, got, tc.Value)
+		}
+	}
+	for _, tc := range v.Bytes.UnframeString {
+		got, err := UnframeString(mustHex(tc.Frame), tc.LenBytes)
+		if checkFrameErr(t, "unframeString "+tc.Frame, err, tc.Error) {
+			continue
+		}
+		if got != tc.Str {
+			t.Fatalf("unframeString %s: got %q want %q", tc.Frame, got, tc.Str)
+		}
+	}
+	for _, tc := range v.Bytes.FramedReader {
+		r := NewFramedReader(mustHex(tc.Buf), tc.LenBytes)
+		var err error
+		for i, kind := range tc.Kinds {
+			var got string
+			switch kind {
+			case "bytes":
+				var b []byte
+				b, err = r.ReadBytes()
+				got = hex.EncodeToString(b)
+			case "bigint":
+				var bi *big.Int
+				bi, err = r.ReadBigInt()
+				if err == nil {
+					got = bi.String()
+				}
+			case "string":
+				got, err = r.ReadString()
+			default:
+				t.Fatalf("framedReader: unknown kind %q", kind)
+			}
+			if err != nil {
+				if i != len(tc.Kinds)-1 {
+					t.Fatalf("framedReader %s read %d: unexpected error: %v", tc.Buf, i, err)
+				}
+				break
+			}
+			if got != tc.Values[i] {
+				t.Fatalf("framedReader %s read %d: got %s want %s", tc.Buf, i, got, tc.Values[i])
+			}
+		}
+		if err == nil {
+			err = r.Finish()
+		}
+		checkFrameErr(t, "framedReader "+tc.Buf, err, tc.Error)
+	}
+}
+
 func TestParity_Coding(t *testing.T) {
 	v := loadVectors(t)
 	for _, tc := range v.Coding.Encode {
@@ -231,6 +601,22 @@ func TestParity_Coding(t *testing.T) {
 			t.Fatalf("decode %s: got %x want %s", tc.B64, got, tc.Bytes)
 		}
 	}
+	for _, tc := range v.Coding.Base58 {
+		if got := EncBase58(mustHex(tc.Bytes)); got != tc.B58 {
+			t.Fatalf("base58 encode %s: got %s want %s", tc.Bytes, got, tc.B58)
+		}
+		got, err := DecBase58(tc.B58)
+		if err != nil || hex.EncodeToString(got) != tc.Bytes {
+			t.Fatalf("base58 decode %s: got %x, %v want %s", tc.B58, got, err, tc.Bytes)
+		}
+	}
+	for _, tc := range v.Coding.Base58Invalid {
+		_, err := DecBase58(tc.B58)
+		var pe *ParityError
+		if ErrorCode(err) != tc.Error || !errors.As(err, &pe) || pe.Params["offset"] != tc.Offset {
+			t.Fatalf("base58 decode %s: got %v want %s at offset %d", tc.B58, err, tc.Error, tc.Offset)
+		}
+	}
 }
 
 func TestParity_Hash(t *testing.T) {
@@ -272,3 +658,384 @@ func TestParity_Hash(t *testing.T) {
 		}
 	}
 }
+
+func TestParity_HashStreaming(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Hash.Streaming {
+		var whole []byte
+		for _, c := range tc.Chunks {
+			whole = append(whole, mustHex(c)...)
+		}
+
+		var oneShot, chunked []byte
+		var err error
+		switch tc.Alg {
+		case "sha2", "sha3":
+			newHash, hashFn := NewSha2, Sha2Hash
+			if tc.Alg == "sha3" {
+				newHash, hashFn = NewSha3, Sha3Hash
+			}
+			oneShot, err = hashFn(whole, tc.Bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := newHash(tc.Bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range tc.Chunks {
+				h.Write(mustHex(c))
+			}
+			chunked = h.Sum(nil)
+		case "shake", "cshake":
+			var x Xof
+			if tc.Alg == "shake" {
+				oneShot, err = ShakeHash(whole, tc.Bits, tc.OutBits)
+				if err == nil {
+					x, err = NewShake(tc.Bits)
+				}
+			} else {
+				oneShot, err = CShakeHash(whole, tc.Bits, tc.OutBits, tc.Fn, tc.Cust)
+				if err == nil {
+					x, err = NewCShake(tc.Bits, tc.Fn, tc.Cust)
+				}
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range tc.Chunks {
+				x.Write(mustHex(c))
+			}
+			// squeeze in two reads to cover incremental output as well
+			chunked = make([]byte, tc.OutBits/8)
+			half := len(chunked) / 2
+			x.Read(chunked[:half])
+			x.Read(chunked[half:])
+		default:
+			t.Fatalf("unknown alg %q", tc.Alg)
+		}
+		if hex.EncodeToString(oneShot) != tc.Hash {
+			t.Fatalf("%s-%d one-shot: got %x want %s", tc.Alg, tc.Bits, oneShot, tc.Hash)
+		}
+		if hex.EncodeToString(chunked) != tc.Hash {
+			t.Fatalf("%s-%d chunked %v: got %x want %s", tc.Alg, tc.Bits, tc.Chunks, chunked, tc.Hash)
+		}
+	}
+}
+
+func TestParity_Mac(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Hash.HmacSha2 {
+		got, err := HmacSha2(mustHex(tc.Key), mustHex(tc.Msg), tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Mac {
+			t.Fatalf("hmac-sha2-%d %s: got %x want %s", tc.Bits, tc.Name, got, tc.Mac)
+		}
+	}
+	for _, tc := range v.Hash.HmacSha3 {
+		got, err := HmacSha3(mustHex(tc.Key), mustHex(tc.Msg), tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Mac {
+			t.Fatalf("hmac-sha3-%d %s: got %x want %s", tc.Bits, tc.Name, got, tc.Mac)
+		}
+	}
+}
+
+func TestParity_Hkdf(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Hash.Hkdf {
+		prk, err := HkdfExtract(mustHex(tc.Salt), mustHex(tc.Ikm), tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(prk) != tc.Prk {
+			t.Fatalf("hkdf extract %s: got %x want %s", tc.Name, prk, tc.Prk)
+		}
+		okm, err := HkdfExpand(prk, mustHex(tc.Info), tc.Length, tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(okm) != tc.Okm {
+			t.Fatalf("hkdf expand %s: got %x want %s", tc.Name, okm, tc.Okm)
+		}
+		okm, err = Hkdf(mustHex(tc.Ikm), mustHex(tc.Salt), mustHex(tc.Info), tc.Length, tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(okm) != tc.Okm {
+			t.Fatalf("hkdf %s: got %x want %s", tc.Name, okm, tc.Okm)
+		}
+	}
+}
+
+func TestParity_HkdfSha3(t *testing.T) {
+	v := loadVectors(t)
+	if len(v.Hash.HkdfSha3) == 0 {
+		t.Fatal("no hkdfSha3 vectors")
+	}
+	for _, tc := range v.Hash.HkdfSha3 {
+		prk, err := HkdfSha3Extract(mustHex(tc.Salt), mustHex(tc.Ikm), tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(prk) != tc.Prk {
+			t.Fatalf("hkdf-sha3 extract %s: got %x want %s", tc.Name, prk, tc.Prk)
+		}
+		okm, err := HkdfSha3Expand(prk, mustHex(tc.Info), tc.Length, tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(okm) != tc.Okm {
+			t.Fatalf("hkdf-sha3 expand %s: got %x want %s", tc.Name, okm, tc.Okm)
+		}
+		okm, err = HkdfSha3(mustHex(tc.Ikm), mustHex(tc.Salt), mustHex(tc.Info), tc.Length, tc.Bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(okm) != tc.Okm {
+			t.Fatalf("hkdf-sha3 %s: got %x want %s", tc.Name, okm, tc.Okm)
+		}
+	}
+}
+
+func TestParity_Sp800185(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Hash.Kmac {
+		f := Kmac
+		if tc.Xof {
+			f = KmacXof
+		}
+		got, err := f(mustHex(tc.Key), mustHex(tc.Msg), tc.Bits, tc.OutBits, tc.Cust)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Out {
+			t.Fatalf("kmac%d xof=%v %s: got %x want %s", tc.Bits, tc.Xof, tc.Name, got, tc.Out)
+		}
+	}
+	for _, tc := range v.Hash.TupleHash {
+		f := TupleHash
+		if tc.Xof {
+			f = TupleHashXof
+		}
+		items := make([][]byte, len(tc.Items))
+		for i, it := range tc.Items {
+			items[i] = mustHex(it)
+		}
+		got, err := f(items, tc.Bits, tc.OutBits, tc.Cust)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Out {
+			t.Fatalf("tuplehash%d xof=%v %s: got %x want %s", tc.Bits, tc.Xof, tc.Name, got, tc.Out)
+		}
+	}
+	for _, tc := range v.Hash.ParallelHash {
+		f := ParallelHash
+		if tc.Xof {
+			f = ParallelHashXof
+		}
+		got, err := f(mustHex(tc.Msg), tc.BlockSize, tc.Bits, tc.OutBits, tc.Cust)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tc.Out {
+			t.Fatalf("parallelhash%d xof=%v %s: got %x want %s", tc.Bits, tc.Xof, tc.Name, got, tc.Out)
+		}
+	}
+}
+
+func TestParity_Password(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Password.Hash {
+		params := PasswordParams{
+			Algorithm:   tc.Alg,
+			Memory:      tc.Memory,
+			Iterations:  tc.Iterations,
+			Parallelism: tc.Parallelism,
+			LogN:        tc.LogN,
+			BlockSize:   tc.BlockSize,
+			HashBits:    tc.HashBits,
+			KeyLen:      tc.KeyLen,
+		}
+		got, err := PasswordHashWithSalt(params, mustHex(tc.Password), mustHex(tc.Salt))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if got != tc.Phc {
+			t.Fatalf("%s: got %s want %s", tc.Name, got, tc.Phc)
+		}
+		params.SaltLen = len(mustHex(tc.Salt))
+		parsed, salt, _, err := ParsePasswordHash(tc.Phc)
+		if err != nil || parsed != params || hex.EncodeToString(salt) != tc.Salt {
+			t.Fatalf("%s parse: got %+v %x, %v", tc.Name, parsed, salt, err)
+		}
+	}
+	for _, tc := range v.Password.Verify {
+		ok, err := PasswordVerify(tc.Phc, mustHex(tc.Password))
+		if tc.Error != "" {
+			if ErrorCode(err) != tc.Error {
+				t.Fatalf("%s: got error %v want %s", tc.Name, err, tc.Error)
+			}
+			continue
+		}
+		if err != nil || ok != tc.Valid {
+			t.Fatalf("%s: got %v, %v want %v", tc.Name, ok, err, tc.Valid)
+		}
+	}
+}
+
+func TestParity_Transcript(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Transcript.Runs {
+		tr, err := NewTranscript(tc.Domain, tc.Bits)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		var absorbed []byte
+		for i, step := range tc.Steps {
+			absorbed = append(absorbed, mustHex(step.Absorbed)...)
+			var got string
+			switch step.Op {
+			case "message":
+				err = tr.AppendMessage(step.Label, mustHex(step.Data))
+			case "bigint":
+				err = tr.AppendBigInt(step.Label, mustBigInt(step.Value))
+			case "fork":
+				tr, err = tr.Fork(step.Label)
+			case "challengeBytes":
+				var out []byte
+				out, err = tr.ChallengeBytes(step.Label, step.N)
+				got = hex.EncodeToString(out)
+			case "challengeScalar":
+				var out *big.Int
+				out, err = tr.ChallengeScalar(step.Label, mustBigInt(step.Modulus))
+				if err == nil {
+					got = out.String()
+				}
+			default:
+				t.Fatalf("%s: unknown op %q", tc.Name, step.Op)
+			}
+			if err != nil {
+				t.Fatalf("%s step %d: %v", tc.Name, i, err)
+			}
+			if got != step.Out {
+				t.Fatalf("%s step %d: got %s want %s", tc.Name, i, got, step.Out)
+			}
+			if step.Op != "challengeBytes" && step.Op != "challengeScalar" {
+				continue
+			}
+			// The output must be cSHAKE over exactly the absorbed bytes listed so far.
+			n := step.N
+			if step.Op == "challengeScalar" {
+				n = (mustBigInt(step.Modulus).BitLen()+7)/8 + 16
+			}
+			raw, err := CShakeHash(absorbed, tc.Bits, n*8, transcriptFunctionName, tc.Domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := hex.EncodeToString(raw)
+			if step.Op == "challengeScalar" {
+				want = new(big.Int).Mod(BytesToBigInt(raw), mustBigInt(step.Modulus)).String()
+			}
+			if got != want {
+				t.Fatalf("%s step %d layout: got %s, cSHAKE of absorbed bytes gives %s", tc.Name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestParity_ExpandMessage(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.H2c.ExpandMessage {
+		var dstPrime, out []byte
+		var err error
+		switch tc.Expander {
+		case "xmd":
+			dstPrime, err = xmdDstPrime([]byte(tc.Dst), tc.Bits)
+			if err == nil {
+				out, err = ExpandMessageXmd([]byte(tc.Msg), []byte(tc.Dst), tc.LenInBytes, tc.Bits)
+			}
+		case "xof":
+			dstPrime, err = xofDstPrime([]byte(tc.Dst), tc.Bits)
+			if err == nil {
+				out, err = ExpandMessageXof([]byte(tc.Msg), []byte(tc.Dst), tc.LenInBytes, tc.Bits)
+			}
+		default:
+			t.Fatalf("unknown expander %q", tc.Expander)
+		}
+		if err != nil {
+			t.Fatalf("%s-%d %q len %d: %v", tc.Expander, tc.Bits, tc.Msg, tc.LenInBytes, err)
+		}
+		if got := hex.EncodeToString(dstPrime); got != tc.DstPrime {
+			t.Fatalf("%s-%d DST_prime: got %s want %s", tc.Expander, tc.Bits, got, tc.DstPrime)
+		}
+		if got := hex.EncodeToString(out); got != tc.Uniform {
+			t.Fatalf("%s-%d %q len %d: got %s want %s", tc.Expander, tc.Bits, tc.Msg, tc.LenInBytes, got, tc.Uniform)
+		}
+	}
+}
+
+// callOp invokes the util function named op with the vector parameters and returns its error.
+func callOp(op string, p map[string]int) error {
+	var err error
+	switch op {
+	case "Sha2Hash":
+		_, err = Sha2Hash(nil, p["bits"])
+	case "Sha3Hash":
+		_, err = Sha3Hash(nil, p["bits"])
+	case "ShakeHash":
+		_, err = ShakeHash(nil, p["bits"], p["outputLenBits"])
+	case "CShakeHash":
+		_, err = CShakeHash(nil, p["bits"], p["outputLenBits"], "", "")
+	case "HmacSha2":
+		_, err = HmacSha2(nil, nil, p["bits"])
+	case "HmacSha3":
+		_, err = HmacSha3(nil, nil, p["bits"])
+	case "HkdfExpand":
+		_, err = HkdfExpand(make([]byte, 32), nil, p["length"], p["bits"])
+	case "HkdfSha3Expand":
+		_, err = HkdfSha3Expand(make([]byte, 32), nil, p["length"], p["bits"])
+	case "Kmac":
+		_, err = Kmac(nil, nil, p["bits"], p["outputLenBits"], "")
+	case "TupleHash":
+		_, err = TupleHash(nil, p["bits"], p["outputLenBits"], "")
+	case "ParallelHash":
+		_, err = ParallelHash(nil, p["blockSize"], p["bits"], p["outputLenBits"], "")
+	case "IntToBytes":
+		_, err = IntToBytes(int64(p["i"]), p["byteLen"])
+	case "UnframeBytes":
+		_, err = UnframeBytes(nil, p["lengthPrefixBytes"])
+	case "ExpandMessageXmd":
+		_, err = ExpandMessageXmd(nil, nil, p["lenInBytes"], p["bits"])
+	case "ExpandMessageXof":
+		_, err = ExpandMessageXof(nil, nil, p["lenInBytes"], p["bits"])
+	default:
+		panic("unknown op " + op)
+	}
+	return err
+}
+
+func TestParity_Errors(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Errors {
+		err := callOp(tc.Op, tc.Params)
+		if got := ErrorCode(err); got != tc.Code {
+			t.Fatalf("%s %v: got code %q (%v) want %q", tc.Op, tc.Params, got, err, tc.Code)
+		}
+		var pe *ParityError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%s %v: %v is not a *ParityError", tc.Op, tc.Params, err)
+		}
+		if pe.Op != tc.Op {
+			t.Fatalf("%s %v: error names op %q", tc.Op, tc.Params, pe.Op)
+		}
+		if pe.Code() != tc.Code {
+			t.Fatalf("%s %v: got Code() %q want %q", tc.Op, tc.Params, pe.Code(), tc.Code)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package util
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Password hashing algorithms, named by their PHC identifier. PBKDF2 is written as
+// "pbkdf2-sha256", "pbkdf2-sha384" or "pbkdf2-sha512" according to HashBits.
+const (
+	PasswordArgon2id = "argon2id"
+	PasswordScrypt   = "scrypt"
+	PasswordPbkdf2   = "pbkdf2"
+)
+
+// Errors returned by the password hashing functions. Use errors.Is to match them.
+var (
+	ErrPasswordFormat    = errors.New("password hash: malformed PHC string")
+	ErrPasswordAlgorithm = errors.New("password hash: unsupported algorithm")
+	ErrPasswordParams    = errors.New("password hash: invalid parameters")
+)
+
+// Upper bounds on the cost parameters. PHC strings may come from either runtime, so a
+// hash that asks for more is rejected with ErrPasswordParams before anything is allocated.
+const (
+	PasswordMaxArgon2Memory     = 1 << 20  // argon2id m, in KiB (1 GiB)
+	PasswordMaxArgon2Iterations = 64       // argon2id t
+	PasswordMaxScryptCost       = 1 << 23  // scrypt N*r*p, 1 GiB of 128-byte blocks
+	PasswordMaxPbkdf2Iterations = 10000000 // pbkdf2 i
+)
+
+// phcB64 is the PHC string B64 codec: the standard alphabet without padding.
+// Strict decoding rejects non-zero trailing bits, so every salt and hash has one encoding.
+var phcB64 = base64.RawStdEncoding.Strict()
+
+// PasswordParams selects a password hashing algorithm and its cost. Only the fields of
+// the chosen Algorithm are used; the others must be zero.
+type PasswordParams struct {
+	Algorithm string
+
+	Memory      uint32 // argon2id m, in KiB
+	Iterations  uint32 // argon2id t, pbkdf2 i
+	Parallelism uint32 // argon2id p, scrypt p
+	LogN        uint32 // scrypt ln, N = 2^ln
+	BlockSize   uint32 // scrypt r
+	HashBits    int    // pbkdf2 SHA-2 bits, 256, 384 or 512
+
+	SaltLen int
+	KeyLen  int
+}
+
+// Argon2idParams returns Argon2id with m=19456, t=2, p=1, the OWASP baseline.
+func Argon2idParams() PasswordParams {
+	return PasswordParams{Algorithm: PasswordArgon2id, Memory: 19456, Iterations: 2, Parallelism: 1, SaltLen: 16, KeyLen: 32}
+}
+
+// ScryptParams returns scrypt with ln=17, r=8, p=1, the OWASP baseline.
+func ScryptParams() PasswordParams {
+	return PasswordParams{Algorithm: PasswordScrypt, LogN: 17, BlockSize: 8, Parallelism: 1, SaltLen: 16, KeyLen: 32}
+}
+
+// Pbkdf2Params returns PBKDF2-HMAC-SHA2 with 600000 iterations for 256 bits and
+// 210000 for 384 and 512 bits. The key length is the hash length.
+func Pbkdf2Params(bits int) PasswordParams {
+	iterations := uint32(210000)
+	if bits == 256 {
+		iterations = 600000
+	}
+	return PasswordParams{Algorithm: PasswordPbkdf2, Iterations: iterations, HashBits: bits, SaltLen: 16, KeyLen: bits / 8}
+}
+
+// validate checks the cost parameters of p against their minimums and the PasswordMax*
+// bounds, ignoring SaltLen.
+func (p PasswordParams) validate(keyLen int) error {
+	if keyLen < 16 || keyLen > 1024 {
+		return ErrPasswordParams
+	}
+	switch p.Algorithm {
+	case PasswordArgon2id:
+		if p.Iterations < 1 || p.Iterations > PasswordMaxArgon2Iterations || p.Parallelism < 1 || p.Parallelism > 255 ||
+			p.Memory < 8*p.Parallelism || p.Memory > PasswordMaxArgon2Memory || p.LogN != 0 || p.BlockSize != 0 || p.HashBits != 0 {
+			return ErrPasswordParams
+		}
+	case PasswordScrypt:
+		if p.LogN < 1 || p.LogN > 30 || p.BlockSize < 1 || p.Parallelism < 1 ||
+			uint64(p.BlockSize)*uint64(p.Parallelism) >= 1<<30 ||
+			(uint64(1)<<p.LogN)*uint64(p.BlockSize)*uint64(p.Parallelism) > PasswordMaxScryptCost || p.Memory != 0 || p.Iterations != 0 || p.HashBits != 0 {
+			return ErrPasswordParams
+		}
+	case PasswordPbkdf2:
+		if _, err := sha2Func(p.HashBits); err != nil || p.Iterations < 1 || p.Iterations > PasswordMaxPbkdf2Iterations ||
+			p.Memory != 0 || p.Parallelism != 0 || p.LogN != 0 || p.BlockSize != 0 {
+			return ErrPasswordParams
+		}
+	default:
+		return ErrPasswordAlgorithm
+	}
+	return nil
+}
+
+// derive runs the password hashing function of p over password and salt.
+func (p PasswordParams) derive(password []byte, salt []byte, keyLen int) ([]byte, error) {
+	if err := p.validate(keyLen); err != nil {
+		return nil, err
+	}
+	if len(salt) < 8 || len(salt) > 1024 {
+		return nil, ErrPasswordParams
+	}
+	switch p.Algorithm {
+	case PasswordArgon2id:
+		return argon2.IDKey(password, salt, p.Iterations, p.Memory, uint8(p.Parallelism), uint32(keyLen)), nil
+	case PasswordScrypt:
+		return scrypt.Key(password, salt, 1<<p.LogN, int(p.BlockSize), int(p.Parallelism), keyLen)
+	default:
+		newHash, _ := sha2Func(p.HashBits)
+		return pbkdf2.Key(newHash, string(password), salt, int(p.Iterations), keyLen)
+	}
+}
+
+// phcID returns the PHC algorithm identifier for p.
+func (p PasswordParams) phcID() string {
+	if p.Algorithm == PasswordPbkdf2 {
+		return fmt.Sprintf("pbkdf2-sha%d", p.HashBits)
+	}
+	return p.Algorithm
+}
+
+// phcParams returns the PHC version and parameter segments for p.
+func (p PasswordParams) phcParams() string {
+	switch p.Algorithm {
+	case PasswordArgon2id:
+		return fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, p.Memory, p.Iterations, p.Parallelism)
+	case PasswordScrypt:
+		return fmt.Sprintf("ln=%d,r=%d,p=%d", p.LogN, p.BlockSize, p.Parallelism)
+	default:
+		return fmt.Sprintf("i=%d", p.Iterations)
+	}
+}
+
+// PasswordHash hashes password with a fresh random salt of params.SaltLen bytes and
+// returns the PHC string, e.g. "$argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>".
+func PasswordHash(params PasswordParams, password []byte) (string, error) {
+	if params.SaltLen < 8 || params.SaltLen > 1024 {
+		return "", ErrPasswordParams
+	}
+	salt := make([]byte, params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return PasswordHashWithSalt(params, password, salt)
+}
+
+// PasswordHashWithSalt is PasswordHash with a caller-chosen salt; params.SaltLen is ignored.
+// It exists for test vectors, so prefer PasswordHash.
+func PasswordHashWithSalt(params PasswordParams, password []byte, salt []byte) (string, error) {
+	key, err := params.derive(password, salt, params.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return "$" + params.phcID() + "$" + params.phcParams() + "$" + phcB64.EncodeToString(salt) + "$" + phcB64.EncodeToString(key), nil
+}
+
+// parsePhcUint parses a canonical decimal: no sign, no leading zeros, at most 32 bits.
+func parsePhcUint(s string) (uint32, error) {
+	if s == "" || (len(s) > 1 && s[0] == '0') || s[0] == '+' || s[0] == '-' {
+		return 0, ErrPasswordFormat
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, ErrPasswordFormat
+	}
+	return uint32(v), nil
+}
+
+// parsePhcParams parses "k1=v1,k2=v2,..." whose keys must appear exactly as in keys.
+func parsePhcParams(s string, keys ...string) ([]uint32, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != len(keys) {
+		return nil, ErrPasswordFormat
+	}
+	out := make([]uint32, len(keys))
+	for i, field := range fields {
+		value, ok := strings.CutPrefix(field, keys[i]+"=")
+		if !ok {
+			return nil, ErrPasswordFormat
+		}
+		v, err := parsePhcUint(value)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// ParsePasswordHash decodes a PHC string produced by PasswordHash into its parameters,
+// salt and hash. Parameters must appear in the canonical order and form.
+func ParsePasswordHash(encoded string) (PasswordParams, []byte, []byte, error) {
+	var p PasswordParams
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return p, nil, nil, ErrPasswordFormat
+	}
+	id, rest := parts[1], parts[2:]
+	switch {
+	case id == PasswordArgon2id:
+		if len(rest) != 4 || rest[0] != fmt.Sprintf("v=%d", argon2.Version) {
+			return p, nil, nil, ErrPasswordFormat
+		}
+		v, err := parsePhcParams(rest[1], "m", "t", "p")
+		if err != nil {
+			return p, nil, nil, err
+		}
+		p = PasswordParams{Algorithm: PasswordArgon2id, Memory: v[0], Iterations: v[1], Parallelism: v[2]}
+		rest = rest[2:]
+	case id == PasswordScrypt:
+		if len(rest) != 3 {
+			return p, nil, nil, ErrPasswordFormat
+		}
+		v, err := parsePhcParams(rest[0], "ln", "r", "p")
+		if err != nil {
+			return p, nil, nil, err
+		}
+		p = PasswordParams{Algorithm: PasswordScrypt, LogN: v[0], BlockSize: v[1], Parallelism: v[2]}
+		rest = rest[1:]
+	case strings.HasPrefix(id, "pbkdf2-sha"):
+		bits, err := parsePhcUint(strings.TrimPrefix(id, "pbkdf2-sha"))
+		if err != nil || len(rest) != 3 {
+			return p, nil, nil, ErrPasswordFormat
+		}
+		if _, err := sha2Func(int(bits)); err != nil {
+			return p, nil, nil, ErrPasswordAlgorithm
+		}
+		v, err := parsePhcParams(rest[0], "i")
+		if err != nil {
+			return p, nil, nil, err
+		}
+		p = PasswordParams{Algorithm: PasswordPbkdf2, Iterations: v[0], HashBits: int(bits)}
+		rest = rest[1:]
+	default:
+		return p, nil, nil, ErrPasswordAlgorithm
+	}
+	salt, err := phcB64.DecodeString(rest[0])
+	if err != nil {
+		return p, nil, nil, ErrPasswordFormat
+	}
+	key, err := phcB64.DecodeString(rest[1])
+	if err != nil {
+		return p, nil, nil, ErrPasswordFormat
+	}
+	p.SaltLen, p.KeyLen = len(salt), len(key)
+	if err := p.validate(len(key)); err != nil {
+		return p, nil, nil, err
+	}
+	if len(salt) < 8 || len(salt) > 1024 {
+		return p, nil, nil, ErrPasswordParams
+	}
+	return p, salt, key, nil
+}
+
+// PasswordVerify reports whether password matches the PHC string encoded.
+// A malformed string is an error, not a mismatch.
+func PasswordVerify(encoded string, password []byte) (bool, error) {
+	params, salt, want, err := ParsePasswordHash(encoded)
+	if err != nil {
+		return false, err
+	}
+	got, err := params.derive(password, salt, len(want))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// NeedsRehash reports whether encoded was produced with anything other than params:
+// a different algorithm, cost, salt length or key length.
+func NeedsRehash(encoded string, params PasswordParams) (bool, error) {
+	current, _, _, err := ParsePasswordHash(encoded)
+	if err != nil {
+		return false, err
+	}
+	return current != params, nil
+}
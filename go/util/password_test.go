@@ -0,0 +1,98 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordHash_RandomSaltRoundTrip(t *testing.T) {
+	params := PasswordParams{Algorithm: PasswordArgon2id, Memory: 64, Iterations: 1, Parallelism: 1, SaltLen: 16, KeyLen: 32}
+	a, err := PasswordHash(params, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := PasswordHash(params, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b || !strings.HasPrefix(a, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("unexpected hashes %s and %s", a, b)
+	}
+	for _, s := range []string{a, b} {
+		ok, err := PasswordVerify(s, []byte("hunter2"))
+		if err != nil || !ok {
+			t.Fatalf("verify %s: %v, %v", s, ok, err)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	params := PasswordParams{Algorithm: PasswordScrypt, LogN: 4, BlockSize: 8, Parallelism: 1, SaltLen: 16, KeyLen: 32}
+	s, err := PasswordHash(params, []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rehash, err := NeedsRehash(s, params); err != nil || rehash {
+		t.Fatalf("same params: got %v, %v", rehash, err)
+	}
+	stronger := params
+	stronger.LogN = 5
+	if rehash, _ := NeedsRehash(s, stronger); !rehash {
+		t.Fatal("expected rehash for a higher cost")
+	}
+	longer := params
+	longer.KeyLen = 64
+	if rehash, _ := NeedsRehash(s, longer); !rehash {
+		t.Fatal("expected rehash for a longer key")
+	}
+	if rehash, _ := NeedsRehash(s, Pbkdf2Params(256)); !rehash {
+		t.Fatal("expected rehash for another algorithm")
+	}
+	if _, err := NeedsRehash("$scrypt$ln=4", params); !errors.Is(err, ErrPasswordFormat) {
+		t.Fatalf("malformed: got %v", err)
+	}
+}
+
+func TestPasswordHash_RejectsBadParams(t *testing.T) {
+	cases := []PasswordParams{
+		{Algorithm: "bcrypt", SaltLen: 16, KeyLen: 32},
+		{Algorithm: PasswordArgon2id, Memory: 64, Iterations: 1, Parallelism: 1, SaltLen: 4, KeyLen: 32},
+		{Algorithm: PasswordArgon2id, Memory: 64, Iterations: 1, Parallelism: 1, SaltLen: 16, KeyLen: 8},
+		{Algorithm: PasswordPbkdf2, Iterations: 1, HashBits: 224, SaltLen: 16, KeyLen: 32},
+		{Algorithm: PasswordPbkdf2, Iterations: 1, HashBits: 256, LogN: 4, SaltLen: 16, KeyLen: 32},
+	}
+	for i, p := range cases {
+		if _, err := PasswordHash(p, []byte("pw")); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestPasswordVerify_RejectsExcessiveCost(t *testing.T) {
+	for _, phc := range []string{
+		"$argon2id$v=19$m=4294967295,t=2,p=1$c29tZXNhbHQ$F1jG2CV3/Nr+yRuIsPKw0J9r4s7cJHBU",
+		"$argon2id$v=19$m=19456,t=4294967295,p=1$c29tZXNhbHQ$F1jG2CV3/Nr+yRuIsPKw0J9r4s7cJHBU",
+		"$scrypt$ln=30,r=8,p=1$c29tZXNhbHQ$F1jG2CV3/Nr+yRuIsPKw0J9r4s7cJHBU",
+		"$scrypt$ln=20,r=8,p=2$c29tZXNhbHQ$F1jG2CV3/Nr+yRuIsPKw0J9r4s7cJHBU",
+		"$pbkdf2-sha256$i=4294967295$c29tZXNhbHQ$F1jG2CV3/Nr+yRuIsPKw0J9r4s7cJHBU",
+	} {
+		if _, err := PasswordVerify(phc, []byte("password")); !errors.Is(err, ErrPasswordParams) {
+			t.Fatalf("%s: got %v want ErrPasswordParams", phc, err)
+		}
+	}
+	p := Argon2idParams()
+	p.Memory = PasswordMaxArgon2Memory + 1
+	if _, err := PasswordHash(p, []byte("pw")); !errors.Is(err, ErrPasswordParams) {
+		t.Fatalf("hash over the memory cap: got %v", err)
+	}
+}
+
+func TestPbkdf2Params_Defaults(t *testing.T) {
+	for _, bits := range []int{256, 384, 512} {
+		p := Pbkdf2Params(bits)
+		if p.KeyLen != bits/8 || p.Iterations < 210000 {
+			t.Fatalf("bits %d: %+v", bits, p)
+		}
+	}
+}
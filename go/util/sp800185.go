@@ -0,0 +1,161 @@
+package util
+
+import "math/bits"
+
+// leftEncode is the SP 800-185 left_encode: the byte length of x followed by x big-endian.
+func leftEncode(x uint64) []byte {
+	n := (bits.Len64(x) + 7) / 8
+	if n == 0 {
+		n = 1
+	}
+	out := make([]byte, n+1)
+	out[0] = byte(n)
+	for i := n; i > 0; i-- {
+		out[i] = byte(x)
+		x >>= 8
+	}
+	return out
+}
+
+// rightEncode is the SP 800-185 right_encode: x big-endian followed by its byte length.
+func rightEncode(x uint64) []byte {
+	l := leftEncode(x)
+	return append(l[1:], l[0])
+}
+
+// encodeString is the SP 800-185 encode_string: left_encode of the bit length, then s.
+func encodeString(s []byte) []byte {
+	return ConcatBytes(leftEncode(uint64(len(s))*8), s)
+}
+
+// bytepad prefixes x with left_encode(w) and zero-pads the result to a multiple of w bytes.
+func bytepad(x []byte, w int) []byte {
+	out := ConcatBytes(leftEncode(uint64(w)), x)
+	if r := len(out) % w; r != 0 {
+		out = append(out, make([]byte, w-r)...)
+	}
+	return out
+}
+
+// sp800185Rate returns the cSHAKE rate in bytes for 128 or 256 capacity.
+func sp800185Rate(bits int) int {
+	if bits == 128 {
+		return 168
+	}
+	return 136
+}
+
+// kmac absorbs key and data into cSHAKE with N = "KMAC" and squeezes outLen bytes.
+// right_encode(outBits) is appended to the input, 0 for the XOF variant. op names the caller in errors.
+func kmac(op string, key []byte, data []byte, bits int, outLen int, outBits uint64, customization string) ([]byte, error) {
+	if bits != 128 && bits != 256 {
+		return nil, newError(op, ErrUnsupportedBits, "bits", bits)
+	}
+	h, err := NewCShake(bits, "KMAC", customization)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(bytepad(encodeString(key), sp800185Rate(bits)))
+	h.Write(data)
+	return readXof(h, rightEncode(outBits), outLen)
+}
+
+// Kmac computes KMAC128 or KMAC256 over data with outputLenBits length.
+// The output length is bound into the MAC, so different lengths give unrelated outputs.
+func Kmac(key []byte, data []byte, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("Kmac", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return kmac("Kmac", key, data, bits, outLen, uint64(outputLenBits), customization)
+}
+
+// KmacXof computes KMACXOF128 or KMACXOF256 over data with outputLenBits length.
+// Shorter outputs are prefixes of longer ones.
+func KmacXof(key []byte, data []byte, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("KmacXof", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return kmac("KmacXof", key, data, bits, outLen, 0, customization)
+}
+
+// tupleHash hashes each item as encode_string into cSHAKE with N = "TupleHash". op names the caller in errors.
+func tupleHash(op string, items [][]byte, bits int, outLen int, outBits uint64, customization string) ([]byte, error) {
+	if bits != 128 && bits != 256 {
+		return nil, newError(op, ErrUnsupportedBits, "bits", bits)
+	}
+	h, err := NewCShake(bits, "TupleHash", customization)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		h.Write(encodeString(item))
+	}
+	return readXof(h, rightEncode(outBits), outLen)
+}
+
+// TupleHash computes TupleHash128 or TupleHash256 over a list of byte strings.
+// Item boundaries are part of the input, so ["ab", "c"] and ["a", "bc"] hash differently.
+func TupleHash(items [][]byte, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("TupleHash", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return tupleHash("TupleHash", items, bits, outLen, uint64(outputLenBits), customization)
+}
+
+// TupleHashXof computes TupleHashXOF128 or TupleHashXOF256 over a list of byte strings.
+func TupleHashXof(items [][]byte, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("TupleHashXof", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return tupleHash("TupleHashXof", items, bits, outLen, 0, customization)
+}
+
+// parallelHash splits data into blockSize-byte blocks, hashes each with cSHAKE and
+// absorbs the concatenated block digests into cSHAKE with N = "ParallelHash". op names the caller in errors.
+func parallelHash(op string, data []byte, blockSize int, bits int, outLen int, outBits uint64, customization string) ([]byte, error) {
+	if bits != 128 && bits != 256 {
+		return nil, newError(op, ErrUnsupportedBits, "bits", bits)
+	}
+	if blockSize <= 0 {
+		return nil, newError(op, ErrInvalidArgument, "blockSize", blockSize)
+	}
+	h, err := NewCShake(bits, "ParallelHash", customization)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(leftEncode(uint64(blockSize)))
+	n := 0
+	for off := 0; off < len(data); off += blockSize {
+		end := min(off+blockSize, len(data))
+		digest, err := CShakeHash(data[off:end], bits, bits*2, "", "")
+		if err != nil {
+			return nil, err
+		}
+		h.Write(digest)
+		n++
+	}
+	h.Write(rightEncode(uint64(n)))
+	return readXof(h, rightEncode(outBits), outLen)
+}
+
+// ParallelHash computes ParallelHash128 or ParallelHash256 over data split into blockSize-byte blocks.
+func ParallelHash(data []byte, blockSize int, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("ParallelHash", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return parallelHash("ParallelHash", data, blockSize, bits, outLen, uint64(outputLenBits), customization)
+}
+
+// ParallelHashXof computes ParallelHashXOF128 or ParallelHashXOF256 over data split into blockSize-byte blocks.
+func ParallelHashXof(data []byte, blockSize int, bits int, outputLenBits int, customization string) ([]byte, error) {
+	outLen, err := outputLen("ParallelHashXof", outputLenBits)
+	if err != nil {
+		return nil, err
+	}
+	return parallelHash("ParallelHashXof", data, blockSize, bits, outLen, 0, customization)
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodings(t *testing.T) {
+	cases := []struct {
+		x           uint64
+		left, right string
+	}{
+		{0, "0100", "0001"},
+		{168, "01a8", "a801"},
+		{256, "020100", "010002"},
+	}
+	for _, c := range cases {
+		if got := hexStr(leftEncode(c.x)); got != c.left {
+			t.Fatalf("left_encode(%d): got %s want %s", c.x, got, c.left)
+		}
+		if got := hexStr(rightEncode(c.x)); got != c.right {
+			t.Fatalf("right_encode(%d): got %s want %s", c.x, got, c.right)
+		}
+	}
+	if got := len(bytepad([]byte{1, 2, 3}, 168)); got != 168 {
+		t.Fatalf("bytepad length: got %d want 168", got)
+	}
+}
+
+func TestKmacOutputLengthBinding(t *testing.T) {
+	key := []byte("key")
+	short, _ := Kmac(key, []byte("msg"), 128, 256, "")
+	long, _ := Kmac(key, []byte("msg"), 128, 512, "")
+	if bytes.Equal(short, long[:32]) {
+		t.Fatal("KMAC output should depend on the requested length")
+	}
+	shortX, _ := KmacXof(key, []byte("msg"), 128, 256, "")
+	longX, _ := KmacXof(key, []byte("msg"), 128, 512, "")
+	if !bytes.Equal(shortX, longX[:32]) {
+		t.Fatal("KMACXOF output should be a prefix of a longer output")
+	}
+}
+
+func TestTupleHashBoundaries(t *testing.T) {
+	a, _ := TupleHash([][]byte{[]byte("ab"), []byte("c")}, 128, 256, "")
+	b, _ := TupleHash([][]byte{[]byte("a"), []byte("bc")}, 128, 256, "")
+	if bytes.Equal(a, b) {
+		t.Fatal("TupleHash should separate item boundaries")
+	}
+}
+
+func TestSp800185Errors(t *testing.T) {
+	if _, err := Kmac(nil, nil, 192, 256, ""); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported KMAC error, got %v", err)
+	}
+	if _, err := TupleHashXof(nil, 128, 12, ""); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("expected output length error, got %v", err)
+	}
+	for name, f := range map[string]func() ([]byte, error){
+		"Kmac":            func() ([]byte, error) { return Kmac(nil, nil, 128, -8, "") },
+		"KmacXof":         func() ([]byte, error) { return KmacXof(nil, nil, 128, -8, "") },
+		"TupleHash":       func() ([]byte, error) { return TupleHash(nil, 128, -8, "") },
+		"TupleHashXof":    func() ([]byte, error) { return TupleHashXof(nil, 128, -8, "") },
+		"ParallelHash":    func() ([]byte, error) { return ParallelHash(nil, 8, 128, -8, "") },
+		"ParallelHashXof": func() ([]byte, error) { return ParallelHashXof(nil, 8, 128, -8, "") },
+	} {
+		if _, err := f(); !errors.Is(err, ErrOutputLen) {
+			t.Fatalf("%s: expected output length error for negative length, got %v", name, err)
+		}
+	}
+	if _, err := ParallelHash(nil, 0, 128, 256, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatal("expected error for zero block size")
+	}
+	if _, err := ParallelHash(nil, 8, 512, 256, ""); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("expected unsupported ParallelHash error, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package util
+
+import "math/big"
+
+// transcriptFunctionName is the cSHAKE function-name string (N) of every Transcript.
+const transcriptFunctionName = "inparity-transcript"
+
+// transcriptPrefixBytes is the length-prefix width of every frame a Transcript absorbs.
+const transcriptPrefixBytes = 4
+
+// Transcript operation bytes. Each operation absorbs op || frame(label) || body.
+const (
+	transcriptOpMessage         = 0x01 // body: frame(data)
+	transcriptOpBigInt          = 0x02 // body: FramedBytesFromBigInt(v)
+	transcriptOpChallengeBytes  = 0x03 // body: frame(uint32 n)
+	transcriptOpChallengeScalar = 0x04 // body: FramedBytesFromBigInt(modulus)
+	transcriptOpFork            = 0x05 // body: empty
+)
+
+// Transcript is a running, domain-separated hash of a protocol's messages built on
+// cSHAKE with N = "inparity-transcript" and S = the domain string. Every operation
+// absorbs a one-byte op code, the framed label and a framed body (4-byte length prefixes),
+// so no two different sequences of calls absorb the same bytes. Challenges are read
+// from a copy of the state after the challenge request itself has been absorbed, so
+// every later challenge depends on every earlier one.
+type Transcript struct {
+	h Xof
+}
+
+// NewTranscript starts a transcript for domain with cSHAKE128 or cSHAKE256 (bits 128 or 256).
+func NewTranscript(domain string, bits int) (*Transcript, error) {
+	h, err := NewCShake(bits, transcriptFunctionName, domain)
+	if err != nil {
+		return nil, err
+	}
+	return &Transcript{h: h}, nil
+}
+
+// absorb writes op, the framed label and the already framed body.
+func (t *Transcript) absorb(op byte, label string, body []byte) error {
+	framedLabel, err := FramedBytesOf(label, transcriptPrefixBytes)
+	if err != nil {
+		return err
+	}
+	t.h.Write([]byte{op})
+	t.h.Write(framedLabel)
+	t.h.Write(body)
+	return nil
+}
+
+// AppendMessage absorbs data under label.
+func (t *Transcript) AppendMessage(label string, data []byte) error {
+	body, err := FramedBytesOf(data, transcriptPrefixBytes)
+	if err != nil {
+		return err
+	}
+	return t.absorb(transcriptOpMessage, label, body)
+}
+
+// AppendBigInt absorbs v under label, framed as FramedBytesFromBigInt so the sign is bound.
+func (t *Transcript) AppendBigInt(label string, v *big.Int) error {
+	body, err := FramedBytesFromBigInt(v, transcriptPrefixBytes)
+	if err != nil {
+		return err
+	}
+	return t.absorb(transcriptOpBigInt, label, body)
+}
+
+// squeeze reads n bytes from a copy of the current state, leaving the transcript absorbing.
+func (t *Transcript) squeeze(n int) []byte {
+	out := make([]byte, n)
+	t.h.Clone().Read(out)
+	return out
+}
+
+// ChallengeBytes absorbs a request for n bytes under label and returns n challenge bytes.
+func (t *Transcript) ChallengeBytes(label string, n int) ([]byte, error) {
+	if n < 0 || int64(n) > 1<<32-1 {
+		return nil, newError("ChallengeBytes", ErrOutputLen, "n", n)
+	}
+	body, err := FramedBytesOf(uint32(n), transcriptPrefixBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.absorb(transcriptOpChallengeBytes, label, body); err != nil {
+		return nil, err
+	}
+	return t.squeeze(n), nil
+}
+
+// ChallengeScalar absorbs a request for a scalar modulo modulus under label and returns
+// a value in [0, modulus). It reads 16 bytes more than the modulus length and reduces,
+// so the bias is below 2^-128.
+func (t *Transcript) ChallengeScalar(label string, modulus *big.Int) (*big.Int, error) {
+	if modulus == nil {
+		return nil, newError("ChallengeScalar", ErrNilInput)
+	}
+	if modulus.Sign() <= 0 {
+		return nil, newError("ChallengeScalar", ErrInvalidArgument)
+	}
+	body, err := FramedBytesFromBigInt(modulus, transcriptPrefixBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.absorb(transcriptOpChallengeScalar, label, body); err != nil {
+		return nil, err
+	}
+	wide := t.squeeze((modulus.BitLen()+7)/8 + 16)
+	return new(big.Int).Mod(BytesToBigInt(wide), modulus), nil
+}
+
+// Clone returns an independent copy of the transcript in its current state.
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{h: t.h.Clone()}
+}
+
+// Fork returns a copy of the transcript that has absorbed a fork marker under label.
+// Forks with different labels, and the original, produce unrelated challenges from here on.
+func (t *Transcript) Fork(label string) (*Transcript, error) {
+	fork := t.Clone()
+	if err := fork.absorb(transcriptOpFork, label, nil); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func mustTranscript(t *testing.T, domain string) *Transcript {
+	t.Helper()
+	tr, err := NewTranscript(domain, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func mustChallenge(t *testing.T, tr *Transcript, label string) []byte {
+	t.Helper()
+	out, err := tr.ChallengeBytes(label, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestTranscript_CloneIsIndependent(t *testing.T) {
+	a := mustTranscript(t, "test")
+	if err := a.AppendMessage("m", []byte("shared")); err != nil {
+		t.Fatal(err)
+	}
+	b := a.Clone()
+	if err := b.AppendMessage("m", []byte("only b")); err != nil {
+		t.Fatal(err)
+	}
+	c := a.Clone()
+	if !bytes.Equal(mustChallenge(t, a, "c"), mustChallenge(t, c, "c")) {
+		t.Fatal("clone diverged without new input")
+	}
+	if bytes.Equal(mustChallenge(t, a, "c"), mustChallenge(t, b, "c")) {
+		t.Fatal("appending to a clone changed the original")
+	}
+}
+
+func TestTranscript_ChallengesChain(t *testing.T) {
+	tr := mustTranscript(t, "test")
+	first := mustChallenge(t, tr, "c")
+	if bytes.Equal(first, mustChallenge(t, tr, "c")) {
+		t.Fatal("repeated challenge returned the same bytes")
+	}
+}
+
+func TestTranscript_Separation(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b func(*Transcript) error
+	}{
+		{
+			"label/data boundary",
+			func(tr *Transcript) error { return tr.AppendMessage("ab", []byte("c")) },
+			func(tr *Transcript) error { return tr.AppendMessage("a", []byte("bc")) },
+		},
+		{
+			"message vs bigint",
+			func(tr *Transcript) error { return tr.AppendMessage("x", []byte{0, 1}) },
+			func(tr *Transcript) error { return tr.AppendBigInt("x", big.NewInt(1)) },
+		},
+		{
+			"fork vs message",
+			func(tr *Transcript) error { return tr.AppendMessage("x", nil) },
+			func(tr *Transcript) error {
+				fork, err := tr.Fork("x")
+				if err == nil {
+					*tr = *fork
+				}
+				return err
+			},
+		},
+	}
+	for _, tc := range cases {
+		a, b := mustTranscript(t, "test"), mustTranscript(t, "test")
+		if err := tc.a(a); err != nil {
+			t.Fatal(err)
+		}
+		if err := tc.b(b); err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(mustChallenge(t, a, "c"), mustChallenge(t, b, "c")) {
+			t.Fatalf("%s: transcripts collide", tc.name)
+		}
+	}
+	if bytes.Equal(mustChallenge(t, mustTranscript(t, "one"), "c"), mustChallenge(t, mustTranscript(t, "two"), "c")) {
+		t.Fatal("domains collide")
+	}
+}
+
+func TestTranscript_Errors(t *testing.T) {
+	if _, err := NewTranscript("test", 512); !errors.Is(err, ErrUnsupportedBits) {
+		t.Fatalf("bits: got %v", err)
+	}
+	tr := mustTranscript(t, "test")
+	if err := tr.AppendBigInt("x", nil); !errors.Is(err, ErrNilInput) {
+		t.Fatalf("nil bigint: got %v", err)
+	}
+	if _, err := tr.ChallengeBytes("c", -1); !errors.Is(err, ErrOutputLen) {
+		t.Fatalf("negative length: got %v", err)
+	}
+	if _, err := tr.ChallengeScalar("c", big.NewInt(0)); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("zero modulus: got %v", err)
+	}
+	if _, err := tr.ChallengeScalar("c", nil); !errors.Is(err, ErrNilInput) {
+		t.Fatalf("nil modulus: got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package vss
+
+import (
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// Group names a prime-order group for commitments.
+type Group string
+
+const (
+	P256         Group = "P-256"
+	Ristretto255 Group = "ristretto255"
+)
+
+// pedersenLabel prefixes the group name in the derivation of the second generator H.
+const pedersenLabel = "inparity-vss-pedersen-H:"
+
+// backend returns the group.Group that implements g.
+func (g Group) backend() (group.Group, error) {
+	switch g {
+	case P256:
+		return group.P256, nil
+	case Ristretto255:
+		return group.Ristretto255, nil
+	default:
+		return nil, ErrUnsupportedGroup
+	}
+}
+
+// pedersenH returns the second generator for Pedersen commitments in g, with no known
+// discrete logarithm with respect to the base.
+//   - P-256: the first SHA-256(label || ctr) digest, for a one-byte counter, that is the
+//     x coordinate of a point, taking the even y (prefix 0x02)
+//   - ristretto255: FromUniformBytes(SHA-512(label))
+func pedersenH(g Group, gr group.Group) group.Element {
+	label := []byte(pedersenLabel + string(g))
+	if g == Ristretto255 {
+		digest, _ := util.Sha2Hash(label, 512)
+		e, _ := group.RistrettoElementFromUniformBytes(digest)
+		h, _ := gr.DecodeElement(e.Bytes())
+		return h
+	}
+	for ctr := 0; ; ctr++ {
+		digest, _ := util.Sha2Hash(append(label, byte(ctr)), 256)
+		if h, err := gr.DecodeElement(util.ConcatBytes([]byte{0x02}, digest)); err == nil {
+			return h
+		}
+	}
+}
+
+// PedersenGenerator returns the encoded second generator H used by Pedersen commitments in g.
+func PedersenGenerator(g Group) ([]byte, error) {
+	gr, err := g.backend()
+	if err != nil {
+		return nil, err
+	}
+	return pedersenH(g, gr).MarshalBinary()
+}
@@ -0,0 +1,161 @@
+package vss
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+type parityVectors struct {
+	Vss struct {
+		PedersenH []struct {
+			Group string
+			H     string
+		}
+		Deal []struct {
+			Name       string
+			Group      string
+			Scheme     string
+			Secret     string
+			N, K       int
+			Seed       string
+			Commitment string
+			Shares     []string
+		}
+		Verify []struct {
+			Name       string
+			Commitment string
+			Share      string
+			Valid      bool
+			Error      string
+		}
+	}
+}
+
+func loadVectors(t *testing.T) parityVectors {
+	t.Helper()
+	path := filepath.Join("..", "..", "testdata", "parity.json")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var v parityVectors
+	if err := json.NewDecoder(f).Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func mustHex(s string) []byte {
+	if s == "" {
+		return []byte{}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// seededReader returns the deterministic randomness source of the vectors, SHAKE128(seed).
+func seededReader(t *testing.T, seed string) util.Xof {
+	t.Helper()
+	h, err := util.NewShake(128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write(mustHex(seed))
+	return h
+}
+
+var vssErrors = map[string]error{
+	"unsupportedGroup":  ErrUnsupportedGroup,
+	"unsupportedScheme": ErrUnsupportedScheme,
+	"shares":            ErrShares,
+	"encoding":          ErrInvalidEncoding,
+}
+
+func TestParity_PedersenGenerator(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Vss.PedersenH {
+		h, err := PedersenGenerator(Group(tc.Group))
+		if err != nil || hex.EncodeToString(h) != tc.H {
+			t.Fatalf("%s: got %x, %v want %s", tc.Group, h, err, tc.H)
+		}
+	}
+}
+
+func TestParity_Deal(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Vss.Deal {
+		secret, _ := new(big.Int).SetString(tc.Secret, 10)
+		dealFn := FeldmanDealWithReader
+		if Scheme(tc.Scheme) == Pedersen {
+			dealFn = PedersenDealWithReader
+		}
+		shares, c, err := dealFn(Group(tc.Group), secret, tc.N, tc.K, seededReader(t, tc.Seed))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.Name, err)
+		}
+		if b, err := c.Bytes(); err != nil || hex.EncodeToString(b) != tc.Commitment {
+			t.Fatalf("%s commitment: got %x, %v want %s", tc.Name, b, err, tc.Commitment)
+		}
+		parsedC, err := ParseCommitment(mustHex(tc.Commitment))
+		if err != nil {
+			t.Fatalf("%s parse commitment: %v", tc.Name, err)
+		}
+		parsed := make([]Share, len(shares))
+		for i, s := range shares {
+			if b, err := s.Bytes(); err != nil || hex.EncodeToString(b) != tc.Shares[i] {
+				t.Fatalf("%s share %d: got %x, %v want %s", tc.Name, i+1, b, err, tc.Shares[i])
+			}
+			if parsed[i], err = ParseShare(mustHex(tc.Shares[i])); err != nil {
+				t.Fatalf("%s parse share %d: %v", tc.Name, i+1, err)
+			}
+			if ok, err := VerifyShare(parsedC, parsed[i]); !ok || err != nil {
+				t.Fatalf("%s verify share %d: %v, %v", tc.Name, i+1, ok, err)
+			}
+		}
+		got, err := Combine(Group(tc.Group), parsed[tc.N-tc.K:])
+		if err != nil || got.Cmp(secret) != 0 {
+			t.Fatalf("%s combine: got %v, %v want %s", tc.Name, got, err, tc.Secret)
+		}
+	}
+}
+
+func TestParity_Verify(t *testing.T) {
+	v := loadVectors(t)
+	for _, tc := range v.Vss.Verify {
+		ok, err := func() (bool, error) {
+			c, err := ParseCommitment(mustHex(tc.Commitment))
+			if err != nil {
+				return false, err
+			}
+			s, err := ParseShare(mustHex(tc.Share))
+			if err != nil {
+				return false, err
+			}
+			return VerifyShare(c, s)
+		}()
+		if tc.Error != "" {
+			sentinel, known := vssErrors[tc.Error]
+			if !known {
+				t.Fatalf("%s: unknown error name %q", tc.Name, tc.Error)
+			}
+			if !errors.Is(err, sentinel) {
+				t.Fatalf("%s: got error %v want %v", tc.Name, err, sentinel)
+			}
+			continue
+		}
+		if err != nil || ok != tc.Valid {
+			t.Fatalf("%s: got %v, %v want %v", tc.Name, ok, err, tc.Valid)
+		}
+	}
+}
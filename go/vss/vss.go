@@ -0,0 +1,322 @@
+// Package vss implements verifiable secret sharing over a prime-order group: Feldman
+// commitments C_j = a_j*G to the coefficients of the sharing polynomial, and Pedersen
+// commitments C_j = a_j*G + b_j*H that also hide the secret. Shares are Shamir shares
+// modulo the group order, and VerifyShare lets each holder check theirs against the
+// dealer's published commitment.
+package vss
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/grzegorzmaniak/inparity/group"
+	"github.com/grzegorzmaniak/inparity/util"
+)
+
+// MaxShares is the largest number of shares dealt at once.
+const MaxShares = 255
+
+// lengthPrefix is the length-prefix width of every frame in a serialized share or commitment.
+const lengthPrefix = 4
+
+// scalarExtraBytes is how many bytes beyond the order's length are read per random scalar,
+// so reducing modulo the order has a bias below 2^-128.
+const scalarExtraBytes = 16
+
+// Scheme is the commitment scheme of a Commitment.
+type Scheme string
+
+const (
+	Feldman  Scheme = "feldman"
+	Pedersen Scheme = "pedersen"
+)
+
+var (
+	ErrUnsupportedGroup  = errors.New("vss: unsupported group")
+	ErrUnsupportedScheme = errors.New("vss: unsupported commitment scheme")
+	ErrThreshold         = errors.New("vss: threshold must satisfy 2 <= k <= n <= 255")
+	ErrSecret            = errors.New("vss: secret is not in [0, group order)")
+	ErrShares            = errors.New("vss: shares are too few, duplicated or out of range")
+	ErrInvalidEncoding   = errors.New("vss: malformed share, commitment or element encoding")
+)
+
+// Share is one share: Y = f(X) and, for Pedersen, Blind = g(X). Blind is nil for Feldman.
+type Share struct {
+	X     int
+	Y     *big.Int
+	Blind *big.Int
+}
+
+// Commitment is the dealer's public commitment to the k coefficients, lowest degree first.
+// Points holds encoded group elements: compressed SEC1 for P-256 (0x00 for the identity)
+// and 32-byte encodings for ristretto255.
+type Commitment struct {
+	Group  Group
+	Scheme Scheme
+	Points [][]byte
+}
+
+// randomScalar reads len(order)+16 bytes from r and reduces them modulo order.
+func randomScalar(r io.Reader, order *big.Int) (*big.Int, error) {
+	buf := make([]byte, (order.BitLen()+7)/8+scalarExtraBytes)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mod(util.BytesToBigInt(buf), order), nil
+}
+
+// evaluate returns the polynomial with coefficients coeffs (lowest first) at x, modulo q.
+func evaluate(coeffs []*big.Int, x int, q *big.Int) *big.Int {
+	bx := big.NewInt(int64(x))
+	y := new(big.Int)
+	for j := len(coeffs) - 1; j >= 0; j-- {
+		y.Mul(y, bx)
+		y.Add(y, coeffs[j])
+		y.Mod(y, q)
+	}
+	return y
+}
+
+// deal samples the polynomials and builds the shares and commitment. Coefficients are
+// read from r in order: a_1..a_{k-1}, then for Pedersen b_0..b_{k-1}.
+func deal(g Group, scheme Scheme, secret *big.Int, n, k int, r io.Reader) ([]Share, *Commitment, error) {
+	gr, err := g.backend()
+	if err != nil {
+		return nil, nil, err
+	}
+	if k < 2 || k > n || n > MaxShares {
+		return nil, nil, ErrThreshold
+	}
+	q := gr.Order()
+	if secret == nil || secret.Sign() < 0 || secret.Cmp(q) >= 0 {
+		return nil, nil, ErrSecret
+	}
+	a := make([]*big.Int, k)
+	a[0] = new(big.Int).Set(secret)
+	for j := 1; j < k; j++ {
+		if a[j], err = randomScalar(r, q); err != nil {
+			return nil, nil, err
+		}
+	}
+	var b []*big.Int
+	if scheme == Pedersen {
+		b = make([]*big.Int, k)
+		for j := range b {
+			if b[j], err = randomScalar(r, q); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	c := &Commitment{Group: g, Scheme: scheme, Points: make([][]byte, k)}
+	var h group.Element
+	if b != nil {
+		h = pedersenH(g, gr)
+	}
+	for j := range a {
+		e := gr.Generator().ScalarMult(gr.NewScalar(a[j]))
+		if b != nil {
+			e = e.Add(h.ScalarMult(gr.NewScalar(b[j])))
+		}
+		if c.Points[j], err = e.MarshalBinary(); err != nil {
+			return nil, nil, err
+		}
+	}
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{X: i + 1, Y: evaluate(a, i+1, q)}
+		if b != nil {
+			shares[i].Blind = evaluate(b, i+1, q)
+		}
+	}
+	return shares, c, nil
+}
+
+// FeldmanDeal splits secret into n shares of which any k recover it, and commits to the
+// coefficients as a_j*G. The commitment C_0 = secret*G reveals the secret's public image.
+func FeldmanDeal(g Group, secret *big.Int, n, k int) ([]Share, *Commitment, error) {
+	return deal(g, Feldman, secret, n, k, rand.Reader)
+}
+
+// FeldmanDealWithReader is FeldmanDeal with the coefficients a_1..a_{k-1} read from r.
+// It exists for test vectors with a deterministic source, so prefer FeldmanDeal.
+func FeldmanDealWithReader(g Group, secret *big.Int, n, k int, r io.Reader) ([]Share, *Commitment, error) {
+	return deal(g, Feldman, secret, n, k, r)
+}
+
+// PedersenDeal is FeldmanDeal with commitments a_j*G + b_j*H for a second random polynomial
+// with coefficients b_j, so the commitment hides the secret. Each share carries Blind = g(X).
+func PedersenDeal(g Group, secret *big.Int, n, k int) ([]Share, *Commitment, error) {
+	return deal(g, Pedersen, secret, n, k, rand.Reader)
+}
+
+// PedersenDealWithReader is PedersenDeal with a_1..a_{k-1} and then b_0..b_{k-1} read from r.
+// It exists for test vectors with a deterministic source, so prefer PedersenDeal.
+func PedersenDealWithReader(g Group, secret *big.Int, n, k int, r io.Reader) ([]Share, *Commitment, error) {
+	return deal(g, Pedersen, secret, n, k, r)
+}
+
+// VerifyShare reports whether s is consistent with the commitment c: Y*G (+ Blind*H for
+// Pedersen) equals the sum of C_j * X^j. A malformed share or commitment is an error,
+// not a mismatch.
+func VerifyShare(c *Commitment, s Share) (bool, error) {
+	gr, err := c.Group.backend()
+	if err != nil {
+		return false, err
+	}
+	q := gr.Order()
+	if len(c.Points) < 2 || len(c.Points) > MaxShares {
+		return false, ErrInvalidEncoding
+	}
+	if s.X < 1 || s.X > MaxShares || !inRange(s.Y, q) {
+		return false, ErrShares
+	}
+	points := make([]group.Element, len(c.Points))
+	for j, p := range c.Points {
+		if points[j], err = gr.DecodeElement(p); err != nil {
+			return false, ErrInvalidEncoding
+		}
+	}
+	lhs := gr.Generator().ScalarMult(gr.NewScalar(s.Y))
+	switch c.Scheme {
+	case Feldman:
+		if s.Blind != nil {
+			return false, ErrShares
+		}
+	case Pedersen:
+		if !inRange(s.Blind, q) {
+			return false, ErrShares
+		}
+		lhs = lhs.Add(pedersenH(c.Group, gr).ScalarMult(gr.NewScalar(s.Blind)))
+	default:
+		return false, ErrUnsupportedScheme
+	}
+
+	// Horner's rule: ((C_{k-1}*x + C_{k-2})*x + ...)*x + C_0.
+	x := gr.NewScalar(big.NewInt(int64(s.X)))
+	rhs := points[len(points)-1]
+	for j := len(points) - 2; j >= 0; j-- {
+		rhs = rhs.ScalarMult(x).Add(points[j])
+	}
+	return lhs.Equal(rhs), nil
+}
+
+func inRange(v, q *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.Cmp(q) < 0
+}
+
+// Combine recovers the secret from at least k distinct shares by Lagrange interpolation at
+// zero modulo the group order. It does not verify the shares; call VerifyShare first.
+func Combine(g Group, shares []Share) (*big.Int, error) {
+	gr, err := g.backend()
+	if err != nil {
+		return nil, err
+	}
+	q := gr.Order()
+	if len(shares) < 2 || len(shares) > MaxShares {
+		return nil, ErrShares
+	}
+	var seen [MaxShares + 1]bool
+	for _, s := range shares {
+		if s.X < 1 || s.X > MaxShares || seen[s.X] || !inRange(s.Y, q) {
+			return nil, ErrShares
+		}
+		seen[s.X] = true
+	}
+	secret := new(big.Int)
+	for i, si := range shares {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			num.Mod(num.Mul(num, big.NewInt(int64(sj.X))), q)
+			den = util.BigModPos(den.Mul(den, big.NewInt(int64(sj.X-si.X))), q)
+		}
+		term := new(big.Int).Mul(si.Y, num)
+		term.Mul(term, den.ModInverse(den, q))
+		secret.Mod(secret.Add(secret, term), q)
+	}
+	return secret, nil
+}
+
+// Bytes serializes the share as FramedConcat of X, Y and, for Pedersen, Blind, each as
+// FramedBytesFromBigInt with a 4-byte length prefix. A Feldman share has the same layout
+// as a shamir.Share.
+func (s Share) Bytes() ([]byte, error) {
+	if s.Y == nil {
+		return nil, ErrShares
+	}
+	fields := []util.FramedField{util.FieldOf(big.NewInt(int64(s.X))), util.FieldOf(s.Y)}
+	if s.Blind != nil {
+		fields = append(fields, util.FieldOf(s.Blind))
+	}
+	return util.FramedConcat(lengthPrefix, fields...)
+}
+
+// ParseShare decodes a share written by Share.Bytes. Ranges are checked by VerifyShare
+// and Combine, which know the group.
+func ParseShare(b []byte) (Share, error) {
+	r := util.NewFramedReader(b, lengthPrefix)
+	x, err := r.ReadBigInt()
+	if err != nil {
+		return Share{}, ErrInvalidEncoding
+	}
+	y, err := r.ReadBigInt()
+	if err != nil {
+		return Share{}, ErrInvalidEncoding
+	}
+	var blind *big.Int
+	if r.Remaining() > 0 {
+		if blind, err = r.ReadBigInt(); err != nil || r.Finish() != nil {
+			return Share{}, ErrInvalidEncoding
+		}
+	}
+	if !x.IsInt64() || x.Int64() < 1 || x.Int64() > MaxShares || y.Sign() < 0 || (blind != nil && blind.Sign() < 0) {
+		return Share{}, ErrInvalidEncoding
+	}
+	return Share{X: int(x.Int64()), Y: y, Blind: blind}, nil
+}
+
+// Bytes serializes the commitment as FramedConcat(group, scheme, points), where points is
+// the [][]byte frame of the encoded elements, all with 4-byte length prefixes.
+func (c *Commitment) Bytes() ([]byte, error) {
+	return util.FramedConcat(lengthPrefix, util.FieldOf(string(c.Group)), util.FieldOf(string(c.Scheme)), util.FieldOf(c.Points))
+}
+
+// ParseCommitment decodes a commitment written by Commitment.Bytes and checks that the
+// group and scheme are supported and every point is a valid element.
+func ParseCommitment(b []byte) (*Commitment, error) {
+	r := util.NewFramedReader(b, lengthPrefix)
+	name, err := r.ReadString()
+	if err != nil {
+		return nil, ErrInvalidEncoding
+	}
+	scheme, err := r.ReadString()
+	if err != nil {
+		return nil, ErrInvalidEncoding
+	}
+	inner, err := r.ReadBytes()
+	if err != nil || r.Finish() != nil {
+		return nil, ErrInvalidEncoding
+	}
+	gr, err := Group(name).backend()
+	if err != nil {
+		return nil, err
+	}
+	if Scheme(scheme) != Feldman && Scheme(scheme) != Pedersen {
+		return nil, ErrUnsupportedScheme
+	}
+	points, err := util.ParseFramed(inner, lengthPrefix)
+	if err != nil || len(points) < 2 || len(points) > MaxShares {
+		return nil, ErrInvalidEncoding
+	}
+	for _, p := range points {
+		if _, err := gr.DecodeElement(p); err != nil {
+			return nil, ErrInvalidEncoding
+		}
+	}
+	return &Commitment{Group: Group(name), Scheme: Scheme(scheme), Points: points}, nil
+}
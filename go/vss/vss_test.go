@@ -0,0 +1,64 @@
+package vss
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/grzegorzmaniak/inparity/ristretto255"
+)
+
+func TestDealVerifyCombine(t *testing.T) {
+	for _, group := range []Group{P256, Ristretto255} {
+		for _, dealFn := range []func(Group, *big.Int, int, int) ([]Share, *Commitment, error){FeldmanDeal, PedersenDeal} {
+			secret := big.NewInt(987654321)
+			shares, c, err := dealFn(group, secret, 4, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range shares {
+				if ok, err := VerifyShare(c, s); !ok || err != nil {
+					t.Fatalf("%s %s share %d: %v, %v", group, c.Scheme, s.X, ok, err)
+				}
+			}
+			got, err := Combine(group, []Share{shares[3], shares[0], shares[2]})
+			if err != nil || got.Cmp(secret) != 0 {
+				t.Fatalf("%s %s: got %v, %v", group, c.Scheme, got, err)
+			}
+		}
+	}
+}
+
+func TestFeldmanCommitsToSecret(t *testing.T) {
+	secret := big.NewInt(31337)
+	_, c, err := FeldmanDeal(Ristretto255, secret, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(c.Points[0]) != string(ristretto255.ScalarBaseMult(secret).Bytes()) {
+		t.Fatal("C_0 is not secret*G")
+	}
+	_, c, err = PedersenDeal(Ristretto255, secret, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(c.Points[0]) == string(ristretto255.ScalarBaseMult(secret).Bytes()) {
+		t.Fatal("Pedersen C_0 reveals secret*G")
+	}
+}
+
+func TestDealErrors(t *testing.T) {
+	if _, _, err := FeldmanDeal("secp256k1", big.NewInt(1), 3, 2); !errors.Is(err, ErrUnsupportedGroup) {
+		t.Fatalf("group: got %v", err)
+	}
+	if _, _, err := FeldmanDeal(P256, big.NewInt(1), 2, 3); !errors.Is(err, ErrThreshold) {
+		t.Fatalf("threshold: got %v", err)
+	}
+	if _, _, err := PedersenDeal(Ristretto255, ristretto255.Order(), 3, 2); !errors.Is(err, ErrSecret) {
+		t.Fatalf("secret: got %v", err)
+	}
+	shares, _, _ := FeldmanDeal(P256, big.NewInt(1), 3, 2)
+	if _, err := Combine(P256, []Share{shares[1], shares[1]}); !errors.Is(err, ErrShares) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+import { bigIntToBytes, concatBytes, framedBytesFromUint8Array, parseFramed } from '../util/bytes';
+import { sha2Hash, hkdfExpand, hmacSha2, type Sha2 } from '../util/hash';
+import { Suites as H2cSuites, hashToCurve } from '../h2c';
+import {
+    P256,
+    Ristretto255,
+    decodeUncompressed,
+    marshalUncompressed,
+    ristrettoElementFromUniformBytes,
+    type Element,
+    type Group,
+    type Scalar,
+} from '../group';
+
+/**
+ * CPace ciphersuite identifiers.
+ */
+const Suites = {
+    Ristretto255Sha512: 'CPACE-RISTR255-SHA512',
+    P256Sha256: 'CPACE-P256_XMD:SHA-256_SSWU_NU_-SHA256',
+} as const;
+
+type Suite = (typeof Suites)[keyof typeof Suites];
+
+// The length prefix of every frame in a wire message.
+const lengthPrefix = 2;
+
+/**
+ * Error codes of the cpace package, matching the Go sentinels.
+ */
+const CpaceErrorCodes = {
+    unsupportedSuite: 'unsupportedSuite',
+    invalidMessage: 'invalidMessage',
+    authentication: 'authentication',
+    state: 'state',
+} as const;
+
+type CpaceErrorCode = (typeof CpaceErrorCodes)[keyof typeof CpaceErrorCodes];
+
+const messages: Record<CpaceErrorCode, string> = {
+    unsupportedSuite: 'cpace: unsupported suite',
+    invalidMessage: 'cpace: malformed message or invalid element',
+    authentication: 'cpace: key confirmation failed',
+    state: 'cpace: state machine used out of order or reused',
+};
+
+/**
+ * Mirrors Go's cpace.ErrUnsupportedSuite, ErrInvalidMessage, ErrAuthentication and ErrState.
+ */
+class CpaceError extends Error {
+    readonly code: CpaceErrorCode;
+
+    constructor(code: CpaceErrorCode) {
+        super(messages[code]);
+        this.name = 'CpaceError';
+        this.code = code;
+    }
+}
+
+/**
+ * What both parties must agree on before the exchange. ci is the channel identifier, such
+ * as the encoded identities of both parties; sid is the session ID, which CPace needs to be
+ * unique to resist replays across sessions. Both may be empty.
+ */
+type Config = {
+    readonly suite: string;
+    readonly ci?: Uint8Array;
+    readonly sid?: Uint8Array;
+};
+
+const enc = new TextEncoder();
+
+// LEB128(len(data)) || data.
+function prependLen(data: Uint8Array): Uint8Array {
+    const out: number[] = [];
+    let n = data.length;
+    for (;;) {
+        const b = n & 0x7f;
+        n >>>= 7;
+        if (n === 0) {
+            out.push(b);
+            break;
+        }
+        out.push(b | 0x80);
+    }
+    return concatBytes(Uint8Array.from(out), data);
+}
+
+// Concatenates prependLen of every field.
+function lvCat(...fields: Uint8Array[]): Uint8Array {
+    return concatBytes(...fields.map(prependLen));
+}
+
+/**
+ * A Config with its suite resolved.
+ */
+class Params {
+    readonly suite: Suite;
+    readonly g: Group;
+    readonly hashBits: Sha2;
+    // dsi is the domain separation identifier, and sInBytes the input block size of the
+    // hash, which the generator string is padded to.
+    readonly dsi: Uint8Array;
+    readonly sInBytes: number;
+    readonly ci: Uint8Array;
+    readonly sid: Uint8Array;
+
+    constructor(cfg: Config) {
+        switch (cfg.suite) {
+            case Suites.Ristretto255Sha512:
+                [this.g, this.hashBits, this.dsi, this.sInBytes] = [Ristretto255, 512, enc.encode('CPaceRistretto255'), 128];
+                break;
+            case Suites.P256Sha256:
+                [this.g, this.hashBits, this.dsi, this.sInBytes] = [P256, 256, enc.encode('CPaceP256_XMD:SHA-256_SSWU_NU_'), 64];
+                break;
+            default:
+                throw new CpaceError('unsupportedSuite');
+        }
+        this.suite = cfg.suite;
+        this.ci = Uint8Array.from(cfg.ci ?? []);
+        this.sid = Uint8Array.from(cfg.sid ?? []);
+    }
+
+    // lv_cat(DSI, PRS, zero padding, CI, sid); the padding makes DSI and PRS fill the
+    // hash's first input block.
+    generatorString(prs: Uint8Array): Uint8Array {
+        const pad = Math.max(0, this.sInBytes - prependLen(prs).length - prependLen(this.dsi).length - 1);
+        return lvCat(this.dsi, prs, new Uint8Array(pad), this.ci, this.sid);
+    }
+
+    // g: for ristretto255 the one-way map of H(generator string), and for P-256
+    // encode_to_curve of the generator string under DSI || "_DST".
+    async generator(prs: Uint8Array): Promise<Element> {
+        const gen = this.generatorString(prs);
+        if (this.suite === Suites.Ristretto255Sha512) {
+            return ristrettoElementFromUniformBytes(await sha2Hash(gen, 512));
+        }
+        const q = await hashToCurve(H2cSuites.P256NU, gen, concatBytes(this.dsi, enc.encode('_DST')));
+        return decodeUncompressed(this.g, concatBytes(Uint8Array.of(0x04), bigIntToBytes(q.x, 32), bigIntToBytes(q.y, 32)));
+    }
+
+    // Parses a peer's Y, rejecting the identity.
+    decodeShare(b: Uint8Array): Element {
+        let e: Element;
+        try {
+            e = decodeUncompressed(this.g, b);
+        } catch {
+            throw new CpaceError('invalidMessage');
+        }
+        if (e.isIdentity()) {
+            throw new CpaceError('invalidMessage');
+        }
+        return e;
+    }
+
+    // scalar_mult_vfy: K = y*peer, rejected if it is the identity, encoded as the element
+    // for ristretto255 and as its x-coordinate for P-256.
+    sharedSecret(y: Scalar, peer: Element): Uint8Array {
+        const k = peer.scalarMult(y);
+        if (k.isIdentity()) {
+            throw new CpaceError('invalidMessage');
+        }
+        const b = marshalUncompressed(k);
+        return this.suite === Suites.P256Sha256 ? b.subarray(1, 33) : b;
+    }
+
+    // ISK = H(lv_cat(DSI || "_ISK", sid, K) || transcript), then the local extension:
+    // KcA || KcB and the session key expanded from ISK, and the MACs over the transcript.
+    async keySchedule(k: Uint8Array, ya: Uint8Array, adA: Uint8Array, yb: Uint8Array, adB: Uint8Array): Promise<Keys> {
+        const transcript = concatBytes(lvCat(ya, adA), lvCat(yb, adB));
+        const isk = await sha2Hash(concatBytes(lvCat(concatBytes(this.dsi, enc.encode('_ISK')), this.sid, k), transcript),
+            this.hashBits);
+        const nh = isk.length;
+        const kc = await hkdfExpand(isk, enc.encode('CPaceConfirmationKeys'), 2 * nh, this.hashBits);
+        const kcA = kc.subarray(0, nh);
+        const kcB = kc.subarray(nh);
+        return {
+            isk,
+            kcA,
+            kcB,
+            macA: await hmacSha2(kcA, transcript, this.hashBits),
+            macB: await hmacSha2(kcB, transcript, this.hashBits),
+            sessionKey: await hkdfExpand(isk, enc.encode('CPaceSessionKey'), nh, this.hashBits),
+        };
+    }
+}
+
+// The key schedule for one exchange.
+type Keys = {
+    isk: Uint8Array;
+    kcA: Uint8Array;
+    kcB: Uint8Array;
+    macA: Uint8Array;
+    macB: Uint8Array;
+    sessionKey: Uint8Array;
+};
+
+// frame and unframe are the wire encoding of a message: its fields framed with 2-byte
+// length prefixes.
+function frame(...fields: Uint8Array[]): Uint8Array {
+    try {
+        return concatBytes(...fields.map(f => framedBytesFromUint8Array(f, lengthPrefix)));
+    } catch {
+        throw new CpaceError('invalidMessage');
+    }
+}
+
+function unframe(msg: Uint8Array, count: number): Uint8Array[] {
+    let fields: Uint8Array[];
+    try {
+        fields = parseFramed(msg, lengthPrefix);
+    } catch {
+        throw new CpaceError('invalidMessage');
+    }
+    if (fields.length !== count) {
+        throw new CpaceError('invalidMessage');
+    }
+    return fields;
+}
+
+export {
+    Suites,
+    type Suite,
+    CpaceErrorCodes,
+    type CpaceErrorCode,
+    CpaceError,
+    type Config,
+    prependLen,
+    lvCat,
+    Params,
+    type Keys,
+    frame,
+    unframe,
+};
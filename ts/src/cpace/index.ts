@@ -0,0 +1,9 @@
+export {
+    Suites,
+    type Suite,
+    CpaceErrorCodes,
+    type CpaceErrorCode,
+    CpaceError,
+    type Config,
+} from './cpace';
+export * from './initiator';
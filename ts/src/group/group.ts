@@ -0,0 +1,220 @@
+import { bigModPos, bigCmp } from '../util/numeric';
+import { bytesToBigInt, bigIntToBytes } from '../util/bytes';
+import { modPow } from '../h2c/field';
+
+/**
+ * How many bytes beyond the order's length are read per random scalar, so reducing modulo
+ * the order has a bias below 2^-128.
+ */
+const scalarExtraBytes = 16;
+
+/**
+ * Error codes of the group package, matching the Go sentinels.
+ */
+const GroupErrorCodes = {
+    invalidElement: 'invalidElement',
+    invalidScalar: 'invalidScalar',
+    zeroScalar: 'zeroScalar',
+    groupMismatch: 'groupMismatch',
+} as const;
+
+type GroupErrorCode = (typeof GroupErrorCodes)[keyof typeof GroupErrorCodes];
+
+const messages: Record<GroupErrorCode, string> = {
+    invalidElement: 'group: invalid element encoding',
+    invalidScalar: 'group: invalid scalar encoding',
+    zeroScalar: 'group: zero scalar has no inverse',
+    groupMismatch: 'group: operands belong to different groups',
+};
+
+/**
+ * Mirrors Go's group.ErrInvalidElement, ErrInvalidScalar, ErrZeroScalar and ErrGroupMismatch.
+ */
+class GroupError extends Error {
+    readonly code: GroupErrorCode;
+
+    constructor(code: GroupErrorCode) {
+        super(messages[code]);
+        this.name = 'GroupError';
+        this.code = code;
+    }
+}
+
+/**
+ * A prime-order group, so protocol code can be written once for every backend: P256, P384,
+ * Secp256k1 and Ristretto255. Elements and scalars are immutable values; mixing values of
+ * different groups throws groupMismatch.
+ */
+interface Group {
+    /** The group's name, such as "P-256" or "ristretto255". */
+    name(): string;
+    /** The prime order of the group. */
+    order(): bigint;
+    identity(): Element;
+    generator(): Element;
+    /** Returns v reduced modulo the order. */
+    newScalar(v: bigint): Scalar;
+    /** Returns a uniformly random scalar from crypto.getRandomValues. */
+    randomScalar(): Scalar;
+    /** Hashes msg to an element under dst with the group's RFC 9380 random-oracle encoding. */
+    hashToElement(msg: Uint8Array, dst: Uint8Array): Promise<Element>;
+    /** Hashes msg to a scalar under dst, as HashToScalar in RFC 9497. */
+    hashToScalar(msg: Uint8Array, dst: Uint8Array): Promise<Scalar>;
+    /** Parses the canonical encoding written by Element.toBytes. */
+    decodeElement(b: Uint8Array): Element;
+    /** Parses the canonical encoding written by Scalar.toBytes. */
+    decodeScalar(b: Uint8Array): Scalar;
+    /** The length of an encoded non-identity element. */
+    elementSize(): number;
+    /** The length of an encoded scalar. */
+    scalarSize(): number;
+}
+
+/**
+ * A group element.
+ */
+interface Element {
+    group(): Group;
+    add(o: Element): Element;
+    subtract(o: Element): Element;
+    negate(): Element;
+    scalarMult(s: Scalar): Element;
+    equal(o: Element): boolean;
+    isIdentity(): boolean;
+    toBytes(): Uint8Array;
+}
+
+/**
+ * An integer modulo the group order.
+ */
+interface Scalar {
+    group(): Group;
+    add(o: Scalar): Scalar;
+    subtract(o: Scalar): Scalar;
+    multiply(o: Scalar): Scalar;
+    negate(): Scalar;
+    /** Returns the multiplicative inverse; throws zeroScalar for zero. */
+    invert(): Scalar;
+    equal(o: Scalar): boolean;
+    isZero(): boolean;
+    /** Returns the scalar as an integer in [0, order). */
+    toBigInt(): bigint;
+    toBytes(): Uint8Array;
+}
+
+// Returns b reversed, for the little-endian encodings of ristretto255.
+function reversed(b: Uint8Array): Uint8Array {
+    return Uint8Array.from(b).reverse();
+}
+
+/**
+ * The scalar arithmetic shared by every backend: integers modulo the order, encoded as
+ * fixed-width big-endian bytes, or little-endian for ristretto255.
+ */
+class ScalarField {
+    readonly g: Group;
+    readonly order: bigint;
+    readonly size: number;
+    readonly littleEndian: boolean;
+
+    constructor(g: Group, order: bigint, size: number, littleEndian = false) {
+        this.g = g;
+        this.order = order;
+        this.size = size;
+        this.littleEndian = littleEndian;
+    }
+
+    newScalar(v: bigint): Scalar {
+        return new FieldScalar(this, bigModPos(v, this.order));
+    }
+
+    random(): Scalar {
+        const buf = new Uint8Array(this.size + scalarExtraBytes);
+        crypto.getRandomValues(buf);
+        return this.newScalar(bytesToBigInt(buf));
+    }
+
+    decode(b: Uint8Array): Scalar {
+        if (b.length !== this.size) {
+            throw new GroupError('invalidScalar');
+        }
+        const v = bytesToBigInt(this.littleEndian ? reversed(b) : b);
+        if (bigCmp(v, this.order) >= 0) {
+            throw new GroupError('invalidScalar');
+        }
+        return new FieldScalar(this, v);
+    }
+
+    // Returns the value of s, which must belong to this field.
+    valueOf(s: Scalar): bigint {
+        if (!(s instanceof FieldScalar) || s.f !== this) {
+            throw new GroupError('groupMismatch');
+        }
+        return s.v;
+    }
+}
+
+class FieldScalar implements Scalar {
+    readonly f: ScalarField;
+    readonly v: bigint;
+
+    constructor(f: ScalarField, v: bigint) {
+        this.f = f;
+        this.v = v;
+    }
+
+    group(): Group {
+        return this.f.g;
+    }
+
+    add(o: Scalar): Scalar {
+        return this.f.newScalar(this.v + this.f.valueOf(o));
+    }
+
+    subtract(o: Scalar): Scalar {
+        return this.f.newScalar(this.v - this.f.valueOf(o));
+    }
+
+    multiply(o: Scalar): Scalar {
+        return this.f.newScalar(this.v * this.f.valueOf(o));
+    }
+
+    negate(): Scalar {
+        return this.f.newScalar(-this.v);
+    }
+
+    invert(): Scalar {
+        if (this.v === 0n) {
+            throw new GroupError('zeroScalar');
+        }
+        return new FieldScalar(this.f, modPow(this.v, this.f.order - 2n, this.f.order));
+    }
+
+    equal(o: Scalar): boolean {
+        return this.v === this.f.valueOf(o);
+    }
+
+    isZero(): boolean {
+        return this.v === 0n;
+    }
+
+    toBigInt(): bigint {
+        return this.v;
+    }
+
+    toBytes(): Uint8Array {
+        const out = bigIntToBytes(this.v, this.f.size);
+        return this.f.littleEndian ? reversed(out) : out;
+    }
+}
+
+export {
+    GroupErrorCodes,
+    type GroupErrorCode,
+    GroupError,
+    type Group,
+    type Element,
+    type Scalar,
+    ScalarField,
+    reversed,
+};
@@ -0,0 +1,3 @@
+export * from './group';
+export * from './weierstrass';
+export * from './ristretto255';
@@ -0,0 +1,326 @@
+import { bytesToBigInt, bigIntToBytes } from '../util/bytes';
+import { expandMessageXmd } from '../util/expand';
+import { Field, modPow } from '../h2c/field';
+import { GroupError, ScalarField, reversed, type Group, type Element, type Scalar } from './group';
+
+/** The length of an encoded ristretto255 element. */
+const ElementSize = 32;
+/** The length of an encoded ristretto255 scalar. */
+const ScalarSize = 32;
+/** The input length of the one-way maps to elements and scalars. */
+const UniformSize = 64;
+
+// The ristretto255 group (RFC 9496) on top of edwards25519. The arithmetic is not constant
+// time.
+const fp = new Field((1n << 255n) - 19n);
+const order = (1n << 252n) + 27742317777372353535851937790883648493n;
+
+// edwardsD is -121665/121666, the d of edwards25519.
+const edwardsD = fp.mul(-121665n, fp.inv0(121666n));
+
+const sqrtM1 = 19681161376707505956807079304988542015446066515923890162744021073123829784752n;
+const sqrtADMinusOne = 25063068953384623474111414158702152701244531502492656460079210482610430750235n;
+const invsqrtAMinusD = 54469307008909316920995813868745141605393597292927456921205312896311721017578n;
+const oneMinusDSq = 1159843021668779879193775521855586647937357759715417654439879720876111806838n;
+const dMinusOneSq = 40440834346308536858101042469323190826248399146238708352240133220865137265952n;
+const generatorX = 15112221349535400772501151409588531511454012693041857206046113283949847762202n;
+const generatorY = fp.mul(4n, fp.inv0(5n));
+const sqrtRatioExpo = (fp.p - 5n) >> 3n;
+
+function isNegative(a: bigint): boolean {
+    return (a & 1n) === 1n;
+}
+
+function abs(a: bigint): bigint {
+    return isNegative(a) ? fp.neg(a) : a;
+}
+
+// Returns [true, +sqrt(u/v)] if u/v is square, and [false, +sqrt(i*u/v)] otherwise.
+function sqrtRatioM1(u: bigint, v: bigint): [boolean, bigint] {
+    const v3 = fp.mul(fp.mul(v, v), v);
+    const v7 = fp.mul(fp.mul(v3, v3), v);
+    let r = fp.mul(fp.mul(u, v3), modPow(fp.mul(u, v7), sqrtRatioExpo, fp.p));
+    const check = fp.mul(v, fp.mul(r, r));
+    const correctSign = check === fp.mod(u);
+    const flippedSign = check === fp.neg(u);
+    const flippedSignI = check === fp.neg(fp.mul(u, sqrtM1));
+    if (flippedSign || flippedSignI) {
+        r = fp.mul(r, sqrtM1);
+    }
+    return [correctSign || flippedSign, abs(r)];
+}
+
+// An edwards25519 point in extended coordinates (X:Y:Z:T) with x = X/Z, y = Y/Z and xy = T/Z.
+type Extended = { x: bigint; y: bigint; z: bigint; t: bigint };
+
+const identityPoint: Extended = { x: 0n, y: 1n, z: 1n, t: 0n };
+const generatorPoint: Extended = { x: generatorX, y: generatorY, z: 1n, t: fp.mul(generatorX, generatorY) };
+
+// The complete extended-coordinate addition for a = -1.
+function add(e: Extended, o: Extended): Extended {
+    const a = fp.mul(fp.sub(e.y, e.x), fp.sub(o.y, o.x));
+    const b = fp.mul(fp.add(e.y, e.x), fp.add(o.y, o.x));
+    const c = fp.mul(fp.mul(e.t, o.t), fp.mul(2n, edwardsD));
+    const d = fp.mul(fp.mul(e.z, o.z), 2n);
+    const ee = fp.sub(b, a);
+    const f = fp.sub(d, c);
+    const g = fp.add(d, c);
+    const h = fp.add(b, a);
+    return { x: fp.mul(ee, f), y: fp.mul(g, h), z: fp.mul(f, g), t: fp.mul(ee, h) };
+}
+
+function negate(e: Extended): Extended {
+    return { x: fp.neg(e.x), y: e.y, z: e.z, t: fp.neg(e.t) };
+}
+
+function scalarMult(e: Extended, k: bigint): Extended {
+    let acc = identityPoint;
+    for (let i = k.toString(2).length - 1; i >= 0 && k > 0n; i--) {
+        acc = add(acc, acc);
+        if ((k >> BigInt(i)) & 1n) {
+            acc = add(acc, e);
+        }
+    }
+    return acc;
+}
+
+// Different edwards25519 points in the same coset compare equal.
+function equal(e: Extended, o: Extended): boolean {
+    return fp.mul(e.x, o.y) === fp.mul(e.y, o.x) || fp.mul(e.y, o.y) === fp.mul(e.x, o.x);
+}
+
+function encode(e: Extended): Uint8Array {
+    const u1 = fp.mul(fp.add(e.z, e.y), fp.sub(e.z, e.y));
+    const u2 = fp.mul(e.x, e.y);
+    const [, invsqrt] = sqrtRatioM1(1n, fp.mul(u1, fp.mul(u2, u2)));
+    const den1 = fp.mul(invsqrt, u1);
+    const den2 = fp.mul(invsqrt, u2);
+    const zInv = fp.mul(fp.mul(den1, den2), e.t);
+
+    let x = e.x;
+    let y = e.y;
+    let denInv = den2;
+    if (isNegative(fp.mul(e.t, zInv))) {
+        x = fp.mul(e.y, sqrtM1);
+        y = fp.mul(e.x, sqrtM1);
+        denInv = fp.mul(den1, invsqrtAMinusD);
+    }
+    if (isNegative(fp.mul(x, zInv))) {
+        y = fp.neg(y);
+    }
+    const s = abs(fp.mul(denInv, fp.sub(e.z, y)));
+    return reversed(bigIntToBytes(s, ElementSize));
+}
+
+// Parses a canonical encoding, rejecting every non-canonical form.
+function decode(b: Uint8Array): Extended {
+    if (b.length !== ElementSize) {
+        throw new GroupError('invalidElement');
+    }
+    const s = bytesToBigInt(reversed(b));
+    if (s >= fp.p || isNegative(s)) {
+        throw new GroupError('invalidElement');
+    }
+    const ss = fp.mul(s, s);
+    const u1 = fp.sub(1n, ss);
+    const u2 = fp.add(1n, ss);
+    const u2Sqr = fp.mul(u2, u2);
+    const v = fp.sub(fp.neg(fp.mul(edwardsD, fp.mul(u1, u1))), u2Sqr);
+    const [wasSquare, invsqrt] = sqrtRatioM1(1n, fp.mul(v, u2Sqr));
+    const denX = fp.mul(invsqrt, u2);
+    const denY = fp.mul(fp.mul(invsqrt, denX), v);
+    const x = abs(fp.mul(fp.mul(2n, s), denX));
+    const y = fp.mul(u1, denY);
+    const t = fp.mul(x, y);
+    if (!wasSquare || isNegative(t) || y === 0n) {
+        throw new GroupError('invalidElement');
+    }
+    return { x, y, z: 1n, t };
+}
+
+// The ristretto255 Elligator map from a field element.
+function mapToPoint(t: bigint): Extended {
+    const r = fp.mul(sqrtM1, fp.mul(t, t));
+    const u = fp.mul(fp.add(r, 1n), oneMinusDSq);
+    const v = fp.mul(fp.sub(-1n, fp.mul(r, edwardsD)), fp.add(r, edwardsD));
+    let [wasSquare, s] = sqrtRatioM1(u, v);
+    let c = fp.neg(1n);
+    if (!wasSquare) {
+        s = fp.neg(abs(fp.mul(s, t)));
+        c = r;
+    }
+    const n = fp.sub(fp.mul(fp.mul(c, fp.sub(r, 1n)), dMinusOneSq), v);
+    const ss = fp.mul(s, s);
+    const w0 = fp.mul(fp.mul(2n, s), v);
+    const w1 = fp.mul(n, sqrtADMinusOne);
+    const w2 = fp.sub(1n, ss);
+    const w3 = fp.add(1n, ss);
+    return { x: fp.mul(w0, w3), y: fp.mul(w2, w1), z: fp.mul(w1, w3), t: fp.mul(w0, w2) };
+}
+
+/**
+ * The ristretto255 group (RFC 9496), hashing with hash_to_ristretto255. Scalars are 32-byte
+ * little-endian integers modulo ℓ = 2^252 + 27742317777372353535851937790883648493.
+ */
+class RistrettoGroup implements Group {
+    readonly scalars: ScalarField;
+
+    constructor() {
+        this.scalars = new ScalarField(this, order, ScalarSize, true);
+    }
+
+    name(): string {
+        return 'ristretto255';
+    }
+
+    order(): bigint {
+        return order;
+    }
+
+    elementSize(): number {
+        return ElementSize;
+    }
+
+    scalarSize(): number {
+        return ScalarSize;
+    }
+
+    identity(): Element {
+        return this.element(identityPoint);
+    }
+
+    generator(): Element {
+        return this.element(generatorPoint);
+    }
+
+    newScalar(v: bigint): Scalar {
+        return this.scalars.newScalar(v);
+    }
+
+    randomScalar(): Scalar {
+        return this.scalars.random();
+    }
+
+    decodeScalar(b: Uint8Array): Scalar {
+        return this.scalars.decode(b);
+    }
+
+    decodeElement(b: Uint8Array): Element {
+        return this.element(decode(b));
+    }
+
+    // hash_to_ristretto255 (RFC 9380, appendix B): 64 bytes from expand_message_xmd over
+    // SHA-512 under dst, mapped with the RFC 9496 one-way map.
+    async hashToElement(msg: Uint8Array, dst: Uint8Array): Promise<Element> {
+        return ristrettoElementFromUniformBytes(await expandMessageXmd(msg, dst, UniformSize, 512));
+    }
+
+    // 64 bytes from expand_message_xmd over SHA-512 under dst, read little-endian, modulo ℓ;
+    // the ristretto255 HashToScalar of RFC 9497.
+    async hashToScalar(msg: Uint8Array, dst: Uint8Array): Promise<Scalar> {
+        return ristrettoScalarFromUniformBytes(await expandMessageXmd(msg, dst, UniformSize, 512));
+    }
+
+    element(e: Extended): RistrettoElement {
+        return new RistrettoElement(this, e);
+    }
+}
+
+class RistrettoElement implements Element {
+    readonly g: RistrettoGroup;
+    readonly e: Extended;
+
+    constructor(g: RistrettoGroup, e: Extended) {
+        this.g = g;
+        this.e = e;
+    }
+
+    private other(o: Element): Extended {
+        if (!(o instanceof RistrettoElement) || o.g !== this.g) {
+            throw new GroupError('groupMismatch');
+        }
+        return o.e;
+    }
+
+    group(): Group {
+        return this.g;
+    }
+
+    add(o: Element): Element {
+        return this.g.element(add(this.e, this.other(o)));
+    }
+
+    subtract(o: Element): Element {
+        return this.g.element(add(this.e, negate(this.other(o))));
+    }
+
+    negate(): Element {
+        return this.g.element(negate(this.e));
+    }
+
+    scalarMult(s: Scalar): Element {
+        return this.g.element(scalarMult(this.e, this.g.scalars.valueOf(s)));
+    }
+
+    equal(o: Element): boolean {
+        return equal(this.e, this.other(o));
+    }
+
+    isIdentity(): boolean {
+        return equal(this.e, identityPoint);
+    }
+
+    toBytes(): Uint8Array {
+        return encode(this.e);
+    }
+}
+
+/** Ristretto255 is the ristretto255 group (RFC 9496). */
+const Ristretto255 = new RistrettoGroup();
+
+/**
+ * Maps 64 uniformly random bytes to an element with no known discrete logarithm
+ * (RFC 9496, section 4.3.4).
+ *
+ * @throws {GroupError} - invalidElement unless b is 64 bytes.
+ *
+ * @param b - The uniform bytes.
+ *
+ * @returns Element - The ristretto255 element.
+ */
+function ristrettoElementFromUniformBytes(b: Uint8Array): Element {
+    if (b.length !== UniformSize) {
+        throw new GroupError('invalidElement');
+    }
+    const half = (h: Uint8Array) => {
+        const c = reversed(h);
+        c[0] &= 0x7f;
+        return fp.mod(bytesToBigInt(c));
+    };
+    return Ristretto255.element(add(mapToPoint(half(b.subarray(0, 32))), mapToPoint(half(b.subarray(32)))));
+}
+
+/**
+ * Reduces 64 little-endian bytes modulo ℓ.
+ *
+ * @throws {GroupError} - invalidScalar unless b is 64 bytes.
+ *
+ * @param b - The uniform bytes.
+ *
+ * @returns Scalar - The ristretto255 scalar.
+ */
+function ristrettoScalarFromUniformBytes(b: Uint8Array): Scalar {
+    if (b.length !== UniformSize) {
+        throw new GroupError('invalidScalar');
+    }
+    return Ristretto255.newScalar(bytesToBigInt(reversed(b)));
+}
+
+export {
+    ElementSize,
+    ScalarSize,
+    UniformSize,
+    Ristretto255,
+    ristrettoElementFromUniformBytes,
+    ristrettoScalarFromUniformBytes,
+};
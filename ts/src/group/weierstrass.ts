@@ -0,0 +1,339 @@
+import { bytesToBigInt, bigIntToBytes, concatBytes } from '../util/bytes';
+import { hashToField, xmdExpander } from '../util/expand';
+import type { Sha2 } from '../util/hash';
+import { Field } from '../h2c/field';
+import { weierstrassAdd, type Point, type Weierstrass as Curve } from '../h2c/sswu';
+import { hashToCurve, Suites, P256 as P256Curve, P384 as P384Curve, Secp256k1 as Secp256k1Curve, type Suite } from '../h2c/h2c';
+import { GroupError, ScalarField, type Group, type Element, type Scalar } from './group';
+
+/**
+ * A prime-order short Weierstrass curve. Elements are encoded as compressed SEC1 points,
+ * and the identity as the single byte 0x00.
+ */
+class Weierstrass implements Group {
+    readonly curve: Curve;
+    readonly f: Field;
+    readonly scalars: ScalarField;
+    private readonly groupName: string;
+    private readonly suite: Suite;
+    private readonly hashBits: Sha2;
+    private readonly secBits: number;
+    private readonly g: Point;
+    private readonly size: number;
+
+    constructor(name: string, curve: Curve, n: bigint, g: Point, suite: Suite, hashBits: Sha2, secBits: number) {
+        this.groupName = name;
+        this.curve = curve;
+        this.f = new Field(curve.p);
+        this.suite = suite;
+        this.hashBits = hashBits;
+        this.secBits = secBits;
+        this.g = g;
+        this.size = Math.ceil(curve.p.toString(2).length / 8);
+        this.scalars = new ScalarField(this, n, Math.ceil(n.toString(2).length / 8));
+    }
+
+    name(): string {
+        return this.groupName;
+    }
+
+    order(): bigint {
+        return this.scalars.order;
+    }
+
+    elementSize(): number {
+        return 1 + this.size;
+    }
+
+    scalarSize(): number {
+        return this.scalars.size;
+    }
+
+    identity(): Element {
+        return this.point({ x: 0n, y: 0n });
+    }
+
+    generator(): Element {
+        return this.point(this.g);
+    }
+
+    newScalar(v: bigint): Scalar {
+        return this.scalars.newScalar(v);
+    }
+
+    randomScalar(): Scalar {
+        return this.scalars.random();
+    }
+
+    decodeScalar(b: Uint8Array): Scalar {
+        return this.scalars.decode(b);
+    }
+
+    async hashToElement(msg: Uint8Array, dst: Uint8Array): Promise<Element> {
+        return this.point(await hashToCurve(this.suite, msg, dst));
+    }
+
+    // hash_to_field over the order with the suite's expand_message_xmd and security level,
+    // as RFC 9497 specifies for the NIST curves.
+    async hashToScalar(msg: Uint8Array, dst: Uint8Array): Promise<Scalar> {
+        const [u] = await hashToField(msg, dst, 1, this.scalars.order, this.secBits, xmdExpander(this.hashBits));
+        return this.scalars.newScalar(u);
+    }
+
+    decodeElement(b: Uint8Array): Element {
+        if (b.length === 1 && b[0] === 0x00) {
+            return this.identity();
+        }
+        if (b.length !== 1 + this.size || (b[0] !== 0x02 && b[0] !== 0x03)) {
+            throw new GroupError('invalidElement');
+        }
+        const f = this.f;
+        const x = bytesToBigInt(b.subarray(1));
+        if (x >= f.p) {
+            throw new GroupError('invalidElement');
+        }
+        let y = f.sqrt(f.add(f.mul(f.add(f.mul(x, x), this.curve.a), x), this.curve.b));
+        if (y === null) {
+            throw new GroupError('invalidElement');
+        }
+        if (Number(y & 1n) !== (b[0] & 1)) {
+            y = f.neg(y);
+        }
+        return this.point({ x, y });
+    }
+
+    /**
+     * Parses an uncompressed SEC1 point 0x04 || x || y, as written by
+     * WeierstrassPoint.toUncompressed.
+     *
+     * @throws {GroupError} - invalidElement for a malformed or off-curve point.
+     */
+    decodeUncompressed(b: Uint8Array): Element {
+        if (b.length !== 1 + 2 * this.size || b[0] !== 0x04) {
+            throw new GroupError('invalidElement');
+        }
+        const f = this.f;
+        const x = bytesToBigInt(b.subarray(1, 1 + this.size));
+        const y = bytesToBigInt(b.subarray(1 + this.size));
+        if (x >= f.p || y >= f.p || f.mul(y, y) !== f.add(f.mul(f.add(f.mul(x, x), this.curve.a), x), this.curve.b)) {
+            throw new GroupError('invalidElement');
+        }
+        return this.point({ x, y });
+    }
+
+    point(p: Point): WeierstrassPoint {
+        return new WeierstrassPoint(this, p);
+    }
+}
+
+// Jacobian coordinates (X, Y, Z) with x = X/Z^2 and y = Y/Z^3, Z = 0 for the identity, so
+// scalar multiplication needs one inversion instead of one per step.
+type Jacobian = [bigint, bigint, bigint];
+
+function jacobianDouble(w: Weierstrass, [x, y, z]: Jacobian): Jacobian {
+    const f = w.f;
+    if (z === 0n || y === 0n) {
+        return [0n, 1n, 0n];
+    }
+    const yy = f.mul(y, y);
+    const zz = f.mul(z, z);
+    const s = f.mul(4n, f.mul(x, yy));
+    const m = f.add(f.mul(3n, f.mul(x, x)), f.mul(w.curve.a, f.mul(zz, zz)));
+    const x3 = f.sub(f.mul(m, m), f.mul(2n, s));
+    const y3 = f.sub(f.mul(m, f.sub(s, x3)), f.mul(8n, f.mul(yy, yy)));
+    return [x3, y3, f.mul(2n, f.mul(y, z))];
+}
+
+function jacobianAdd(w: Weierstrass, p1: Jacobian, p2: Jacobian): Jacobian {
+    const f = w.f;
+    if (p1[2] === 0n) return p2;
+    if (p2[2] === 0n) return p1;
+    const z1z1 = f.mul(p1[2], p1[2]);
+    const z2z2 = f.mul(p2[2], p2[2]);
+    const u1 = f.mul(p1[0], z2z2);
+    const u2 = f.mul(p2[0], z1z1);
+    const s1 = f.mul(p1[1], f.mul(p2[2], z2z2));
+    const s2 = f.mul(p2[1], f.mul(p1[2], z1z1));
+    if (u1 === u2) {
+        return s1 === s2 ? jacobianDouble(w, p1) : [0n, 1n, 0n];
+    }
+    const h = f.sub(u2, u1);
+    const r = f.sub(s2, s1);
+    const hh = f.mul(h, h);
+    const hhh = f.mul(h, hh);
+    const v = f.mul(u1, hh);
+    const x3 = f.sub(f.sub(f.mul(r, r), hhh), f.mul(2n, v));
+    const y3 = f.sub(f.mul(r, f.sub(v, x3)), f.mul(s1, hhh));
+    return [x3, y3, f.mul(h, f.mul(p1[2], p2[2]))];
+}
+
+function toAffine(w: Weierstrass, [x, y, z]: Jacobian): Point {
+    const f = w.f;
+    if (z === 0n) {
+        return { x: 0n, y: 0n };
+    }
+    const zInv = f.inv0(z);
+    const zInv2 = f.mul(zInv, zInv);
+    return { x: f.mul(x, zInv2), y: f.mul(y, f.mul(zInv2, zInv)) };
+}
+
+/**
+ * An affine point; the identity is (0, 0).
+ */
+class WeierstrassPoint implements Element {
+    readonly w: Weierstrass;
+    readonly p: Point;
+
+    constructor(w: Weierstrass, p: Point) {
+        this.w = w;
+        this.p = p;
+    }
+
+    private other(o: Element): Point {
+        if (!(o instanceof WeierstrassPoint) || o.w !== this.w) {
+            throw new GroupError('groupMismatch');
+        }
+        return o.p;
+    }
+
+    group(): Group {
+        return this.w;
+    }
+
+    isIdentity(): boolean {
+        return this.p.x === 0n && this.p.y === 0n;
+    }
+
+    add(o: Element): Element {
+        return this.w.point(weierstrassAdd(this.w.curve, this.p, this.other(o)));
+    }
+
+    subtract(o: Element): Element {
+        this.other(o);
+        return this.add(o.negate());
+    }
+
+    negate(): Element {
+        if (this.isIdentity()) {
+            return this;
+        }
+        return this.w.point({ x: this.p.x, y: this.w.f.neg(this.p.y) });
+    }
+
+    scalarMult(s: Scalar): Element {
+        const k = this.w.scalars.valueOf(s);
+        if (this.isIdentity() || k === 0n) {
+            return this.w.identity();
+        }
+        const base: Jacobian = [this.p.x, this.p.y, 1n];
+        let acc: Jacobian = [0n, 1n, 0n];
+        for (let i = k.toString(2).length - 1; i >= 0; i--) {
+            acc = jacobianDouble(this.w, acc);
+            if ((k >> BigInt(i)) & 1n) {
+                acc = jacobianAdd(this.w, acc, base);
+            }
+        }
+        return this.w.point(toAffine(this.w, acc));
+    }
+
+    equal(o: Element): boolean {
+        const q = this.other(o);
+        return this.p.x === q.x && this.p.y === q.y;
+    }
+
+    toBytes(): Uint8Array {
+        if (this.isIdentity()) {
+            return new Uint8Array([0x00]);
+        }
+        return concatBytes(new Uint8Array([0x02 | Number(this.p.y & 1n)]), bigIntToBytes(this.p.x, this.w.elementSize() - 1));
+    }
+
+    /**
+     * Returns the uncompressed SEC1 encoding 0x04 || x || y, which protocols such as SPAKE2
+     * (RFC 9382) hash and send. The identity keeps its toBytes encoding.
+     */
+    toUncompressed(): Uint8Array {
+        if (this.isIdentity()) {
+            return this.toBytes();
+        }
+        const size = this.w.elementSize() - 1;
+        return concatBytes(new Uint8Array([0x04]), bigIntToBytes(this.p.x, size), bigIntToBytes(this.p.y, size));
+    }
+}
+
+/** NIST P-256 with hash_to_curve suite P256_XMD:SHA-256_SSWU_RO_. */
+const P256: Group = new Weierstrass(
+    'P-256',
+    P256Curve,
+    0xffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551n,
+    {
+        x: 0x6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296n,
+        y: 0x4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5n,
+    },
+    Suites.P256RO,
+    256,
+    128,
+);
+
+/** NIST P-384 with hash_to_curve suite P384_XMD:SHA-384_SSWU_RO_. */
+const P384: Group = new Weierstrass(
+    'P-384',
+    P384Curve,
+    0xffffffffffffffffffffffffffffffffffffffffffffffffc7634d81f4372ddf581a0db248b0a77aecec196accc52973n,
+    {
+        x: 0xaa87ca22be8b05378eb1c71ef320ad746e1d3b628ba79b9859f741e082542a385502f25dbf55296c3a545e3872760ab7n,
+        y: 0x3617de4a96262c6f5d9e98bf9292dc29f8f41dbd289a147ce9da3113b5f0b8c00a60b1ce1d7e819d7a431d7c90ea0e5fn,
+    },
+    Suites.P384RO,
+    384,
+    192,
+);
+
+/** secp256k1 with hash_to_curve suite secp256k1_XMD:SHA-256_SSWU_RO_. */
+const Secp256k1: Group = new Weierstrass(
+    'secp256k1',
+    Secp256k1Curve,
+    0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141n,
+    {
+        x: 0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798n,
+        y: 0x483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8n,
+    },
+    Suites.Secp256k1RO,
+    256,
+    128,
+);
+
+/**
+ * Returns the uncompressed SEC1 encoding of an element of a Weierstrass group; the identity
+ * and ristretto255 elements keep their toBytes encoding.
+ *
+ * @param e - The element.
+ *
+ * @returns Uint8Array - The encoding.
+ */
+function marshalUncompressed(e: Element): Uint8Array {
+    return e instanceof WeierstrassPoint ? e.toUncompressed() : e.toBytes();
+}
+
+/**
+ * Parses the encoding written by marshalUncompressed for g: an uncompressed point on the
+ * curve for a Weierstrass group, or g.decodeElement otherwise.
+ *
+ * @throws {GroupError} - invalidElement.
+ *
+ * @param g - The group.
+ * @param b - The encoding.
+ *
+ * @returns Element - The element.
+ */
+function decodeUncompressed(g: Group, b: Uint8Array): Element {
+    return g instanceof Weierstrass ? g.decodeUncompressed(b) : g.decodeElement(b);
+}
+
+export {
+    P256,
+    P384,
+    Secp256k1,
+    marshalUncompressed,
+    decodeUncompressed,
+};
@@ -0,0 +1,82 @@
+import { Field, sgn0 } from './field';
+import type { Point } from './sswu';
+
+// edwards25519 is hashed by Elligator 2 to curve25519, v^2 = s^3 + J*s^2 + s, followed by
+// the rational map to edwards25519, -x^2 + y^2 = 1 + d*x^2*y^2 (RFC 9380, section 6.8.2).
+const field25519 = new Field((1n << 255n) - 19n);
+
+const montgomeryJ = 486662n;
+const elligatorZ = 2n;
+
+// edwardsD is -121665/121666.
+const edwardsD = field25519.mul(-121665n, field25519.inv0(121666n));
+
+function evenSqrt(f: Field, a: bigint): bigint {
+    const r = f.sqrt(a) as bigint;
+    return sgn0(r) === 1 ? f.neg(r) : r;
+}
+
+// edwardsC1 is sqrt(-486664) with sgn0 = 0, the scale of the rational map.
+const edwardsC1 = evenSqrt(field25519, -486664n);
+
+// map_to_curve_elligator2 for curve25519 (K = 1), returning (s, t).
+function elligator2(u: bigint): [bigint, bigint] {
+    const f = field25519;
+    let x1 = f.mul(f.neg(montgomeryJ), f.inv0(f.add(1n, f.mul(elligatorZ, f.mul(u, u)))));
+    if (x1 === 0n) {
+        x1 = f.neg(montgomeryJ);
+    }
+    const g = (x: bigint) => f.add(f.mul(f.add(f.mul(x, x), f.mul(montgomeryJ, x)), x), x);
+    let y = f.sqrt(g(x1));
+    if (y !== null) {
+        if (sgn0(y) === 0) {
+            y = f.neg(y);
+        }
+        return [x1, y];
+    }
+    const x2 = f.sub(f.neg(x1), montgomeryJ);
+    return [x2, evenSqrt(f, g(x2))];
+}
+
+/**
+ * Maps u with Elligator 2 and converts the curve25519 point (s, t) to edwards25519 as
+ * (c1*s/t, (s-1)/(s+1)), sending the exceptional cases to (0, 1).
+ */
+function edwards25519MapToCurve(u: bigint): Point {
+    const f = field25519;
+    const [s, t] = elligator2(u);
+    const sPlus1 = f.add(s, 1n);
+    if (t === 0n || sPlus1 === 0n) {
+        return { x: 0n, y: 1n };
+    }
+    return { x: f.mul(f.mul(edwardsC1, s), f.inv0(t)), y: f.mul(f.sub(s, 1n), f.inv0(sPlus1)) };
+}
+
+/**
+ * The complete affine addition law on edwards25519.
+ */
+function edwardsAdd(p1: Point, p2: Point): Point {
+    const f = field25519;
+    const dxy = f.mul(edwardsD, f.mul(f.mul(p1.x, p2.x), f.mul(p1.y, p2.y)));
+    return {
+        x: f.mul(f.add(f.mul(p1.x, p2.y), f.mul(p1.y, p2.x)), f.inv0(f.add(1n, dxy))),
+        y: f.mul(f.add(f.mul(p1.y, p2.y), f.mul(p1.x, p2.x)), f.inv0(f.sub(1n, dxy))),
+    };
+}
+
+/**
+ * Multiplies by the cofactor 8 with three doublings.
+ */
+function edwardsClearCofactor(p: Point): Point {
+    for (let i = 0; i < 3; i++) {
+        p = edwardsAdd(p, p);
+    }
+    return p;
+}
+
+export {
+    field25519,
+    edwards25519MapToCurve,
+    edwardsAdd,
+    edwardsClearCofactor,
+};
@@ -0,0 +1,92 @@
+import { bigModPos } from '../util/numeric';
+
+/**
+ * Returns base^exp mod m for exp >= 0.
+ */
+function modPow(base: bigint, exp: bigint, m: bigint): bigint {
+    let result = 1n;
+    base = bigModPos(base, m);
+    while (exp > 0n) {
+        if (exp & 1n) result = (result * base) % m;
+        base = (base * base) % m;
+        exp >>= 1n;
+    }
+    return result;
+}
+
+/**
+ * Arithmetic modulo a prime p. All results are in [0, p).
+ */
+class Field {
+    readonly p: bigint;
+
+    constructor(p: bigint) {
+        this.p = p;
+    }
+
+    mod(a: bigint): bigint {
+        return bigModPos(a, this.p);
+    }
+
+    add(a: bigint, b: bigint): bigint {
+        return this.mod(a + b);
+    }
+
+    sub(a: bigint, b: bigint): bigint {
+        return this.mod(a - b);
+    }
+
+    mul(a: bigint, b: bigint): bigint {
+        return this.mod(a * b);
+    }
+
+    neg(a: bigint): bigint {
+        return this.mod(-a);
+    }
+
+    // The inverse with inv0(0) = 0.
+    inv0(a: bigint): bigint {
+        return modPow(a, this.p - 2n, this.p);
+    }
+
+    // A square root of a, or null if a is not a square. Covers p = 3 mod 4 and p = 5 mod 8,
+    // which includes every supported curve.
+    sqrt(a: bigint): bigint | null {
+        const p = this.p;
+        a = this.mod(a);
+        let r: bigint;
+        if (p % 4n === 3n) {
+            r = modPow(a, (p + 1n) / 4n, p);
+        } else if (p % 8n === 5n) {
+            // Atkin's algorithm.
+            const v = modPow(2n * a, (p - 5n) / 8n, p);
+            const i = this.mul(2n * a, v * v);
+            r = this.mul(a * v, i - 1n);
+        } else {
+            throw new RangeError('h2c: unsupported field');
+        }
+        return this.mul(r, r) === a ? r : null;
+    }
+
+    // Evaluates the polynomial with coefficients c (constant term first) at x.
+    poly(c: bigint[], x: bigint): bigint {
+        let acc = 0n;
+        for (let i = c.length - 1; i >= 0; i--) {
+            acc = this.add(this.mul(acc, x), c[i]);
+        }
+        return acc;
+    }
+}
+
+/**
+ * The sign of a field element, its parity (RFC 9380, section 4.1).
+ */
+function sgn0(a: bigint): number {
+    return Number(a & 1n);
+}
+
+export {
+    Field,
+    modPow,
+    sgn0,
+};
@@ -0,0 +1,237 @@
+import { hashToField as utilHashToField, xmdExpander } from '../util/expand';
+import type { Sha2 } from '../util/hash';
+import { SSWU, weierstrassAdd, type Point, type Weierstrass } from './sswu';
+import { secp256k1MapToCurve } from './secp256k1';
+import { field25519, edwards25519MapToCurve, edwardsAdd, edwardsClearCofactor } from './edwards25519';
+
+/**
+ * RFC 9380 suite IDs, in both the random-oracle (_RO_) and nonuniform (_NU_) encodings.
+ */
+const Suites = {
+    P256RO: 'P256_XMD:SHA-256_SSWU_RO_',
+    P256NU: 'P256_XMD:SHA-256_SSWU_NU_',
+    P384RO: 'P384_XMD:SHA-384_SSWU_RO_',
+    P384NU: 'P384_XMD:SHA-384_SSWU_NU_',
+    P521RO: 'P521_XMD:SHA-512_SSWU_RO_',
+    P521NU: 'P521_XMD:SHA-512_SSWU_NU_',
+    Secp256k1RO: 'secp256k1_XMD:SHA-256_SSWU_RO_',
+    Secp256k1NU: 'secp256k1_XMD:SHA-256_SSWU_NU_',
+    Edwards25519RO: 'edwards25519_XMD:SHA-512_ELL2_RO_',
+    Edwards25519NU: 'edwards25519_XMD:SHA-512_ELL2_NU_',
+} as const;
+
+type Suite = (typeof Suites)[keyof typeof Suites];
+
+/**
+ * Error codes of the h2c package, matching the Go sentinels.
+ */
+const H2cErrorCodes = {
+    unsupportedSuite: 'unsupportedSuite',
+    fieldElement: 'fieldElement',
+} as const;
+
+type H2cErrorCode = (typeof H2cErrorCodes)[keyof typeof H2cErrorCodes];
+
+const messages: Record<H2cErrorCode, string> = {
+    unsupportedSuite: 'h2c: unsupported suite',
+    fieldElement: 'h2c: field element is not in [0, p)',
+};
+
+/**
+ * Mirrors Go's h2c.ErrUnsupportedSuite and h2c.ErrFieldElement.
+ */
+class H2cError extends Error {
+    readonly code: H2cErrorCode;
+
+    constructor(code: H2cErrorCode) {
+        super(messages[code]);
+        this.name = 'H2cError';
+        this.code = code;
+    }
+}
+
+/**
+ * The short Weierstrass target curves, a = -3 for the NIST curves.
+ */
+const P256: Weierstrass = {
+    p: 0xffffffff00000001000000000000000000000000ffffffffffffffffffffffffn,
+    a: -3n,
+    b: 0x5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604bn,
+};
+const P384: Weierstrass = {
+    p: 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffffn,
+    a: -3n,
+    b: 0xb3312fa7e23ee7e4988e056be3f82d19181d9c6efe8141120314088f5013875ac656398d8a2ed19d2a85c8edd3ec2aefn,
+};
+const P521: Weierstrass = {
+    p: (1n << 521n) - 1n,
+    a: -3n,
+    b: 0x51953eb9618e1c9a1f929a21a0b68540eea2da725b99b315f3b8b489918ef109e156193951ec7e937b1652c0bd3bb1bf073573df883d2c34f1ef451fd46b503f00n,
+};
+const Secp256k1: Weierstrass = {
+    p: 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2fn,
+    a: 0n,
+    b: 7n,
+};
+
+// The arithmetic of one target curve.
+type Curve = {
+    p: bigint;
+    securityBits: number;
+    hashBits: Sha2;
+    mapToCurve: (u: bigint) => Point;
+    add: (p1: Point, p2: Point) => Point;
+    clear: (p: Point) => Point;
+};
+
+// clear_cofactor for the prime-order curves.
+const noCofactor = (p: Point) => p;
+
+function nistCurve(c: Weierstrass, z: bigint, securityBits: number, hashBits: Sha2): Curve {
+    const s = new SSWU(c.p, c.a, c.b, z);
+    return {
+        p: c.p,
+        securityBits,
+        hashBits,
+        mapToCurve: u => s.mapToCurve(u),
+        add: (p1, p2) => weierstrassAdd(c, p1, p2),
+        clear: noCofactor,
+    };
+}
+
+const p256Curve = nistCurve(P256, -10n, 128, 256);
+const p384Curve = nistCurve(P384, -12n, 192, 384);
+const p521Curve = nistCurve(P521, -4n, 256, 512);
+const secp256k1Curve: Curve = {
+    p: Secp256k1.p,
+    securityBits: 128,
+    hashBits: 256,
+    mapToCurve: secp256k1MapToCurve,
+    add: (p1, p2) => weierstrassAdd(Secp256k1, p1, p2),
+    clear: noCofactor,
+};
+const edwards25519Curve: Curve = {
+    p: field25519.p,
+    securityBits: 128,
+    hashBits: 512,
+    mapToCurve: edwards25519MapToCurve,
+    add: edwardsAdd,
+    clear: edwardsClearCofactor,
+};
+
+// Returns the curve of s and whether s is a random-oracle suite.
+function lookup(s: string): [Curve, boolean] {
+    switch (s) {
+        case Suites.P256RO:
+        case Suites.P256NU:
+            return [p256Curve, s === Suites.P256RO];
+        case Suites.P384RO:
+        case Suites.P384NU:
+            return [p384Curve, s === Suites.P384RO];
+        case Suites.P521RO:
+        case Suites.P521NU:
+            return [p521Curve, s === Suites.P521RO];
+        case Suites.Secp256k1RO:
+        case Suites.Secp256k1NU:
+            return [secp256k1Curve, s === Suites.Secp256k1RO];
+        case Suites.Edwards25519RO:
+        case Suites.Edwards25519NU:
+            return [edwards25519Curve, s === Suites.Edwards25519RO];
+        default:
+            throw new H2cError('unsupportedSuite');
+    }
+}
+
+/**
+ * Reports whether s is a random-oracle (_RO_) suite, whose hashToCurve output is
+ * indistinguishable from a random point; a nonuniform (_NU_) suite is cheaper but its
+ * output is not uniformly distributed.
+ *
+ * @param s - The suite ID.
+ *
+ * @returns boolean - True for an _RO_ suite, false otherwise.
+ */
+function isRandomOracle(s: string): boolean {
+    try {
+        return lookup(s)[1];
+    } catch {
+        return false;
+    }
+}
+
+/**
+ * Hashes msg to count elements of the suite's base field with the suite's
+ * expand_message_xmd and security level.
+ *
+ * @throws {H2cError} - unsupportedSuite.
+ *
+ * @param s - The suite ID.
+ * @param msg - The message to hash.
+ * @param dst - The domain separation tag.
+ * @param count - The number of field elements.
+ *
+ * @returns A promise that resolves to count elements of [0, p).
+ */
+async function hashToField(s: string, msg: Uint8Array, dst: Uint8Array, count: number): Promise<bigint[]> {
+    const [c] = lookup(s);
+    return utilHashToField(msg, dst, count, c.p, c.securityBits, xmdExpander(c.hashBits));
+}
+
+/**
+ * Maps the field element u to a point with the suite's map_to_curve, before cofactor
+ * clearing. For secp256k1 this includes the isogeny map, and for edwards25519 the map from
+ * curve25519 to edwards25519.
+ *
+ * @throws {H2cError} - unsupportedSuite, or fieldElement when u is not in [0, p).
+ *
+ * @param s - The suite ID.
+ * @param u - The field element.
+ *
+ * @returns Point - The affine point.
+ */
+function mapToCurve(s: string, u: bigint): Point {
+    const [c] = lookup(s);
+    if (u < 0n || u >= c.p) {
+        throw new H2cError('fieldElement');
+    }
+    return c.mapToCurve(u);
+}
+
+/**
+ * Hashes msg to a point of the suite's curve under the domain separation tag dst. For a
+ * random-oracle suite this is hash_to_curve, which maps two field elements and adds the
+ * results; for a nonuniform suite it is encode_to_curve, which maps one.
+ *
+ * @throws {H2cError} - unsupportedSuite.
+ *
+ * @param s - The suite ID.
+ * @param msg - The message to hash.
+ * @param dst - The domain separation tag.
+ *
+ * @returns A promise that resolves to the affine point.
+ */
+async function hashToCurve(s: string, msg: Uint8Array, dst: Uint8Array): Promise<Point> {
+    const [c, ro] = lookup(s);
+    const u = await utilHashToField(msg, dst, ro ? 2 : 1, c.p, c.securityBits, xmdExpander(c.hashBits));
+    let q = c.mapToCurve(u[0]);
+    if (ro) {
+        q = c.add(q, c.mapToCurve(u[1]));
+    }
+    return c.clear(q);
+}
+
+export {
+    Suites,
+    type Suite,
+    H2cErrorCodes,
+    type H2cErrorCode,
+    H2cError,
+    P256,
+    P384,
+    P521,
+    Secp256k1,
+    isRandomOracle,
+    hashToField,
+    mapToCurve,
+    hashToCurve,
+};
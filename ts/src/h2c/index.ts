@@ -0,0 +1,2 @@
+export * from './h2c';
+export { type Point, type Weierstrass, weierstrassAdd } from './sswu';
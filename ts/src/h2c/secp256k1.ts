@@ -0,0 +1,59 @@
+import { Field } from './field';
+import { SSWU, type Point } from './sswu';
+
+// secp256k1 has A = 0, so the simplified SWU map targets the 3-isogenous curve
+// y^2 = x^3 + A'*x + B' and iso_map carries the result back (RFC 9380, appendix E.1).
+const secp256k1Field = new Field(0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2fn);
+const secp256k1Iso = new SSWU(
+    secp256k1Field.p,
+    0x3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533n,
+    1771n,
+    -11n,
+);
+
+// Coefficients of the isogeny map, constant term first.
+const isoXNum = [
+    0x8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7n,
+    0x07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581n,
+    0x534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262n,
+    0x8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88cn,
+];
+const isoXDen = [
+    0xd35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49bn,
+    0xedadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14n,
+    0x01n,
+];
+const isoYNum = [
+    0x4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23cn,
+    0xc75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3n,
+    0x29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931n,
+    0x2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84n,
+];
+const isoYDen = [
+    0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93bn,
+    0x7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573n,
+    0x6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76fn,
+    0x01n,
+];
+
+/**
+ * map_to_curve_simple_swu on the isogenous curve followed by iso_map. A zero denominator
+ * maps to the identity (0, 0).
+ */
+function secp256k1MapToCurve(u: bigint): Point {
+    const f = secp256k1Field;
+    const q = secp256k1Iso.mapToCurve(u);
+    const xDen = f.poly(isoXDen, q.x);
+    const yDen = f.poly(isoYDen, q.x);
+    if (xDen === 0n || yDen === 0n) {
+        return { x: 0n, y: 0n };
+    }
+    return {
+        x: f.mul(f.poly(isoXNum, q.x), f.inv0(xDen)),
+        y: f.mul(q.y, f.mul(f.poly(isoYNum, q.x), f.inv0(yDen))),
+    };
+}
+
+export {
+    secp256k1MapToCurve,
+};
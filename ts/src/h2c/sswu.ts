@@ -0,0 +1,79 @@
+import { Field, sgn0 } from './field';
+
+/**
+ * An affine point. The identity is (0, 0) on the Weierstrass curves, and (0, 1) on
+ * edwards25519.
+ */
+type Point = { x: bigint; y: bigint };
+
+/**
+ * A short Weierstrass curve y^2 = x^3 + a*x + b over GF(p).
+ */
+type Weierstrass = { p: bigint; a: bigint; b: bigint };
+
+/**
+ * Adds two affine points of c, with (0, 0) as the identity, including doubling.
+ */
+function weierstrassAdd(c: Weierstrass, p1: Point, p2: Point): Point {
+    const f = new Field(c.p);
+    if (p1.x === 0n && p1.y === 0n) return p2;
+    if (p2.x === 0n && p2.y === 0n) return p1;
+    let lambda: bigint;
+    if (p1.x === p2.x) {
+        if (p1.y !== p2.y || p1.y === 0n) return { x: 0n, y: 0n };
+        lambda = f.mul(f.add(f.mul(3n, f.mul(p1.x, p1.x)), c.a), f.inv0(f.mul(2n, p1.y)));
+    } else {
+        lambda = f.mul(f.sub(p2.y, p1.y), f.inv0(f.sub(p2.x, p1.x)));
+    }
+    const x = f.sub(f.sub(f.mul(lambda, lambda), p1.x), p2.x);
+    return { x, y: f.sub(f.mul(lambda, f.sub(p1.x, x)), p1.y) };
+}
+
+/**
+ * The simplified Shallue-van de Woestijne-Ulas map to y^2 = x^3 + A*x + B with A*B != 0
+ * (RFC 9380, section 6.6.2).
+ */
+class SSWU {
+    private readonly f: Field;
+    private readonly a: bigint;
+    private readonly b: bigint;
+    private readonly z: bigint;
+
+    constructor(p: bigint, a: bigint, b: bigint, z: bigint) {
+        this.f = new Field(p);
+        this.a = this.f.mod(a);
+        this.b = this.f.mod(b);
+        this.z = this.f.mod(z);
+    }
+
+    private g(x: bigint): bigint {
+        const f = this.f;
+        return f.add(f.mul(f.add(f.mul(x, x), this.a), x), this.b);
+    }
+
+    mapToCurve(u: bigint): Point {
+        const f = this.f;
+        const zu2 = f.mul(this.z, f.mul(u, u));
+        const tv1 = f.inv0(f.add(f.mul(zu2, zu2), zu2));
+        const x1 = tv1 === 0n
+            ? f.mul(this.b, f.inv0(f.mul(this.z, this.a)))
+            : f.mul(f.mul(f.neg(this.b), f.inv0(this.a)), f.add(tv1, 1n));
+        let x = x1;
+        let y = f.sqrt(this.g(x1));
+        if (y === null) {
+            x = f.mul(zu2, x1);
+            y = f.sqrt(this.g(x)) as bigint;
+        }
+        if (sgn0(u) !== sgn0(y)) {
+            y = f.neg(y);
+        }
+        return { x, y };
+    }
+}
+
+export {
+    type Point,
+    type Weierstrass,
+    weierstrassAdd,
+    SSWU,
+};
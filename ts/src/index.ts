@@ -1 +1,8 @@
-export * as Util from './util';
\ No newline at end of file
+export * as Util from './util';
+export * as Keys from './keys';
+export * as H2c from './h2c';
+export * as Group from './group';
+export * as Oprf from './oprf';
+export * as Opaque from './opaque';
+export * as Spake2 from './spake2';
+export * as Cpace from './cpace';
@@ -0,0 +1,119 @@
+import { concatBytes } from '../util/bytes';
+import { encBase58, decBase58 } from '../util/coding';
+import { Curves, KeysError, isEC, normalizePublicKey, compressPublicKey } from './keys';
+
+const didKeyPrefix = 'did:key:';
+
+// multicodecs are the unsigned-varint multicodec prefixes for raw public keys.
+const multicodecs: [string, Uint8Array][] = [
+    [Curves.Ed25519, new Uint8Array([0xed, 0x01])],
+    [Curves.X25519, new Uint8Array([0xec, 0x01])],
+    [Curves.Secp256k1, new Uint8Array([0xe7, 0x01])],
+    [Curves.P256, new Uint8Array([0x80, 0x24])],
+    [Curves.P384, new Uint8Array([0x81, 0x24])],
+    [Curves.P521, new Uint8Array([0x82, 0x24])],
+];
+
+/**
+ * Returns the multicodec-prefixed public key used by did:key. EC points are compressed
+ * as the did:key method requires.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ *
+ * @returns Uint8Array - The prefixed public key.
+ *
+ * @throws {KeysError} as normalizePublicKey.
+ */
+function multicodec(curve: string, publicKey: Uint8Array): Uint8Array {
+    const pub = isEC(curve) ? compressPublicKey(curve, publicKey) : normalizePublicKey(curve, publicKey);
+    const entry = multicodecs.find(([c]) => c === curve);
+    if (!entry) throw new KeysError('unsupportedCurve');
+    return concatBytes(entry[1], pub);
+}
+
+/**
+ * Decodes a multicodec-prefixed public key.
+ *
+ * @param data - The prefixed public key.
+ *
+ * @returns [string, Uint8Array] - The key type and canonical raw public key.
+ *
+ * @throws {KeysError} unsupportedCurve for an unknown prefix, invalidKey for a malformed key.
+ */
+function parseMulticodec(data: Uint8Array): [string, Uint8Array] {
+    for (const [curve, prefix] of multicodecs) {
+        if (data.length >= prefix.length && prefix.every((b, i) => data[i] === b)) {
+            return [curve, normalizePublicKey(curve, data.subarray(prefix.length))];
+        }
+    }
+    throw new KeysError('unsupportedCurve');
+}
+
+/**
+ * Returns the multibase (base58btc, 'z' prefix) encoding of multicodec, the method-specific
+ * identifier of a did:key.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ *
+ * @returns string - The fingerprint.
+ */
+function fingerprint(curve: string, publicKey: Uint8Array): string {
+    return 'z' + encBase58(multicodec(curve, publicKey));
+}
+
+/**
+ * Decodes a base58btc multibase fingerprint.
+ *
+ * @param s - The fingerprint.
+ *
+ * @returns [string, Uint8Array] - The key type and canonical raw public key.
+ *
+ * @throws {KeysError} encoding for another multibase or invalid base58, otherwise as parseMulticodec.
+ */
+function parseFingerprint(s: string): [string, Uint8Array] {
+    if (!s.startsWith('z')) throw new KeysError('encoding');
+    let data: Uint8Array;
+    try {
+        data = decBase58(s.slice(1));
+    } catch {
+        throw new KeysError('encoding');
+    }
+    return parseMulticodec(data);
+}
+
+/**
+ * Returns the did:key identifier for a raw public key.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ *
+ * @returns string - The did:key identifier.
+ */
+function didKey(curve: string, publicKey: Uint8Array): string {
+    return didKeyPrefix + fingerprint(curve, publicKey);
+}
+
+/**
+ * Decodes a did:key identifier. DID URL fragments such as "#z6Mk..." are rejected.
+ *
+ * @param s - The did:key identifier.
+ *
+ * @returns [string, Uint8Array] - The key type and canonical raw public key.
+ *
+ * @throws {KeysError} encoding for a malformed identifier, otherwise as parseFingerprint.
+ */
+function parseDidKey(s: string): [string, Uint8Array] {
+    if (!s.startsWith(didKeyPrefix) || /[#?/]/.test(s)) throw new KeysError('encoding');
+    return parseFingerprint(s.slice(didKeyPrefix.length));
+}
+
+export {
+    multicodec,
+    parseMulticodec,
+    fingerprint,
+    parseFingerprint,
+    didKey,
+    parseDidKey,
+};
\ No newline at end of file
@@ -0,0 +1,3 @@
+export * from './keys';
+export * from './jwk';
+export * from './fingerprint';
\ No newline at end of file
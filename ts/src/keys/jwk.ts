@@ -0,0 +1,120 @@
+import { encUrlSafe, decUrlSafe } from '../util/coding';
+import { sha2Hash, type Sha2 } from '../util/hash';
+import { Curves, KeysError, isEC, normalizePublicKey, splitPoint, joinPoint } from './keys';
+
+/**
+ * A public JSON Web Key (RFC 7517) of the OKP (RFC 8037) or EC (RFC 7518) key type.
+ */
+type JWK = { crv: string; kty: string; x: string; y?: string; d?: string };
+
+/**
+ * Builds the public JWK for a raw public key. Coordinates are fixed width and encoded with encUrlSafe.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ *
+ * @returns JWK - The public JWK, members in lexicographic order.
+ *
+ * @throws {KeysError} as normalizePublicKey.
+ */
+function publicKeyToJWK(curve: string, publicKey: Uint8Array): JWK {
+    if (isEC(curve)) {
+        const [x, y] = splitPoint(curve, publicKey);
+        return { crv: curve, kty: 'EC', x: encUrlSafe(x), y: encUrlSafe(y) };
+    }
+    const pub = normalizePublicKey(curve, publicKey);
+    return { crv: curve, kty: 'OKP', x: encUrlSafe(pub) };
+}
+
+/**
+ * Checks kty against crv and returns the key type and raw public key of a JWK.
+ *
+ * @param jwk - The JWK; private and unknown members are ignored.
+ *
+ * @returns [string, Uint8Array] - The key type and canonical raw public key.
+ *
+ * @throws {KeysError} unsupportedCurve, invalidKey or encoding.
+ */
+function jwkPublicKey(jwk: JWK): [string, Uint8Array] {
+    const curve = jwk.crv;
+    if (curve === Curves.Ed25519 || curve === Curves.X25519) {
+        if (jwk.kty !== 'OKP' || jwk.y) throw new KeysError('invalidKey');
+        return [curve, normalizePublicKey(curve, decode(jwk.x))];
+    }
+    if (!isEC(curve)) throw new KeysError('unsupportedCurve');
+    if (jwk.kty !== 'EC') throw new KeysError('invalidKey');
+    return [curve, joinPoint(curve, decode(jwk.x), decode(jwk.y))];
+}
+
+function decode(s: string | undefined): Uint8Array {
+    if (typeof s !== 'string' || !/^[A-Za-z0-9_-]*$/.test(s) || s.length % 4 === 1) throw new KeysError('encoding');
+    return decUrlSafe(s);
+}
+
+/**
+ * Returns the canonical JSON hashed by jwkThumbprint: only the required public members,
+ * in lexicographic order, without whitespace.
+ *
+ * @param jwk - The JWK.
+ *
+ * @returns string - The RFC 7638 thumbprint input.
+ *
+ * @throws {KeysError} as jwkPublicKey.
+ */
+function jwkThumbprintInput(jwk: JWK): string {
+    const canonical = publicKeyToJWK(...jwkPublicKey(jwk));
+    const members: Record<string, string> = { crv: canonical.crv, kty: canonical.kty, x: canonical.x };
+    if (canonical.y) members.y = canonical.y;
+    return JSON.stringify(members);
+}
+
+/**
+ * Computes the RFC 7638 thumbprint with SHA-2 of 256, 384 or 512 bits.
+ * Private members are ignored, so a private JWK and its public JWK share a thumbprint.
+ *
+ * @param jwk - The JWK.
+ * @param hashBits - The SHA-2 output size.
+ *
+ * @returns Promise<Uint8Array> - The thumbprint.
+ *
+ * @throws {KeysError} as jwkPublicKey.
+ * @throws {ParityError} unsupportedBits for any other hashBits.
+ */
+async function jwkThumbprint(jwk: JWK, hashBits: Sha2): Promise<Uint8Array> {
+    return sha2Hash(new TextEncoder().encode(jwkThumbprintInput(jwk)), hashBits);
+}
+
+/**
+ * Returns jwkThumbprint encoded with encUrlSafe, the form used for "kid".
+ *
+ * @param jwk - The JWK.
+ * @param hashBits - The SHA-2 output size.
+ *
+ * @returns Promise<string> - The encoded thumbprint.
+ */
+async function jwkThumbprintString(jwk: JWK, hashBits: Sha2): Promise<string> {
+    return encUrlSafe(await jwkThumbprint(jwk, hashBits));
+}
+
+/**
+ * Returns the encoded RFC 7638 thumbprint of a raw public key.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ * @param hashBits - The SHA-2 output size.
+ *
+ * @returns Promise<string> - The encoded thumbprint.
+ */
+async function publicKeyThumbprint(curve: string, publicKey: Uint8Array, hashBits: Sha2): Promise<string> {
+    return jwkThumbprintString(publicKeyToJWK(curve, publicKey), hashBits);
+}
+
+export {
+    type JWK,
+    publicKeyToJWK,
+    jwkPublicKey,
+    jwkThumbprintInput,
+    jwkThumbprint,
+    jwkThumbprintString,
+    publicKeyThumbprint,
+};
\ No newline at end of file
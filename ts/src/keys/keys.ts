@@ -0,0 +1,212 @@
+import { bigModPos } from '../util/numeric';
+import { bytesToBigInt, bigIntToBytes, concatBytes } from '../util/bytes';
+
+/**
+ * Key types, named by their JWK "crv" (RFC 7518, 8037, 8812).
+ */
+const Curves = {
+    Ed25519: 'Ed25519',
+    X25519: 'X25519',
+    P256: 'P-256',
+    P384: 'P-384',
+    P521: 'P-521',
+    Secp256k1: 'secp256k1',
+} as const;
+
+type Curve = (typeof Curves)[keyof typeof Curves];
+
+/**
+ * Error codes of the keys package, matching the Go sentinels and the "error" fields of
+ * testdata/parity.json.
+ */
+const KeysErrorCodes = {
+    unsupportedCurve: 'unsupportedCurve',
+    invalidKey: 'invalidKey',
+    encoding: 'encoding',
+} as const;
+
+type KeysErrorCode = (typeof KeysErrorCodes)[keyof typeof KeysErrorCodes];
+
+const messages: Record<KeysErrorCode, string> = {
+    unsupportedCurve: 'keys: unsupported curve',
+    invalidKey: 'keys: invalid key',
+    encoding: 'keys: invalid encoding',
+};
+
+/**
+ * Mirrors Go's keys.ErrUnsupportedCurve, keys.ErrInvalidKey and keys.ErrInvalidEncoding.
+ */
+class KeysError extends Error {
+    readonly code: KeysErrorCode;
+
+    constructor(code: KeysErrorCode) {
+        super(messages[code]);
+        this.name = 'KeysError';
+        this.code = code;
+    }
+}
+
+/**
+ * Short Weierstrass curve y^2 = x^3 + ax + b over GF(p). Every supported p is 3 mod 4.
+ */
+type CurveParams = { p: bigint; a: bigint; b: bigint; size: number };
+
+const ecCurves: Record<string, CurveParams> = {
+    [Curves.P256]: {
+        p: 0xffffffff00000001000000000000000000000000ffffffffffffffffffffffffn,
+        a: -3n,
+        b: 0x5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604bn,
+        size: 32,
+    },
+    [Curves.P384]: {
+        p: 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffffn,
+        a: -3n,
+        b: 0xb3312fa7e23ee7e4988e056be3f82d19181d9c6efe8141120314088f5013875ac656398d8a2ed19d2a85c8edd3ec2aefn,
+        size: 48,
+    },
+    [Curves.P521]: {
+        p: (1n << 521n) - 1n,
+        a: -3n,
+        b: 0x51953eb9618e1c9a1f929a21a0b68540eea2da725b99b315f3b8b489918ef109e156193951ec7e937b1652c0bd3bb1bf073573df883d2c34f1ef451fd46b503f00n,
+        size: 66,
+    },
+    [Curves.Secp256k1]: {
+        p: 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2fn,
+        a: 0n,
+        b: 7n,
+        size: 32,
+    },
+};
+
+/**
+ * Reports whether curve is a short Weierstrass curve encoded with SEC1 points.
+ *
+ * @param curve - The key type.
+ *
+ * @returns boolean - True for P-256, P-384, P-521 and secp256k1.
+ */
+function isEC(curve: string): boolean {
+    return curve in ecCurves;
+}
+
+function modPow(base: bigint, exp: bigint, m: bigint): bigint {
+    let result = 1n;
+    base = bigModPos(base, m);
+    while (exp > 0n) {
+        if (exp & 1n) result = (result * base) % m;
+        base = (base * base) % m;
+        exp >>= 1n;
+    }
+    return result;
+}
+
+/**
+ * Returns x^3 + ax + b, the square of y for a point with abscissa x.
+ */
+function rhs(c: CurveParams, x: bigint): bigint {
+    return bigModPos(x * x * x + c.a * x + c.b, c.p);
+}
+
+/**
+ * Checks a raw public key and returns its canonical form: the 32 bytes for Ed25519 and X25519,
+ * the uncompressed SEC1 point for the EC curves. Compressed SEC1 points are accepted and expanded.
+ *
+ * @param curve - The key type.
+ * @param publicKey - The raw public key.
+ *
+ * @returns Uint8Array - The canonical public key.
+ *
+ * @throws {KeysError} unsupportedCurve for an unknown key type, invalidKey for a malformed key
+ * or a point not on the curve.
+ */
+function normalizePublicKey(curve: string, publicKey: Uint8Array): Uint8Array {
+    if (curve === Curves.Ed25519 || curve === Curves.X25519) {
+        if (publicKey.length !== 32) throw new KeysError('invalidKey');
+        return publicKey.slice();
+    }
+    const c = ecCurves[curve];
+    if (!c) throw new KeysError('unsupportedCurve');
+
+    const tag = publicKey[0];
+    if (tag === 0x04 && publicKey.length === 1 + 2 * c.size) {
+        const x = bytesToBigInt(publicKey.subarray(1, 1 + c.size));
+        const y = bytesToBigInt(publicKey.subarray(1 + c.size));
+        if (x >= c.p || y >= c.p || (y * y) % c.p !== rhs(c, x)) throw new KeysError('invalidKey');
+        return publicKey.slice();
+    }
+    if ((tag === 0x02 || tag === 0x03) && publicKey.length === 1 + c.size) {
+        const x = bytesToBigInt(publicKey.subarray(1));
+        if (x >= c.p) throw new KeysError('invalidKey');
+        const y2 = rhs(c, x);
+        let y = modPow(y2, (c.p + 1n) / 4n, c.p);
+        if ((y * y) % c.p !== y2) throw new KeysError('invalidKey');
+        if (Number(y & 1n) !== (tag & 1)) y = bigModPos(-y, c.p);
+        return concatBytes(new Uint8Array([0x04]), bigIntToBytes(x, c.size), bigIntToBytes(y, c.size));
+    }
+    throw new KeysError('invalidKey');
+}
+
+/**
+ * Returns the compressed SEC1 form of an EC public key.
+ *
+ * @param curve - An EC key type.
+ * @param publicKey - The raw public key, compressed or uncompressed.
+ *
+ * @returns Uint8Array - The 0x02/0x03-prefixed x coordinate.
+ *
+ * @throws {KeysError} as normalizePublicKey.
+ */
+function compressPublicKey(curve: string, publicKey: Uint8Array): Uint8Array {
+    const pub = normalizePublicKey(curve, publicKey);
+    const size = ecCurves[curve].size;
+    const out = pub.slice(0, 1 + size);
+    out[0] = 0x02 | (pub[pub.length - 1] & 1);
+    return out;
+}
+
+/**
+ * Returns the fixed-width x and y coordinates of an EC public key.
+ *
+ * @param curve - An EC key type.
+ * @param publicKey - The raw public key, compressed or uncompressed.
+ *
+ * @returns [Uint8Array, Uint8Array] - The x and y coordinates.
+ *
+ * @throws {KeysError} as normalizePublicKey.
+ */
+function splitPoint(curve: string, publicKey: Uint8Array): [Uint8Array, Uint8Array] {
+    const pub = normalizePublicKey(curve, publicKey);
+    const size = ecCurves[curve].size;
+    return [pub.slice(1, 1 + size), pub.slice(1 + size)];
+}
+
+/**
+ * Builds an uncompressed SEC1 point from fixed-width coordinates and validates it.
+ *
+ * @param curve - An EC key type.
+ * @param x - The x coordinate.
+ * @param y - The y coordinate.
+ *
+ * @returns Uint8Array - The uncompressed SEC1 point.
+ *
+ * @throws {KeysError} as normalizePublicKey, or invalidKey if a coordinate has the wrong width.
+ */
+function joinPoint(curve: string, x: Uint8Array, y: Uint8Array): Uint8Array {
+    const c = ecCurves[curve];
+    if (!c) throw new KeysError('unsupportedCurve');
+    if (x.length !== c.size || y.length !== c.size) throw new KeysError('invalidKey');
+    return normalizePublicKey(curve, concatBytes(new Uint8Array([0x04]), x, y));
+}
+
+export {
+    type Curve,
+    type KeysErrorCode,
+    Curves,
+    KeysErrorCodes,
+    KeysError,
+    isEC,
+    normalizePublicKey,
+    compressPublicKey,
+    splitPoint,
+    joinPoint,
+};
\ No newline at end of file
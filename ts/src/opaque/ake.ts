@@ -0,0 +1,44 @@
+import { concatBytes } from '../util/bytes';
+import type { Params } from './opaque';
+import { frame, type KE1, type KE2 } from './messages';
+import type { Credentials } from './envelope';
+
+// The 3DH transcript: "OPAQUEv1-" || framed(context) || framed(client_identity) || ke1 ||
+// framed(server_identity) || credential_response || server_nonce || server_public_keyshare,
+// with the identities taken from the cleartext credentials.
+function preamble(p: Params, creds: Credentials, ke1: KE1, ke2: KE2): Uint8Array {
+    return concatBytes(new TextEncoder().encode('OPAQUEv1-'), frame(p.context), frame(creds.clientIdentity),
+        ke1.serialize(), frame(creds.serverIdentity), ke2.credentialResponse(), ke2.serverNonce, ke2.serverPublicKeyshare);
+}
+
+// The outputs of DeriveKeys: the two MAC keys and the session key.
+type SessionKeys = {
+    km2: Uint8Array;
+    km3: Uint8Array;
+    sessionKey: Uint8Array;
+};
+
+async function deriveKeys(p: Params, ikm: Uint8Array, pre: Uint8Array): Promise<SessionKeys> {
+    const prk = await p.extract(ikm);
+    const transcript = await p.hash(pre);
+    const handshakeSecret = await p.deriveSecret(prk, 'HandshakeSecret', transcript);
+    return {
+        km2: await p.deriveSecret(handshakeSecret, 'ServerMAC', new Uint8Array()),
+        km3: await p.deriveSecret(handshakeSecret, 'ClientMAC', new Uint8Array()),
+        sessionKey: await p.deriveSecret(prk, 'SessionKey', transcript),
+    };
+}
+
+// Returns the server MAC over Hash(preamble) and the client MAC over
+// Hash(preamble || server_mac).
+async function macs(p: Params, keys: SessionKeys, pre: Uint8Array): Promise<[Uint8Array, Uint8Array]> {
+    const serverMAC = await p.mac(keys.km2, await p.hash(pre));
+    const clientMAC = await p.mac(keys.km3, await p.hash(concatBytes(pre, serverMAC)));
+    return [serverMAC, clientMAC];
+}
+
+export {
+    preamble,
+    deriveKeys,
+    macs,
+};
@@ -0,0 +1,210 @@
+import { concatBytes } from '../util/bytes';
+import type { Scalar } from '../group';
+import { Client, Modes, type BlindState } from '../oprf';
+import { NonceSize, SeedSize, OpaqueError, Params, diffieHellman, equalBytes, systemEntropy, xor, type Config,
+    type Entropy } from './opaque';
+import { KE1, KE2, KE3, RegistrationRequest, type RegistrationRecord, type RegistrationResponse } from './messages';
+import { credentialResponsePad, maskingKey, recover, store } from './envelope';
+import { deriveKeys, macs, preamble } from './ake';
+
+// Tracks a state machine: every start and finish runs once, in that order.
+const Stages = {
+    new: 0,
+    started: 1,
+    done: 2,
+} as const;
+
+type Stage = (typeof Stages)[keyof typeof Stages];
+
+// Blinds the password and later unblinds the evaluation into the randomized password,
+// which both client flows share.
+class ClientOPRF {
+    private client: Client | undefined;
+    private state: BlindState | undefined;
+
+    async blind(p: Params, ent: Entropy, password: Uint8Array): Promise<Uint8Array> {
+        const client = new Client(p.suite, Modes.OPRF);
+        const st = await client.blindWithScalars([password], new Uint8Array(), [ent.blind(p.suite)]);
+        this.client = client;
+        this.state = st;
+        return st.blindedElements[0].toBytes();
+    }
+
+    async finalize(p: Params, evaluatedMessage: Uint8Array): Promise<Uint8Array> {
+        const evaluated = p.decodeElement(evaluatedMessage);
+        const [output] = await (this.client as Client).finalize(this.state as BlindState, [evaluated]);
+        return p.randomizedPassword(output);
+    }
+}
+
+/**
+ * The client side of registration: start sends a RegistrationRequest, and finish turns the
+ * server's response into the record to upload.
+ */
+class ClientRegistration {
+    private readonly p: Params;
+    private readonly ent: Entropy;
+    private stage: Stage = Stages.new;
+    private readonly oprf = new ClientOPRF();
+
+    /**
+     * @throws {OpaqueError} - unsupportedSuite, or invalidMessage for a context over 65535 bytes.
+     *
+     * @param cfg - The configuration, which must match the server's.
+     * @param ent - Replaces the random source; it exists for test vectors with fixed
+     * randomness, so leave it out.
+     */
+    constructor(cfg: Config, ent: Entropy = systemEntropy) {
+        this.p = new Params(cfg);
+        this.ent = ent;
+    }
+
+    /**
+     * Blinds the password into a RegistrationRequest.
+     *
+     * @throws {OpaqueError} - state if called twice.
+     *
+     * @param password - The password.
+     *
+     * @returns A promise that resolves to the request.
+     */
+    async start(password: Uint8Array): Promise<RegistrationRequest> {
+        if (this.stage !== Stages.new) {
+            throw new OpaqueError('state');
+        }
+        this.stage = Stages.done;
+        const blinded = await this.oprf.blind(this.p, this.ent, password);
+        this.stage = Stages.started;
+        return new RegistrationRequest(blinded);
+    }
+
+    /**
+     * Seals the envelope and returns the RegistrationRecord to upload and the export key,
+     * an application secret the server never learns. Empty identities default to the public
+     * keys; the same identities must be passed at login.
+     *
+     * @throws {OpaqueError} - state out of order, or invalidMessage for a malformed response.
+     *
+     * @param resp - The server's response.
+     * @param serverIdentity - The server identity, or empty for its public key.
+     * @param clientIdentity - The client identity, or empty for its public key.
+     *
+     * @returns A promise that resolves to [record, exportKey].
+     */
+    async finish(resp: RegistrationResponse, serverIdentity: Uint8Array = new Uint8Array(),
+        clientIdentity: Uint8Array = new Uint8Array()): Promise<[RegistrationRecord, Uint8Array]> {
+        if (this.stage !== Stages.started) {
+            throw new OpaqueError('state');
+        }
+        this.stage = Stages.done;
+        this.p.decodeElement(resp.serverPublicKey);
+        const randomizedPassword = await this.oprf.finalize(this.p, resp.evaluatedMessage);
+        const nonce = this.ent.bytes(NonceSize);
+        return store(this.p, randomizedPassword, nonce, resp.serverPublicKey, serverIdentity, clientIdentity);
+    }
+}
+
+/**
+ * The client side of login: start sends KE1, and finish checks KE2, returning KE3 and the
+ * session key.
+ */
+class ClientLogin {
+    private readonly p: Params;
+    private readonly ent: Entropy;
+    private stage: Stage = Stages.new;
+    private readonly oprf = new ClientOPRF();
+    private ke1: KE1 | undefined;
+    private keyshare: Scalar | undefined;
+
+    /**
+     * @throws {OpaqueError} - unsupportedSuite, or invalidMessage for a context over 65535 bytes.
+     *
+     * @param cfg - The configuration, which must match the server's.
+     * @param ent - Replaces the random source; it exists for test vectors with fixed
+     * randomness, so leave it out.
+     */
+    constructor(cfg: Config, ent: Entropy = systemEntropy) {
+        this.p = new Params(cfg);
+        this.ent = ent;
+    }
+
+    /**
+     * Blinds the password and generates the client's ephemeral key share.
+     *
+     * @throws {OpaqueError} - state if called twice.
+     *
+     * @param password - The password.
+     *
+     * @returns A promise that resolves to KE1.
+     */
+    async start(password: Uint8Array): Promise<KE1> {
+        if (this.stage !== Stages.new) {
+            throw new OpaqueError('state');
+        }
+        this.stage = Stages.done;
+        const blinded = await this.oprf.blind(this.p, this.ent, password);
+        const nonce = this.ent.bytes(NonceSize);
+        const [sk, pk] = await this.p.deriveDHKeyPair(this.ent.bytes(SeedSize));
+        this.keyshare = sk;
+        this.ke1 = new KE1(blinded, nonce, pk);
+        this.stage = Stages.started;
+        return this.ke1;
+    }
+
+    /**
+     * Recovers the client's credentials from KE2 and authenticates the server. A wrong
+     * password or an unregistered user fails with envelopeRecovery, and a server that does
+     * not hold the expected private key with serverAuthentication.
+     *
+     * @throws {OpaqueError} - state, invalidMessage, envelopeRecovery or serverAuthentication.
+     *
+     * @param ke2 - The server's response.
+     * @param serverIdentity - The server identity given at registration, or empty.
+     * @param clientIdentity - The client identity given at registration, or empty.
+     *
+     * @returns A promise that resolves to [ke3, sessionKey, exportKey].
+     */
+    async finish(ke2: KE2, serverIdentity: Uint8Array = new Uint8Array(),
+        clientIdentity: Uint8Array = new Uint8Array()): Promise<[KE3, Uint8Array, Uint8Array]> {
+        if (this.stage !== Stages.started) {
+            throw new OpaqueError('state');
+        }
+        this.stage = Stages.done;
+        const p = this.p;
+        ke2.check(p);
+        const randomizedPassword = await this.oprf.finalize(p, ke2.evaluatedMessage);
+        const pad = await credentialResponsePad(p, await maskingKey(p, randomizedPassword), ke2.maskingNonce);
+        const unmasked = xor(pad, ke2.maskedResponse);
+        const serverPublicKey = unmasked.subarray(0, p.npk);
+        const env = { nonce: unmasked.subarray(p.npk, p.npk + NonceSize), authTag: unmasked.subarray(p.npk + NonceSize) };
+        const [clientPrivateKey, creds, exportKey] = await recover(p, randomizedPassword, serverPublicKey, env,
+            serverIdentity, clientIdentity);
+
+        let serverPK;
+        try {
+            serverPK = p.decodeElement(serverPublicKey);
+        } catch {
+            throw new OpaqueError('serverAuthentication');
+        }
+        const serverKeyshare = p.decodeElement(ke2.serverPublicKeyshare);
+        const keyshare = this.keyshare as Scalar;
+        const ikm = concatBytes(
+            diffieHellman(keyshare, serverKeyshare),
+            diffieHellman(keyshare, serverPK),
+            diffieHellman(clientPrivateKey, serverKeyshare),
+        );
+        const pre = preamble(p, creds, this.ke1 as KE1, ke2);
+        const keys = await deriveKeys(p, ikm, pre);
+        const [serverMAC, clientMAC] = await macs(p, keys, pre);
+        if (!equalBytes(serverMAC, ke2.serverMAC)) {
+            throw new OpaqueError('serverAuthentication');
+        }
+        this.keyshare = undefined;
+        return [new KE3(clientMAC), keys.sessionKey, exportKey];
+    }
+}
+
+export {
+    ClientRegistration,
+    ClientLogin,
+};
@@ -0,0 +1,82 @@
+import { concatBytes } from '../util/bytes';
+import type { Scalar } from '../group';
+import { NonceSize, SeedSize, OpaqueError, equalBytes, type Params } from './opaque';
+import { frame, RegistrationRecord, type Envelope } from './messages';
+
+const enc = new TextEncoder();
+
+/**
+ * The CleartextCredentials of RFC 9807: the server public key and both identities, each
+ * identity defaulting to the corresponding public key.
+ */
+type Credentials = {
+    readonly serverPublicKey: Uint8Array;
+    readonly serverIdentity: Uint8Array;
+    readonly clientIdentity: Uint8Array;
+};
+
+function newCredentials(serverPublicKey: Uint8Array, clientPublicKey: Uint8Array, serverIdentity: Uint8Array,
+    clientIdentity: Uint8Array): Credentials {
+    if (serverIdentity.length === 0) serverIdentity = serverPublicKey;
+    if (clientIdentity.length === 0) clientIdentity = clientPublicKey;
+    if (serverIdentity.length > 0xffff || clientIdentity.length > 0xffff) {
+        throw new OpaqueError('invalidMessage');
+    }
+    return { serverPublicKey, serverIdentity, clientIdentity };
+}
+
+function serializeCredentials(c: Credentials): Uint8Array {
+    return concatBytes(c.serverPublicKey, frame(c.serverIdentity, c.clientIdentity));
+}
+
+// The keys Expand derives from the randomized password and envelope nonce.
+async function envelopeKeys(p: Params, randomizedPassword: Uint8Array, nonce: Uint8Array) {
+    return {
+        authKey: await p.expand(randomizedPassword, concatBytes(nonce, enc.encode('AuthKey')), p.nh),
+        exportKey: await p.expand(randomizedPassword, concatBytes(nonce, enc.encode('ExportKey')), p.nh),
+        seed: await p.expand(randomizedPassword, concatBytes(nonce, enc.encode('PrivateKey')), SeedSize),
+    };
+}
+
+function maskingKey(p: Params, randomizedPassword: Uint8Array): Promise<Uint8Array> {
+    return p.expand(randomizedPassword, enc.encode('MaskingKey'), p.nh);
+}
+
+// Seals a new envelope under nonce and returns the record with the export key.
+async function store(p: Params, randomizedPassword: Uint8Array, nonce: Uint8Array, serverPublicKey: Uint8Array,
+    serverIdentity: Uint8Array, clientIdentity: Uint8Array): Promise<[RegistrationRecord, Uint8Array]> {
+    const keys = await envelopeKeys(p, randomizedPassword, nonce);
+    const [, clientPublicKey] = await p.deriveDHKeyPair(keys.seed);
+    const creds = newCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity);
+    const authTag = await p.mac(keys.authKey, concatBytes(nonce, serializeCredentials(creds)));
+    const record = new RegistrationRecord(clientPublicKey, await maskingKey(p, randomizedPassword), { nonce, authTag });
+    return [record, keys.exportKey];
+}
+
+// Opens an envelope, returning the client private key, the credentials it was bound to and
+// the export key, or throwing envelopeRecovery if the tag does not match.
+async function recover(p: Params, randomizedPassword: Uint8Array, serverPublicKey: Uint8Array, env: Envelope,
+    serverIdentity: Uint8Array, clientIdentity: Uint8Array): Promise<[Scalar, Credentials, Uint8Array]> {
+    const keys = await envelopeKeys(p, randomizedPassword, env.nonce);
+    const [clientPrivateKey, clientPublicKey] = await p.deriveDHKeyPair(keys.seed);
+    const creds = newCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity);
+    const expected = await p.mac(keys.authKey, concatBytes(env.nonce, serializeCredentials(creds)));
+    if (!equalBytes(expected, env.authTag)) {
+        throw new OpaqueError('envelopeRecovery');
+    }
+    return [clientPrivateKey, creds, keys.exportKey];
+}
+
+// Expand(masking_key, masking_nonce || "CredentialResponsePad", Npk + Nn + Nm), the pad
+// that hides the server public key and envelope in KE2.
+function credentialResponsePad(p: Params, key: Uint8Array, maskingNonce: Uint8Array): Promise<Uint8Array> {
+    return p.expand(key, concatBytes(maskingNonce, enc.encode('CredentialResponsePad')), p.npk + NonceSize + p.nh);
+}
+
+export {
+    type Credentials,
+    maskingKey,
+    store,
+    recover,
+    credentialResponsePad,
+};
@@ -0,0 +1,27 @@
+export {
+    NonceSize,
+    SeedSize,
+    OpaqueErrorCodes,
+    type OpaqueErrorCode,
+    OpaqueError,
+    type KSF,
+    identityKSF,
+    type Config,
+    type Entropy,
+} from './opaque';
+export {
+    RegistrationRequest,
+    RegistrationResponse,
+    type Envelope,
+    RegistrationRecord,
+    KE1,
+    KE2,
+    KE3,
+    parseRegistrationRequest,
+    parseRegistrationResponse,
+    parseRegistrationRecord,
+    parseKE1,
+    parseKE2,
+    parseKE3,
+} from './messages';
+export * from './client';
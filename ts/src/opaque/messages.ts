@@ -0,0 +1,274 @@
+import { concatBytes, framedBytesFromUint8Array, parseFramed } from '../util/bytes';
+import { NonceSize, OpaqueError, lengthPrefix, type Params } from './opaque';
+
+// Frames fields with 2-byte length prefixes, as Go's opaque frame.
+function frame(...fields: Uint8Array[]): Uint8Array {
+    try {
+        return concatBytes(...fields.map(f => framedBytesFromUint8Array(f, lengthPrefix)));
+    } catch {
+        throw new OpaqueError('invalidMessage');
+    }
+}
+
+// Splits a message written by frame into exactly n fields.
+function unframe(b: Uint8Array, n: number): Uint8Array[] {
+    let fields: Uint8Array[];
+    try {
+        fields = parseFramed(b, lengthPrefix);
+    } catch {
+        throw new OpaqueError('invalidMessage');
+    }
+    if (fields.length !== n) {
+        throw new OpaqueError('invalidMessage');
+    }
+    return fields;
+}
+
+/**
+ * The client's first registration message.
+ */
+class RegistrationRequest {
+    readonly blindedMessage: Uint8Array;
+
+    constructor(blindedMessage: Uint8Array) {
+        this.blindedMessage = blindedMessage;
+    }
+
+    /** Frames the blinded message. */
+    toBytes(): Uint8Array {
+        return frame(this.blindedMessage);
+    }
+}
+
+/**
+ * The server's registration message.
+ */
+class RegistrationResponse {
+    readonly evaluatedMessage: Uint8Array;
+    readonly serverPublicKey: Uint8Array;
+
+    constructor(evaluatedMessage: Uint8Array, serverPublicKey: Uint8Array) {
+        this.evaluatedMessage = evaluatedMessage;
+        this.serverPublicKey = serverPublicKey;
+    }
+
+    /** Frames the evaluated message and the server public key. */
+    toBytes(): Uint8Array {
+        return frame(this.evaluatedMessage, this.serverPublicKey);
+    }
+
+    /** The fixed-width RFC 9807 encoding, as recorded by the parity vectors. */
+    serialize(): Uint8Array {
+        return concatBytes(this.evaluatedMessage, this.serverPublicKey);
+    }
+}
+
+/**
+ * Lets the client recover its private key: the nonce it was derived under and a MAC over
+ * the nonce and the cleartext credentials.
+ */
+type Envelope = {
+    readonly nonce: Uint8Array;
+    readonly authTag: Uint8Array;
+};
+
+/**
+ * What the server stores per client in place of a password hash.
+ */
+class RegistrationRecord {
+    readonly clientPublicKey: Uint8Array;
+    readonly maskingKey: Uint8Array;
+    readonly envelope: Envelope;
+
+    constructor(clientPublicKey: Uint8Array, maskingKey: Uint8Array, envelope: Envelope) {
+        this.clientPublicKey = clientPublicKey;
+        this.maskingKey = maskingKey;
+        this.envelope = envelope;
+    }
+
+    /** Frames the client public key, masking key, envelope nonce and auth tag. */
+    toBytes(): Uint8Array {
+        return frame(this.clientPublicKey, this.maskingKey, this.envelope.nonce, this.envelope.authTag);
+    }
+
+    /** The fixed-width RFC 9807 encoding, as recorded by the parity vectors. */
+    serialize(): Uint8Array {
+        return concatBytes(this.clientPublicKey, this.maskingKey, this.envelope.nonce, this.envelope.authTag);
+    }
+}
+
+/**
+ * The client's first login message: a credential request and its key share.
+ */
+class KE1 {
+    readonly blindedMessage: Uint8Array;
+    readonly clientNonce: Uint8Array;
+    readonly clientPublicKeyshare: Uint8Array;
+
+    constructor(
+        blindedMessage: Uint8Array,
+        clientNonce: Uint8Array,
+        clientPublicKeyshare: Uint8Array,
+    ) {
+        this.blindedMessage = blindedMessage;
+        this.clientNonce = clientNonce;
+        this.clientPublicKeyshare = clientPublicKeyshare;
+    }
+
+    /** Frames the blinded message, client nonce and client key share. */
+    toBytes(): Uint8Array {
+        return frame(this.blindedMessage, this.clientNonce, this.clientPublicKeyshare);
+    }
+
+    /** The fixed-width RFC 9807 encoding, which the key schedule hashes. */
+    serialize(): Uint8Array {
+        return concatBytes(this.blindedMessage, this.clientNonce, this.clientPublicKeyshare);
+    }
+}
+
+/**
+ * The server's login response: the masked credentials, its key share and its MAC.
+ */
+class KE2 {
+    readonly evaluatedMessage: Uint8Array;
+    readonly maskingNonce: Uint8Array;
+    readonly maskedResponse: Uint8Array;
+    readonly serverNonce: Uint8Array;
+    readonly serverPublicKeyshare: Uint8Array;
+    readonly serverMAC: Uint8Array;
+
+    constructor(
+        evaluatedMessage: Uint8Array,
+        maskingNonce: Uint8Array,
+        maskedResponse: Uint8Array,
+        serverNonce: Uint8Array,
+        serverPublicKeyshare: Uint8Array,
+        serverMAC: Uint8Array,
+    ) {
+        this.evaluatedMessage = evaluatedMessage;
+        this.maskingNonce = maskingNonce;
+        this.maskedResponse = maskedResponse;
+        this.serverNonce = serverNonce;
+        this.serverPublicKeyshare = serverPublicKeyshare;
+        this.serverMAC = serverMAC;
+    }
+
+    /** Frames the six KE2 fields in RFC 9807 order. */
+    toBytes(): Uint8Array {
+        return frame(this.evaluatedMessage, this.maskingNonce, this.maskedResponse, this.serverNonce,
+            this.serverPublicKeyshare, this.serverMAC);
+    }
+
+    /** The CredentialResponse prefix of KE2. */
+    credentialResponse(): Uint8Array {
+        return concatBytes(this.evaluatedMessage, this.maskingNonce, this.maskedResponse);
+    }
+
+    /** The fixed-width RFC 9807 encoding, as recorded by the parity vectors. */
+    serialize(): Uint8Array {
+        return concatBytes(this.credentialResponse(), this.serverNonce, this.serverPublicKeyshare, this.serverMAC);
+    }
+
+    // Validates the fixed field sizes for the suite.
+    check(p: Params): void {
+        if (this.evaluatedMessage.length !== p.npk || this.maskingNonce.length !== NonceSize ||
+            this.maskedResponse.length !== p.npk + NonceSize + p.nh || this.serverNonce.length !== NonceSize ||
+            this.serverPublicKeyshare.length !== p.npk || this.serverMAC.length !== p.nh) {
+            throw new OpaqueError('invalidMessage');
+        }
+    }
+}
+
+/**
+ * The client's final login message.
+ */
+class KE3 {
+    readonly clientMAC: Uint8Array;
+
+    constructor(clientMAC: Uint8Array) {
+        this.clientMAC = clientMAC;
+    }
+
+    /** Frames the client MAC. */
+    toBytes(): Uint8Array {
+        return frame(this.clientMAC);
+    }
+}
+
+/**
+ * Decodes a message written by RegistrationRequest.toBytes. Field sizes are checked by the
+ * state machines, which know the suite.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseRegistrationRequest(b: Uint8Array): RegistrationRequest {
+    const [blinded] = unframe(b, 1);
+    return new RegistrationRequest(blinded);
+}
+
+/**
+ * Decodes a message written by RegistrationResponse.toBytes.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseRegistrationResponse(b: Uint8Array): RegistrationResponse {
+    const f = unframe(b, 2);
+    return new RegistrationResponse(f[0], f[1]);
+}
+
+/**
+ * Decodes a record written by RegistrationRecord.toBytes.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseRegistrationRecord(b: Uint8Array): RegistrationRecord {
+    const f = unframe(b, 4);
+    return new RegistrationRecord(f[0], f[1], { nonce: f[2], authTag: f[3] });
+}
+
+/**
+ * Decodes a message written by KE1.toBytes.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseKE1(b: Uint8Array): KE1 {
+    const f = unframe(b, 3);
+    return new KE1(f[0], f[1], f[2]);
+}
+
+/**
+ * Decodes a message written by KE2.toBytes.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseKE2(b: Uint8Array): KE2 {
+    const f = unframe(b, 6);
+    return new KE2(f[0], f[1], f[2], f[3], f[4], f[5]);
+}
+
+/**
+ * Decodes a message written by KE3.toBytes.
+ *
+ * @throws {OpaqueError} - invalidMessage.
+ */
+function parseKE3(b: Uint8Array): KE3 {
+    const [mac] = unframe(b, 1);
+    return new KE3(mac);
+}
+
+export {
+    frame,
+    RegistrationRequest,
+    RegistrationResponse,
+    type Envelope,
+    RegistrationRecord,
+    KE1,
+    KE2,
+    KE3,
+    parseRegistrationRequest,
+    parseRegistrationResponse,
+    parseRegistrationRecord,
+    parseKE1,
+    parseKE2,
+    parseKE3,
+};
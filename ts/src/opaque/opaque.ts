@@ -0,0 +1,235 @@
+import { argon2idAsync } from '@noble/hashes/argon2.js';
+import { concatBytes } from '../util/bytes';
+import { sha2Hash, hmacSha2, hkdfExtract, hkdfExpand, type Sha2 } from '../util/hash';
+import { P256, Ristretto255, type Group, type Element, type Scalar } from '../group';
+import { Modes, Suites, decodeElement as oprfDecodeElement, deriveKeyPair } from '../oprf';
+
+/**
+ * Nn, the length of the envelope, masking and key exchange nonces.
+ */
+const NonceSize = 32;
+
+/**
+ * Nseed, the length of the seeds behind derived key pairs.
+ */
+const SeedSize = 32;
+
+// The length prefix of the framed wire encodings and the preamble.
+const lengthPrefix = 2;
+
+/**
+ * Error codes of the opaque package, matching the Go sentinels.
+ */
+const OpaqueErrorCodes = {
+    unsupportedSuite: 'unsupportedSuite',
+    invalidMessage: 'invalidMessage',
+    envelopeRecovery: 'envelopeRecovery',
+    serverAuthentication: 'serverAuthentication',
+    clientAuthentication: 'clientAuthentication',
+    state: 'state',
+} as const;
+
+type OpaqueErrorCode = (typeof OpaqueErrorCodes)[keyof typeof OpaqueErrorCodes];
+
+const messages: Record<OpaqueErrorCode, string> = {
+    unsupportedSuite: 'opaque: unsupported suite',
+    invalidMessage: 'opaque: malformed message',
+    envelopeRecovery: 'opaque: envelope recovery failed',
+    serverAuthentication: 'opaque: server authentication failed',
+    clientAuthentication: 'opaque: client authentication failed',
+    state: 'opaque: state machine used out of order or reused',
+};
+
+/**
+ * Mirrors the Go opaque sentinels, from ErrUnsupportedSuite to ErrState.
+ */
+class OpaqueError extends Error {
+    readonly code: OpaqueErrorCode;
+
+    constructor(code: OpaqueErrorCode) {
+        super(messages[code]);
+        this.name = 'OpaqueError';
+        this.code = code;
+    }
+}
+
+/**
+ * A key stretching function applied to the OPRF output, such as a memory-hard hash with
+ * fixed parameters. Both sides of a deployment must use the same one.
+ */
+type KSF = (oprfOutput: Uint8Array) => Promise<Uint8Array>;
+
+/**
+ * Returns the OPRF output unchanged. RFC 9807 allows it only for testing: a stolen record
+ * can then be attacked offline at the cost of one hash per guess.
+ */
+const identityKSF: KSF = async oprfOutput => oprfOutput;
+
+// The default KSF, as Go's: Argon2id with m=19456, t=2, p=1 (util.Argon2idParams), the
+// all-zero 16-byte salt of RFC 9807 section 4.3.1, and an Nh-byte output.
+function argon2idKSF(nh: number): KSF {
+    return oprfOutput => argon2idAsync(oprfOutput, new Uint8Array(16), { m: 19456, t: 2, p: 1, dkLen: nh });
+}
+
+/**
+ * An OPAQUE-3DH configuration. suite selects the OPRF and the group, with HKDF, HMAC and
+ * SHA-2 at the suite's hash size (SHA-512 for ristretto255, SHA-256 for P-256). context is
+ * bound into every login transcript. A missing ksf is Argon2id with the Go default cost;
+ * identityKSF must be chosen explicitly.
+ */
+type Config = {
+    readonly suite: string;
+    readonly context?: Uint8Array;
+    readonly ksf?: KSF;
+};
+
+/**
+ * Supplies the fresh values of a protocol run. The default draws from
+ * crypto.getRandomValues; tests replace it with the fixed inputs of the parity vectors.
+ */
+type Entropy = {
+    bytes(n: number): Uint8Array;
+    blind(suite: string): Scalar;
+};
+
+const systemEntropy: Entropy = {
+    bytes(n: number): Uint8Array {
+        const b = new Uint8Array(n);
+        crypto.getRandomValues(b);
+        return b;
+    },
+    blind(suite: string): Scalar {
+        const g = new Params({ suite, ksf: identityKSF }).g;
+        for (;;) {
+            const s = g.randomScalar();
+            if (!s.isZero()) return s;
+        }
+    },
+};
+
+const enc = new TextEncoder();
+
+/**
+ * A validated Config with the RFC 9807 sizes of its suite.
+ */
+class Params {
+    readonly suite: string;
+    readonly g: Group;
+    readonly context: Uint8Array;
+    readonly ksf: KSF;
+    readonly hashBits: Sha2;
+    /** Nh = Nm = Nx, the hash, MAC and KDF output size. */
+    readonly nh: number;
+    /** Npk = Noe, the encoded element size. */
+    readonly npk: number;
+    /** Nok, the OPRF private key size. */
+    readonly nok = 32;
+
+    constructor(cfg: Config) {
+        switch (cfg.suite) {
+            case Suites.Ristretto255Sha512:
+                [this.g, this.hashBits, this.nh] = [Ristretto255, 512, 64];
+                break;
+            case Suites.P256Sha256:
+                [this.g, this.hashBits, this.nh] = [P256, 256, 32];
+                break;
+            default:
+                throw new OpaqueError('unsupportedSuite');
+        }
+        this.suite = cfg.suite;
+        this.npk = this.g.elementSize();
+        this.context = Uint8Array.from(cfg.context ?? []);
+        if (this.context.length > 0xffff) {
+            throw new OpaqueError('invalidMessage');
+        }
+        this.ksf = cfg.ksf ?? argon2idKSF(this.nh);
+    }
+
+    hash(msg: Uint8Array): Promise<Uint8Array> {
+        return sha2Hash(msg, this.hashBits);
+    }
+
+    mac(key: Uint8Array, msg: Uint8Array): Promise<Uint8Array> {
+        return hmacSha2(key, msg, this.hashBits);
+    }
+
+    extract(ikm: Uint8Array): Promise<Uint8Array> {
+        return hkdfExtract(new Uint8Array(), ikm, this.hashBits);
+    }
+
+    expand(prk: Uint8Array, info: Uint8Array, length: number): Promise<Uint8Array> {
+        return hkdfExpand(prk, info, length, this.hashBits);
+    }
+
+    // Expand(secret, I2OSP(length, 2) || I2OSP(len(label), 1) || label ||
+    // I2OSP(len(context), 1) || context, length), with label prefixed by "OPAQUE-".
+    expandLabel(secret: Uint8Array, label: string, context: Uint8Array, length: number): Promise<Uint8Array> {
+        const full = enc.encode('OPAQUE-' + label);
+        const info = concatBytes(new Uint8Array([length >> 8, length & 0xff, full.length]), full,
+            new Uint8Array([context.length]), context);
+        return this.expand(secret, info, length);
+    }
+
+    deriveSecret(secret: Uint8Array, label: string, transcriptHash: Uint8Array): Promise<Uint8Array> {
+        return this.expandLabel(secret, label, transcriptHash, this.nh);
+    }
+
+    // Extract("", oprf_output || Stretch(oprf_output)).
+    async randomizedPassword(oprfOutput: Uint8Array): Promise<Uint8Array> {
+        const stretched = await this.ksf(oprfOutput);
+        return this.extract(concatBytes(oprfOutput, stretched));
+    }
+
+    // DeriveDiffieHellmanKeyPair: the OPRF DeriveKeyPair under the
+    // "OPAQUE-DeriveDiffieHellmanKeyPair" info string.
+    async deriveDHKeyPair(seed: Uint8Array): Promise<[Scalar, Uint8Array]> {
+        const [sk, pk] = await deriveKeyPair(this.suite, Modes.OPRF, seed, enc.encode('OPAQUE-DeriveDiffieHellmanKeyPair'));
+        return [sk, pk.toBytes()];
+    }
+
+    decodeElement(b: Uint8Array): Element {
+        if (b.length !== this.npk) {
+            throw new OpaqueError('invalidMessage');
+        }
+        try {
+            return oprfDecodeElement(this.suite, b);
+        } catch {
+            throw new OpaqueError('invalidMessage');
+        }
+    }
+}
+
+// SerializeElement(sk * pk).
+function diffieHellman(sk: Scalar, pk: Element): Uint8Array {
+    return pk.scalarMult(sk).toBytes();
+}
+
+function xor(a: Uint8Array, b: Uint8Array): Uint8Array {
+    return a.map((v, i) => v ^ b[i]);
+}
+
+// Compares two MACs without an early exit, as Go's hmac.Equal.
+function equalBytes(a: Uint8Array, b: Uint8Array): boolean {
+    if (a.length !== b.length) return false;
+    let diff = 0;
+    for (let i = 0; i < a.length; i++) diff |= a[i] ^ b[i];
+    return diff === 0;
+}
+
+export {
+    NonceSize,
+    SeedSize,
+    lengthPrefix,
+    OpaqueErrorCodes,
+    type OpaqueErrorCode,
+    OpaqueError,
+    type KSF,
+    identityKSF,
+    type Config,
+    type Entropy,
+    systemEntropy,
+    Params,
+    diffieHellman,
+    xor,
+    equalBytes,
+};
@@ -0,0 +1,125 @@
+import { concatBytes } from '../util/bytes';
+import type { Element, Scalar } from '../group';
+import { Modes, OprfError, Params, framed, randomNonZero } from './oprf';
+import { verifyProof, type Proof } from './proof';
+
+/**
+ * What the client keeps between blind and finalize. It holds the blinds, so it must stay
+ * secret and be used for one finalize only.
+ */
+type BlindState = {
+    readonly inputs: Uint8Array[];
+    readonly info: Uint8Array;
+    readonly blinds: Scalar[];
+    /** The elements to send to the server, one per input. */
+    readonly blindedElements: Element[];
+    readonly tweakedKey?: Element;
+};
+
+/**
+ * Blinds inputs and finalizes the server's evaluations in one mode.
+ */
+class Client {
+    private readonly p: Params;
+    private readonly pk: Element | undefined;
+
+    /**
+     * @throws {OprfError} - unsupportedSuite, unsupportedMode, or invalidElement for a
+     * missing or invalid pk in VOPRF and POPRF modes.
+     *
+     * @param suite - The ciphersuite.
+     * @param mode - The protocol mode.
+     * @param pk - The server's public key, used to verify proofs; required in VOPRF and
+     * POPRF modes and ignored in OPRF mode.
+     */
+    constructor(suite: string, mode: number, pk?: Element) {
+        this.p = new Params(suite, mode);
+        if (mode !== Modes.OPRF) {
+            this.p.checkElement(pk);
+        }
+        this.pk = pk;
+    }
+
+    /**
+     * Hashes each input to the group and multiplies it by a fresh random blind.
+     *
+     * @throws {OprfError} - unsupportedMode for info outside POPRF mode, or invalidInput.
+     *
+     * @param inputs - The PRF inputs.
+     * @param info - The POPRF public input; must be empty in the other modes.
+     *
+     * @returns A promise that resolves to the blind state.
+     */
+    blind(inputs: Uint8Array[], info: Uint8Array = new Uint8Array()): Promise<BlindState> {
+        return this.blindWithScalars(inputs, info, inputs.map(() => randomNonZero(this.p.g)));
+    }
+
+    /**
+     * blind with the blinds given, one per input. It exists for test vectors with fixed
+     * blinds, so prefer blind.
+     */
+    async blindWithScalars(inputs: Uint8Array[], info: Uint8Array, blinds: Scalar[]): Promise<BlindState> {
+        const p = this.p;
+        if (p.mode !== Modes.POPRF && info.length > 0) {
+            throw new OprfError('unsupportedMode');
+        }
+        if (inputs.length === 0 || blinds.length !== inputs.length) {
+            throw new OprfError('invalidInput');
+        }
+        const blindedElements: Element[] = [];
+        for (let i = 0; i < inputs.length; i++) {
+            if (blinds[i].group() !== p.g || blinds[i].isZero()) {
+                throw new OprfError('invalidInput');
+            }
+            const e = await p.hashToGroup(inputs[i]);
+            if (e.isIdentity()) {
+                throw new OprfError('invalidInput');
+            }
+            blindedElements.push(e.scalarMult(blinds[i]));
+        }
+        let tweakedKey: Element | undefined;
+        if (p.mode === Modes.POPRF) {
+            const m = await p.hashToScalar(concatBytes(new TextEncoder().encode('Info'), framed(info)));
+            tweakedKey = p.g.generator().scalarMult(m).add(this.pk as Element);
+            if (tweakedKey.isIdentity()) {
+                throw new OprfError('invalidInput');
+            }
+        }
+        return { inputs: inputs.map(i => Uint8Array.from(i)), info, blinds, blindedElements, tweakedKey };
+    }
+
+    /**
+     * Verifies the server's proof (VOPRF and POPRF modes) and unblinds each evaluated
+     * element.
+     *
+     * @throws {OprfError} - invalidInput, invalidElement, or verify for a bad proof.
+     *
+     * @param st - The state returned by blind.
+     * @param evaluated - The server's evaluated elements, one per input.
+     * @param proof - The server's proof; ignored in OPRF mode.
+     *
+     * @returns A promise that resolves to one PRF output per input.
+     */
+    async finalize(st: BlindState, evaluated: Element[], proof?: Proof): Promise<Uint8Array[]> {
+        const p = this.p;
+        if (evaluated.length !== st.blindedElements.length) {
+            throw new OprfError('invalidInput');
+        }
+        evaluated.forEach(e => p.checkElement(e));
+        if (p.mode === Modes.VOPRF) {
+            await verifyProof(p, p.g.generator(), this.pk as Element, st.blindedElements, evaluated, proof);
+        } else if (p.mode === Modes.POPRF) {
+            await verifyProof(p, p.g.generator(), st.tweakedKey as Element, evaluated, st.blindedElements, proof);
+        }
+        const outputs: Uint8Array[] = [];
+        for (let i = 0; i < evaluated.length; i++) {
+            outputs.push(await p.finalizeHash(st.inputs[i], st.info, evaluated[i].scalarMult(st.blinds[i].invert())));
+        }
+        return outputs;
+    }
+}
+
+export {
+    type BlindState,
+    Client,
+};
@@ -0,0 +1,14 @@
+export {
+    Modes,
+    type Mode,
+    Suites,
+    type Suite,
+    OprfErrorCodes,
+    type OprfErrorCode,
+    OprfError,
+    decodeElement,
+    decodeScalar,
+    deriveKeyPair,
+} from './oprf';
+export { Proof, parseProof } from './proof';
+export * from './client';
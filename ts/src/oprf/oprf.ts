@@ -0,0 +1,232 @@
+import { concatBytes, framedBytesFromUint8Array } from '../util/bytes';
+import { sha2Hash, type Sha2 } from '../util/hash';
+import { P256, Ristretto255, type Group, type Element, type Scalar } from '../group';
+
+/**
+ * RFC 9497 protocol variants.
+ */
+const Modes = {
+    OPRF: 0x00,
+    VOPRF: 0x01,
+    POPRF: 0x02,
+} as const;
+
+type Mode = (typeof Modes)[keyof typeof Modes];
+
+/**
+ * RFC 9497 ciphersuite identifiers.
+ */
+const Suites = {
+    Ristretto255Sha512: 'ristretto255-SHA512',
+    P256Sha256: 'P256-SHA256',
+} as const;
+
+type Suite = (typeof Suites)[keyof typeof Suites];
+
+// The I2OSP(len, 2) prefix in front of every variable-length hash input.
+const lengthPrefix = 2;
+
+/**
+ * Error codes of the oprf package, matching the Go sentinels.
+ */
+const OprfErrorCodes = {
+    unsupportedSuite: 'unsupportedSuite',
+    unsupportedMode: 'unsupportedMode',
+    invalidInput: 'invalidInput',
+    deriveKeyPair: 'deriveKeyPair',
+    inverse: 'inverse',
+    verify: 'verify',
+    invalidElement: 'invalidElement',
+} as const;
+
+type OprfErrorCode = (typeof OprfErrorCodes)[keyof typeof OprfErrorCodes];
+
+const messages: Record<OprfErrorCode, string> = {
+    unsupportedSuite: 'oprf: unsupported suite',
+    unsupportedMode: 'oprf: unsupported mode',
+    invalidInput: 'oprf: input hashes to the identity or is malformed',
+    deriveKeyPair: 'oprf: no valid key derived from the seed',
+    inverse: 'oprf: tweaked key has no inverse',
+    verify: 'oprf: proof verification failed',
+    invalidElement: 'oprf: invalid element encoding',
+};
+
+/**
+ * Mirrors the Go oprf sentinels, from ErrUnsupportedSuite to ErrInvalidElement.
+ */
+class OprfError extends Error {
+    readonly code: OprfErrorCode;
+
+    constructor(code: OprfErrorCode) {
+        super(messages[code]);
+        this.name = 'OprfError';
+        this.code = code;
+    }
+}
+
+const enc = new TextEncoder();
+
+/**
+ * A suite in one mode, with the contextString that separates their hashes.
+ */
+class Params {
+    readonly suite: Suite;
+    readonly mode: Mode;
+    readonly g: Group;
+    readonly hashBits: Sha2;
+    readonly context: Uint8Array;
+
+    constructor(suite: string, mode: number) {
+        switch (suite) {
+            case Suites.Ristretto255Sha512:
+                this.g = Ristretto255;
+                this.hashBits = 512;
+                break;
+            case Suites.P256Sha256:
+                this.g = P256;
+                this.hashBits = 256;
+                break;
+            default:
+                throw new OprfError('unsupportedSuite');
+        }
+        if (mode !== Modes.OPRF && mode !== Modes.VOPRF && mode !== Modes.POPRF) {
+            throw new OprfError('unsupportedMode');
+        }
+        this.suite = suite;
+        this.mode = mode;
+        // contextString = "OPRFV1-" || I2OSP(mode, 1) || "-" || identifier
+        this.context = concatBytes(enc.encode('OPRFV1-'), new Uint8Array([mode]), enc.encode('-' + suite));
+    }
+
+    dst(prefix: string): Uint8Array {
+        return concatBytes(enc.encode(prefix), this.context);
+    }
+
+    hash(data: Uint8Array): Promise<Uint8Array> {
+        return sha2Hash(data, this.hashBits);
+    }
+
+    hashToGroup(input: Uint8Array): Promise<Element> {
+        return this.g.hashToElement(input, this.dst('HashToGroup-'));
+    }
+
+    hashToScalar(data: Uint8Array): Promise<Scalar> {
+        return this.g.hashToScalar(data, this.dst('HashToScalar-'));
+    }
+
+    // Rejects an element of another group or the identity, which cannot be serialized in
+    // RFC 9497.
+    checkElement(e: Element | undefined): Element {
+        if (!e || e.group() !== this.g || e.isIdentity()) {
+            throw new OprfError('invalidElement');
+        }
+        return e;
+    }
+
+    // Hash(I2OSP(len(input), 2) || input [|| I2OSP(len(info), 2) || info] ||
+    // I2OSP(len(element), 2) || element || "Finalize"), with info only in POPRF mode.
+    finalizeHash(input: Uint8Array, info: Uint8Array, e: Element): Promise<Uint8Array> {
+        const parts = this.mode === Modes.POPRF ? [input, info, e.toBytes()] : [input, e.toBytes()];
+        return this.hash(concatBytes(framed(...parts), enc.encode('Finalize')));
+    }
+}
+
+/**
+ * Concatenates each part as I2OSP(len(part), 2) || part.
+ */
+function framed(...parts: Uint8Array[]): Uint8Array {
+    try {
+        return concatBytes(...parts.map(part => framedBytesFromUint8Array(part, lengthPrefix)));
+    } catch {
+        throw new OprfError('invalidInput');
+    }
+}
+
+/**
+ * Parses a serialized element of the suite's group, rejecting the identity.
+ *
+ * @throws {OprfError} - unsupportedSuite or invalidElement.
+ *
+ * @param suite - The ciphersuite.
+ * @param b - The serialized element.
+ *
+ * @returns Element - The element.
+ */
+function decodeElement(suite: string, b: Uint8Array): Element {
+    const p = new Params(suite, Modes.OPRF);
+    let e: Element;
+    try {
+        e = p.g.decodeElement(b);
+    } catch {
+        throw new OprfError('invalidElement');
+    }
+    return p.checkElement(e);
+}
+
+/**
+ * Parses a serialized scalar of the suite's group, such as a blind or private key.
+ *
+ * @throws {OprfError} - unsupportedSuite.
+ * @throws {GroupError} - invalidScalar.
+ *
+ * @param suite - The ciphersuite.
+ * @param b - The serialized scalar.
+ *
+ * @returns Scalar - The scalar.
+ */
+function decodeScalar(suite: string, b: Uint8Array): Scalar {
+    return new Params(suite, Modes.OPRF).g.decodeScalar(b);
+}
+
+/**
+ * Derives a private key and its public key from a seed and public info string,
+ * deterministically for the suite and mode (RFC 9497, section 3.2.1).
+ *
+ * @throws {OprfError} - unsupportedSuite, unsupportedMode, invalidInput or deriveKeyPair.
+ *
+ * @param suite - The ciphersuite.
+ * @param mode - The protocol mode.
+ * @param seed - The seed.
+ * @param info - The public key info.
+ *
+ * @returns A promise that resolves to [sk, pk].
+ */
+async function deriveKeyPair(suite: string, mode: number, seed: Uint8Array, info: Uint8Array): Promise<[Scalar, Element]> {
+    const p = new Params(suite, mode);
+    const deriveInput = concatBytes(seed, framed(info));
+    const dst = p.dst('DeriveKeyPair');
+    for (let counter = 0; counter < 256; counter++) {
+        const sk = await p.g.hashToScalar(concatBytes(deriveInput, new Uint8Array([counter])), dst);
+        if (!sk.isZero()) {
+            return [sk, p.g.generator().scalarMult(sk)];
+        }
+    }
+    throw new OprfError('deriveKeyPair');
+}
+
+/**
+ * Returns a random nonzero scalar of g.
+ */
+function randomNonZero(g: Group): Scalar {
+    for (;;) {
+        const s = g.randomScalar();
+        if (!s.isZero()) return s;
+    }
+}
+
+export {
+    Modes,
+    type Mode,
+    Suites,
+    type Suite,
+    OprfErrorCodes,
+    type OprfErrorCode,
+    OprfError,
+    Params,
+    framed,
+    lengthPrefix,
+    decodeElement,
+    decodeScalar,
+    deriveKeyPair,
+    randomNonZero,
+};
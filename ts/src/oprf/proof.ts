@@ -0,0 +1,93 @@
+import { concatBytes, intToBytes } from '../util/bytes';
+import type { Element, Scalar } from '../group';
+import { Modes, OprfError, Params, framed, lengthPrefix } from './oprf';
+
+const enc = new TextEncoder();
+
+/**
+ * A batched DLEQ proof (RFC 9497, section 2.2) that log_A(B) = log_C(D) for every pair of
+ * elements C[i], D[i]: the server's key k satisfies B = k*A and D[i] = k*C[i].
+ */
+class Proof {
+    readonly c: Scalar;
+    readonly s: Scalar;
+
+    constructor(c: Scalar, s: Scalar) {
+        this.c = c;
+        this.s = s;
+    }
+
+    /**
+     * Serializes the proof as SerializeScalar(c) || SerializeScalar(s).
+     */
+    toBytes(): Uint8Array {
+        return concatBytes(this.c.toBytes(), this.s.toBytes());
+    }
+}
+
+/**
+ * Decodes a proof written by Proof.toBytes.
+ *
+ * @throws {OprfError} - unsupportedSuite or invalidInput.
+ *
+ * @param suite - The ciphersuite.
+ * @param b - The serialized proof.
+ *
+ * @returns Proof - The proof.
+ */
+function parseProof(suite: string, b: Uint8Array): Proof {
+    const p = new Params(suite, Modes.VOPRF);
+    const n = p.g.scalarSize();
+    if (b.length !== 2 * n) {
+        throw new OprfError('invalidInput');
+    }
+    try {
+        return new Proof(p.g.decodeScalar(b.subarray(0, n)), p.g.decodeScalar(b.subarray(n)));
+    } catch {
+        throw new OprfError('invalidInput');
+    }
+}
+
+// Folds C and D into M = sum d_i*C[i] and Z = sum d_i*D[i], with each d_i hashed from a
+// seed bound to B.
+async function composites(p: Params, b: Element, c: Element[], d: Element[]): Promise<[Element, Element]> {
+    const seed = await p.hash(framed(b.toBytes(), p.dst('Seed-')));
+    let m = p.g.identity();
+    let z = p.g.identity();
+    for (let i = 0; i < c.length; i++) {
+        const di = await p.hashToScalar(concatBytes(
+            framed(seed),
+            intToBytes(i, lengthPrefix),
+            framed(c[i].toBytes(), d[i].toBytes()),
+            enc.encode('Composite'),
+        ));
+        m = c[i].scalarMult(di).add(m);
+        z = d[i].scalarMult(di).add(z);
+    }
+    return [m, z];
+}
+
+function challenge(p: Params, b: Element, m: Element, z: Element, t2: Element, t3: Element): Promise<Scalar> {
+    const transcript = framed(b.toBytes(), m.toBytes(), z.toBytes(), t2.toBytes(), t3.toBytes());
+    return p.hashToScalar(concatBytes(transcript, enc.encode('Challenge')));
+}
+
+// Verifies the proof that B = k*A and D[i] = k*C[i], throwing verify on failure.
+async function verifyProof(p: Params, a: Element, b: Element, c: Element[], d: Element[], pr: Proof | undefined): Promise<void> {
+    if (!pr || pr.c.group() !== p.g || pr.s.group() !== p.g) {
+        throw new OprfError('verify');
+    }
+    const [m, z] = await composites(p, b, c, d);
+    const t2 = a.scalarMult(pr.s).add(b.scalarMult(pr.c));
+    const t3 = m.scalarMult(pr.s).add(z.scalarMult(pr.c));
+    const ch = await challenge(p, b, m, z, t2, t3);
+    if (!ch.equal(pr.c)) {
+        throw new OprfError('verify');
+    }
+}
+
+export {
+    Proof,
+    parseProof,
+    verifyProof,
+};
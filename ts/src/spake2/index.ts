@@ -0,0 +1,9 @@
+export {
+    Suites,
+    type Suite,
+    Spake2ErrorCodes,
+    type Spake2ErrorCode,
+    Spake2Error,
+    type Config,
+} from './spake2';
+export * from './initiator';
@@ -0,0 +1,116 @@
+import { marshalUncompressed, type Element, type Scalar } from '../group';
+import { Params, Spake2Error, frame, unframe, type Config } from './spake2';
+
+// Tracks the state machine: each step runs once, in order.
+const Stages = {
+    new: 0,
+    started: 1,
+    done: 2,
+} as const;
+
+type Stage = (typeof Stages)[keyof typeof Stages];
+
+// Compares two MACs without an early exit, as Go's hmac.Equal.
+function equalBytes(a: Uint8Array, b: Uint8Array): boolean {
+    if (a.length !== b.length) return false;
+    let diff = 0;
+    for (let i = 0; i < a.length; i++) diff |= a[i] ^ b[i];
+    return diff === 0;
+}
+
+/**
+ * Party A: start sends pA, and finish checks the responder's pB and MAC, returning A's MAC
+ * and the session key. The responder is Go only.
+ */
+class Initiator {
+    private readonly p: Params;
+    private readonly w: Scalar;
+    private stage: Stage = Stages.new;
+    private x: Scalar | undefined;
+    private pA: Element | undefined;
+
+    /**
+     * Takes the password scalar w directly. It exists for test vectors that give w, so
+     * prefer newInitiator.
+     *
+     * @throws {Spake2Error} - unsupportedSuite.
+     */
+    constructor(cfg: Config, w: Scalar) {
+        this.p = new Params(cfg);
+        this.w = w;
+    }
+
+    /**
+     * Draws x and returns the first message, pA.
+     *
+     * @throws {Spake2Error} - state if called twice.
+     *
+     * @returns A promise that resolves to the framed message.
+     */
+    start(): Promise<Uint8Array> {
+        return this.startWithScalar(this.p.g.randomScalar());
+    }
+
+    /**
+     * start with the scalar x given. It exists for test vectors with a fixed x, so prefer
+     * start.
+     */
+    async startWithScalar(x: Scalar): Promise<Uint8Array> {
+        if (this.stage !== Stages.new) {
+            throw new Spake2Error('state');
+        }
+        this.stage = Stages.started;
+        this.x = x;
+        const [m] = await this.p.mn();
+        this.pA = this.p.share(x, this.w, m);
+        return frame(marshalUncompressed(this.pA));
+    }
+
+    /**
+     * Takes the responder's message, pB and its MAC, and returns the last message, A's MAC,
+     * with the session key Ke. A wrong password fails with authentication.
+     *
+     * @throws {Spake2Error} - state, invalidMessage or authentication.
+     *
+     * @param msg - The responder's message.
+     *
+     * @returns A promise that resolves to [msg3, Ke].
+     */
+    async finish(msg: Uint8Array): Promise<[Uint8Array, Uint8Array]> {
+        if (this.stage !== Stages.started) {
+            throw new Spake2Error('state');
+        }
+        this.stage = Stages.done;
+        const x = this.x as Scalar;
+        this.x = undefined;
+        const fields = unframe(msg, 2);
+        const pB = this.p.decodeShare(fields[0]);
+        const [, n] = await this.p.mn();
+        const k = this.p.sharedElement(x, this.w, pB, n);
+        const ks = await this.p.keySchedule(this.pA as Element, pB, k, this.w);
+        if (!equalBytes(fields[1], ks.macB)) {
+            throw new Spake2Error('authentication');
+        }
+        return [frame(ks.macA), ks.ke];
+    }
+}
+
+/**
+ * Returns the initiator for cfg and the shared password.
+ *
+ * @throws {Spake2Error} - unsupportedSuite.
+ *
+ * @param cfg - The configuration, which must match the responder's.
+ * @param password - The shared password.
+ *
+ * @returns A promise that resolves to the initiator.
+ */
+async function newInitiator(cfg: Config, password: Uint8Array): Promise<Initiator> {
+    const p = new Params(cfg);
+    return new Initiator(cfg, await p.passwordScalar(password));
+}
+
+export {
+    Initiator,
+    newInitiator,
+};
@@ -0,0 +1,230 @@
+import { bigIntToBytes, concatBytes, framedBytesFromUint8Array, parseFramed } from '../util/bytes';
+import { sha2Hash, hkdf, hmacSha2, type Sha2 } from '../util/hash';
+import {
+    P256,
+    Ristretto255,
+    decodeUncompressed,
+    marshalUncompressed,
+    ristrettoElementFromUniformBytes,
+    type Element,
+    type Group,
+    type Scalar,
+} from '../group';
+
+/**
+ * SPAKE2 ciphersuite identifiers.
+ */
+const Suites = {
+    Ristretto255Sha512: 'SPAKE2-ristretto255-SHA512-HKDF-HMAC',
+    P256Sha256: 'SPAKE2-P256-SHA256-HKDF-HMAC',
+} as const;
+
+type Suite = (typeof Suites)[keyof typeof Suites];
+
+// The length prefix of every frame in a wire message.
+const lengthPrefix = 2;
+
+/**
+ * Error codes of the spake2 package, matching the Go sentinels.
+ */
+const Spake2ErrorCodes = {
+    unsupportedSuite: 'unsupportedSuite',
+    invalidMessage: 'invalidMessage',
+    authentication: 'authentication',
+    state: 'state',
+} as const;
+
+type Spake2ErrorCode = (typeof Spake2ErrorCodes)[keyof typeof Spake2ErrorCodes];
+
+const messages: Record<Spake2ErrorCode, string> = {
+    unsupportedSuite: 'spake2: unsupported suite',
+    invalidMessage: 'spake2: malformed message or invalid element',
+    authentication: 'spake2: key confirmation failed',
+    state: 'spake2: state machine used out of order or reused',
+};
+
+/**
+ * Mirrors Go's spake2.ErrUnsupportedSuite, ErrInvalidMessage, ErrAuthentication and ErrState.
+ */
+class Spake2Error extends Error {
+    readonly code: Spake2ErrorCode;
+
+    constructor(code: Spake2ErrorCode) {
+        super(messages[code]);
+        this.name = 'Spake2Error';
+        this.code = code;
+    }
+}
+
+/**
+ * What both parties must agree on before the exchange. identityA and identityB name the
+ * initiator and the responder and may be empty; aad is bound into the confirmation keys;
+ * ksf stretches the password before it is hashed to w, and a missing ksf is the identity.
+ */
+type Config = {
+    readonly suite: string;
+    readonly identityA?: Uint8Array;
+    readonly identityB?: Uint8Array;
+    readonly aad?: Uint8Array;
+    readonly ksf?: (password: Uint8Array) => Promise<Uint8Array>;
+};
+
+const enc = new TextEncoder();
+
+// The fixed elements M and N: the RFC 9382 constants for P-256, and for ristretto255 the
+// one-way map of SHA-512 of a seed, as in Go.
+const p256M = P256.decodeElement(bigIntToBytes(0x02886e2f97ace46e55ba9dd7242579f2993b64e16ef3dcab95afd497333d8fa12fn, 33));
+const p256N = P256.decodeElement(bigIntToBytes(0x03d8bbd6c639c62937b04d997f38c3770719c629d7014d49a24b4f98baa1292b49n, 33));
+let ristrettoMN: Promise<[Element, Element]> | undefined;
+
+function ristrettoConstants(): Promise<[Element, Element]> {
+    const derive = async (seed: string) =>
+        ristrettoElementFromUniformBytes(await sha2Hash(enc.encode(`SPAKE2 ristretto255 point generation seed (${seed})`), 512));
+    ristrettoMN ??= Promise.all([derive('M'), derive('N')]);
+    return ristrettoMN;
+}
+
+/**
+ * A Config with its suite resolved.
+ */
+class Params {
+    readonly suite: Suite;
+    readonly g: Group;
+    readonly hashBits: Sha2;
+    readonly identityA: Uint8Array;
+    readonly identityB: Uint8Array;
+    readonly aad: Uint8Array;
+    readonly ksf: ((password: Uint8Array) => Promise<Uint8Array>) | undefined;
+
+    constructor(cfg: Config) {
+        switch (cfg.suite) {
+            case Suites.Ristretto255Sha512:
+                [this.g, this.hashBits] = [Ristretto255, 512];
+                break;
+            case Suites.P256Sha256:
+                [this.g, this.hashBits] = [P256, 256];
+                break;
+            default:
+                throw new Spake2Error('unsupportedSuite');
+        }
+        this.suite = cfg.suite;
+        this.identityA = Uint8Array.from(cfg.identityA ?? []);
+        this.identityB = Uint8Array.from(cfg.identityB ?? []);
+        this.aad = Uint8Array.from(cfg.aad ?? []);
+        this.ksf = cfg.ksf;
+    }
+
+    async mn(): Promise<[Element, Element]> {
+        return this.suite === Suites.P256Sha256 ? [p256M, p256N] : ristrettoConstants();
+    }
+
+    // w = HashToScalar(KSF(password)) under the DST "SPAKE2-w-" || suite.
+    async passwordScalar(password: Uint8Array): Promise<Scalar> {
+        const stretched = this.ksf ? await this.ksf(password) : password;
+        return this.g.hashToScalar(stretched, enc.encode('SPAKE2-w-' + this.suite));
+    }
+
+    // Parses pA or pB, rejecting the identity.
+    decodeShare(b: Uint8Array): Element {
+        let e: Element;
+        try {
+            e = decodeUncompressed(this.g, b);
+        } catch {
+            throw new Spake2Error('invalidMessage');
+        }
+        if (e.isIdentity()) {
+            throw new Spake2Error('invalidMessage');
+        }
+        return e;
+    }
+
+    // x*G + w*blind, the public share of one side.
+    share(x: Scalar, w: Scalar, blind: Element): Element {
+        return this.g.generator().scalarMult(x).add(blind.scalarMult(w));
+    }
+
+    // K = x*(peer - w*blind); the cofactor of both groups is 1.
+    sharedElement(x: Scalar, w: Scalar, peer: Element, blind: Element): Element {
+        const k = peer.subtract(blind.scalarMult(w)).scalarMult(x);
+        if (k.isIdentity()) {
+            throw new Spake2Error('invalidMessage');
+        }
+        return k;
+    }
+
+    // The RFC 9382 key schedule: Ke || Ka = Hash(TT), KcA || KcB = HKDF(Ka, nil,
+    // "ConfirmationKeys" || AAD), and the MACs over TT.
+    async keySchedule(pA: Element, pB: Element, k: Element, w: Scalar): Promise<Keys> {
+        const tt = transcript(this.identityA, this.identityB, marshalUncompressed(pA), marshalUncompressed(pB),
+            marshalUncompressed(k), w.toBytes());
+        const h = await sha2Hash(tt, this.hashBits);
+        const half = h.length / 2;
+        const kc = await hkdf(h.subarray(half), new Uint8Array(), concatBytes(enc.encode('ConfirmationKeys'), this.aad),
+            h.length, this.hashBits);
+        const kcA = kc.subarray(0, half);
+        const kcB = kc.subarray(half);
+        return {
+            ke: h.subarray(0, half),
+            ka: h.subarray(half),
+            kcA,
+            kcB,
+            macA: await hmacSha2(kcA, tt, this.hashBits),
+            macB: await hmacSha2(kcB, tt, this.hashBits),
+        };
+    }
+}
+
+// The key schedule for one exchange.
+type Keys = {
+    ke: Uint8Array;
+    ka: Uint8Array;
+    kcA: Uint8Array;
+    kcB: Uint8Array;
+    macA: Uint8Array;
+    macB: Uint8Array;
+};
+
+// TT: each field prefixed with its length as 8 little-endian bytes.
+function transcript(...fields: Uint8Array[]): Uint8Array {
+    return concatBytes(...fields.flatMap(f => {
+        const n = new Uint8Array(8);
+        new DataView(n.buffer).setBigUint64(0, BigInt(f.length), true);
+        return [n, f];
+    }));
+}
+
+// frame and unframe are the wire encoding of a message: its fields framed with 2-byte
+// length prefixes.
+function frame(...fields: Uint8Array[]): Uint8Array {
+    try {
+        return concatBytes(...fields.map(f => framedBytesFromUint8Array(f, lengthPrefix)));
+    } catch {
+        throw new Spake2Error('invalidMessage');
+    }
+}
+
+function unframe(msg: Uint8Array, count: number): Uint8Array[] {
+    let fields: Uint8Array[];
+    try {
+        fields = parseFramed(msg, lengthPrefix);
+    } catch {
+        throw new Spake2Error('invalidMessage');
+    }
+    if (fields.length !== count) {
+        throw new Spake2Error('invalidMessage');
+    }
+    return fields;
+}
+
+export {
+    Suites,
+    type Suite,
+    Spake2ErrorCodes,
+    type Spake2ErrorCode,
+    Spake2Error,
+    type Config,
+    Params,
+    type Keys,
+    frame,
+    unframe,
+};
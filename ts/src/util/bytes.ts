@@ -1,3 +1,5 @@
+import { ParityError } from './errors';
+
 /**
  * Converts a Uint8Array to a BigInt.
  *
@@ -50,7 +52,8 @@ function bigIntToByteArray(value: bigint): Uint8Array {
  *
  * Converts an integer to a Uint8Array of specified byte length.
  *
- * @throws {RangeError} - If the input integer is negative or exceeds the safe integer range, or does not fit in the requested byte length.
+ * @throws {ParityError} - invalidArgument if byteLength is not positive, negative if the input integer is negative,
+ * overflow if it exceeds the safe integer range or does not fit in the requested byte length.
  *
  * @param i - The integer to convert.
  * @param byteLength - The desired byte length of the output array.
@@ -58,19 +61,43 @@ function bigIntToByteArray(value: bigint): Uint8Array {
  * @returns Uint8Array - The resulting byte array.
  */
 function intToBytes(i: number, byteLength: number): Uint8Array {
-    if (i < 0) throw new RangeError("intToBytes only supports non-negative integers");
-    if (!Number.isSafeInteger(i)) throw new RangeError("Input exceeds 53-bit safe integer range");
-    if (byteLength <= 0) throw new RangeError("byteLength must be positive");
+    if (byteLength <= 0) throw new ParityError('IntToBytes', 'invalidArgument', { byteLen: byteLength });
+    if (i < 0) throw new ParityError('IntToBytes', 'negative', { i });
+    if (!Number.isSafeInteger(i)) throw new ParityError('IntToBytes', 'overflow', { i, byteLen: byteLength });
+
+    try {
+        return bigIntToBytes(BigInt(i), byteLength);
+    } catch {
+        throw new ParityError('IntToBytes', 'overflow', { i, byteLen: byteLength });
+    }
+}
+
+/**
+ * @note This function returns the big-endian representation.
+ *
+ * intToBytes for a bigint: value left-padded with zeros to exactly byteLength bytes.
+ *
+ * @throws {ParityError} - invalidArgument if byteLength is not positive, negative if value is negative,
+ * overflow if it does not fit in the requested byte length.
+ *
+ * @param value - The BigInt to convert.
+ * @param byteLength - The desired byte length of the output array.
+ *
+ * @returns Uint8Array - The resulting byte array.
+ */
+function bigIntToBytes(value: bigint, byteLength: number): Uint8Array {
+    if (!(byteLength > 0)) throw new ParityError('BigIntToBytes', 'invalidArgument', { byteLen: byteLength });
+    if (value < 0n) throw new ParityError('BigIntToBytes', 'negative');
 
     const bytes = new Uint8Array(byteLength);
-    let value = BigInt(i);
+    let v = value;
 
     for (let b = byteLength - 1; b >= 0; b--) {
-        bytes[b] = Number(value & 0xffn);
-        value >>= 8n;
+        bytes[b] = Number(v & 0xffn);
+        v >>= 8n;
     }
 
-    if (value !== 0n) throw new RangeError("Input does not fit in the requested byte length");
+    if (v !== 0n) throw new ParityError('BigIntToBytes', 'overflow', { byteLen: byteLength });
 
     return bytes;
 }
@@ -162,13 +189,197 @@ function framedBytes(input: Uint8Array | bigint | string, lengthPrefixBytes: num
     else return framedBytesFromString(input, lengthPrefixBytes);
 }
 
+/**
+ * Reads consecutive frames from a buffer, e.g. one built with concatBytes from several framedBytes outputs.
+ * All frames must use the same lengthPrefixBytes. Mirrors Go's util.FramedReader.
+ */
+class FramedReader {
+    private readonly buf: Uint8Array;
+    private readonly lengthPrefixBytes: number;
+    private off = 0;
+
+    /**
+     * @param buf - The buffer to read from.
+     * @param lengthPrefixBytes - The number of bytes used for every length prefix.
+     */
+    constructor(buf: Uint8Array, lengthPrefixBytes: number) {
+        this.buf = buf;
+        this.lengthPrefixBytes = lengthPrefixBytes;
+    }
+
+    /**
+     * @returns number - The number of unread bytes.
+     */
+    remaining(): number {
+        return this.buf.length - this.off;
+    }
+
+    /**
+     * @throws {ParityError} - trailing if any bytes are left unread.
+     */
+    finish(): void {
+        if (this.remaining() !== 0) throw new ParityError('FramedReader', 'trailing');
+    }
+
+    // Reads a big-endian length prefix and checks that many bytes (plus extra) follow.
+    private readLength(extra: number): number {
+        if (this.lengthPrefixBytes <= 0) {
+            throw new ParityError('FramedReader', 'invalidArgument', { lengthPrefixBytes: this.lengthPrefixBytes });
+        }
+        if (this.remaining() < this.lengthPrefixBytes) throw new ParityError('FramedReader', 'truncated');
+        const n = bytesToBigInt(this.buf.subarray(this.off, this.off + this.lengthPrefixBytes));
+        const avail = this.remaining() - this.lengthPrefixBytes - extra;
+        if (avail < 0 || n > BigInt(avail)) throw new ParityError('FramedReader', 'truncated');
+        this.off += this.lengthPrefixBytes;
+        return Number(n);
+    }
+
+    /**
+     * Reads one [length][data] frame.
+     *
+     * @throws {ParityError} - truncated if the frame does not fit in the buffer.
+     *
+     * @returns Uint8Array - A copy of data.
+     */
+    readBytes(): Uint8Array {
+        const n = this.readLength(0);
+        const out = this.buf.slice(this.off, this.off + n);
+        this.off += n;
+        return out;
+    }
+
+    /**
+     * Reads one [length][sign byte][magnitude] frame as produced by framedBytesFromBigInt. The magnitude must be
+     * minimal: no leading zero bytes, zero encoded as a single 0x00 with sign 0.
+     *
+     * @throws {ParityError} - truncated, sign or nonMinimal.
+     *
+     * @returns bigint - The decoded value.
+     */
+    readBigInt(): bigint {
+        const start = this.off;
+        const n = this.readLength(1);
+        const sign = this.buf[this.off];
+        const magnitude = this.buf.subarray(this.off + 1, this.off + 1 + n);
+        if (sign > 1) {
+            this.off = start;
+            throw new ParityError('FramedReader', 'sign');
+        }
+        if (n === 0 || (n > 1 && magnitude[0] === 0) || (n === 1 && magnitude[0] === 0 && sign === 1)) {
+            this.off = start;
+            throw new ParityError('FramedReader', 'nonMinimal');
+        }
+        const v = bytesToBigInt(magnitude);
+        this.off += 1 + n;
+        return sign === 1 ? -v : v;
+    }
+
+    /**
+     * Reads one [length][data] frame and decodes data as UTF-8.
+     *
+     * @throws {ParityError} - truncated, or utf8 if data is not valid UTF-8.
+     *
+     * @returns string - The decoded string.
+     */
+    readString(): string {
+        const start = this.off;
+        const b = this.readBytes();
+        try {
+            return new TextDecoder('utf-8', { fatal: true }).decode(b);
+        } catch {
+            this.off = start;
+            throw new ParityError('FramedReader', 'utf8');
+        }
+    }
+}
+
+// Runs read on a reader over frame, requires it to consume the whole frame, and reports failures under op.
+function unframe<T>(op: string, frame: Uint8Array, lengthPrefixBytes: number, read: (r: FramedReader) => T): T {
+    const r = new FramedReader(frame, lengthPrefixBytes);
+    let out: T;
+    try {
+        out = read(r);
+        r.finish();
+    } catch (err) {
+        if (err instanceof ParityError) throw new ParityError(op, err.code, err.params);
+        throw err;
+    }
+    return out;
+}
+
+/**
+ * Decodes a single frame produced by framedBytesFromUint8Array.
+ *
+ * @throws {ParityError} - truncated or trailing.
+ *
+ * @param frame - The frame to decode.
+ * @param lengthPrefixBytes - The number of bytes used for the length prefix.
+ *
+ * @returns Uint8Array - The framed data.
+ */
+function unframeBytes(frame: Uint8Array, lengthPrefixBytes: number): Uint8Array {
+    return unframe('UnframeBytes', frame, lengthPrefixBytes, r => r.readBytes());
+}
+
+/**
+ * Decodes a single frame produced by framedBytesFromBigInt.
+ *
+ * @throws {ParityError} - truncated, sign, nonMinimal or trailing.
+ *
+ * @param frame - The frame to decode.
+ * @param lengthPrefixBytes - The number of bytes used for the length prefix.
+ *
+ * @returns bigint - The framed value.
+ */
+function unframeBigInt(frame: Uint8Array, lengthPrefixBytes: number): bigint {
+    return unframe('UnframeBigInt', frame, lengthPrefixBytes, r => r.readBigInt());
+}
+
+/**
+ * Decodes a single frame produced by framedBytesFromString.
+ *
+ * @throws {ParityError} - truncated, utf8 or trailing.
+ *
+ * @param frame - The frame to decode.
+ * @param lengthPrefixBytes - The number of bytes used for the length prefix.
+ *
+ * @returns string - The framed string.
+ */
+function unframeString(frame: Uint8Array, lengthPrefixBytes: number): string {
+    return unframe('UnframeString', frame, lengthPrefixBytes, r => r.readString());
+}
+
+/**
+ * Splits a buffer of consecutive [length][data] frames into their payloads.
+ *
+ * @throws {ParityError} - truncated if the last frame does not fit.
+ *
+ * @param buf - The buffer to split.
+ * @param lengthPrefixBytes - The number of bytes used for every length prefix.
+ *
+ * @returns Uint8Array[] - The payloads in order.
+ */
+function parseFramed(buf: Uint8Array, lengthPrefixBytes: number): Uint8Array[] {
+    return unframe('ParseFramed', buf, lengthPrefixBytes, r => {
+        const out: Uint8Array[] = [];
+        while (r.remaining() > 0) out.push(r.readBytes());
+        return out;
+    });
+}
+
 export {
     bytesToBigInt,
     bigIntToByteArray,
     intToBytes,
+    bigIntToBytes,
     concatBytes,
     framedBytesFromUint8Array,
     framedBytesFromBigInt,
     framedBytesFromString,
-    framedBytes
+    framedBytes,
+    FramedReader,
+    unframeBytes,
+    unframeBigInt,
+    unframeString,
+    parseFramed
 };
\ No newline at end of file
@@ -1,3 +1,7 @@
+import { ParityError } from './errors';
+
+const base58Alphabet = '123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz';
+
 /**
  * URL-safe base64 encoding without padding, this implementation defaults to
  * using `btoa`/`atob` in browser environments and `Buffer` in Node.js.
@@ -70,7 +74,74 @@ function decUrlSafe(data: string): Uint8Array {
     throw new Error('No base64 decoder available');
 }
 
+/**
+ * Encodes data with the Bitcoin base58 alphabet. Each leading zero byte becomes a '1'.
+ *
+ * @param data - The input byte array to encode.
+ *
+ * @returns The base58 encoded string.
+ */
+function encBase58(data: Uint8Array): string {
+    let zeros = 0;
+    while (zeros < data.length && data[zeros] === 0) zeros++;
+
+    // Little-endian base58 digits of the remaining bytes.
+    const digits: number[] = [];
+    for (let i = zeros; i < data.length; i++) {
+        let carry = data[i];
+        for (let j = 0; j < digits.length; j++) {
+            carry += digits[j] << 8;
+            digits[j] = carry % 58;
+            carry = Math.floor(carry / 58);
+        }
+        while (carry > 0) {
+            digits.push(carry % 58);
+            carry = Math.floor(carry / 58);
+        }
+    }
+
+    let out = '1'.repeat(zeros);
+    for (let i = digits.length - 1; i >= 0; i--) out += base58Alphabet[digits[i]];
+    return out;
+}
+
+/**
+ * Decodes a Bitcoin base58 string.
+ *
+ * @param data - The base58 encoded string to decode.
+ *
+ * @returns The decoded byte array.
+ *
+ * @throws {ParityError} encoding, with the offset of the first character outside the alphabet.
+ */
+function decBase58(data: string): Uint8Array {
+    let zeros = 0;
+    while (zeros < data.length && data[zeros] === '1') zeros++;
+
+    // Little-endian bytes of the remaining digits.
+    const bytesLE: number[] = [];
+    for (let i = zeros; i < data.length; i++) {
+        let carry = base58Alphabet.indexOf(data[i]);
+        if (carry < 0) throw new ParityError('DecBase58', 'encoding', { offset: i });
+        for (let j = 0; j < bytesLE.length; j++) {
+            carry += bytesLE[j] * 58;
+            bytesLE[j] = carry & 0xff;
+            carry >>= 8;
+        }
+        while (carry > 0) {
+            bytesLE.push(carry & 0xff);
+            carry >>= 8;
+        }
+    }
+
+    const out = new Uint8Array(zeros + bytesLE.length);
+    for (let i = 0; i < bytesLE.length; i++) out[out.length - 1 - i] = bytesLE[i];
+    return out;
+}
+
 export {
     encUrlSafe,
-    decUrlSafe
+    decUrlSafe,
+    encBase58,
+    decBase58
 };
@@ -0,0 +1,78 @@
+/**
+ * Stable error codes shared with the Go implementation (util.ErrorCode) and used in the
+ * "error"/"code" fields of testdata/parity.json.
+ */
+const ErrorCodes = {
+    unsupportedBits: 'unsupportedBits',
+    negative: 'negative',
+    overflow: 'overflow',
+    nilInput: 'nilInput',
+    outputLen: 'outputLen',
+    invalidArgument: 'invalidArgument',
+    encoding: 'encoding',
+    truncated: 'truncated',
+    sign: 'sign',
+    nonMinimal: 'nonMinimal',
+    trailing: 'trailing',
+    utf8: 'utf8',
+    passwordFormat: 'passwordFormat',
+    passwordAlgorithm: 'passwordAlgorithm',
+    passwordParams: 'passwordParams',
+} as const;
+
+type ErrorCode = (typeof ErrorCodes)[keyof typeof ErrorCodes];
+
+const messages: Record<ErrorCode, string> = {
+    unsupportedBits: 'unsupported bit length',
+    negative: 'negative input',
+    overflow: 'input does not fit in the requested length',
+    nilInput: 'nil input',
+    outputLen: 'invalid output length',
+    invalidArgument: 'invalid argument',
+    encoding: 'invalid encoding',
+    truncated: 'framed bytes: truncated input',
+    sign: 'framed bytes: invalid sign byte',
+    nonMinimal: 'framed bytes: non-minimal magnitude',
+    trailing: 'framed bytes: trailing data',
+    utf8: 'framed bytes: invalid UTF-8',
+    passwordFormat: 'password hash: malformed PHC string',
+    passwordAlgorithm: 'password hash: unsupported algorithm',
+    passwordParams: 'password hash: invalid parameters',
+};
+
+/**
+ * Mirrors Go's *util.ParityError: the util function that failed, the numeric parameters it
+ * was called with, and the stable code of the failure.
+ */
+class ParityError extends Error {
+    readonly op: string;
+    readonly params: Record<string, number>;
+    readonly code: ErrorCode;
+
+    constructor(op: string, code: ErrorCode, params: Record<string, number> = {}) {
+        const args = Object.keys(params).sort().map(name => `${name}=${params[name]}`).join(', ');
+        super(`util.${op}(${args}): ${messages[code]}`);
+        this.name = 'ParityError';
+        this.op = op;
+        this.code = code;
+        this.params = params;
+    }
+}
+
+/**
+ * Returns the stable code of a ParityError, or "" for anything else.
+ *
+ * @param err - The caught error.
+ *
+ * @returns string - The error code, or "".
+ */
+function errorCode(err: unknown): string {
+    return err instanceof ParityError ? err.code : '';
+}
+
+export {
+    type ErrorCode,
+    ErrorCodes,
+    ParityError,
+    errorCode,
+};
@@ -0,0 +1,168 @@
+import { sha2Hash, shakeHash, type Sha2, type Shake } from './hash';
+import { bytesToBigInt, concatBytes } from './bytes';
+import { bigModPos } from './numeric';
+import { ParityError } from './errors';
+
+// Prepended to a domain separation tag longer than 255 bytes before it is hashed down
+// (RFC 9380, section 5.3.3).
+const oversizeDstPrefix = new TextEncoder().encode('H2C-OVERSIZE-DST-');
+
+// The largest lenInBytes of expand_message, fixed by its 2-byte length field.
+const maxExpandLen = 65535;
+
+// SHA-2 block sizes in bytes, the length of Z_pad.
+const sha2BlockLen: Record<number, number> = { 256: 64, 384: 128, 512: 128 };
+
+// DST_prime = DST || I2OSP(len(DST), 1), hashing an oversize DST with SHA-2.
+async function xmdDstPrime(dst: Uint8Array, bits: Sha2): Promise<Uint8Array> {
+    if (dst.length > 255) {
+        dst = await sha2Hash(concatBytes(oversizeDstPrefix, dst), bits);
+    }
+    return concatBytes(dst, new Uint8Array([dst.length]));
+}
+
+// DST_prime for expand_message_xof, hashing an oversize DST to 2k bits with SHAKE.
+async function xofDstPrime(dst: Uint8Array, bits: Shake): Promise<Uint8Array> {
+    if (dst.length > 255) {
+        dst = await shakeHash(concatBytes(oversizeDstPrefix, dst), bits, 2 * bits);
+    }
+    return concatBytes(dst, new Uint8Array([dst.length]));
+}
+
+/**
+ * expand_message_xmd (RFC 9380, section 5.3.1) over SHA-2. A DST longer than 255 bytes is
+ * hashed down first.
+ *
+ * @throws {ParityError} - unsupportedBits, or outputLen when lenInBytes is outside [1, 65535]
+ * or more than 255 hash lengths.
+ *
+ * @param msg - The message to expand.
+ * @param dst - The domain separation tag.
+ * @param lenInBytes - The number of uniform bytes to return.
+ * @param bits - The SHA-2 bit length (256, 384, or 512).
+ *
+ * @returns A promise that resolves to lenInBytes uniform bytes.
+ */
+async function expandMessageXmd(msg: Uint8Array, dst: Uint8Array, lenInBytes: number, bits: Sha2): Promise<Uint8Array> {
+    const blockLen = sha2BlockLen[bits];
+    if (blockLen === undefined) {
+        throw new ParityError('ExpandMessageXmd', 'unsupportedBits', { bits });
+    }
+    const hashLen = bits / 8;
+    const ell = Math.ceil(lenInBytes / hashLen);
+    if (!Number.isInteger(lenInBytes) || lenInBytes < 1 || lenInBytes > maxExpandLen || ell > 255) {
+        throw new ParityError('ExpandMessageXmd', 'outputLen', { lenInBytes });
+    }
+    const dstPrime = await xmdDstPrime(dst, bits);
+    const lenField = new Uint8Array([lenInBytes >> 8, lenInBytes & 0xff]);
+
+    // b_0 = H(Z_pad || msg || I2OSP(len_in_bytes, 2) || I2OSP(0, 1) || DST_prime)
+    const b0 = await sha2Hash(concatBytes(new Uint8Array(blockLen), msg, lenField, new Uint8Array([0]), dstPrime), bits);
+
+    // b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime), with b_1 = H(b_0 || 1 || DST_prime)
+    const out = new Uint8Array(ell * hashLen);
+    let prev = new Uint8Array(hashLen);
+    for (let i = 1; i <= ell; i++) {
+        const x = new Uint8Array(hashLen);
+        for (let j = 0; j < hashLen; j++) {
+            x[j] = b0[j] ^ prev[j];
+        }
+        prev = await sha2Hash(concatBytes(x, new Uint8Array([i]), dstPrime), bits);
+        out.set(prev, (i - 1) * hashLen);
+    }
+    return out.slice(0, lenInBytes);
+}
+
+/**
+ * expand_message_xof (RFC 9380, section 5.3.2) over SHAKE128 or SHAKE256. A DST longer than
+ * 255 bytes is hashed down to 2*bits bits first.
+ *
+ * @throws {ParityError} - outputLen when lenInBytes is outside [1, 65535], or unsupportedBits.
+ *
+ * @param msg - The message to expand.
+ * @param dst - The domain separation tag.
+ * @param lenInBytes - The number of uniform bytes to return.
+ * @param bits - The SHAKE bit length (128 or 256).
+ *
+ * @returns A promise that resolves to lenInBytes uniform bytes.
+ */
+async function expandMessageXof(msg: Uint8Array, dst: Uint8Array, lenInBytes: number, bits: Shake): Promise<Uint8Array> {
+    if (!Number.isInteger(lenInBytes) || lenInBytes < 1 || lenInBytes > maxExpandLen) {
+        throw new ParityError('ExpandMessageXof', 'outputLen', { lenInBytes });
+    }
+    if (bits !== 128 && bits !== 256) {
+        throw new ParityError('ExpandMessageXof', 'unsupportedBits', { bits });
+    }
+    const dstPrime = await xofDstPrime(dst, bits);
+    const msgPrime = concatBytes(msg, new Uint8Array([lenInBytes >> 8, lenInBytes & 0xff]), dstPrime);
+    return shakeHash(msgPrime, bits, lenInBytes * 8);
+}
+
+/**
+ * An expand_message function with its hash fixed, as used by hashToField.
+ */
+type Expander = (msg: Uint8Array, dst: Uint8Array, lenInBytes: number) => Promise<Uint8Array>;
+
+/**
+ * Returns expandMessageXmd over SHA-2 with bits 256, 384 or 512.
+ */
+function xmdExpander(bits: Sha2): Expander {
+    return (msg, dst, lenInBytes) => expandMessageXmd(msg, dst, lenInBytes, bits);
+}
+
+/**
+ * Returns expandMessageXof over SHAKE with bits 128 or 256.
+ */
+function xofExpander(bits: Shake): Expander {
+    return (msg, dst, lenInBytes) => expandMessageXof(msg, dst, lenInBytes, bits);
+}
+
+/**
+ * hash_to_field (RFC 9380, section 5.2) for a prime field (m = 1). Each element is reduced
+ * from L = ceil((ceil(log2(modulus)) + k) / 8) expanded bytes, where k is the target
+ * security level in bits. With modulus set to a group order it is also the hash_to_scalar
+ * of protocols such as RFC 9497.
+ *
+ * @throws {ParityError} - invalidArgument for a modulus below 2, or a count or securityBits
+ * below 1.
+ *
+ * @param msg - The message to hash.
+ * @param dst - The domain separation tag.
+ * @param count - The number of field elements to return.
+ * @param modulus - The prime modulus.
+ * @param securityBits - The target security level k in bits.
+ * @param expand - The expand_message function.
+ *
+ * @returns A promise that resolves to count elements of [0, modulus).
+ */
+async function hashToField(
+    msg: Uint8Array,
+    dst: Uint8Array,
+    count: number,
+    modulus: bigint,
+    securityBits: number,
+    expand: Expander,
+): Promise<bigint[]> {
+    if (modulus < 2n) {
+        throw new ParityError('HashToField', 'invalidArgument');
+    }
+    if (count < 1 || securityBits < 1) {
+        throw new ParityError('HashToField', 'invalidArgument', { count, securityBits });
+    }
+    const l = Math.ceil((modulus.toString(2).length + securityBits) / 8);
+    const uniform = await expand(msg, dst, count * l);
+    const out: bigint[] = [];
+    for (let i = 0; i < count; i++) {
+        out.push(bigModPos(bytesToBigInt(uniform.subarray(i * l, (i + 1) * l)), modulus));
+    }
+    return out;
+}
+
+export {
+    type Expander,
+    expandMessageXmd,
+    expandMessageXof,
+    xmdExpander,
+    xofExpander,
+    hashToField,
+};
@@ -0,0 +1,135 @@
+import { ParityError } from './errors';
+import { concatBytes, framedBytesFromUint8Array, framedBytesFromBigInt } from './bytes';
+
+/**
+ * The kinds framedBytesOf accepts, named as in the "type" fields of testdata/parity.json.
+ * Go picks the encoding from the static type; TS takes it explicitly.
+ */
+type FrameKind =
+    | 'bytes' | 'string' | 'bigint' | 'bool'
+    | 'int8' | 'int16' | 'int32' | 'int64'
+    | 'uint8' | 'uint16' | 'uint32' | 'uint64'
+    | 'bytesList';
+
+type FrameValue = Uint8Array | string | bigint | boolean | number | Uint8Array[];
+
+// Byte width and signedness of the fixed-size integer kinds.
+const intKinds: Record<string, [number, boolean]> = {
+    int8: [1, true], int16: [2, true], int32: [4, true], int64: [8, true],
+    uint8: [1, false], uint16: [2, false], uint32: [4, false], uint64: [8, false],
+};
+
+function invalid(): never {
+    throw new ParityError('FramedBytesOf', 'invalidArgument');
+}
+
+/**
+ * Returns the big-endian two's-complement bytes of an integer at a fixed width.
+ */
+function intPayload(value: FrameValue, width: number, signed: boolean): Uint8Array {
+    if (typeof value === 'number' && !Number.isSafeInteger(value)) invalid();
+    if (typeof value !== 'number' && typeof value !== 'bigint') invalid();
+    let v = BigInt(value);
+
+    const bits = BigInt(width * 8);
+    const min = signed ? -(1n << (bits - 1n)) : 0n;
+    const max = signed ? (1n << (bits - 1n)) - 1n : (1n << bits) - 1n;
+    if (v < min || v > max) throw new ParityError('FramedBytesOf', v < 0n && !signed ? 'negative' : 'overflow', { byteLen: width });
+    if (v < 0n) v += 1n << bits;
+
+    const out = new Uint8Array(width);
+    for (let i = width - 1; i >= 0; i--, v >>= 8n) out[i] = Number(v & 0xffn);
+    return out;
+}
+
+/**
+ * Returns the bytes framedBytesOf places after the length prefix, for every kind but bigint.
+ */
+function framePayload(kind: FrameKind, value: FrameValue, lengthPrefixBytes: number): Uint8Array {
+    switch (kind) {
+        case 'bytes':
+            if (!(value instanceof Uint8Array)) invalid();
+            return value;
+        case 'string':
+            if (typeof value !== 'string') invalid();
+            return new TextEncoder().encode(value);
+        case 'bool':
+            if (typeof value !== 'boolean') invalid();
+            return new Uint8Array([value ? 1 : 0]);
+        case 'bytesList':
+            if (!Array.isArray(value) || !value.every(item => item instanceof Uint8Array)) invalid();
+            return concatBytes(...value.map(item => framedBytesFromUint8Array(item, lengthPrefixBytes)));
+    }
+    const int = intKinds[kind];
+    if (!int) invalid();
+    return intPayload(value, int[0], int[1]);
+}
+
+/**
+ * @note This function assumes a big-endian representation.
+ *
+ * Frames a value of the given kind with a length prefix, mirroring Go's util.FramedBytesOf.
+ *  - bytes, string: the bytes, as framedBytesFromUint8Array and framedBytesFromString
+ *  - bigint: [length][sign byte][magnitude], as framedBytesFromBigInt
+ *  - bool: one byte, 0x00 or 0x01
+ *  - int8..int64, uint8..uint64: big-endian two's complement at the kind's width (1, 2, 4 or 8 bytes),
+ *    from a safe-integer number or a bigint
+ *  - bytesList: the concatenated frames of the items, each with the same lengthPrefixBytes
+ *
+ * @param kind - How to encode value.
+ * @param value - The value to frame.
+ * @param lengthPrefixBytes - The number of bytes to use for the length prefix.
+ *
+ * @returns Uint8Array - The framed byte array.
+ *
+ * @throws {ParityError} invalidArgument if value does not match kind, overflow or negative if an
+ * integer does not fit the kind.
+ */
+function framedBytesOf(kind: FrameKind, value: FrameValue, lengthPrefixBytes: number): Uint8Array {
+    if (kind === 'bigint') {
+        if (typeof value !== 'bigint') invalid();
+        return framedBytesFromBigInt(value, lengthPrefixBytes);
+    }
+    return framedBytesFromUint8Array(framePayload(kind, value, lengthPrefixBytes), lengthPrefixBytes);
+}
+
+/**
+ * One field of a framedConcat transcript, created with fieldOf.
+ */
+type FramedField = { readonly kind: FrameKind; readonly value: FrameValue };
+
+/**
+ * Wraps a value for framedConcat. It is encoded exactly like framedBytesOf(kind, value, ...).
+ *
+ * @param kind - How to encode value.
+ * @param value - The value of the field.
+ *
+ * @returns FramedField - The field.
+ */
+function fieldOf(kind: FrameKind, value: FrameValue): FramedField {
+    return { kind, value };
+}
+
+/**
+ * Frames each field with lengthPrefixBytes and concatenates the frames, so that parseFramed
+ * or a FramedReader can split the transcript again.
+ *
+ * @param lengthPrefixBytes - The number of bytes to use for every length prefix.
+ * @param fields - The fields, in order.
+ *
+ * @returns Uint8Array - The concatenated frames.
+ *
+ * @throws {ParityError} as framedBytesOf.
+ */
+function framedConcat(lengthPrefixBytes: number, ...fields: FramedField[]): Uint8Array {
+    return concatBytes(...fields.map(f => framedBytesOf(f.kind, f.value, lengthPrefixBytes)));
+}
+
+export {
+    type FrameKind,
+    type FrameValue,
+    type FramedField,
+    framedBytesOf,
+    fieldOf,
+    framedConcat,
+};
\ No newline at end of file
@@ -8,6 +8,10 @@ import {
     shake256 as nobleShake256,
 } from '@noble/hashes/sha3.js';
 import { cshake128 as nobleCshake128, cshake256 as nobleCshake256 } from '@noble/hashes/sha3-addons.js';
+import { hmac } from '@noble/hashes/hmac.js';
+import { extract as nobleHkdfExtract, expand as nobleHkdfExpand } from '@noble/hashes/hkdf.js';
+import type { CHash } from '@noble/hashes/utils.js';
+import { ParityError } from './errors';
 
 // Keep public types
 type Sha2 = 256 | 384 | 512;
@@ -29,7 +33,7 @@ async function sha2Hash(data: Uint8Array, bits: Sha2): Promise<Uint8Array> {
         case 512:
             return sha512(data);
         default:
-            throw new Error(`Unsupported SHA-2 bit length: ${bits}`);
+            throw new ParityError('Sha2Hash', 'unsupportedBits', { bits });
     }
 }
 
@@ -55,13 +59,30 @@ async function sha3Hash(data: Uint8Array, bits: Sha3): Promise<Uint8Array> {
         case 512:
             return nobleSha3_512(data);
         default:
-            throw new Error(`Unsupported SHA-3 bit length: ${bits}`);
+            throw new ParityError('Sha3Hash', 'unsupportedBits', { bits });
     }
 }
 
 // XOFs
 type Shake = 128 | 256;
 
+/**
+ * Validates an XOF output length in bits for op: it must be a non-negative multiple of 8.
+ *
+ * @throws {ParityError} - outputLen under op.
+ *
+ * @param op - The function name reported in the error.
+ * @param outputLengthInBits - The requested output length in bits.
+ *
+ * @returns number - The output length in bytes.
+ */
+function outputLen(op: string, outputLengthInBits: number): number {
+    if (outputLengthInBits < 0 || outputLengthInBits % 8 !== 0) {
+        throw new ParityError(op, 'outputLen', { outputLenBits: outputLengthInBits });
+    }
+    return outputLengthInBits >>> 3;
+}
+
 /**
  * Computes the SHAKE hash of the given data.
  *
@@ -72,15 +93,14 @@ type Shake = 128 | 256;
  * @returns A promise that resolves to the hash as a Uint8Array.
  */
 async function shakeHash(data: Uint8Array, bits: Shake, outputLengthInBits: number): Promise<Uint8Array> {
-    if (outputLengthInBits % 8 !== 0) throw new Error('outputLengthInBits must be a multiple of 8');
-    const dkLen = outputLengthInBits >>> 3; // bytes
+    const dkLen = outputLen('ShakeHash', outputLengthInBits);
     switch (bits) {
         case 128:
             return nobleShake128(data, { dkLen });
         case 256:
             return nobleShake256(data, { dkLen });
         default:
-            throw new Error(`Unsupported SHAKE bit length: ${bits}`);
+            throw new ParityError('ShakeHash', 'unsupportedBits', { bits });
     }
 }
 
@@ -105,15 +125,17 @@ async function cShakeHash(
     functionName: string,
     customization: string
 ): Promise<Uint8Array> {
-    if (outputLengthInBits % 8 !== 0) throw new Error('outputLengthInBits must be a multiple of 8');
-    const dkLen = outputLengthInBits >>> 3; // bytes
+    const dkLen = outputLen('CShakeHash', outputLengthInBits);
+    if (bits !== 128 && bits !== 256) {
+        throw new ParityError('CShakeHash', 'unsupportedBits', { bits });
+    }
 
     if (functionName === '' && customization === '') {
         return shakeHash(data, bits, outputLengthInBits);
     }
 
     const opts: any = { dkLen, personalization: customization };
-    if (functionName) opts.name = functionName;
+    if (functionName) opts.NISTfn = functionName;
 
     switch (bits) {
         case 128:
@@ -121,8 +143,161 @@ async function cShakeHash(
         case 256:
             return nobleCshake256(data, opts);
         default:
-            throw new Error(`Unsupported cSHAKE bit length: ${bits}`);
+            throw new ParityError('CShakeHash', 'unsupportedBits', { bits });
+    }
+}
+
+// Returns the noble SHA-2 hash for bits, failing like Sha2Hash under op.
+function sha2Of(op: string, bits: Sha2): CHash {
+    switch (bits) {
+        case 256:
+            return sha256;
+        case 384:
+            return sha384;
+        case 512:
+            return sha512;
+        default:
+            throw new ParityError(op, 'unsupportedBits', { bits });
+    }
+}
+
+// Returns the noble SHA-3 hash for bits, failing like Sha3Hash under op.
+function sha3Of(op: string, bits: Sha3): CHash {
+    switch (bits) {
+        case 224:
+            return nobleSha3_224;
+        case 256:
+            return nobleSha3_256;
+        case 384:
+            return nobleSha3_384;
+        case 512:
+            return nobleSha3_512;
+        default:
+            throw new ParityError(op, 'unsupportedBits', { bits });
+    }
+}
+
+/**
+ * Computes HMAC (RFC 2104) over SHA-2.
+ *
+ * @param key - The MAC key.
+ * @param msg - The message to authenticate.
+ * @param bits - The SHA-2 bit length (256, 384, or 512).
+ *
+ * @returns A promise that resolves to the MAC.
+ */
+async function hmacSha2(key: Uint8Array, msg: Uint8Array, bits: Sha2): Promise<Uint8Array> {
+    return hmac(sha2Of('HmacSha2', bits), key, msg);
+}
+
+/**
+ * Computes HMAC (RFC 2104) over SHA-3.
+ *
+ * @param key - The MAC key.
+ * @param msg - The message to authenticate.
+ * @param bits - The SHA-3 bit length (224, 256, 384, or 512).
+ *
+ * @returns A promise that resolves to the MAC.
+ */
+async function hmacSha3(key: Uint8Array, msg: Uint8Array, bits: Sha3): Promise<Uint8Array> {
+    return hmac(sha3Of('HmacSha3', bits), key, msg);
+}
+
+// RFC 5869 expand with Go's length check: at most 255 hash lengths.
+function hkdfExpandWith(op: string, hash: CHash, prk: Uint8Array, info: Uint8Array, length: number, bits: number): Uint8Array {
+    if (!Number.isInteger(length) || length < 0 || length > 255 * hash.outputLen) {
+        throw new ParityError(op, 'outputLen', { length, bits });
     }
+    if (length === 0) return new Uint8Array();
+    return nobleHkdfExpand(hash, prk, info, length);
+}
+
+/**
+ * Computes the RFC 5869 pseudorandom key HMAC-SHA2(salt, ikm). An empty salt is replaced by hash-length zeros.
+ *
+ * @param salt - The optional salt.
+ * @param ikm - The input keying material.
+ * @param bits - The SHA-2 bit length (256, 384, or 512).
+ *
+ * @returns A promise that resolves to the pseudorandom key.
+ */
+async function hkdfExtract(salt: Uint8Array, ikm: Uint8Array, bits: Sha2): Promise<Uint8Array> {
+    return nobleHkdfExtract(sha2Of('HkdfExtract', bits), ikm, salt);
+}
+
+/**
+ * Expands prk into length bytes of output keying material bound to info (RFC 5869).
+ *
+ * @throws {ParityError} - outputLen if length is negative or more than 255 hash lengths.
+ *
+ * @param prk - The pseudorandom key.
+ * @param info - The context string.
+ * @param length - The output length in bytes.
+ * @param bits - The SHA-2 bit length (256, 384, or 512).
+ *
+ * @returns A promise that resolves to the output keying material.
+ */
+async function hkdfExpand(prk: Uint8Array, info: Uint8Array, length: number, bits: Sha2): Promise<Uint8Array> {
+    return hkdfExpandWith('HkdfExpand', sha2Of('HkdfExpand', bits), prk, info, length, bits);
+}
+
+/**
+ * Runs hkdfExtract followed by hkdfExpand.
+ *
+ * @param ikm - The input keying material.
+ * @param salt - The optional salt.
+ * @param info - The context string.
+ * @param length - The output length in bytes.
+ * @param bits - The SHA-2 bit length (256, 384, or 512).
+ *
+ * @returns A promise that resolves to the output keying material.
+ */
+async function hkdf(ikm: Uint8Array, salt: Uint8Array, info: Uint8Array, length: number, bits: Sha2): Promise<Uint8Array> {
+    return hkdfExpand(await hkdfExtract(salt, ikm, bits), info, length, bits);
+}
+
+/**
+ * hkdfExtract over HMAC-SHA3.
+ *
+ * @param salt - The optional salt.
+ * @param ikm - The input keying material.
+ * @param bits - The SHA-3 bit length (224, 256, 384, or 512).
+ *
+ * @returns A promise that resolves to the pseudorandom key.
+ */
+async function hkdfSha3Extract(salt: Uint8Array, ikm: Uint8Array, bits: Sha3): Promise<Uint8Array> {
+    return nobleHkdfExtract(sha3Of('HkdfSha3Extract', bits), ikm, salt);
+}
+
+/**
+ * hkdfExpand over HMAC-SHA3.
+ *
+ * @throws {ParityError} - outputLen if length is negative or more than 255 hash lengths.
+ *
+ * @param prk - The pseudorandom key.
+ * @param info - The context string.
+ * @param length - The output length in bytes.
+ * @param bits - The SHA-3 bit length (224, 256, 384, or 512).
+ *
+ * @returns A promise that resolves to the output keying material.
+ */
+async function hkdfSha3Expand(prk: Uint8Array, info: Uint8Array, length: number, bits: Sha3): Promise<Uint8Array> {
+    return hkdfExpandWith('HkdfSha3Expand', sha3Of('HkdfSha3Expand', bits), prk, info, length, bits);
+}
+
+/**
+ * Runs hkdfSha3Extract followed by hkdfSha3Expand.
+ *
+ * @param ikm - The input keying material.
+ * @param salt - The optional salt.
+ * @param info - The context string.
+ * @param length - The output length in bytes.
+ * @param bits - The SHA-3 bit length (224, 256, 384, or 512).
+ *
+ * @returns A promise that resolves to the output keying material.
+ */
+async function hkdfSha3(ikm: Uint8Array, salt: Uint8Array, info: Uint8Array, length: number, bits: Sha3): Promise<Uint8Array> {
+    return hkdfSha3Expand(await hkdfSha3Extract(salt, ikm, bits), info, length, bits);
 }
 
 export {
@@ -134,4 +309,13 @@ export {
     sha3Hash,
     shakeHash,
     cShakeHash,
+    outputLen,
+    hmacSha2,
+    hmacSha3,
+    hkdfExtract,
+    hkdfExpand,
+    hkdf,
+    hkdfSha3Extract,
+    hkdfSha3Expand,
+    hkdfSha3,
 };
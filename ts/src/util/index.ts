@@ -1,4 +1,8 @@
 export * from './bytes';
+export * from './frame';
 export * from './coding';
 export * from './numeric';
-export * from './hash';
\ No newline at end of file
+export * from './hash';
+export * from './errors';
+export * from './sp800185';
+export * from './expand';
\ No newline at end of file
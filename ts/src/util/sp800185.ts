@@ -0,0 +1,175 @@
+import { cShakeHash, outputLen, type CShake } from './hash';
+import { concatBytes } from './bytes';
+import { ParityError } from './errors';
+
+// SP 800-185 left_encode: the byte length of x followed by x big-endian.
+function leftEncode(x: number): Uint8Array {
+    const bytes: number[] = [];
+    let v = BigInt(x);
+    do {
+        bytes.unshift(Number(v & 0xffn));
+        v >>= 8n;
+    } while (v > 0n);
+    return new Uint8Array([bytes.length, ...bytes]);
+}
+
+// SP 800-185 right_encode: x big-endian followed by its byte length.
+function rightEncode(x: number): Uint8Array {
+    const l = leftEncode(x);
+    return concatBytes(l.subarray(1), l.subarray(0, 1));
+}
+
+// SP 800-185 encode_string: left_encode of the bit length, then s.
+function encodeString(s: Uint8Array): Uint8Array {
+    return concatBytes(leftEncode(s.length * 8), s);
+}
+
+// Prefixes x with left_encode(w) and zero-pads the result to a multiple of w bytes.
+function bytepad(x: Uint8Array, w: number): Uint8Array {
+    const out = concatBytes(leftEncode(w), x);
+    const r = out.length % w;
+    return r === 0 ? out : concatBytes(out, new Uint8Array(w - r));
+}
+
+function checkBits(op: string, bits: number): void {
+    if (bits !== 128 && bits !== 256) throw new ParityError(op, 'unsupportedBits', { bits });
+}
+
+async function kmacWith(op: string, key: Uint8Array, data: Uint8Array, bits: CShake, outputLenBits: number, outBits: number, customization: string): Promise<Uint8Array> {
+    outputLen(op, outputLenBits);
+    checkBits(op, bits);
+    const rate = bits === 128 ? 168 : 136;
+    const input = concatBytes(bytepad(encodeString(key), rate), data, rightEncode(outBits));
+    return cShakeHash(input, bits, outputLenBits, 'KMAC', customization);
+}
+
+/**
+ * Computes KMAC128 or KMAC256 (SP 800-185). The output length is bound into the MAC.
+ *
+ * @throws {ParityError} - outputLen or unsupportedBits.
+ *
+ * @param key - The MAC key.
+ * @param data - The message.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the MAC.
+ */
+async function kmac(key: Uint8Array, data: Uint8Array, bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return kmacWith('Kmac', key, data, bits, outputLenBits, outputLenBits, customization);
+}
+
+/**
+ * Computes KMACXOF128 or KMACXOF256 (SP 800-185). Shorter outputs are prefixes of longer ones.
+ *
+ * @throws {ParityError} - outputLen or unsupportedBits.
+ *
+ * @param key - The MAC key.
+ * @param data - The message.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the MAC.
+ */
+async function kmacXof(key: Uint8Array, data: Uint8Array, bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return kmacWith('KmacXof', key, data, bits, outputLenBits, 0, customization);
+}
+
+async function tupleHashWith(op: string, items: Uint8Array[], bits: CShake, outputLenBits: number, outBits: number, customization: string): Promise<Uint8Array> {
+    outputLen(op, outputLenBits);
+    checkBits(op, bits);
+    const input = concatBytes(...items.map(encodeString), rightEncode(outBits));
+    return cShakeHash(input, bits, outputLenBits, 'TupleHash', customization);
+}
+
+/**
+ * Computes TupleHash128 or TupleHash256 (SP 800-185) over a list of byte strings, so item boundaries are part of
+ * the input.
+ *
+ * @throws {ParityError} - outputLen or unsupportedBits.
+ *
+ * @param items - The byte strings to hash.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the hash.
+ */
+async function tupleHash(items: Uint8Array[], bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return tupleHashWith('TupleHash', items, bits, outputLenBits, outputLenBits, customization);
+}
+
+/**
+ * Computes TupleHashXOF128 or TupleHashXOF256 (SP 800-185) over a list of byte strings.
+ *
+ * @throws {ParityError} - outputLen or unsupportedBits.
+ *
+ * @param items - The byte strings to hash.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the hash.
+ */
+async function tupleHashXof(items: Uint8Array[], bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return tupleHashWith('TupleHashXof', items, bits, outputLenBits, 0, customization);
+}
+
+async function parallelHashWith(op: string, data: Uint8Array, blockSize: number, bits: CShake, outputLenBits: number, outBits: number, customization: string): Promise<Uint8Array> {
+    outputLen(op, outputLenBits);
+    checkBits(op, bits);
+    if (!Number.isInteger(blockSize) || blockSize <= 0) throw new ParityError(op, 'invalidArgument', { blockSize });
+    const parts: Uint8Array[] = [leftEncode(blockSize)];
+    let n = 0;
+    for (let off = 0; off < data.length; off += blockSize) {
+        parts.push(await cShakeHash(data.subarray(off, off + blockSize), bits, bits * 2, '', ''));
+        n++;
+    }
+    parts.push(rightEncode(n), rightEncode(outBits));
+    return cShakeHash(concatBytes(...parts), bits, outputLenBits, 'ParallelHash', customization);
+}
+
+/**
+ * Computes ParallelHash128 or ParallelHash256 (SP 800-185) over data split into blockSize-byte blocks.
+ *
+ * @throws {ParityError} - outputLen, unsupportedBits, or invalidArgument if blockSize is not positive.
+ *
+ * @param data - The message.
+ * @param blockSize - The block size in bytes.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the hash.
+ */
+async function parallelHash(data: Uint8Array, blockSize: number, bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return parallelHashWith('ParallelHash', data, blockSize, bits, outputLenBits, outputLenBits, customization);
+}
+
+/**
+ * Computes ParallelHashXOF128 or ParallelHashXOF256 (SP 800-185) over data split into blockSize-byte blocks.
+ *
+ * @throws {ParityError} - outputLen, unsupportedBits, or invalidArgument if blockSize is not positive.
+ *
+ * @param data - The message.
+ * @param blockSize - The block size in bytes.
+ * @param bits - The capacity (128 or 256).
+ * @param outputLenBits - The output length in bits, a multiple of 8.
+ * @param customization - The customization string S.
+ *
+ * @returns A promise that resolves to the hash.
+ */
+async function parallelHashXof(data: Uint8Array, blockSize: number, bits: CShake, outputLenBits: number, customization: string): Promise<Uint8Array> {
+    return parallelHashWith('ParallelHashXof', data, blockSize, bits, outputLenBits, 0, customization);
+}
+
+export {
+    kmac,
+    kmacXof,
+    tupleHash,
+    tupleHashXof,
+    parallelHash,
+    parallelHashXof,
+};
@@ -0,0 +1,30 @@
+import { describe, it, expect } from 'vitest';
+import { prependLen } from '../../src/cpace/cpace';
+import { Suites, newInitiator } from '../../src/cpace';
+
+const enc = new TextEncoder();
+
+describe('cpace', () => {
+  it('prefixes LEB128 lengths', () => {
+    for (const [n, prefix] of [[0, [0x00]], [1, [0x01]], [127, [0x7f]], [128, [0x80, 0x01]], [300, [0xac, 0x02]]] as const) {
+      expect(Array.from(prependLen(new Uint8Array(n)).subarray(0, prefix.length))).toEqual(prefix);
+    }
+  });
+
+  it('rejects unknown suites', async () => {
+    await expect(newInitiator({ suite: 'CPACE-P384' }, enc.encode('pw'))).rejects.toThrowError('cpace: unsupported suite');
+  });
+
+  it('runs each step once and in order', async () => {
+    const a = await newInitiator({ suite: Suites.Ristretto255Sha512 }, enc.encode('pw'));
+    await expect(a.finish(new Uint8Array())).rejects.toThrowError('cpace: state machine used out of order or reused');
+    a.start();
+    expect(() => a.start()).toThrowError('cpace: state machine');
+  });
+
+  it('rejects malformed messages', async () => {
+    const a = await newInitiator({ suite: Suites.P256Sha256 }, enc.encode('pw'));
+    a.start();
+    await expect(a.finish(new Uint8Array([0, 1, 0]))).rejects.toThrowError('cpace: malformed message or invalid element');
+  });
+});
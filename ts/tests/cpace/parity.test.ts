@@ -0,0 +1,64 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import { Ristretto255, P256, decodeUncompressed } from '../../src/group';
+import { Params } from '../../src/cpace/cpace';
+import { CpaceError, Suites, newInitiator } from '../../src/cpace';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+// The TS initiator against the Go responder's recorded msg2.
+describe('parity: cpace', () => {
+    for (const tc of (vectors as any).cpace.vectors) {
+        const cfg = { suite: tc.suite, ci: unhex(tc.ci), sid: unhex(tc.sid) };
+        const g = tc.suite === Suites.P256Sha256 ? P256 : Ristretto255;
+        const ext = tc.localExtension;
+
+        it(`${tc.name} key schedule`, async () => {
+            const p = new Params(cfg);
+            const prs = unhex(tc.prs);
+            expect(hex(p.generatorString(prs))).toEqual(tc.generatorString);
+            const gen = await p.generator(prs);
+            const ya = g.decodeScalar(unhex(tc.secretA));
+            const yb = g.decodeScalar(unhex(tc.secretB));
+            const publicA = decodeUncompressed(g, unhex(tc.publicA));
+            const publicB = decodeUncompressed(g, unhex(tc.publicB));
+            expect(publicA.equal(gen.scalarMult(ya))).toBe(true);
+            expect(publicB.equal(gen.scalarMult(yb))).toBe(true);
+            const k = p.sharedSecret(ya, publicB);
+            expect(hex(k)).toEqual(tc.K);
+            const ks = await p.keySchedule(k, unhex(tc.publicA), unhex(tc.adA), unhex(tc.publicB), unhex(tc.adB));
+            expect(hex(ks.isk)).toEqual(tc.isk);
+            expect(hex(ks.kcA)).toEqual(ext.KcA);
+            expect(hex(ks.kcB)).toEqual(ext.KcB);
+            expect(hex(ks.macA)).toEqual(ext.macA);
+            expect(hex(ks.macB)).toEqual(ext.macB);
+            expect(hex(ks.sessionKey)).toEqual(ext.sessionKey);
+            if (tc.draft) {
+                expect(hex(p.sharedSecret(ya, p.decodeShare(unhex(tc.draft.publicB))))).toEqual(tc.draft.K);
+                expect(hex(p.sharedSecret(yb, p.decodeShare(unhex(tc.draft.publicA))))).toEqual(tc.draft.K);
+            }
+        });
+
+        it(`${tc.name} initiator`, async () => {
+            const a = await newInitiator(cfg, unhex(tc.prs), unhex(tc.adA));
+            expect(hex(a.startWithScalar(g.decodeScalar(unhex(tc.secretA))))).toEqual(ext.msg1);
+            const [msg3, key] = await a.finish(unhex(ext.msg2));
+            expect(hex(msg3)).toEqual(ext.msg3);
+            expect(hex(key)).toEqual(ext.sessionKey);
+            expect(hex(a.peerAD() as Uint8Array)).toEqual(tc.adB);
+        });
+
+        it(`${tc.name} initiator with a wrong password`, async () => {
+            const a = await newInitiator(cfg, new TextEncoder().encode('wrong'), unhex(tc.adA));
+            a.startWithScalar(g.decodeScalar(unhex(tc.secretA)));
+            await expect(a.finish(unhex(ext.msg2))).rejects.toThrowError(CpaceError);
+        });
+    }
+});
@@ -0,0 +1,38 @@
+import { describe, it, expect } from 'vitest';
+import { P256, P384, Secp256k1, Ristretto255, GroupError, marshalUncompressed, decodeUncompressed, type Group } from '../../src/group';
+
+const backends: Group[] = [P256, P384, Secp256k1, Ristretto255];
+
+describe('group conformance', () => {
+  for (const g of backends) {
+    it(`${g.name()} element and scalar laws`, () => {
+      const a = g.randomScalar();
+      const b = g.randomScalar();
+      const G = g.generator();
+      expect(G.scalarMult(a).add(G.scalarMult(b)).equal(G.scalarMult(a.add(b)))).toBe(true);
+      expect(G.scalarMult(a).subtract(G.scalarMult(a)).isIdentity()).toBe(true);
+      expect(G.add(G.negate()).isIdentity()).toBe(true);
+      expect(G.scalarMult(g.newScalar(g.order())).isIdentity()).toBe(true);
+      expect(a.multiply(a.invert()).equal(g.newScalar(1n))).toBe(true);
+      expect(g.decodeScalar(a.toBytes()).equal(a)).toBe(true);
+      expect(a.toBytes().length).toEqual(g.scalarSize());
+      const e = G.scalarMult(b);
+      expect(e.toBytes().length).toEqual(g.elementSize());
+      expect(g.decodeElement(e.toBytes()).equal(e)).toBe(true);
+      expect(decodeUncompressed(g, marshalUncompressed(e)).equal(e)).toBe(true);
+    });
+  }
+
+  it('rejects a zero inverse and mixed groups', () => {
+    expect(() => P256.newScalar(0n).invert()).toThrowError('group: zero scalar has no inverse');
+    expect(() => P256.generator().add(P384.generator())).toThrowError(GroupError);
+    expect(() => Ristretto255.generator().scalarMult(P256.newScalar(1n))).toThrowError('group: operands belong to different groups');
+  });
+
+  it('rejects malformed points', () => {
+    const bad = new Uint8Array(33).fill(0xff);
+    bad[0] = 0x02;
+    expect(() => P256.decodeElement(bad)).toThrowError('group: invalid element encoding');
+    expect(() => decodeUncompressed(P256, new Uint8Array(65).fill(4, 0, 1))).toThrowError(GroupError);
+  });
+});
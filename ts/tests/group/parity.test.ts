@@ -0,0 +1,124 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import {
+    GroupError, P256, P384, Secp256k1, Ristretto255, ristrettoElementFromUniformBytes, ristrettoScalarFromUniformBytes,
+    type Group, type Scalar,
+} from '../../src/group';
+import { encUrlSafe, decUrlSafe } from '../../src/util/coding';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+const backends: Group[] = [P256, P384, Secp256k1, Ristretto255];
+
+function backendByName(name: string): Group {
+    const g = backends.find(b => b.name() === name);
+    if (!g) throw new Error(`no backend named ${name}`);
+    return g;
+}
+
+function codeOf(f: () => unknown): string | undefined {
+    try {
+        f();
+    } catch (err) {
+        expect(err).toBeInstanceOf(GroupError);
+        return (err as GroupError).code;
+    }
+    return undefined;
+}
+
+const r = (vectors as any).group.ristretto255;
+
+describe('parity: ristretto255 multiples', () => {
+    it('k*G by addition and by scalar multiplication', () => {
+        let acc = Ristretto255.identity();
+        for (const tc of r.multiples) {
+            expect(hex(acc.toBytes())).toEqual(tc.hex);
+            const e = Ristretto255.generator().scalarMult(Ristretto255.newScalar(BigInt(tc.k)));
+            expect(encUrlSafe(e.toBytes())).toEqual(tc.urlSafe);
+            expect(Ristretto255.decodeElement(decUrlSafe(tc.urlSafe)).equal(acc)).toBe(true);
+            acc = acc.add(Ristretto255.generator());
+        }
+    });
+    for (const enc of r.badEncodings) {
+        it(`rejects ${enc}`, () => {
+            expect(codeOf(() => Ristretto255.decodeElement(unhex(enc)))).toEqual('invalidElement');
+        });
+    }
+});
+
+describe('parity: ristretto255 hashing', () => {
+    for (const tc of r.fromUniformBytes) {
+        it(tc.label, () => {
+            expect(hex(ristrettoElementFromUniformBytes(unhex(tc.uniform)).toBytes())).toEqual(tc.element);
+        });
+    }
+    for (const tc of r.hashToElement) {
+        it(`hashToElement ${tc.msg}`, async () => {
+            const e = await Ristretto255.hashToElement(unhex(tc.msg), unhex(tc.dst));
+            expect(hex(e.scalarMult(Ristretto255.decodeScalar(unhex(tc.scalar))).toBytes())).toEqual(tc.product);
+        });
+    }
+    for (const tc of r.hashToScalar) {
+        it(`hashToScalar ${tc.dst}`, async () => {
+            expect(hex((await Ristretto255.hashToScalar(unhex(tc.msg), unhex(tc.dst))).toBytes())).toEqual(tc.scalar);
+        });
+    }
+});
+
+describe('parity: ristretto255 scalars', () => {
+    for (const tc of r.scalarOps) {
+        it(tc.op, () => {
+            const a = Ristretto255.decodeScalar(unhex(tc.a));
+            const b = () => Ristretto255.decodeScalar(unhex(tc.b));
+            const ops: Record<string, () => Scalar> = {
+                add: () => a.add(b()),
+                subtract: () => a.subtract(b()),
+                multiply: () => a.multiply(b()),
+                negate: () => a.negate(),
+                invert: () => a.invert(),
+            };
+            expect(ops[tc.op]).toBeDefined();
+            expect(hex(ops[tc.op]().toBytes())).toEqual(tc.out);
+        });
+    }
+    for (const tc of r.scalarFromUniformBytes) {
+        it(`fromUniformBytes ${tc.uniform.slice(0, 16)}`, () => {
+            expect(hex(ristrettoScalarFromUniformBytes(unhex(tc.uniform)).toBytes())).toEqual(tc.scalar);
+        });
+    }
+    for (const enc of r.badScalars) {
+        it(`rejects scalar ${enc}`, () => {
+            expect(codeOf(() => Ristretto255.decodeScalar(unhex(enc)))).toEqual('invalidScalar');
+        });
+    }
+});
+
+describe('parity: group backends', () => {
+    for (const tc of (vectors as any).group.backends) {
+        const g = backendByName(tc.group);
+        it(`${tc.group} generator and identity`, () => {
+            for (const [e, enc] of [[g.generator(), tc.generator], [g.identity(), tc.identity]] as const) {
+                expect(hex(e.toBytes())).toEqual(enc);
+                expect(g.decodeElement(unhex(enc)).equal(e)).toBe(true);
+            }
+        });
+        for (const h of tc.hashToElement) {
+            it(`${tc.group} hashToElement ${h.msg}`, async () => {
+                const e = await g.hashToElement(unhex(h.msg), unhex(h.dst));
+                expect(hex(e.scalarMult(g.decodeScalar(unhex(h.scalar))).toBytes())).toEqual(h.product);
+            });
+        }
+        for (const h of tc.hashToScalar) {
+            it(`${tc.group} hashToScalar ${h.dst}`, async () => {
+                expect(hex((await g.hashToScalar(unhex(h.msg), unhex(h.dst))).toBytes())).toEqual(h.scalar);
+            });
+        }
+    }
+});
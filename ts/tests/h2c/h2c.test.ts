@@ -0,0 +1,15 @@
+import { describe, it, expect } from 'vitest';
+import { hashToCurve, hashToField, mapToCurve, H2cError, P256, Suites } from '../../src/h2c';
+
+describe('h2c errors', () => {
+  it('rejects an unknown suite', async () => {
+    await expect(hashToCurve('P256_XMD:SHA-256_SSWU_XX_', new Uint8Array(), new Uint8Array())).rejects.toThrowError(H2cError);
+    await expect(hashToField('', new Uint8Array(), new Uint8Array(), 1)).rejects.toThrowError('h2c: unsupported suite');
+  });
+
+  it('rejects a field element outside [0, p)', () => {
+    for (const u of [-1n, P256.p]) {
+      expect(() => mapToCurve(Suites.P256NU, u)).toThrowError('h2c: field element is not in [0, p)');
+    }
+  });
+});
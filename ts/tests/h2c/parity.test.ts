@@ -0,0 +1,29 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import { hashToField, mapToCurve, hashToCurve, isRandomOracle, type Point } from '../../src/h2c';
+
+const enc = new TextEncoder();
+
+function coord(v: bigint): string {
+    return v.toString(16);
+}
+function want(p: { x: string; y: string }): { x: string; y: string } {
+    return { x: BigInt('0x' + p.x).toString(16), y: BigInt('0x' + p.y).toString(16) };
+}
+function got(p: Point): { x: string; y: string } {
+    return { x: coord(p.x), y: coord(p.y) };
+}
+
+describe('parity: h2c', () => {
+    for (const tc of (vectors as any).h2c.suites) {
+        it(`${tc.suite} ${JSON.stringify(tc.msg.slice(0, 16))}`, async () => {
+            expect(isRandomOracle(tc.suite)).toEqual(tc.q.length === 2);
+            const u = await hashToField(tc.suite, enc.encode(tc.msg), enc.encode(tc.dst), tc.u.length);
+            expect(u).toEqual(tc.u.map((h: string) => BigInt('0x' + h)));
+            tc.q.forEach((q: { x: string; y: string }, i: number) => {
+                expect(got(mapToCurve(tc.suite, u[i]))).toEqual(want(q));
+            });
+            expect(got(await hashToCurve(tc.suite, enc.encode(tc.msg), enc.encode(tc.dst)))).toEqual(want(tc.p));
+        });
+    }
+});
@@ -0,0 +1,29 @@
+import { describe, it, expect } from 'vitest';
+import { Curves, KeysError, normalizePublicKey, compressPublicKey, publicKeyThumbprint, didKey } from '../../src/keys';
+
+function unhex(s: string): Uint8Array {
+  const out = new Uint8Array(s.length / 2);
+  for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+  return out;
+}
+
+// RFC 7517 appendix A.2 P-256 key.
+const full = unhex('0430a0424cd21c2944838a2d75c92b37e76ea20d9f00893a3b4eee8a3c0aafec3ee04b65e92456d9888b52b379bdfbd51ee869ef1f0fc65b6659695b6cce081723');
+
+describe('keys', () => {
+  it('expands compressed points', async () => {
+    const comp = compressPublicKey(Curves.P256, full);
+    expect(comp.length).toEqual(33);
+    expect(normalizePublicKey(Curves.P256, comp)).toEqual(full);
+    expect(didKey(Curves.P256, comp)).toEqual(didKey(Curves.P256, full));
+    expect(await publicKeyThumbprint(Curves.P256, comp, 256)).toEqual(await publicKeyThumbprint(Curves.P256, full, 256));
+  });
+
+  it('rejects points off the curve', () => {
+    const bad = full.slice();
+    bad[bad.length - 1] ^= 1;
+    expect(() => normalizePublicKey(Curves.P256, bad)).toThrowError(KeysError);
+    expect(() => normalizePublicKey(Curves.Ed25519, full)).toThrowError('keys: invalid key');
+    expect(() => normalizePublicKey('P-192', full)).toThrowError('keys: unsupported curve');
+  });
+});
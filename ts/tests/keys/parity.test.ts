@@ -0,0 +1,48 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import {
+    KeysError, publicKeyToJWK, jwkThumbprintInput, publicKeyThumbprint, multicodec, didKey, parseDidKey,
+} from '../../src/keys';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+describe('parity: keys thumbprint', () => {
+    for (const tc of (vectors as any).keys.thumbprint) {
+        it(`${tc.name} sha${tc.hashBits}`, async () => {
+            const jwk = publicKeyToJWK(tc.curve, unhex(tc.publicKey));
+            expect(jwkThumbprintInput(jwk)).toEqual(tc.input);
+            expect(await publicKeyThumbprint(tc.curve, unhex(tc.publicKey), tc.hashBits)).toEqual(tc.thumbprint);
+        });
+    }
+});
+
+describe('parity: keys didKey', () => {
+    for (const tc of (vectors as any).keys.didKey) {
+        it(tc.name, () => {
+            expect(hex(multicodec(tc.curve, unhex(tc.publicKey)))).toEqual(tc.multicodec);
+            expect(didKey(tc.curve, unhex(tc.publicKey))).toEqual(tc.didKey);
+            const [curve, pub] = parseDidKey(tc.didKey);
+            expect(curve).toEqual(tc.curve);
+            expect(hex(pub)).toEqual(tc.publicKey);
+        });
+    }
+    for (const tc of (vectors as any).keys.didKeyDecode) {
+        it(`rejects ${tc.name}`, () => {
+            let caught: unknown;
+            try {
+                parseDidKey(tc.didKey);
+            } catch (err) {
+                caught = err;
+            }
+            expect(caught).toBeInstanceOf(KeysError);
+            expect((caught as KeysError).code).toEqual(tc.error);
+        });
+    }
+});
@@ -0,0 +1,31 @@
+import { describe, it, expect } from 'vitest';
+import { Suites } from '../../src/oprf';
+import { ClientLogin, ClientRegistration, KE2, identityKSF, parseKE1, parseKE3 } from '../../src/opaque';
+
+const enc = new TextEncoder();
+const cfg = { suite: Suites.Ristretto255Sha512, ksf: identityKSF };
+
+describe('opaque client', () => {
+  it('rejects unknown suites', () => {
+    expect(() => new ClientLogin({ suite: 'P384-SHA384' })).toThrowError('opaque: unsupported suite');
+  });
+
+  it('runs each step once and in order', async () => {
+    const reg = new ClientRegistration(cfg);
+    await reg.start(enc.encode('pw'));
+    await expect(reg.start(enc.encode('pw'))).rejects.toThrowError('opaque: state machine used out of order or reused');
+
+    const login = new ClientLogin(cfg);
+    const empty = new Uint8Array();
+    await expect(login.finish(new KE2(empty, empty, empty, empty, empty, empty))).rejects.toThrowError('opaque: state machine');
+  });
+
+  it('rejects malformed messages', async () => {
+    const login = new ClientLogin(cfg);
+    await login.start(enc.encode('pw'));
+    const empty = new Uint8Array();
+    await expect(login.finish(new KE2(empty, empty, empty, empty, empty, empty))).rejects.toThrowError('opaque: malformed message');
+    expect(() => parseKE1(new Uint8Array([0, 1]))).toThrowError('opaque: malformed message');
+    expect(() => parseKE3(new Uint8Array([0, 1, 7, 0, 0]))).toThrowError('opaque: malformed message');
+  });
+});
@@ -0,0 +1,80 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import { decodeScalar } from '../../src/oprf';
+import {
+    ClientLogin,
+    ClientRegistration,
+    OpaqueError,
+    identityKSF,
+    parseKE2,
+    parseRegistrationResponse,
+    type Entropy,
+} from '../../src/opaque';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+// Replays the blinds, nonces and seeds of a vector in protocol order.
+function fixedEntropy(blinds: string[], values: string[]): Entropy {
+    return {
+        bytes(n: number): Uint8Array {
+            const b = unhex(values.shift() as string);
+            if (b.length !== n) throw new Error('fixed entropy: wrong length');
+            return b;
+        },
+        blind: (suite: string) => decodeScalar(suite, unhex(blinds.shift() as string)),
+    };
+}
+
+// The client side of every vector, reading the Go server's messages from their wire
+// encodings as a TS client receives them.
+describe('parity: opaque', () => {
+    for (const tc of (vectors as any).opaque.vectors) {
+        const cfg = { suite: tc.suite, context: unhex(tc.context), ksf: identityKSF };
+        const password = unhex(tc.password);
+        const serverIdentity = unhex(tc.serverIdentity);
+        const clientIdentity = unhex(tc.clientIdentity);
+
+        it(`${tc.name} registration`, async () => {
+            const reg = new ClientRegistration(cfg, fixedEntropy([tc.blindRegistration], [tc.envelopeNonce]));
+            const req = await reg.start(password);
+            expect(hex(req.blindedMessage)).toEqual(tc.registrationRequest);
+            expect(hex(req.toBytes())).toEqual(tc.wire.registrationRequest);
+
+            const resp = parseRegistrationResponse(unhex(tc.wire.registrationResponse));
+            expect(hex(resp.serialize())).toEqual(tc.registrationResponse);
+            const [record, exportKey] = await reg.finish(resp, serverIdentity, clientIdentity);
+            expect(hex(record.serialize())).toEqual(tc.registrationRecord);
+            expect(hex(record.toBytes())).toEqual(tc.wire.registrationRecord);
+            expect(hex(exportKey)).toEqual(tc.exportKey);
+        });
+
+        it(`${tc.name} login`, async () => {
+            const login = new ClientLogin(cfg, fixedEntropy([tc.blindLogin], [tc.clientNonce, tc.clientKeyshareSeed]));
+            const ke1 = await login.start(password);
+            expect(hex(ke1.serialize())).toEqual(tc.ke1);
+            expect(hex(ke1.toBytes())).toEqual(tc.wire.ke1);
+
+            const ke2 = parseKE2(unhex(tc.wire.ke2));
+            expect(hex(ke2.serialize())).toEqual(tc.ke2);
+            const [ke3, sessionKey, exportKey] = await login.finish(ke2, serverIdentity, clientIdentity);
+            expect(hex(ke3.clientMAC)).toEqual(tc.ke3);
+            expect(hex(ke3.toBytes())).toEqual(tc.wire.ke3);
+            expect(hex(sessionKey)).toEqual(tc.sessionKey);
+            expect(hex(exportKey)).toEqual(tc.exportKey);
+        });
+
+        it(`${tc.name} login with a wrong password`, async () => {
+            const login = new ClientLogin(cfg, fixedEntropy([tc.blindLogin], [tc.clientNonce, tc.clientKeyshareSeed]));
+            await login.start(new TextEncoder().encode('wrong password'));
+            await expect(login.finish(parseKE2(unhex(tc.wire.ke2)), serverIdentity, clientIdentity))
+                .rejects.toThrowError(OpaqueError);
+        });
+    }
+});
@@ -0,0 +1,25 @@
+import { describe, it, expect } from 'vitest';
+import { Client, Modes, Suites, OprfError, deriveKeyPair } from '../../src/oprf';
+
+const enc = new TextEncoder();
+
+describe('oprf client', () => {
+  it('rejects unknown suites and modes', () => {
+    expect(() => new Client('P384-SHA384', Modes.OPRF)).toThrowError('oprf: unsupported suite');
+    expect(() => new Client(Suites.Ristretto255Sha512, 3)).toThrowError('oprf: unsupported mode');
+    expect(() => new Client(Suites.P256Sha256, Modes.VOPRF)).toThrowError('oprf: invalid element encoding');
+  });
+
+  it('rejects info outside POPRF mode and empty batches', async () => {
+    const client = new Client(Suites.Ristretto255Sha512, Modes.OPRF);
+    await expect(client.blind([enc.encode('x')], enc.encode('info'))).rejects.toThrowError(OprfError);
+    await expect(client.blind([])).rejects.toThrowError('oprf: input hashes to the identity or is malformed');
+  });
+
+  it('requires a proof in VOPRF mode', async () => {
+    const [, pk] = await deriveKeyPair(Suites.Ristretto255Sha512, Modes.VOPRF, new Uint8Array(32), new Uint8Array());
+    const client = new Client(Suites.Ristretto255Sha512, Modes.VOPRF, pk);
+    const st = await client.blind([enc.encode('x')]);
+    await expect(client.finalize(st, st.blindedElements)).rejects.toThrowError('oprf: proof verification failed');
+  });
+});
@@ -0,0 +1,52 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import { Client, OprfError, Proof, decodeElement, decodeScalar, deriveKeyPair, parseProof } from '../../src/oprf';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+describe('parity: oprf', () => {
+    for (const s of (vectors as any).oprf.suites) {
+        it(`${s.suite}/${s.mode} deriveKeyPair`, async () => {
+            const [sk, pk] = await deriveKeyPair(s.suite, s.mode, unhex(s.seed), unhex(s.keyInfo));
+            expect(hex(sk.toBytes())).toEqual(s.skSm);
+            if (s.pkSm !== '') {
+                expect(hex(pk.toBytes())).toEqual(s.pkSm);
+            }
+        });
+        s.vectors.forEach((tc: any, i: number) => {
+            it(`${s.suite}/${s.mode}/${i} client`, async () => {
+                const client = new Client(s.suite, s.mode, s.pkSm === '' ? undefined : decodeElement(s.suite, unhex(s.pkSm)));
+                const inputs = tc.input.map(unhex);
+                const blinds = tc.blind.map((b: string) => decodeScalar(s.suite, unhex(b)));
+                const st = await client.blindWithScalars(inputs, unhex(tc.info), blinds);
+                expect(st.blindedElements.map(e => hex(e.toBytes()))).toEqual(tc.blindedElement);
+
+                const evaluated = tc.evaluationElement.map((e: string) => decodeElement(s.suite, unhex(e)));
+                const proof = tc.proof === '' ? undefined : parseProof(s.suite, unhex(tc.proof));
+                if (proof) {
+                    expect(hex(proof.toBytes())).toEqual(tc.proof);
+                }
+                const outputs = await client.finalize(st, evaluated, proof);
+                expect(outputs.map(hex)).toEqual(tc.output);
+
+                if (proof) {
+                    let caught: unknown;
+                    try {
+                        await client.finalize(st, evaluated, new Proof(proof.s, proof.c));
+                    } catch (err) {
+                        caught = err;
+                    }
+                    expect(caught).toBeInstanceOf(OprfError);
+                    expect((caught as OprfError).code).toEqual('verify');
+                }
+            });
+        });
+    }
+});
@@ -0,0 +1,41 @@
+import { describe, it, expect } from 'vitest';
+import vectors from '../../../testdata/parity.json';
+import { P256, Ristretto255 } from '../../src/group';
+import { Initiator, Spake2Error, Suites, newInitiator } from '../../src/spake2';
+
+function hex(buf: Uint8Array): string {
+    return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+function unhex(s: string): Uint8Array {
+    const out = new Uint8Array(s.length / 2);
+    for (let i = 0; i < s.length; i += 2) out[i / 2] = parseInt(s.slice(i, i + 2), 16);
+    return out;
+}
+
+// The TS initiator against the Go responder's recorded msg2.
+describe('parity: spake2', () => {
+    for (const tc of (vectors as any).spake2.vectors) {
+        const cfg = { suite: tc.suite, identityA: unhex(tc.identityA), identityB: unhex(tc.identityB), aad: unhex(tc.aad) };
+        const g = tc.suite === Suites.P256Sha256 ? P256 : Ristretto255;
+
+        it(`${tc.name} initiator`, async () => {
+            const w = g.decodeScalar(unhex(tc.w));
+            if (tc.password) {
+                const fromPassword = await newInitiator(cfg, unhex(tc.password));
+                expect(hex(await fromPassword.startWithScalar(g.decodeScalar(unhex(tc.x))))).toEqual(tc.msg1);
+            }
+            const a = new Initiator(cfg, w);
+            const msg1 = await a.startWithScalar(g.decodeScalar(unhex(tc.x)));
+            expect(hex(msg1)).toEqual(tc.msg1);
+            const [msg3, ke] = await a.finish(unhex(tc.msg2));
+            expect(hex(msg3)).toEqual(tc.msg3);
+            expect(hex(ke)).toEqual(tc.Ke);
+        });
+
+        it(`${tc.name} initiator with a wrong password`, async () => {
+            const a = await newInitiator(cfg, new TextEncoder().encode('wrong'));
+            await a.startWithScalar(g.decodeScalar(unhex(tc.x)));
+            await expect(a.finish(unhex(tc.msg2))).rejects.toThrowError(Spake2Error);
+        });
+    }
+});
@@ -0,0 +1,23 @@
+import { describe, it, expect } from 'vitest';
+import { Suites, newInitiator } from '../../src/spake2';
+
+const enc = new TextEncoder();
+
+describe('spake2 initiator', () => {
+  it('rejects unknown suites', async () => {
+    await expect(newInitiator({ suite: 'SPAKE2-P384' }, enc.encode('pw'))).rejects.toThrowError('spake2: unsupported suite');
+  });
+
+  it('runs each step once and in order', async () => {
+    const a = await newInitiator({ suite: Suites.Ristretto255Sha512 }, enc.encode('pw'));
+    await expect(a.finish(new Uint8Array())).rejects.toThrowError('spake2: state machine used out of order or reused');
+    await a.start();
+    await expect(a.start()).rejects.toThrowError('spake2: state machine');
+  });
+
+  it('rejects malformed and identity shares', async () => {
+    const a = await newInitiator({ suite: Suites.P256Sha256 }, enc.encode('pw'));
+    await a.start();
+    await expect(a.finish(new Uint8Array([0, 1, 0, 0, 0]))).rejects.toThrowError('spake2: malformed message or invalid element');
+  });
+});
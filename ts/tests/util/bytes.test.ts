@@ -1,5 +1,9 @@
 import { describe, it, expect } from 'vitest';
-import { bytesToBigInt, bigIntToByteArray, intToBytes, concatBytes, framedBytesFromUint8Array, framedBytesFromBigInt, framedBytes } from '../../src/util/bytes';
+import {
+  bytesToBigInt, bigIntToByteArray, intToBytes, concatBytes, framedBytesFromUint8Array, framedBytesFromBigInt, framedBytes,
+  FramedReader, parseFramed, unframeBigInt,
+} from '../../src/util/bytes';
+import { errorCode } from '../../src/util/errors';
 
 function hex(buf: Uint8Array): string {
   return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
@@ -59,3 +63,34 @@ describe('bytes utilities', () => {
     expect(hex(s)).toEqual('0141');
   });
 });
+
+describe('framed decoding', () => {
+  it('round-trips a concatenated transcript', () => {
+    const buf = concatBytes(framedBytes(new Uint8Array([0xaa]), 2), framedBytes(-258n, 2), framedBytes('é', 2));
+    const r = new FramedReader(buf, 2);
+    expect(hex(r.readBytes())).toEqual('aa');
+    expect(r.readBigInt()).toEqual(-258n);
+    expect(r.readString()).toEqual('é');
+    expect(r.remaining()).toEqual(0);
+    r.finish();
+  });
+
+  it('leaves the reader in place after a bad frame', () => {
+    const r = new FramedReader(new Uint8Array([0x01, 0x02, 0x05, 0x01, 0x00]), 1);
+    expect(() => r.readBigInt()).toThrowError();
+    expect(r.remaining()).toEqual(5);
+  });
+
+  it('reports the caller op', () => {
+    let caught: unknown;
+    try {
+      unframeBigInt(new Uint8Array([0x00, 0x00]), 1);
+    } catch (err) {
+      caught = err;
+    }
+    expect(caught).toMatchObject({ op: 'UnframeBigInt', code: 'nonMinimal' });
+    expect(errorCode(caught)).toEqual('nonMinimal');
+    expect(() => parseFramed(new Uint8Array([0x02, 0xaa]), 1)).toThrowError();
+    expect(parseFramed(new Uint8Array([0x01, 0xaa, 0x00]), 1).map(hex)).toEqual(['aa', '']);
+  });
+});
@@ -0,0 +1,30 @@
+import { describe, it, expect } from 'vitest';
+import { ParityError, errorCode } from '../../src/util/errors';
+import { intToBytes } from '../../src/util/bytes';
+import { shakeHash } from '../../src/util/hash';
+
+describe('error codes', () => {
+  it('ParityError formats like Go', () => {
+    let caught: unknown;
+    try {
+      intToBytes(300, 1);
+    } catch (err) {
+      caught = err;
+    }
+    expect(caught).toBeInstanceOf(ParityError);
+    const pe = caught as ParityError;
+    expect(pe.op).toEqual('IntToBytes');
+    expect(pe.params).toEqual({ i: 300, byteLen: 1 });
+    expect(pe.code).toEqual('overflow');
+    expect(pe.message).toEqual('util.IntToBytes(byteLen=1, i=300): input does not fit in the requested length');
+  });
+
+  it('errorCode ignores foreign errors', () => {
+    expect(errorCode(new Error('other'))).toEqual('');
+    expect(errorCode(undefined)).toEqual('');
+  });
+
+  it('negative output lengths are outputLen', async () => {
+    await expect(shakeHash(new Uint8Array(), 128, -8)).rejects.toMatchObject({ op: 'ShakeHash', code: 'outputLen' });
+  });
+});
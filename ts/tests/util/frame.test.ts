@@ -0,0 +1,49 @@
+import { describe, it, expect } from 'vitest';
+import { framedBytes, FramedReader } from '../../src/util/bytes';
+import { framedBytesOf, fieldOf, framedConcat } from '../../src/util/frame';
+import { errorCode } from '../../src/util/errors';
+
+function hex(buf: Uint8Array): string {
+  return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+
+function codeOf(f: () => unknown): string {
+  try {
+    f();
+  } catch (err) {
+    return errorCode(err);
+  }
+  return '';
+}
+
+describe('typed framing', () => {
+  it('matches the dispatcher for bytes, strings and bigints', () => {
+    const data = new Uint8Array([0xaa, 0xbb]);
+    expect(framedBytesOf('bytes', data, 2)).toEqual(framedBytes(data, 2));
+    expect(framedBytesOf('string', 'é', 2)).toEqual(framedBytes('é', 2));
+    expect(framedBytesOf('bigint', -258n, 2)).toEqual(framedBytes(-258n, 2));
+  });
+
+  it('accepts numbers and bigints for integers', () => {
+    expect(hex(framedBytesOf('int16', -2, 1))).toEqual('02fffe');
+    expect(hex(framedBytesOf('int16', -2n, 1))).toEqual('02fffe');
+  });
+
+  it('round-trips through a FramedReader', () => {
+    const buf = framedConcat(2, fieldOf('string', 'ctx'), fieldOf('bigint', -5n), fieldOf('uint8', 7));
+    const r = new FramedReader(buf, 2);
+    expect(r.readString()).toEqual('ctx');
+    expect(r.readBigInt()).toEqual(-5n);
+    expect(hex(r.readBytes())).toEqual('07');
+    r.finish();
+  });
+
+  it('rejects values that do not fit the kind', () => {
+    expect(codeOf(() => framedBytesOf('uint8', 256, 1))).toEqual('overflow');
+    expect(codeOf(() => framedBytesOf('int8', -129n, 1))).toEqual('overflow');
+    expect(codeOf(() => framedBytesOf('uint32', -1, 1))).toEqual('negative');
+    expect(codeOf(() => framedBytesOf('int64', 1.5, 1))).toEqual('invalidArgument');
+    expect(codeOf(() => framedBytesOf('bool', 1, 1))).toEqual('invalidArgument');
+    expect(codeOf(() => framedConcat(1, fieldOf('bytes', new Uint8Array(256))))).toEqual('overflow');
+  });
+});
@@ -1,5 +1,7 @@
 import { describe, it, expect } from 'vitest';
-import { sha2Hash, sha3Hash, shakeHash, cShakeHash, type Sha2, type Sha3, type Shake, type CShake } from '../../src/util/hash';
+import {
+  sha2Hash, sha3Hash, shakeHash, cShakeHash, hmacSha2, hkdf, hkdfExpand, type Sha2, type Sha3, type Shake, type CShake,
+} from '../../src/util/hash';
 
 function hex(buf: Uint8Array): string {
   return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
@@ -116,3 +118,23 @@ describe('cshakeHash', () => {
     });
   }
 });
+
+describe('hmac and hkdf', () => {
+  it('an empty HKDF salt is hash-length zeros', async () => {
+    const ikm = new TextEncoder().encode('ikm');
+    const a = await hkdf(ikm, new Uint8Array(), new Uint8Array(), 32, 256);
+    const b = await hkdf(ikm, new Uint8Array(32), new Uint8Array(), 32, 256);
+    expect(hex(a)).toEqual(hex(b));
+  });
+
+  it('hkdfExpand allows zero and at most 255 hash lengths', async () => {
+    const prk = new Uint8Array(32);
+    expect(await hkdfExpand(prk, new Uint8Array(), 0, 256)).toHaveLength(0);
+    expect(await hkdfExpand(prk, new Uint8Array(), 255 * 32, 256)).toHaveLength(255 * 32);
+    await expect(hkdfExpand(prk, new Uint8Array(), 255 * 32 + 1, 256)).rejects.toMatchObject({ op: 'HkdfExpand', code: 'outputLen' });
+  });
+
+  it('rejects unsupported bit lengths', async () => {
+    await expect(hmacSha2(new Uint8Array(), new Uint8Array(), 160 as Sha2)).rejects.toMatchObject({ op: 'HmacSha2', code: 'unsupportedBits' });
+  });
+});
@@ -1,9 +1,18 @@
 import { describe, it, expect } from 'vitest';
 import vectors from '../../../testdata/parity.json';
-import { bytesToBigInt, bigIntToByteArray, intToBytes, concatBytes, framedBytesFromUint8Array, framedBytesFromBigInt, framedBytesFromString } from '../../src/util/bytes';
+import {
+    bytesToBigInt, bigIntToByteArray, intToBytes, bigIntToBytes, concatBytes, framedBytesFromUint8Array, framedBytesFromBigInt, framedBytesFromString,
+    FramedReader, unframeBytes, unframeBigInt, unframeString,
+} from '../../src/util/bytes';
+import { framedBytesOf, fieldOf, framedConcat, type FrameKind, type FrameValue } from '../../src/util/frame';
 import {bigCmp, bigModPos} from "../../src/util/numeric";
-import { encUrlSafe, decUrlSafe } from '../../src/util/coding';
-import { sha2Hash, sha3Hash, shakeHash, cShakeHash } from '../../src/util/hash';
+import { encUrlSafe, decUrlSafe, encBase58, decBase58 } from '../../src/util/coding';
+import {
+    sha2Hash, sha3Hash, shakeHash, cShakeHash, hmacSha2, hmacSha3, hkdfExtract, hkdfExpand, hkdf, hkdfSha3Extract, hkdfSha3Expand, hkdfSha3,
+} from '../../src/util/hash';
+import { kmac, kmacXof, tupleHash, tupleHashXof, parallelHash, parallelHashXof } from '../../src/util/sp800185';
+import { expandMessageXmd, expandMessageXof } from '../../src/util/expand';
+import { ErrorCodes, ParityError, errorCode } from '../../src/util/errors';
 
 function hex(buf: Uint8Array): string {
     return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
@@ -67,6 +76,12 @@ describe('parity: bytes', () => {
             }
         });
     }
+    for (const tc of (vectors as any).bytes.bigIntToBytes) {
+        it(`bigIntToBytes ${tc.bigint},${tc.len}`, () => {
+            const got = expectCode(() => bigIntToBytes(BigInt(tc.bigint), tc.len), tc.error) as Uint8Array | undefined;
+            if (!tc.error) expect(hex(got!)).toEqual(tc.bytes);
+        });
+    }
     for (const tc of (vectors as any).bytes.concat) {
         it(`concat ${tc.a}+${tc.b}`, () => {
             const got = concatBytes(unhex(tc.a), unhex(tc.b));
@@ -101,6 +116,91 @@ describe('parity: bytes', () => {
     }
 });
 
+// Typed framing parity: the "type" field names the FrameKind, "value" is its string form (hex for bytes).
+
+function frameValue(kind: FrameKind, value: any): FrameValue {
+    switch (kind) {
+        case 'bytes': return unhex(value);
+        case 'string': return value;
+        case 'bool': return value === 'true';
+        case 'bytesList': return value.map(unhex);
+        default: return BigInt(value);
+    }
+}
+
+describe('parity: typed framing', () => {
+    for (const tc of (vectors as any).bytes.framedOf) {
+        it(`framedOf ${tc.type} ${JSON.stringify(tc.value)}`, () => {
+            const got = framedBytesOf(tc.type, frameValue(tc.type, tc.value), tc.lenBytes);
+            expect(hex(got)).toEqual(tc.frame);
+        });
+    }
+    for (const tc of (vectors as any).bytes.framedConcat) {
+        it(`framedConcat ${tc.out}`, () => {
+            const fields = tc.fields.map((f: any) => fieldOf(f.type, frameValue(f.type, f.value)));
+            expect(hex(framedConcat(tc.lenBytes, ...fields))).toEqual(tc.out);
+        });
+    }
+});
+
+// Decoding parity: a vector with an error must fail with that code, otherwise decode to the recorded value.
+
+function expectCode(f: () => unknown, code: string | undefined): unknown {
+    if (!code) return f();
+    expect(f).toThrowError(ParityError);
+    try {
+        f();
+    } catch (err) {
+        expect(errorCode(err)).toEqual(code);
+    }
+    return undefined;
+}
+
+describe('parity: unframe', () => {
+    for (const tc of (vectors as any).bytes.unframeBytes) {
+        it(`unframeBytes ${tc.frame}`, () => {
+            const got = expectCode(() => unframeBytes(unhex(tc.frame), tc.lenBytes), tc.error);
+            if (!tc.error) expect(hex(got as Uint8Array)).toEqual(tc.data);
+        });
+    }
+    for (const tc of (vectors as any).bytes.unframeBigInt) {
+        it(`unframeBigInt ${tc.frame}`, () => {
+            const got = expectCode(() => unframeBigInt(unhex(tc.frame), tc.lenBytes), tc.error);
+            if (!tc.error) expect(got).toEqual(BigInt(tc.value));
+        });
+    }
+    for (const tc of (vectors as any).bytes.unframeString) {
+        it(`unframeString ${tc.frame}`, () => {
+            const got = expectCode(() => unframeString(unhex(tc.frame), tc.lenBytes), tc.error);
+            if (!tc.error) expect(got).toEqual(tc.str);
+        });
+    }
+    for (const tc of (vectors as any).bytes.framedReader) {
+        it(`framedReader ${tc.buf}`, () => {
+            const r = new FramedReader(unhex(tc.buf), tc.lenBytes);
+            const reads: Record<string, () => string> = {
+                bytes: () => hex(r.readBytes()),
+                bigint: () => r.readBigInt().toString(),
+                string: () => r.readString(),
+            };
+            const last = tc.kinds.length - 1;
+            for (let i = 0; i < last; i++) {
+                expect(reads[tc.kinds[i]]()).toEqual(tc.values[i]);
+            }
+            if (tc.error === 'trailing') {
+                expect(reads[tc.kinds[last]]()).toEqual(tc.values[last]);
+                expectCode(() => r.finish(), tc.error);
+            } else {
+                const got = expectCode(reads[tc.kinds[last]], tc.error);
+                if (!tc.error) {
+                    expect(got).toEqual(tc.values[last]);
+                    r.finish();
+                }
+            }
+        });
+    }
+});
+
 // Coding parity
 
 describe('parity: coding', () => {
@@ -116,6 +216,18 @@ describe('parity: coding', () => {
             expect(hex(got)).toEqual(tc.bytes);
         });
     }
+    for (const tc of (vectors as any).coding.base58) {
+        it(`base58 ${tc.bytes}`, () => {
+            expect(encBase58(unhex(tc.bytes))).toEqual(tc.b58);
+            expect(hex(decBase58(tc.b58))).toEqual(tc.bytes);
+        });
+    }
+    for (const tc of (vectors as any).coding.base58Invalid) {
+        it(`base58 rejects ${tc.b58}`, () => {
+            expectCode(() => decBase58(tc.b58), tc.error);
+            expect(() => decBase58(tc.b58)).toThrowError(`offset=${tc.offset}`);
+        });
+    }
 });
 
 // Hash parity
@@ -149,4 +261,104 @@ describe('parity: hash', () => {
             expect(hex(out)).toEqual(tc.hash);
         });
     }
+    for (const tc of (vectors as any).hash.hmacSha2) {
+        it(`hmacSha2 ${tc.name}`, async () => {
+            expect(hex(await hmacSha2(unhex(tc.key), unhex(tc.msg), tc.bits))).toEqual(tc.mac);
+        });
+    }
+    for (const tc of (vectors as any).hash.hmacSha3) {
+        it(`hmacSha3 ${tc.name}`, async () => {
+            expect(hex(await hmacSha3(unhex(tc.key), unhex(tc.msg), tc.bits))).toEqual(tc.mac);
+        });
+    }
+    for (const tc of (vectors as any).hash.hkdf) {
+        it(`hkdf ${tc.name}`, async () => {
+            const prk = await hkdfExtract(unhex(tc.salt), unhex(tc.ikm), tc.bits);
+            expect(hex(prk)).toEqual(tc.prk);
+            expect(hex(await hkdfExpand(prk, unhex(tc.info), tc.length, tc.bits))).toEqual(tc.okm);
+            expect(hex(await hkdf(unhex(tc.ikm), unhex(tc.salt), unhex(tc.info), tc.length, tc.bits))).toEqual(tc.okm);
+        });
+    }
+    for (const tc of (vectors as any).hash.hkdfSha3) {
+        it(`hkdfSha3 ${tc.name}`, async () => {
+            const prk = await hkdfSha3Extract(unhex(tc.salt), unhex(tc.ikm), tc.bits);
+            expect(hex(prk)).toEqual(tc.prk);
+            expect(hex(await hkdfSha3Expand(prk, unhex(tc.info), tc.length, tc.bits))).toEqual(tc.okm);
+            expect(hex(await hkdfSha3(unhex(tc.ikm), unhex(tc.salt), unhex(tc.info), tc.length, tc.bits))).toEqual(tc.okm);
+        });
+    }
+    for (const tc of (vectors as any).hash.kmac) {
+        it(`kmac ${tc.name}`, async () => {
+            const f = tc.xof ? kmacXof : kmac;
+            expect(hex(await f(unhex(tc.key), unhex(tc.msg), tc.bits, tc.outBits, tc.cust))).toEqual(tc.out);
+        });
+    }
+    for (const tc of (vectors as any).hash.tupleHash) {
+        it(`tupleHash ${tc.name}`, async () => {
+            const f = tc.xof ? tupleHashXof : tupleHash;
+            expect(hex(await f(tc.items.map(unhex), tc.bits, tc.outBits, tc.cust))).toEqual(tc.out);
+        });
+    }
+    for (const tc of (vectors as any).hash.parallelHash) {
+        it(`parallelHash ${tc.name}`, async () => {
+            const f = tc.xof ? parallelHashXof : parallelHash;
+            expect(hex(await f(unhex(tc.msg), tc.blockSize, tc.bits, tc.outBits, tc.cust))).toEqual(tc.out);
+        });
+    }
+});
+
+// expand_message parity (RFC 9380, appendix K)
+
+describe('parity: expand_message', () => {
+    const enc = new TextEncoder();
+    for (const tc of (vectors as any).h2c.expandMessage) {
+        it(`${tc.expander}-${tc.bits} ${JSON.stringify(tc.msg.slice(0, 16))} len ${tc.lenInBytes} dst ${tc.dst.length}`, async () => {
+            const f = tc.expander === 'xmd' ? expandMessageXmd : expandMessageXof;
+            expect(hex(await f(enc.encode(tc.msg), enc.encode(tc.dst), tc.lenInBytes, tc.bits))).toEqual(tc.uniform);
+        });
+    }
+});
+
+// Error code parity: every vector whose op exists in TS must fail with the same op and code.
+
+const errorOps: Record<string, (p: Record<string, number>) => unknown> = {
+    Sha2Hash: p => sha2Hash(new Uint8Array(), p.bits as any),
+    Sha3Hash: p => sha3Hash(new Uint8Array(), p.bits as any),
+    ShakeHash: p => shakeHash(new Uint8Array(), p.bits as any, p.outputLenBits),
+    CShakeHash: p => cShakeHash(new Uint8Array(), p.bits as any, p.outputLenBits, '', ''),
+    IntToBytes: p => intToBytes(p.i, p.byteLen),
+    HmacSha2: p => hmacSha2(new Uint8Array(), new Uint8Array(), p.bits as any),
+    HmacSha3: p => hmacSha3(new Uint8Array(), new Uint8Array(), p.bits as any),
+    HkdfExpand: p => hkdfExpand(new Uint8Array(32), new Uint8Array(), p.length, p.bits as any),
+    HkdfSha3Expand: p => hkdfSha3Expand(new Uint8Array(32), new Uint8Array(), p.length, p.bits as any),
+    Kmac: p => kmac(new Uint8Array(), new Uint8Array(), p.bits as any, p.outputLenBits, ''),
+    TupleHash: p => tupleHash([], p.bits as any, p.outputLenBits, ''),
+    ParallelHash: p => parallelHash(new Uint8Array(), p.blockSize, p.bits as any, p.outputLenBits, ''),
+    UnframeBytes: p => unframeBytes(new Uint8Array(), p.lengthPrefixBytes),
+    ExpandMessageXmd: p => expandMessageXmd(new Uint8Array(), new Uint8Array(), p.lenInBytes, p.bits as any),
+    ExpandMessageXof: p => expandMessageXof(new Uint8Array(), new Uint8Array(), p.lenInBytes, p.bits as any),
+};
+
+describe('parity: errors', () => {
+    it('knows every code in the vectors', () => {
+        const codes = new Set<string>(Object.values(ErrorCodes));
+        for (const tc of (vectors as any).errors) {
+            expect(codes.has(tc.code)).toBe(true);
+        }
+    });
+    for (const tc of (vectors as any).errors) {
+        const op = errorOps[tc.op];
+        if (!op) continue; // Go-only functions
+        it(`${tc.op} ${JSON.stringify(tc.params)} -> ${tc.code}`, async () => {
+            let caught: unknown;
+            try {
+                await op(tc.params);
+            } catch (err) {
+                caught = err;
+            }
+            expect(caught).toBeInstanceOf(ParityError);
+            expect((caught as ParityError).op).toEqual(tc.op);
+            expect(errorCode(caught)).toEqual(tc.code);
+        });
+    }
 });
@@ -0,0 +1,32 @@
+import { describe, it, expect } from 'vitest';
+import { kmac, kmacXof, tupleHash, parallelHash } from '../../src/util/sp800185';
+
+function hex(buf: Uint8Array): string {
+  return Array.from(buf).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+
+const te = new TextEncoder();
+
+describe('SP 800-185', () => {
+  it('binds the output length, except for the XOF variants', async () => {
+    const key = te.encode('key'), msg = te.encode('msg');
+    const short = await kmac(key, msg, 128, 128, '');
+    const long = await kmac(key, msg, 128, 256, '');
+    expect(hex(long).startsWith(hex(short))).toBe(false);
+    const xofShort = await kmacXof(key, msg, 128, 128, '');
+    const xofLong = await kmacXof(key, msg, 128, 256, '');
+    expect(hex(xofLong).startsWith(hex(xofShort))).toBe(true);
+  });
+
+  it('keeps TupleHash item boundaries', async () => {
+    const a = await tupleHash([te.encode('ab'), te.encode('c')], 256, 256, '');
+    const b = await tupleHash([te.encode('a'), te.encode('bc')], 256, 256, '');
+    expect(hex(a)).not.toEqual(hex(b));
+  });
+
+  it('reports the caller op', async () => {
+    await expect(kmacXof(new Uint8Array(), new Uint8Array(), 128, -8, '')).rejects.toMatchObject({ op: 'KmacXof', code: 'outputLen' });
+    await expect(tupleHash([], 512 as any, 256, '')).rejects.toMatchObject({ op: 'TupleHash', code: 'unsupportedBits' });
+    await expect(parallelHash(new Uint8Array(), -1, 128, 256, '')).rejects.toMatchObject({ op: 'ParallelHash', code: 'invalidArgument' });
+  });
+});